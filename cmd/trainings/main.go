@@ -8,12 +8,14 @@ import (
 
 	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres"
 	"github.com/EnduranNSU/trainings/internal/app"
+	"github.com/EnduranNSU/trainings/internal/domain"
 	"github.com/EnduranNSU/trainings/internal/logging"
 	svc "github.com/EnduranNSU/trainings/internal/service"
 	_ "github.com/joho/godotenv/autoload"
 	_ "github.com/lib/pq"
 	"github.com/num30/config"
 	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
 )
 
 func init() {
@@ -69,10 +71,15 @@ func main() {
 	trepo := postgres.NewTrainingRepository(db)
 	erepo := postgres.NewExerciseRepository(db)
 
-	tsvc := svc.NewTrainingService(trepo)
+	tsvc := svc.NewTrainingService(trepo, toProgressionRules(cfg.Progression))
 	esvc := svc.NewExerciseService(erepo)
 
-	srv := app.SetupServer(tsvc, esvc, cfg.Http.Addr)
+	// Фоновое создание тренировок по расписаниям, останавливается вместе с сервером
+	materializerCtx, stopMaterializer := context.WithCancel(context.Background())
+	defer stopMaterializer()
+	go svc.NewScheduleMaterializer(trepo, cfg.Schedule.MaterializeInterval).Run(materializerCtx)
+
+	srv := app.SetupServer(tsvc, esvc, cfg.Http.Addr, cfg.Auth)
 	
 	if err := srv.StartServer(); err != nil {
 		log.Fatal().Err(err).
@@ -80,6 +87,14 @@ func main() {
 	}
 }
 
+func toProgressionRules(cfg app.ProgressionConfig) domain.ProgressionRules {
+	return domain.ProgressionRules{
+		WeightStep:    decimal.NewFromFloat(cfg.WeightStep),
+		DeloadAfter:   cfg.DeloadAfter,
+		DeloadPercent: decimal.NewFromFloat(cfg.DeloadPercent),
+	}
+}
+
 func toLoggerConfig(cfg app.LoggerConfig) logging.Config {
 	return logging.Config{
 		Level: cfg.Level,
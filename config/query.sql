@@ -5,6 +5,7 @@ SELECT
     e.description,
     e.video_url,
     e.image_url,
+    e.type,
     COALESCE(
         json_agg(
             json_build_object(
@@ -27,6 +28,7 @@ SELECT
     e.description,
     e.video_url,
     e.image_url,
+    e.type,
     COALESCE(
         json_agg(
             json_build_object(
@@ -51,12 +53,89 @@ SELECT
     e.title,
     e.description,
     e.video_url,
-    e.image_url
+    e.image_url,
+    e.type
 FROM exercise e
 INNER JOIN exercise_to_tag et ON e.id = et.exercise_id
 WHERE et.tag_id = $1
 ORDER BY e.id;
 
+-- name: GetTagByID :one
+SELECT id, type FROM tag WHERE id = $1;
+
+-- name: CreateExercise :one
+-- Администрирование каталога упражнений
+INSERT INTO exercise (
+    title,
+    description,
+    video_url,
+    image_url,
+    type
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, title, description, video_url, image_url, type;
+
+-- name: UpdateExercise :one
+UPDATE exercise
+SET 
+    title = $1,
+    description = $2,
+    video_url = $3,
+    image_url = $4,
+    type = $5
+WHERE id = $6
+RETURNING id, title, description, video_url, image_url, type;
+
+-- name: IsExerciseUsed :one
+-- Используется ли упражнение в тренировках и шаблонах пользователей или глобальных тренировках
+SELECT EXISTS (
+    SELECT 1 FROM trained_exercise WHERE exercise_id = $1
+    UNION ALL
+    SELECT 1 FROM global_training_exercise WHERE exercise_id = $1
+    UNION ALL
+    SELECT 1 FROM training_template_exercise WHERE exercise_id = $1
+) as used;
+
+-- name: DeleteExercise :execrows
+DELETE FROM exercise WHERE id = $1;
+
+-- name: CreateTag :one
+INSERT INTO tag (type) VALUES ($1)
+RETURNING id, type;
+
+-- name: UpdateTag :one
+UPDATE tag SET type = $1 WHERE id = $2
+RETURNING id, type;
+
+-- name: DeleteTag :execrows
+DELETE FROM tag WHERE id = $1;
+
+-- name: AttachTagToExercise :exec
+INSERT INTO exercise_to_tag (exercise_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTagFromExercise :execrows
+DELETE FROM exercise_to_tag
+WHERE exercise_id = $1 AND tag_id = $2;
+
+-- name: GetExerciseAliases :many
+SELECT alias
+FROM exercise_alias
+WHERE exercise_id = $1
+ORDER BY alias;
+
+-- name: DeleteExerciseAliases :exec
+DELETE FROM exercise_alias
+WHERE exercise_id = $1;
+
+-- name: AddExerciseAlias :execrows
+-- Название, которое уже занято другим упражнением (без учета регистра), не добавляется
+INSERT INTO exercise_alias (exercise_id, alias)
+VALUES ($1, $2)
+ON CONFLICT ((lower(alias))) DO NOTHING;
+
 -- name: GetTrainingsByUser :many
 SELECT 
     t.id,
@@ -70,13 +149,69 @@ SELECT
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    t.rating
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused
 FROM training t
 LEFT JOIN trained_exercise te ON t.id = te.training_id
 WHERE t.user_id = $1
 GROUP BY t.id
 ORDER BY t.planned_date DESC;
 
+-- name: GetTrainingsFiltered :many
+-- Страница тренировок пользователя с фильтрами и keyset-пагинацией.
+-- sort_key - числовой ключ сортировки (sort_by: 'planned_date', 'actual_date' или 'rating'): даты - дни от 1970-01-01,
+-- тренировки без фактической даты или рейтинга идут в конце при сортировке по убыванию.
+-- cursor_key и cursor_id - ключ и ID последней записи предыдущей страницы
+SELECT 
+    f.id,
+    f.title,
+    f.user_id,
+    f.is_done,
+    f.planned_date,
+    f.actual_date,
+    f.started_at,
+    f.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM f.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM f.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM f.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    f.rating,
+    f.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = f.id AND tp.ended_at IS NULL
+    ) as is_paused,
+    f.sort_key
+FROM (
+    SELECT
+        t.*,
+        CAST(CASE sqlc.arg(sort_by)::text
+            WHEN 'actual_date' THEN COALESCE(t.actual_date - DATE '1970-01-01', -1000000)
+            WHEN 'rating' THEN COALESCE(t.rating, 0)
+            ELSE t.planned_date - DATE '1970-01-01'
+        END AS BIGINT) as sort_key
+    FROM training t
+    WHERE t.user_id = sqlc.arg(user_id)
+        AND (sqlc.narg(planned_from)::date IS NULL OR t.planned_date >= sqlc.narg(planned_from)::date)
+        AND (sqlc.narg(planned_to)::date IS NULL OR t.planned_date <= sqlc.narg(planned_to)::date)
+        AND (sqlc.narg(is_done)::boolean IS NULL OR t.is_done = sqlc.narg(is_done)::boolean)
+        AND (sqlc.narg(min_rating)::integer IS NULL OR t.rating >= sqlc.narg(min_rating)::integer)
+        AND (sqlc.narg(max_rating)::integer IS NULL OR t.rating <= sqlc.narg(max_rating)::integer)
+        AND (sqlc.narg(search)::text IS NULL OR strpos(lower(t.title), lower(sqlc.narg(search)::text)) > 0)
+) f
+WHERE sqlc.narg(cursor_id)::bigint IS NULL
+    OR (sqlc.arg(sort_desc)::boolean AND (f.sort_key, f.id) < (sqlc.narg(cursor_key)::bigint, sqlc.narg(cursor_id)::bigint))
+    OR (NOT sqlc.arg(sort_desc)::boolean AND (f.sort_key, f.id) > (sqlc.narg(cursor_key)::bigint, sqlc.narg(cursor_id)::bigint))
+ORDER BY
+    CASE WHEN sqlc.arg(sort_desc)::boolean THEN f.sort_key END DESC,
+    CASE WHEN sqlc.arg(sort_desc)::boolean THEN f.id END DESC,
+    f.sort_key,
+    f.id
+LIMIT sqlc.arg(page_limit);
+
 -- name: CreateTraining :one
 INSERT INTO training (
     title,
@@ -89,9 +224,10 @@ INSERT INTO training (
     total_duration,
     total_rest_time,
     total_exercise_time,
-    rating
+    rating,
+    status
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
 RETURNING 
     id,
@@ -105,9 +241,18 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating;
+    rating,
+    status;
+
+-- name: LockTraining :one
+-- Блокировка тренировки пользователя до конца транзакции: добавления упражнений в одну тренировку
+-- выполняются по очереди и не получают одинаковый position
+SELECT id FROM training
+WHERE id = $1 AND user_id = $2
+FOR UPDATE;
 
 -- name: AddExerciseToTraining :one
+-- Добавление упражнения только в тренировку, принадлежащую пользователю
 INSERT INTO trained_exercise (
     training_id,
     exercise_id,
@@ -117,10 +262,17 @@ INSERT INTO trained_exercise (
     time,
     doing,
     rest,
-    notes
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    notes,
+    position,
+    group_id,
+    group_type
 )
+SELECT t.id, $2, $3, $4, $5, $6, $7, $8, $9,
+    -- Новое упражнение встает в конец тренировки
+    (SELECT COALESCE(MAX(te.position), 0) + 1 FROM trained_exercise te WHERE te.training_id = t.id),
+    $11, $12
+FROM training t
+WHERE t.id = $1 AND t.user_id = $10
 RETURNING 
     id,
     training_id,
@@ -131,45 +283,50 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM time)::bigint, 0) as bigint) as time,
     CAST(COALESCE(EXTRACT(EPOCH FROM doing)::bigint, 0)as bigint) as doing,
     CAST(COALESCE(EXTRACT(EPOCH FROM rest)::bigint, 0)as bigint) as rest,
-    notes;
+    notes,
+    position,
+    group_id,
+    group_type;
 
 -- name: UpdateTrainedExercise :one
-UPDATE trained_exercise
+UPDATE trained_exercise te
 SET 
-    weight = COALESCE($1, weight),
-    approaches = COALESCE($2, approaches),
-    reps = COALESCE($3, reps),
-    time = COALESCE($4, time),
-    doing = COALESCE($5, doing),
-    rest = COALESCE($6, rest),
-    notes = COALESCE($7, notes)
-WHERE id = $8
+    weight = COALESCE($1, te.weight),
+    approaches = COALESCE($2, te.approaches),
+    reps = COALESCE($3, te.reps),
+    time = COALESCE($4, te.time),
+    doing = COALESCE($5, te.doing),
+    rest = COALESCE($6, te.rest),
+    notes = COALESCE($7, te.notes)
+FROM training t
+WHERE te.id = $8 AND te.training_id = t.id AND t.user_id = $9
 RETURNING 
-    id,
-    training_id,
-    exercise_id,
-    weight,
-    approaches,
-    reps,
-    CAST(COALESCE(EXTRACT(EPOCH FROM time)::bigint, 0) as bigint) as time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM doing)::bigint, 0)as bigint) as doing,
-    CAST(COALESCE(EXTRACT(EPOCH FROM rest)::bigint, 0)as bigint) as rest,
-    notes;
+    te.id,
+    te.training_id,
+    te.exercise_id,
+    te.weight,
+    te.approaches,
+    te.reps,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint) as time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0)as bigint) as doing,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0)as bigint) as rest,
+    te.notes,
+    te.position,
+    te.group_id,
+    te.group_type;
 
 -- name: UpdateTraining :one
+-- Обновление полей тренировки. Статус, is_done, время начала и окончания и фактическая дата
+-- меняются только переходами жизненного цикла
 UPDATE training
 SET
-    is_done = COALESCE($1, is_done),
-    planned_date = COALESCE($2, planned_date),
-    actual_date = COALESCE($3, actual_date),
-    started_at = COALESCE($4, started_at),
-    finished_at = COALESCE($5, finished_at),
-    total_duration = COALESCE($6, total_duration),
-    total_rest_time = COALESCE($7, total_rest_time),
-    total_exercise_time = COALESCE($8, total_exercise_time),
-    rating = COALESCE($9, rating),
-    title = COALESCE($10, title)
-WHERE id = $11
+    planned_date = COALESCE($1, planned_date),
+    total_duration = COALESCE($2, total_duration),
+    total_rest_time = COALESCE($3, total_rest_time),
+    total_exercise_time = COALESCE($4, total_exercise_time),
+    rating = COALESCE($5, rating),
+    title = COALESCE($6, title)
+WHERE id = $7 AND user_id = $8
 RETURNING 
     id,
     title,
@@ -182,9 +339,12 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating;
+    rating,
+    status;
 
 -- name: GetTrainingWithExercises :one
+-- Подходы агрегируются в approaches/reps/weight упражнения, если они есть:
+-- approaches — число рабочих подходов, reps — сумма повторений, weight — максимальный вес
 SELECT 
     t.id,
     t.title,
@@ -198,58 +358,156 @@ SELECT
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
     t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
     COALESCE(
         json_agg(
             json_build_object(
                 'id', te.id,
                 'training_id', te.training_id, 
                 'exercise_id', te.exercise_id,
-                'weight', te.weight,
-                'approaches', te.approaches,
-                'reps', te.reps,
+                'weight', CASE WHEN s.sets IS NULL THEN te.weight ELSE s.weight END,
+                'approaches', CASE WHEN s.sets IS NULL THEN te.approaches ELSE s.approaches END,
+                'reps', CASE WHEN s.sets IS NULL THEN te.reps ELSE s.reps END,
                 'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
                 'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
                 'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
-                'notes', te.notes
-            )
+                'notes', te.notes,
+                'sets', COALESCE(s.sets, '[]'::json),
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
         ) FILTER (WHERE te.id IS NOT NULL),
         '[]'
     ) as exercises
 FROM training t
 LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.id = $1
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) FILTER (WHERE NOT ts.is_warmup) as approaches,
+        SUM(ts.reps) FILTER (WHERE NOT ts.is_warmup) as reps,
+        MAX(ts.weight) FILTER (WHERE NOT ts.is_warmup) as weight,
+        json_agg(
+            json_build_object(
+                'id', ts.id,
+                'trained_exercise_id', ts.trained_exercise_id,
+                'ordinal', ts.ordinal,
+                'weight', ts.weight,
+                'reps', ts.reps,
+                'duration', EXTRACT(EPOCH FROM ts.duration)::bigint,
+                'rpe', ts.rpe,
+                'is_warmup', ts.is_warmup
+            ) ORDER BY ts.ordinal
+        ) as sets
+    FROM trained_set ts
+    WHERE ts.trained_exercise_id = te.id
+) s ON TRUE
+WHERE t.id = $1 AND t.user_id = $2
 GROUP BY t.id;
 
 -- name: DeleteExerciseFromTraining :exec
-DELETE FROM trained_exercise 
-WHERE id = $1 AND training_id = $2;
+DELETE FROM trained_exercise te
+USING training t
+WHERE te.id = $1 AND te.training_id = $2
+    AND t.id = te.training_id AND t.user_id = $3;
+
+-- name: RenumberTrainedExercises :exec
+-- Сплошная нумерация упражнений тренировки после удаления
+UPDATE trained_exercise te
+SET position = n.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) as rn
+    FROM trained_exercise
+    WHERE training_id = $1
+) n
+WHERE te.id = n.id AND te.position <> n.rn;
+
+-- name: SetTrainedExercisePosition :execrows
+UPDATE trained_exercise
+SET position = $1
+WHERE id = $2 AND training_id = $3;
+
+-- name: SetTrainedExerciseGroup :one
+-- Включение упражнения в группу или исключение из нее (NULL)
+UPDATE trained_exercise te
+SET group_id = $1, group_type = $2
+FROM training t
+WHERE te.id = $3 AND te.training_id = t.id AND t.user_id = $4
+RETURNING 
+    te.id,
+    te.training_id,
+    te.exercise_id,
+    te.weight,
+    te.approaches,
+    te.reps,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint) as time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0)as bigint) as doing,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0)as bigint) as rest,
+    te.notes,
+    te.position,
+    te.group_id,
+    te.group_type;
 
 -- name: DeleteTrainingAndExercises :exec
 WITH deleted_exercises AS (
-    DELETE FROM trained_exercise WHERE training_id = $1
+    DELETE FROM trained_exercise
+    WHERE training_id IN (SELECT id FROM training WHERE id = $1 AND user_id = $2)
 )
-DELETE FROM training WHERE training.id = $1;
+DELETE FROM training WHERE training.id = $1 AND training.user_id = $2;
+
+-- name: GetTrainingOwner :one
+-- Владелец тренировки (для проверки доступа)
+SELECT user_id FROM training WHERE id = $1;
+
+-- name: GetTrainedExerciseOwner :one
+-- Владелец и тренировка, в которую входит выполненное упражнение
+SELECT t.user_id, te.training_id
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE te.id = $1;
 
 
 -- name: UpdateExerciseTime :one
 -- Обновление времени выполнения упражнения (doing) и времени отдыха (rest)
-UPDATE trained_exercise
+UPDATE trained_exercise te
 SET 
-    doing = COALESCE($1, doing),
-    rest = COALESCE($2, rest),
-    time = COALESCE($3, time)  -- Общее время упражнения (doing + rest)
-WHERE id = $4
+    doing = COALESCE($1, te.doing),
+    rest = COALESCE($2, te.rest),
+    time = COALESCE($3, te.time)  -- Общее время упражнения (doing + rest)
+FROM training t
+WHERE te.id = $4 AND te.training_id = t.id AND t.user_id = $5
 RETURNING 
-    id,
-    training_id,
-    exercise_id,
-    weight,
-    approaches,
-    reps,
-    CAST(COALESCE(EXTRACT(EPOCH FROM time)::bigint, 0) as bigint) as time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM doing)::bigint, 0)as bigint) as doing,
-    CAST(COALESCE(EXTRACT(EPOCH FROM rest)::bigint, 0)as bigint) as rest,
-    notes;
+    te.id,
+    te.training_id,
+    te.exercise_id,
+    te.weight,
+    te.approaches,
+    te.reps,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint) as time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0)as bigint) as doing,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0)as bigint) as rest,
+    te.notes,
+    te.position,
+    te.group_id,
+    te.group_type;
 
 -- name: UpdateTrainingTimers :one
 -- Обновление времени тренировки (старт, финиш, общая продолжительность)
@@ -260,7 +518,7 @@ SET
     total_duration = COALESCE($3, total_duration),
     total_rest_time = COALESCE($4, total_rest_time),
     total_exercise_time = COALESCE($5, total_exercise_time)
-WHERE id = $6
+WHERE id = $6 AND user_id = $7
 RETURNING 
     id,
     title,
@@ -273,7 +531,8 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating;
+    rating,
+    status;
 
 -- name: CalculateTrainingTotalTime :one
 -- Расчет общего времени тренировки на основе всех упражнений
@@ -282,10 +541,12 @@ SELECT
     COALESCE(SUM(EXTRACT(EPOCH FROM te.rest)), 0) as total_rest_seconds,
     COALESCE(SUM(EXTRACT(EPOCH FROM te.doing)) + SUM(EXTRACT(EPOCH FROM te.rest)), 0) as total_seconds
 FROM trained_exercise te
-WHERE te.training_id = $1;
+JOIN training t ON t.id = te.training_id
+WHERE te.training_id = $1 AND t.user_id = $2;
 
 -- name: GetCurrentTraining :one
--- Получение тренировки на сегодня для пользователя
+-- Получение тренировки на сегодня для пользователя.
+-- $2 - сегодняшняя дата в часовом поясе пользователя
 SELECT 
     t.id,
     t.title,
@@ -299,6 +560,11 @@ SELECT
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
     t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
     COALESCE(
         json_agg(
             json_build_object(
@@ -311,22 +577,40 @@ SELECT
                 'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
                 'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
                 'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
-                'notes', te.notes
-            )
+                'notes', te.notes,
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
         ) FILTER (WHERE te.id IS NOT NULL),
         '[]'
     ) as exercises
 FROM training t
 LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
 WHERE t.user_id = $1 
-    AND t.planned_date = CURRENT_DATE
+    AND t.planned_date = $2
     AND t.is_done = false
 GROUP BY t.id
 ORDER BY t.planned_date DESC
 LIMIT 1;
 
 -- name: GetTodaysTraining :many
--- Получение всех тренировок на сегодня для пользователя
+-- Получение всех тренировок на сегодня для пользователя.
+-- $2 - сегодняшняя дата в часовом поясе пользователя
 SELECT 
     t.id,
     t.title,
@@ -340,6 +624,11 @@ SELECT
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
     t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
     COALESCE(
         json_agg(
             json_build_object(
@@ -352,15 +641,32 @@ SELECT
                 'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
                 'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
                 'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
-                'notes', te.notes
-            )
+                'notes', te.notes,
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
         ) FILTER (WHERE te.id IS NOT NULL),
         '[]'
     ) as exercises
 FROM training t
 LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
 WHERE t.user_id = $1 
-    AND t.planned_date = CURRENT_DATE
+    AND t.planned_date = $2
 GROUP BY t.id
 ORDER BY t.planned_date DESC;
 
@@ -392,8 +698,17 @@ SELECT
                         WHERE et2.exercise_id = e.id
                     ),
                     '[]'
-                )
-            )
+                ),
+                'slot_id', gte.id,
+                'position', gte.position,
+                'approaches', gte.approaches,
+                'reps', gte.reps,
+                'weight', gte.weight,
+                'duration', EXTRACT(EPOCH FROM gte.duration)::bigint,
+                'rest', EXTRACT(EPOCH FROM gte.rest)::bigint,
+                'group_id', gte.group_id,
+                'group_type', gte.group_type
+            ) ORDER BY gte.position
         ) FILTER (WHERE e.id IS NOT NULL),
         '[]'
     ) as exercises
@@ -436,8 +751,17 @@ SELECT
                         WHERE et2.exercise_id = e.id
                     ),
                     '[]'
-                )
-            )
+                ),
+                'slot_id', gte.id,
+                'position', gte.position,
+                'approaches', gte.approaches,
+                'reps', gte.reps,
+                'weight', gte.weight,
+                'duration', EXTRACT(EPOCH FROM gte.duration)::bigint,
+                'rest', EXTRACT(EPOCH FROM gte.rest)::bigint,
+                'group_id', gte.group_id,
+                'group_type', gte.group_type
+            ) ORDER BY gte.position
         ) FILTER (WHERE e.id IS NOT NULL),
         '[]'
     ) as exercises
@@ -475,8 +799,17 @@ SELECT
                         WHERE et2.exercise_id = e.id
                     ),
                     '[]'
-                )
-            )
+                ),
+                'slot_id', gte.id,
+                'position', gte.position,
+                'approaches', gte.approaches,
+                'reps', gte.reps,
+                'weight', gte.weight,
+                'duration', EXTRACT(EPOCH FROM gte.duration)::bigint,
+                'rest', EXTRACT(EPOCH FROM gte.rest)::bigint,
+                'group_id', gte.group_id,
+                'group_type', gte.group_type
+            ) ORDER BY gte.position
         ) FILTER (WHERE e.id IS NOT NULL),
         '[]'
     ) as exercises
@@ -488,13 +821,41 @@ GROUP BY gt.id, gt.level
 ORDER BY gt.id;
 
 -- name: MarkTrainingAsDone :one
--- Отметить тренировку как выполненную
+-- Отметить тренировку как выполненную. actual_date - дата в часовом поясе пользователя
 UPDATE training
 SET 
     is_done = true,
-    actual_date = CURRENT_DATE,
-    finished_at = COALESCE($1, CURRENT_TIMESTAMP)
-WHERE id = $2 AND user_id = $3
+    status = 'completed',
+    actual_date = $1,
+    finished_at = COALESCE($2, CURRENT_TIMESTAMP)
+WHERE id = $3 AND user_id = $4 AND status IN ('planned', 'in_progress')
+RETURNING 
+    id,
+    title,
+    user_id,
+    is_done,
+    planned_date,
+    actual_date,
+    started_at,
+    finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    rating,
+    status;
+
+-- name: CompleteTraining :one
+-- Завершение тренировки с оценкой и итоговой продолжительностью. Условие на статус защищает
+-- от параллельного пропуска, отмены или повторного завершения
+UPDATE training
+SET
+    is_done = true,
+    status = 'completed',
+    actual_date = $1,
+    finished_at = $2,
+    rating = COALESCE($3, rating),
+    total_duration = COALESCE($4, total_duration)
+WHERE id = $5 AND user_id = $6 AND status IN ('planned', 'in_progress', 'paused')
 RETURNING 
     id,
     title,
@@ -507,21 +868,38 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating;
+    rating,
+    status;
 
 -- name: GetTrainingStats :one
--- Получение статистики по тренировке (общее время выполнения и отдыха)
+-- Получение статистики по тренировке (общее время выполнения и отдыха).
+-- Для упражнений с подходами считаются только рабочие подходы, для остальных — агрегаты упражнения
 SELECT 
     t.id,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
     COUNT(te.id) as exercise_count,
-    COALESCE(SUM(te.approaches), 0) as total_approaches,
-    COALESCE(SUM(te.reps), 0) as total_reps
+    CAST(COALESCE(SUM(CASE WHEN s.set_count > 0 THEN s.approaches ELSE te.approaches END), 0) as bigint) as total_approaches,
+    CAST(COALESCE(SUM(CASE WHEN s.set_count > 0 THEN s.reps ELSE te.reps END), 0) as bigint) as total_reps,
+    CAST(COALESCE(SUM(CASE WHEN s.set_count > 0 THEN s.volume ELSE te.weight * te.reps * te.approaches END), 0) as text) as total_volume,
+    CAST(COALESCE(SUM(tc.distance), 0) as text) as total_distance,
+    CAST(COALESCE(SUM(tc.distance) FILTER (WHERE tc.moving_time IS NOT NULL), 0) as text) as paced_distance,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(tc.moving_time) FILTER (WHERE tc.distance > 0))::bigint, 0) as bigint) as paced_time,
+    CAST(COALESCE(SUM(tc.calories), 0) as bigint) as total_calories
 FROM training t
 LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.id = $1
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) as set_count,
+        COUNT(*) FILTER (WHERE NOT ts.is_warmup) as approaches,
+        SUM(ts.reps) FILTER (WHERE NOT ts.is_warmup) as reps,
+        SUM(ts.weight * ts.reps) FILTER (WHERE NOT ts.is_warmup) as volume
+    FROM trained_set ts
+    WHERE ts.trained_exercise_id = te.id
+) s ON TRUE
+WHERE t.id = $1 AND t.user_id = $2
 GROUP BY t.id;
 
 -- name: StartTraining :one
@@ -529,8 +907,9 @@ GROUP BY t.id;
 UPDATE training
 SET 
     started_at = COALESCE($1, CURRENT_TIMESTAMP),
-    is_done = false
-WHERE id = $2 AND user_id = $3
+    is_done = false,
+    status = 'in_progress'
+WHERE id = $2 AND user_id = $3 AND status = 'planned'
 RETURNING 
     id,
     title,
@@ -543,7 +922,8 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating;
+    rating,
+    status;
 
 -- name: GetGlobalTrainingById :one
 SELECT id, level, title
@@ -551,6 +931,943 @@ FROM global_training
 WHERE id = $1;
 
 -- name: GetGlobalTrainingExercises :many
-SELECT gte.id, gte.global_training_id, gte.exercise_id
+-- Слоты глобальной тренировки с предписанной нагрузкой в порядке выполнения
+SELECT
+    gte.id,
+    gte.global_training_id,
+    gte.exercise_id,
+    gte.position,
+    gte.approaches,
+    gte.reps,
+    gte.weight,
+    EXTRACT(EPOCH FROM gte.duration)::bigint as duration,
+    EXTRACT(EPOCH FROM gte.rest)::bigint as rest,
+    gte.group_id,
+    gte.group_type
 FROM global_training_exercise gte
-WHERE gte.global_training_id = $1;
\ No newline at end of file
+WHERE gte.global_training_id = $1
+ORDER BY gte.position;
+
+-- name: CreateGlobalTraining :one
+-- Администрирование глобальных тренировок
+INSERT INTO global_training (title, description, level)
+VALUES ($1, $2, $3)
+RETURNING id, title, description, level;
+
+-- name: UpdateGlobalTraining :execrows
+UPDATE global_training
+SET
+    title = $1,
+    description = $2,
+    level = $3
+WHERE id = $4;
+
+-- name: DeleteGlobalTraining :execrows
+DELETE FROM global_training WHERE id = $1;
+
+-- name: IsGlobalTrainingUsedByProgram :one
+-- Входит ли глобальная тренировка в сессии программ
+SELECT EXISTS (
+    SELECT 1 FROM program_session WHERE global_training_id = $1
+) as used;
+
+-- name: DeleteGlobalTrainingExercises :exec
+DELETE FROM global_training_exercise WHERE global_training_id = $1;
+
+-- name: AddGlobalTrainingExercise :exec
+INSERT INTO global_training_exercise (
+    global_training_id,
+    exercise_id,
+    position,
+    approaches,
+    reps,
+    weight,
+    duration,
+    rest,
+    group_id,
+    group_type
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+);
+
+-- name: CountExercisesByIDs :one
+SELECT COUNT(*) FROM exercise WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: ListTrainedSets :many
+-- Подходы выполненного упражнения в порядке выполнения
+SELECT
+    ts.id,
+    ts.trained_exercise_id,
+    ts.ordinal,
+    ts.weight,
+    ts.reps,
+    EXTRACT(EPOCH FROM ts.duration)::bigint as duration,
+    ts.rpe,
+    ts.is_warmup
+FROM trained_set ts
+JOIN trained_exercise te ON te.id = ts.trained_exercise_id
+JOIN training t ON t.id = te.training_id
+WHERE ts.trained_exercise_id = $1 AND t.user_id = $2
+ORDER BY ts.ordinal;
+
+-- name: AddTrainedSet :one
+-- Новый подход добавляется в конец списка
+INSERT INTO trained_set (
+    trained_exercise_id,
+    ordinal,
+    weight,
+    reps,
+    duration,
+    rpe,
+    is_warmup
+)
+SELECT
+    te.id,
+    COALESCE((SELECT MAX(ordinal) FROM trained_set WHERE trained_exercise_id = te.id), 0) + 1,
+    $2, $3, $4, $5, $6
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE te.id = $1 AND t.user_id = $7
+RETURNING
+    id,
+    trained_exercise_id,
+    ordinal,
+    weight,
+    reps,
+    EXTRACT(EPOCH FROM duration)::bigint as duration,
+    rpe,
+    is_warmup;
+
+-- name: UpdateTrainedSet :one
+UPDATE trained_set ts
+SET
+    weight = COALESCE($1, ts.weight),
+    reps = COALESCE($2, ts.reps),
+    duration = COALESCE($3, ts.duration),
+    rpe = COALESCE($4, ts.rpe),
+    is_warmup = COALESCE($5, ts.is_warmup)
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE ts.id = $6 AND ts.trained_exercise_id = $7
+    AND te.id = ts.trained_exercise_id AND t.user_id = $8
+RETURNING
+    ts.id,
+    ts.trained_exercise_id,
+    ts.ordinal,
+    ts.weight,
+    ts.reps,
+    EXTRACT(EPOCH FROM ts.duration)::bigint as duration,
+    ts.rpe,
+    ts.is_warmup;
+
+-- name: DeleteTrainedSet :execrows
+DELETE FROM trained_set ts
+USING trained_exercise te, training t
+WHERE ts.id = $1 AND ts.trained_exercise_id = $2
+    AND te.id = ts.trained_exercise_id AND t.id = te.training_id AND t.user_id = $3;
+
+-- name: RenumberTrainedSets :exec
+-- Сплошная нумерация подходов после удаления
+UPDATE trained_set ts
+SET ordinal = n.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY ordinal) as rn
+    FROM trained_set
+    WHERE trained_exercise_id = $1
+) n
+WHERE ts.id = n.id AND ts.ordinal <> n.rn;
+
+-- name: SetTrainedSetOrdinal :execrows
+UPDATE trained_set
+SET ordinal = $1
+WHERE id = $2 AND trained_exercise_id = $3;
+
+-- name: StartTrainingPause :execrows
+-- Перевести идущую тренировку в статус paused и открыть паузу
+WITH paused AS (
+    UPDATE training
+    SET status = 'paused'
+    WHERE id = $1 AND user_id = $2 AND status = 'in_progress'
+    RETURNING id
+)
+INSERT INTO training_pause (training_id, started_at)
+SELECT id, CURRENT_TIMESTAMP
+FROM paused;
+
+-- name: EndTrainingPause :execrows
+-- Вернуть тренировку в статус in_progress и закрыть открытую паузу.
+-- Число строк - по обновлению тренировки, то есть по фактической смене статуса
+WITH closed AS (
+    UPDATE training_pause tp
+    SET ended_at = CURRENT_TIMESTAMP
+    FROM training t
+    WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+        AND t.id = $1 AND t.user_id = $2 AND t.status = 'paused'
+    RETURNING tp.id
+)
+UPDATE training
+SET status = 'in_progress'
+WHERE id = $1 AND user_id = $2 AND status = 'paused';
+
+-- name: GetTrainingPausedTime :one
+-- Суммарное время пауз в микросекундах; открытая пауза считается до текущего момента
+SELECT CAST(COALESCE(
+    EXTRACT(EPOCH FROM SUM(COALESCE(tp.ended_at, CURRENT_TIMESTAMP) - tp.started_at)) * 1000000,
+    0
+) as bigint) as paused_microseconds
+FROM training_pause tp
+JOIN training t ON t.id = tp.training_id
+WHERE tp.training_id = $1 AND t.user_id = $2;
+
+-- name: DeletePersonalRecordsByTrainedExercise :exec
+-- Удалить рекорды, установленные выполненным упражнением, перед их пересчетом
+DELETE FROM personal_record
+WHERE trained_exercise_id = $1 AND user_id = $2;
+
+-- name: CreatePersonalRecord :one
+-- Сохранить результат завершенной тренировки, если он лучше текущего рекорда того же типа
+-- (для max_reps - при том же весе). Если рекорд не побит, строка не вставляется
+INSERT INTO personal_record (user_id, exercise_id, trained_exercise_id, type, value, weight, achieved_at)
+SELECT
+    t.user_id,
+    te.exercise_id,
+    te.id,
+    CAST($3 AS VARCHAR(20)),
+    CAST($4 AS DECIMAL(12,2)),
+    CAST($5 AS DECIMAL(5,2)),
+    COALESCE(t.finished_at, t.started_at, t.planned_date::timestamp)
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE te.id = $1 AND t.user_id = $2 AND t.status = 'completed'
+    AND NOT EXISTS (
+        SELECT 1 FROM personal_record pr
+        WHERE pr.user_id = t.user_id
+            AND pr.exercise_id = te.exercise_id
+            AND pr.type = $3
+            AND pr.weight IS NOT DISTINCT FROM CAST($5 AS DECIMAL(5,2))
+            AND pr.value >= $4
+    )
+RETURNING id, user_id, exercise_id, trained_exercise_id, type, value, weight, achieved_at;
+
+-- name: GetPersonalRecords :many
+-- Текущие рекорды пользователя: лучший результат по упражнению, типу и весу
+SELECT DISTINCT ON (pr.exercise_id, pr.type, pr.weight)
+    pr.id,
+    pr.user_id,
+    pr.exercise_id,
+    e.title as exercise_title,
+    pr.trained_exercise_id,
+    te.training_id,
+    pr.type,
+    pr.value,
+    pr.weight,
+    pr.achieved_at
+FROM personal_record pr
+JOIN exercise e ON e.id = pr.exercise_id
+JOIN trained_exercise te ON te.id = pr.trained_exercise_id
+WHERE pr.user_id = $1
+ORDER BY pr.exercise_id, pr.type, pr.weight, pr.value DESC, pr.achieved_at;
+
+-- name: GetExercisePersonalRecords :many
+-- Текущие рекорды пользователя по одному упражнению
+SELECT DISTINCT ON (pr.type, pr.weight)
+    pr.id,
+    pr.user_id,
+    pr.exercise_id,
+    e.title as exercise_title,
+    pr.trained_exercise_id,
+    te.training_id,
+    pr.type,
+    pr.value,
+    pr.weight,
+    pr.achieved_at
+FROM personal_record pr
+JOIN exercise e ON e.id = pr.exercise_id
+JOIN trained_exercise te ON te.id = pr.trained_exercise_id
+WHERE pr.user_id = $1 AND pr.exercise_id = $2
+ORDER BY pr.type, pr.weight, pr.value DESC, pr.achieved_at;
+
+-- name: GetExerciseStrengthEfforts :many
+-- Рабочие подходы упражнения в завершенных тренировках пользователя за период.
+-- Для упражнений без подходов берутся вес и повторения самого упражнения на все его подходы.
+-- Упражнения, в которых записаны только разминочные подходы, не учитываются
+SELECT
+    CAST(COALESCE(t.actual_date, t.planned_date) AS DATE) as training_date,
+    CAST(COALESCE(CASE WHEN ts.id IS NULL THEN te.weight ELSE ts.weight END, 0) AS TEXT) as weight,
+    CAST(COALESCE(CASE WHEN ts.id IS NULL THEN te.reps ELSE ts.reps END, 0) AS INTEGER) as reps,
+    CAST(CASE WHEN ts.id IS NULL THEN COALESCE(te.approaches, 1) ELSE 1 END AS INTEGER) as sets
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+LEFT JOIN trained_set ts ON ts.trained_exercise_id = te.id AND NOT ts.is_warmup
+WHERE t.user_id = $1 AND te.exercise_id = $2 AND t.status = 'completed'
+    AND (ts.id IS NOT NULL OR NOT EXISTS (
+        SELECT 1 FROM trained_set ws WHERE ws.trained_exercise_id = te.id
+    ))
+    AND COALESCE(t.actual_date, t.planned_date) BETWEEN $3 AND $4
+ORDER BY training_date, te.id, ts.ordinal;
+
+-- name: GetUserTrainingStatsTotals :one
+-- Статистика тренировок пользователя за период.
+-- Объем считается так же, как в GetTrainingStats: по рабочим подходам, а без подходов - по агрегатам упражнения.
+-- Объем и кардио-показатели считаются по завершенным тренировкам; темп - по упражнениям, у которых есть и дистанция, и время
+SELECT
+    COUNT(t.id) as total_trainings,
+    COUNT(t.id) FILTER (WHERE t.is_done) as completed_trainings,
+    COUNT(t.id) FILTER (WHERE t.status = 'skipped') as skipped_trainings,
+    CAST(COALESCE(AVG(t.rating) FILTER (WHERE t.is_done), 0) AS FLOAT8) as average_rating,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_duration))::bigint, 0) AS BIGINT) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_rest_time))::bigint, 0) AS BIGINT) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_exercise_time))::bigint, 0) AS BIGINT) as total_exercise_time,
+    CAST(COALESCE(SUM(v.volume), 0) AS TEXT) as total_volume,
+    CAST(COALESCE(SUM(c.distance), 0) AS TEXT) as total_distance,
+    CAST(COALESCE(SUM(c.paced_distance), 0) AS TEXT) as paced_distance,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(c.paced_time))::bigint, 0) AS BIGINT) as paced_time,
+    CAST(COALESCE(SUM(c.calories), 0) AS BIGINT) as total_calories
+FROM training t
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(CASE WHEN s.set_count > 0 THEN s.volume ELSE te.weight * te.reps * te.approaches END) as volume
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    LEFT JOIN LATERAL (
+        SELECT
+            COUNT(*) as set_count,
+            SUM(ts.weight * ts.reps) FILTER (WHERE NOT ts.is_warmup) as volume
+        FROM trained_set ts
+        WHERE ts.trained_exercise_id = te.id
+    ) s ON TRUE
+    WHERE t.user_id = sqlc.arg(user_id) AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+    GROUP BY te.training_id
+) v ON v.training_id = t.id
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(tc.distance) as distance,
+        SUM(tc.distance) FILTER (WHERE tc.moving_time IS NOT NULL) as paced_distance,
+        SUM(tc.moving_time) FILTER (WHERE tc.distance > 0) as paced_time,
+        SUM(tc.calories) as calories
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+    WHERE t.user_id = sqlc.arg(user_id) AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+    GROUP BY te.training_id
+) c ON c.training_id = t.id
+WHERE t.user_id = sqlc.arg(user_id)
+    AND COALESCE(t.actual_date, t.planned_date) BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date;
+
+-- name: GetUserTrainingStatsByPeriod :many
+-- Статистика тренировок пользователя за период с разбивкой по дням, неделям или месяцам (group_by - 'day', 'week' или 'month').
+-- Объем и кардио-показатели, как и в GetUserTrainingStatsTotals, считаются по завершенным тренировкам.
+-- Периоды без тренировок не возвращаются
+SELECT
+    CAST(date_trunc(sqlc.arg(group_by)::text, CAST(COALESCE(t.actual_date, t.planned_date) AS TIMESTAMP)) AS DATE) as period_start,
+    COUNT(t.id) as total_trainings,
+    COUNT(t.id) FILTER (WHERE t.is_done) as completed_trainings,
+    COUNT(t.id) FILTER (WHERE t.status = 'skipped') as skipped_trainings,
+    CAST(COALESCE(AVG(t.rating) FILTER (WHERE t.is_done), 0) AS FLOAT8) as average_rating,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_duration))::bigint, 0) AS BIGINT) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_rest_time))::bigint, 0) AS BIGINT) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_exercise_time))::bigint, 0) AS BIGINT) as total_exercise_time,
+    CAST(COALESCE(SUM(v.volume), 0) AS TEXT) as total_volume,
+    CAST(COALESCE(SUM(c.distance), 0) AS TEXT) as total_distance,
+    CAST(COALESCE(SUM(c.paced_distance), 0) AS TEXT) as paced_distance,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(c.paced_time))::bigint, 0) AS BIGINT) as paced_time,
+    CAST(COALESCE(SUM(c.calories), 0) AS BIGINT) as total_calories
+FROM training t
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(CASE WHEN s.set_count > 0 THEN s.volume ELSE te.weight * te.reps * te.approaches END) as volume
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    LEFT JOIN LATERAL (
+        SELECT
+            COUNT(*) as set_count,
+            SUM(ts.weight * ts.reps) FILTER (WHERE NOT ts.is_warmup) as volume
+        FROM trained_set ts
+        WHERE ts.trained_exercise_id = te.id
+    ) s ON TRUE
+    WHERE t.user_id = sqlc.arg(user_id) AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+    GROUP BY te.training_id
+) v ON v.training_id = t.id
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(tc.distance) as distance,
+        SUM(tc.distance) FILTER (WHERE tc.moving_time IS NOT NULL) as paced_distance,
+        SUM(tc.moving_time) FILTER (WHERE tc.distance > 0) as paced_time,
+        SUM(tc.calories) as calories
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+    WHERE t.user_id = sqlc.arg(user_id) AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+    GROUP BY te.training_id
+) c ON c.training_id = t.id
+WHERE t.user_id = sqlc.arg(user_id)
+    AND COALESCE(t.actual_date, t.planned_date) BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+GROUP BY period_start
+ORDER BY period_start;
+
+-- name: GetUserTrainingDates :many
+-- Даты и статусы всех тренировок пользователя для расчета серий и регулярности
+SELECT planned_date, actual_date, is_done, status
+FROM training
+WHERE user_id = $1
+ORDER BY COALESCE(actual_date, planned_date), id;
+
+-- name: GetUserSettings :one
+SELECT user_id, week_start, rest_days, timezone, updated_at
+FROM user_settings
+WHERE user_id = $1;
+
+-- name: UpsertUserSettings :one
+INSERT INTO user_settings (user_id, week_start, rest_days, timezone, updated_at)
+VALUES ($1, $2, $3, $4, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+    week_start = EXCLUDED.week_start,
+    rest_days = EXCLUDED.rest_days,
+    timezone = EXCLUDED.timezone,
+    updated_at = NOW()
+RETURNING user_id, week_start, rest_days, timezone, updated_at;
+
+-- name: GetTrainingCalendar :many
+-- Календарь тренировок пользователя по запланированным датам с from_date по to_date: строка на каждую тренировку
+-- и по одной строке с пустыми полями тренировки на дни без тренировок
+SELECT
+    CAST(d.day AS DATE) as day,
+    t.id as training_id,
+    t.title,
+    t.status,
+    t.rating,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) AS BIGINT) as total_duration,
+    COUNT(te.id) as exercise_count
+FROM generate_series(sqlc.arg(from_date)::date, sqlc.arg(to_date)::date, INTERVAL '1 day') d(day)
+LEFT JOIN training t ON t.user_id = sqlc.arg(user_id) AND t.planned_date = CAST(d.day AS DATE)
+LEFT JOIN trained_exercise te ON te.training_id = t.id
+GROUP BY d.day, t.id
+ORDER BY d.day, t.id;
+
+-- name: CreateTrainingSchedule :one
+INSERT INTO training_schedule (user_id, title, rrule, start_date, global_training_id, template_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at;
+
+-- name: GetTrainingSchedule :one
+SELECT id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+FROM training_schedule
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetTrainingSchedules :many
+SELECT id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+FROM training_schedule
+WHERE user_id = $1
+ORDER BY created_at, id;
+
+-- name: GetSchedulesToMaterialize :many
+-- Расписания, тренировки по которым созданы не до конца горизонта $1
+SELECT id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+FROM training_schedule
+WHERE materialized_until IS NULL OR materialized_until < $1
+ORDER BY id;
+
+-- name: UpdateTrainingSchedule :one
+UPDATE training_schedule
+SET
+    title = $1,
+    rrule = $2,
+    global_training_id = $3,
+    template_id = $4,
+    materialized_until = $5
+WHERE id = $6 AND user_id = $7
+RETURNING id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at;
+
+-- name: SetScheduleMaterializedUntil :exec
+UPDATE training_schedule SET materialized_until = $2 WHERE id = $1;
+
+-- name: DeleteTrainingSchedule :execrows
+DELETE FROM training_schedule WHERE id = $1 AND user_id = $2;
+
+-- name: CreateScheduledTraining :one
+-- Тренировка повторения расписания; если тренировка на этот день повторения уже есть, строка не возвращается
+INSERT INTO training (title, user_id, planned_date, status, schedule_id, occurrence_date)
+VALUES ($1, $2, $3, 'planned', $4, $3)
+ON CONFLICT (schedule_id, occurrence_date) DO NOTHING
+RETURNING id;
+
+-- name: GetScheduledTrainings :many
+-- Тренировки, созданные по расписанию для повторений с from_date по to_date
+SELECT id, planned_date, occurrence_date, status
+FROM training
+WHERE schedule_id = sqlc.arg(schedule_id)::bigint
+    AND occurrence_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+ORDER BY occurrence_date;
+
+-- name: DeletePlannedScheduledTrainings :execrows
+-- Удаление еще не начатых тренировок расписания для повторений начиная с from_date (и до to_date, если задано)
+DELETE FROM training
+WHERE schedule_id = sqlc.arg(schedule_id)::bigint
+    AND occurrence_date >= sqlc.arg(from_date)::date
+    AND (sqlc.narg(to_date)::date IS NULL OR occurrence_date <= sqlc.narg(to_date)::date)
+    AND status = 'planned'
+    AND started_at IS NULL;
+
+-- name: GetTrainingScheduleSkips :many
+SELECT occurrence_date
+FROM training_schedule_skip
+WHERE schedule_id = $1
+ORDER BY occurrence_date;
+
+-- name: CreateTrainingScheduleSkip :exec
+INSERT INTO training_schedule_skip (schedule_id, occurrence_date)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetPrograms :many
+SELECT id, title, description, level, weeks
+FROM program
+ORDER BY
+    CASE level
+        WHEN 'beginner' THEN 1
+        WHEN 'intermediate' THEN 2
+        WHEN 'advanced' THEN 3
+    END,
+    id;
+
+-- name: GetProgramByID :one
+SELECT id, title, description, level, weeks
+FROM program
+WHERE id = $1;
+
+-- name: GetProgramSessions :many
+-- Тренировочные дни программы по порядку недель и дней
+SELECT
+    ps.id,
+    ps.week,
+    ps.day,
+    ps.global_training_id,
+    gt.title as global_training_title,
+    ps.weight_increment,
+    ps.reps_increment
+FROM program_session ps
+JOIN global_training gt ON gt.id = ps.global_training_id
+WHERE ps.program_id = $1
+ORDER BY ps.week, ps.day;
+
+-- name: CreateProgram :one
+-- Администрирование программ
+INSERT INTO program (title, description, level, weeks)
+VALUES ($1, $2, $3, $4)
+RETURNING id, title, description, level, weeks;
+
+-- name: UpdateProgram :execrows
+UPDATE program
+SET
+    title = $1,
+    description = $2,
+    level = $3,
+    weeks = $4
+WHERE id = $5;
+
+-- name: DeleteProgram :execrows
+DELETE FROM program WHERE id = $1;
+
+-- name: DeleteRemovedProgramSessions :exec
+-- Удаление тренировочных дней, которых нет в новом списке (пары week[i], day[i])
+DELETE FROM program_session ps
+WHERE ps.program_id = $1
+    AND NOT EXISTS (
+        SELECT 1
+        FROM unnest($2::int[], $3::int[]) AS k(week, day)
+        WHERE k.week = ps.week AND k.day = ps.day
+    );
+
+-- name: UpsertProgramSession :exec
+-- Тренировочный день программы. Существующий день с той же неделей и номером обновляется,
+-- сохраняя id, на который ссылаются тренировки записанных пользователей
+INSERT INTO program_session (
+    program_id,
+    week,
+    day,
+    global_training_id,
+    weight_increment,
+    reps_increment
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (program_id, week, day) DO UPDATE SET
+    global_training_id = EXCLUDED.global_training_id,
+    weight_increment = EXCLUDED.weight_increment,
+    reps_increment = EXCLUDED.reps_increment;
+
+-- name: CountGlobalTrainingsByIDs :one
+SELECT COUNT(*) FROM global_training WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: CreateProgramEnrollment :one
+INSERT INTO program_enrollment (program_id, user_id, start_date, weekdays)
+VALUES ($1, $2, $3, $4)
+RETURNING id;
+
+-- name: GetProgramEnrollment :one
+SELECT e.id, e.program_id, p.title as program_title, p.weeks, e.user_id, e.start_date, e.weekdays, e.created_at
+FROM program_enrollment e
+JOIN program p ON p.id = e.program_id
+WHERE e.id = $1 AND e.user_id = $2;
+
+-- name: GetProgramEnrollments :many
+SELECT e.id, e.program_id, p.title as program_title, p.weeks, e.user_id, e.start_date, e.weekdays, e.created_at
+FROM program_enrollment e
+JOIN program p ON p.id = e.program_id
+WHERE e.user_id = $1
+ORDER BY e.created_at DESC, e.id DESC;
+
+-- name: DeleteProgramEnrollment :execrows
+DELETE FROM program_enrollment WHERE id = $1 AND user_id = $2;
+
+-- name: CreateProgramTraining :one
+-- Тренировка тренировочного дня программы
+INSERT INTO training (title, user_id, planned_date, status, enrollment_id, program_session_id)
+VALUES ($1, $2, $3, 'planned', $4, $5)
+RETURNING id;
+
+-- name: GetEnrollmentProgress :one
+-- Прохождение программы: число тренировок по статусам и ближайшая невыполненная тренировка
+SELECT
+    COUNT(t.id) as total_sessions,
+    COUNT(t.id) FILTER (WHERE t.is_done) as completed_sessions,
+    COUNT(t.id) FILTER (WHERE t.status IN ('skipped', 'cancelled')) as skipped_sessions,
+    n.id as next_training_id,
+    n.title as next_title,
+    n.planned_date as next_planned_date,
+    n.week as next_week,
+    n.day as next_day
+FROM program_enrollment e
+LEFT JOIN training t ON t.enrollment_id = e.id
+LEFT JOIN LATERAL (
+    SELECT nt.id, nt.title, nt.planned_date, ps.week, ps.day
+    FROM training nt
+    JOIN program_session ps ON ps.id = nt.program_session_id
+    WHERE nt.enrollment_id = e.id
+        AND NOT nt.is_done
+        AND nt.status NOT IN ('skipped', 'cancelled')
+    ORDER BY nt.planned_date, ps.week, ps.day
+    LIMIT 1
+) n ON TRUE
+WHERE e.id = $1
+GROUP BY n.id, n.title, n.planned_date, n.week, n.day;
+
+-- name: DeletePlannedEnrollmentTrainings :execrows
+-- Удаление еще не начатых тренировок программы начиная с $2
+DELETE FROM training
+WHERE enrollment_id = $1
+    AND planned_date >= $2
+    AND status = 'planned'
+    AND started_at IS NULL;
+
+-- name: GetRecentExerciseSessions :many
+-- Последние выполнения упражнения в завершенных тренировках пользователя, начиная с последнего
+SELECT
+    te.id,
+    te.training_id,
+    CAST(COALESCE(t.actual_date, t.planned_date) AS DATE) as training_date,
+    te.weight,
+    te.approaches,
+    te.reps
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE t.user_id = $1 AND te.exercise_id = $2 AND t.status = 'completed'
+ORDER BY training_date DESC, te.id DESC
+LIMIT $3;
+
+-- name: GetWorkingSetsByTrainedExercises :many
+-- Рабочие подходы нескольких выполненных упражнений в порядке выполнения
+SELECT trained_exercise_id, weight, reps
+FROM trained_set
+WHERE trained_exercise_id = ANY(sqlc.arg(ids)::bigint[]) AND NOT is_warmup
+ORDER BY trained_exercise_id, ordinal;
+
+-- name: GetTrainingTemplates :many
+-- Шаблоны пользователя, начиная с последнего созданного
+SELECT id, user_id, title, description, created_at
+FROM training_template
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: GetTrainingTemplate :one
+SELECT id, user_id, title, description, created_at
+FROM training_template
+WHERE id = $1 AND user_id = $2;
+
+-- name: GetTrainingTemplateExercises :many
+-- Упражнения шаблона с целевой нагрузкой в порядке выполнения
+SELECT
+    tte.id,
+    tte.exercise_id,
+    e.title as exercise_title,
+    tte.position,
+    tte.approaches,
+    tte.reps,
+    tte.weight,
+    EXTRACT(EPOCH FROM tte.duration)::bigint as duration,
+    EXTRACT(EPOCH FROM tte.rest)::bigint as rest,
+    tte.group_id,
+    tte.group_type
+FROM training_template_exercise tte
+JOIN exercise e ON e.id = tte.exercise_id
+WHERE tte.template_id = $1
+ORDER BY tte.position;
+
+-- name: CreateTrainingTemplate :one
+INSERT INTO training_template (user_id, title, description)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: UpdateTrainingTemplate :execrows
+UPDATE training_template
+SET title = $1, description = $2
+WHERE id = $3 AND user_id = $4;
+
+-- name: DeleteTrainingTemplate :execrows
+DELETE FROM training_template
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteTrainingTemplateExercises :exec
+DELETE FROM training_template_exercise
+WHERE template_id = $1;
+
+-- name: AddTrainingTemplateExercise :exec
+INSERT INTO training_template_exercise (
+    template_id,
+    exercise_id,
+    position,
+    approaches,
+    reps,
+    weight,
+    duration,
+    rest,
+    group_id,
+    group_type
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+);
+
+-- name: CopyTrainedExercisesToTemplate :exec
+-- Упражнения тренировки становятся упражнениями шаблона в том же порядке и с теми же группами,
+-- время выполнения упражнения - целевой длительностью
+INSERT INTO training_template_exercise (template_id, exercise_id, position, approaches, reps, weight, duration, rest, group_id, group_type)
+SELECT
+    sqlc.arg(template_id)::bigint,
+    te.exercise_id,
+    ROW_NUMBER() OVER (ORDER BY te.position, te.id),
+    NULLIF(te.approaches, 0),
+    NULLIF(te.reps, 0),
+    te.weight,
+    te.doing,
+    te.rest,
+    te.group_id,
+    te.group_type
+FROM trained_exercise te
+WHERE te.training_id = sqlc.arg(training_id);
+
+-- name: CreatePlannedTraining :one
+-- Запланированная тренировка без упражнений: копия тренировки или тренировка по шаблону
+INSERT INTO training (title, user_id, planned_date, status)
+VALUES ($1, $2, $3, 'planned')
+RETURNING id;
+
+-- name: CopyTrainedExercises :exec
+-- Копирование всех упражнений тренировки в другую тренировку в том же порядке
+INSERT INTO trained_exercise (training_id, exercise_id, weight, approaches, reps, time, doing, rest, notes, position, group_id, group_type)
+SELECT sqlc.arg(target_training_id)::bigint, exercise_id, weight, approaches, reps, time, doing, rest, notes, position, group_id, group_type
+FROM trained_exercise
+WHERE training_id = sqlc.arg(source_training_id)
+ORDER BY position, id;
+
+-- name: AddTemplateExercisesToTraining :exec
+-- Упражнения шаблона с целевой нагрузкой добавляются в тренировку в порядке шаблона
+INSERT INTO trained_exercise (training_id, exercise_id, weight, approaches, reps, doing, rest, position, group_id, group_type)
+SELECT sqlc.arg(training_id)::bigint, exercise_id, weight, approaches, reps, duration, rest, position, group_id, group_type
+FROM training_template_exercise
+WHERE template_id = sqlc.arg(template_id)
+ORDER BY position;
+
+-- name: GetExerciseCatalogNames :many
+-- Названия и типы упражнений каталога с другими названиями для сопоставления при импорте истории
+SELECT
+    e.id,
+    e.title,
+    e.type,
+    CAST(COALESCE(array_agg(a.alias ORDER BY a.alias) FILTER (WHERE a.alias IS NOT NULL), '{}') AS TEXT[]) as aliases
+FROM exercise e
+LEFT JOIN exercise_alias a ON a.exercise_id = e.id
+GROUP BY e.id
+ORDER BY e.id;
+
+-- name: GetTrainingTitlesInRange :many
+-- Даты и названия тренировок пользователя с from по to: по ним импорт находит уже загруженные тренировки
+SELECT planned_date, title
+FROM training
+WHERE user_id = sqlc.arg(user_id) AND planned_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+ORDER BY planned_date;
+
+-- name: GetTrainingsForExport :many
+-- Пачка тренировок пользователя с упражнениями и подходами для выгрузки, по planned_date и id.
+-- after_date и after_id - последняя тренировка предыдущей пачки; для первой пачки не задаются
+SELECT 
+    t.id,
+    t.title,
+    t.user_id,
+    t.is_done,
+    t.planned_date,
+    t.actual_date,
+    t.started_at,
+    t.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', te.id,
+                'training_id', te.training_id, 
+                'exercise_id', te.exercise_id,
+                'weight', CASE WHEN s.sets IS NULL THEN te.weight ELSE s.weight END,
+                'approaches', CASE WHEN s.sets IS NULL THEN te.approaches ELSE s.approaches END,
+                'reps', CASE WHEN s.sets IS NULL THEN te.reps ELSE s.reps END,
+                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
+                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
+                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
+                'notes', te.notes,
+                'sets', COALESCE(s.sets, '[]'::json),
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
+        ) FILTER (WHERE te.id IS NOT NULL),
+        '[]'
+    ) as exercises
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) FILTER (WHERE NOT ts.is_warmup) as approaches,
+        SUM(ts.reps) FILTER (WHERE NOT ts.is_warmup) as reps,
+        MAX(ts.weight) FILTER (WHERE NOT ts.is_warmup) as weight,
+        json_agg(
+            json_build_object(
+                'id', ts.id,
+                'trained_exercise_id', ts.trained_exercise_id,
+                'ordinal', ts.ordinal,
+                'weight', ts.weight,
+                'reps', ts.reps,
+                'duration', EXTRACT(EPOCH FROM ts.duration)::bigint,
+                'rpe', ts.rpe,
+                'is_warmup', ts.is_warmup
+            ) ORDER BY ts.ordinal
+        ) as sets
+    FROM trained_set ts
+    WHERE ts.trained_exercise_id = te.id
+) s ON TRUE
+WHERE t.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(from_date)::date IS NULL OR t.planned_date >= sqlc.narg(from_date)::date)
+    AND (sqlc.narg(to_date)::date IS NULL OR t.planned_date <= sqlc.narg(to_date)::date)
+    AND (sqlc.narg(after_id)::bigint IS NULL
+        OR (t.planned_date, t.id) > (sqlc.narg(after_date)::date, sqlc.narg(after_id)::bigint))
+GROUP BY t.id
+ORDER BY t.planned_date, t.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: UpsertCalendarToken :exec
+-- Новый ключ заменяет прежний: старая ссылка на календарь перестает работать
+INSERT INTO calendar_token (user_id, token_hash, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+    token_hash = EXCLUDED.token_hash,
+    created_at = NOW();
+
+-- name: DeleteCalendarToken :execrows
+DELETE FROM calendar_token
+WHERE user_id = $1;
+
+-- name: GetCalendarTokenUser :one
+SELECT user_id
+FROM calendar_token
+WHERE token_hash = $1;
+
+-- name: UpsertTrainedExerciseCardio :exec
+INSERT INTO trained_exercise_cardio (
+    trained_exercise_id,
+    distance,
+    moving_time,
+    elevation_gain,
+    avg_heart_rate,
+    max_heart_rate,
+    avg_pace,
+    distance_unit,
+    avg_speed,
+    calories,
+    incline,
+    resistance_level
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (trained_exercise_id) DO UPDATE SET
+    distance = EXCLUDED.distance,
+    moving_time = EXCLUDED.moving_time,
+    elevation_gain = EXCLUDED.elevation_gain,
+    avg_heart_rate = EXCLUDED.avg_heart_rate,
+    max_heart_rate = EXCLUDED.max_heart_rate,
+    avg_pace = EXCLUDED.avg_pace,
+    distance_unit = EXCLUDED.distance_unit,
+    avg_speed = EXCLUDED.avg_speed,
+    calories = EXCLUDED.calories,
+    incline = EXCLUDED.incline,
+    resistance_level = EXCLUDED.resistance_level;
+
+-- name: GetExerciseType :one
+-- Тип упражнения каталога для проверки кардио-показателей
+SELECT type FROM exercise WHERE id = $1;
+
+-- name: GetTrainedExerciseCardio :one
+-- Тип упражнения каталога и сохраненные кардио-показатели выполненного упражнения
+SELECT
+    e.type,
+    tc.trained_exercise_id IS NOT NULL as has_cardio,
+    tc.distance,
+    CAST(EXTRACT(EPOCH FROM tc.moving_time)::bigint as bigint) as moving_time,
+    tc.elevation_gain,
+    tc.avg_heart_rate,
+    tc.max_heart_rate,
+    CAST(EXTRACT(EPOCH FROM tc.avg_pace)::bigint as bigint) as avg_pace,
+    tc.distance_unit,
+    tc.avg_speed,
+    tc.calories,
+    tc.incline,
+    tc.resistance_level
+FROM trained_exercise te
+JOIN exercise e ON e.id = te.exercise_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+WHERE te.id = $1;
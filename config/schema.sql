@@ -13,7 +13,15 @@ CREATE TABLE "exercise"(
     "title" TEXT NOT NULL,
     "description" TEXT NOT NULL,
     "video_url" TEXT NOT NULL,
-    "image_url" TEXT NOT NULL
+    "image_url" TEXT NOT NULL,
+    -- Тип упражнения определяет, какие кардио-показатели можно сохранять при выполнении
+    "type" VARCHAR(20) NOT NULL DEFAULT 'strength' CHECK(type IN('strength', 'running', 'walking', 'cycling', 'rowing', 'swimming', 'cardio'))
+);
+
+-- Другие названия упражнения (например, из Strong или Hevy) для сопоставления при импорте истории
+CREATE TABLE "exercise_alias"(
+    "exercise_id" BIGINT NOT NULL,
+    "alias" TEXT NOT NULL
 );
 
 -- Связующая таблица упражнений и тегов
@@ -36,7 +44,14 @@ CREATE TABLE "training"(
     "total_duration" INTERVAL NULL,
     "total_rest_time" INTERVAL NULL,
     "total_exercise_time" INTERVAL NULL,
-    "rating" INTEGER CHECK(rating >= 1 AND rating <= 5) NULL
+    "rating" INTEGER CHECK(rating >= 1 AND rating <= 5) NULL,
+    "status" VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK(status IN('planned', 'in_progress', 'paused', 'completed', 'skipped', 'cancelled')),
+    -- Расписание, по которому создана тренировка, и день повторения, которому она соответствует
+    "schedule_id" BIGINT NULL,
+    "occurrence_date" DATE NULL,
+    -- Запись на программу и тренировочный день программы, по которым создана тренировка
+    "enrollment_id" BIGINT NULL,
+    "program_session_id" BIGINT NULL
 );
 
 -- Таблица выполненных упражнений в тренировке
@@ -50,7 +65,102 @@ CREATE TABLE "trained_exercise"(
     "time" INTERVAL NULL,
     "doing" INTERVAL NULL,
     "rest" INTERVAL NULL,
-    "notes" TEXT NULL
+    "notes" TEXT NULL,
+    -- Порядок выполнения внутри тренировки
+    "position" INTEGER NOT NULL DEFAULT 0,
+    -- Группа упражнений, выполняемых подряд (суперсет, круг, гигантский сет):
+    -- упражнения тренировки с одинаковым group_id входят в одну группу
+    "group_id" INTEGER NULL CHECK(group_id > 0),
+    "group_type" VARCHAR(20) NULL CHECK(group_type IN('superset', 'circuit', 'giant_set')),
+    CHECK((group_id IS NULL) = (group_type IS NULL))
+);
+
+-- Показатели кардио-упражнения (пробежки, заезда, заплыва). Дистанция и набор высоты - в метрах,
+-- avg_pace - время на километр, avg_speed - км/ч, incline - наклон дорожки в процентах
+CREATE TABLE "trained_exercise_cardio"(
+    "trained_exercise_id" BIGINT NOT NULL PRIMARY KEY,
+    "distance" DECIMAL(9,1) NULL CHECK(distance >= 0),
+    "moving_time" INTERVAL NULL,
+    "elevation_gain" DECIMAL(7,1) NULL CHECK(elevation_gain >= 0),
+    "avg_heart_rate" INTEGER NULL CHECK(avg_heart_rate > 0),
+    "max_heart_rate" INTEGER NULL CHECK(max_heart_rate > 0),
+    "avg_pace" INTERVAL NULL,
+    -- Единица, в которой пользователь вводит и видит дистанцию; хранится дистанция всегда в метрах
+    "distance_unit" VARCHAR(2) NULL CHECK(distance_unit IN('m', 'km', 'mi')),
+    "avg_speed" DECIMAL(5,1) NULL CHECK(avg_speed > 0),
+    "calories" INTEGER NULL CHECK(calories > 0),
+    "incline" DECIMAL(3,1) NULL,
+    "resistance_level" INTEGER NULL CHECK(resistance_level >= 0)
+);
+
+-- Таблица подходов выполненного упражнения
+CREATE TABLE "trained_set"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "trained_exercise_id" BIGINT NOT NULL,
+    "ordinal" INTEGER NOT NULL CHECK(ordinal > 0),
+    "weight" DECIMAL(5,2) NULL CHECK(weight >= 0),
+    "reps" INTEGER NULL CHECK(reps >= 0),
+    "duration" INTERVAL NULL,
+    "rpe" DECIMAL(3,1) NULL CHECK(rpe >= 1 AND rpe <= 10),
+    "is_warmup" BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Паузы тренировки. Открытая пауза (ended_at IS NULL) может быть только одна
+CREATE TABLE "training_pause"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "training_id" BIGINT NOT NULL,
+    "started_at" TIMESTAMP NOT NULL,
+    "ended_at" TIMESTAMP NULL CHECK(ended_at >= started_at)
+);
+
+-- Личные рекорды. Строка добавляется каждый раз, когда рекорд побит, текущий рекорд -
+-- лучшее значение по (user_id, exercise_id, type, weight). weight задан только для max_reps,
+-- value - килограммы, повторения, секунды (max_duration) или килограммы x повторения (max_volume)
+CREATE TABLE "personal_record"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "user_id" UUID NOT NULL,
+    "exercise_id" BIGINT NOT NULL,
+    "trained_exercise_id" BIGINT NOT NULL,
+    "type" VARCHAR(20) NOT NULL CHECK(type IN('max_weight', 'max_reps', 'estimated_1rm', 'max_duration', 'max_volume')),
+    "value" DECIMAL(12,2) NOT NULL CHECK(value > 0),
+    "weight" DECIMAL(5,2) NULL,
+    "achieved_at" TIMESTAMP NOT NULL
+);
+
+-- Персональные настройки пользователя. Пока строки нет, действуют значения по умолчанию.
+-- week_start - первый день недели (0 - воскресенье, 1 - понедельник),
+-- rest_days - сколько дней отдыха подряд не прерывают серию тренировок,
+-- timezone - часовой пояс IANA (например, Asia/Novosibirsk), NULL - UTC
+CREATE TABLE "user_settings"(
+    "user_id" UUID NOT NULL PRIMARY KEY,
+    "week_start" INTEGER NOT NULL DEFAULT 1 CHECK(week_start >= 0 AND week_start <= 6),
+    "rest_days" INTEGER NOT NULL DEFAULT 1 CHECK(rest_days >= 0 AND rest_days <= 6),
+    "timezone" TEXT NULL,
+    "updated_at" TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Расписания повторяющихся тренировок. rrule - подмножество RRULE из RFC 5545
+-- (FREQ=WEEKLY с INTERVAL, BYDAY и UNTIL или COUNT), отсчитываемое от start_date.
+-- materialized_until - день, до которого тренировки по расписанию уже созданы
+CREATE TABLE "training_schedule"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "user_id" UUID NOT NULL,
+    "title" TEXT NOT NULL,
+    "rrule" TEXT NOT NULL,
+    "start_date" DATE NOT NULL,
+    "global_training_id" BIGINT NULL,
+    "template_id" BIGINT NULL,
+    "materialized_until" DATE NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT NOW(),
+    -- Упражнения повторений берутся из глобальной тренировки или шаблона пользователя, но не из обоих
+    CHECK (global_training_id IS NULL OR template_id IS NULL)
+);
+
+-- Пропущенные повторения расписания: тренировки на эти дни не создаются
+CREATE TABLE "training_schedule_skip"(
+    "schedule_id" BIGINT NOT NULL,
+    "occurrence_date" DATE NOT NULL,
+    PRIMARY KEY ("schedule_id", "occurrence_date")
 );
 
 -- Таблица глобальных тренировок
@@ -65,7 +175,81 @@ CREATE TABLE "global_training"(
 CREATE TABLE "global_training_exercise"(
     "id" BIGSERIAL NOT NULL PRIMARY KEY,
     "global_training_id" BIGINT NOT NULL,
-    "exercise_id" BIGINT NOT NULL
+    "exercise_id" BIGINT NOT NULL,
+    "position" INTEGER NOT NULL DEFAULT 0,
+    "approaches" INTEGER NULL CHECK(approaches > 0),
+    "reps" INTEGER NULL CHECK(reps > 0),
+    "weight" DECIMAL(5,2) NULL CHECK(weight >= 0),
+    "duration" INTERVAL NULL,
+    "rest" INTERVAL NULL,
+    "group_id" INTEGER NULL CHECK(group_id > 0),
+    "group_type" VARCHAR(20) NULL CHECK(group_type IN('superset', 'circuit', 'giant_set')),
+    CHECK((group_id IS NULL) = (group_type IS NULL))
+);
+
+-- Многонедельные программы тренировок
+CREATE TABLE "program"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "title" TEXT NOT NULL,
+    "description" TEXT NOT NULL,
+    "level" VARCHAR(50) NOT NULL CHECK(level IN('beginner', 'intermediate', 'advanced')),
+    "weeks" INTEGER NOT NULL CHECK(weeks > 0)
+);
+
+-- Тренировочные дни программы: day - порядковый номер тренировки внутри недели week.
+-- weight_increment и reps_increment прибавляются к предписанной нагрузке глобальной тренировки
+-- за каждую неделю после первой
+CREATE TABLE "program_session"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "program_id" BIGINT NOT NULL,
+    "week" INTEGER NOT NULL CHECK(week > 0),
+    "day" INTEGER NOT NULL CHECK(day > 0 AND day <= 7),
+    "global_training_id" BIGINT NOT NULL,
+    "weight_increment" DECIMAL(5,2) NULL,
+    "reps_increment" INTEGER NULL
+);
+
+-- Записи пользователей на программы. weekdays - предпочитаемые дни недели (0 - воскресенье)
+CREATE TABLE "program_enrollment"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "program_id" BIGINT NOT NULL,
+    "user_id" UUID NOT NULL,
+    "start_date" DATE NOT NULL,
+    "weekdays" INTEGER[] NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Пользовательские шаблоны тренировок
+CREATE TABLE "training_template"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "user_id" UUID NOT NULL,
+    "title" TEXT NOT NULL,
+    "description" TEXT NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Упражнения шаблона с целевой нагрузкой в порядке выполнения
+CREATE TABLE "training_template_exercise"(
+    "id" BIGSERIAL NOT NULL PRIMARY KEY,
+    "template_id" BIGINT NOT NULL,
+    "exercise_id" BIGINT NOT NULL,
+    "position" INTEGER NOT NULL,
+    "approaches" INTEGER NULL CHECK(approaches > 0),
+    "reps" INTEGER NULL CHECK(reps > 0),
+    "weight" DECIMAL(5,2) NULL CHECK(weight >= 0),
+    "duration" INTERVAL NULL,
+    "rest" INTERVAL NULL,
+    "group_id" INTEGER NULL CHECK(group_id > 0),
+    "group_type" VARCHAR(20) NULL CHECK(group_type IN('superset', 'circuit', 'giant_set')),
+    CHECK((group_id IS NULL) = (group_type IS NULL))
+);
+
+-- Ключи подписки на календарь тренировок: у пользователя не больше одного ключа.
+-- Хранится только SHA-256 ключа, сам ключ показывается пользователю один раз
+CREATE TABLE "calendar_token"(
+    "user_id" UUID NOT NULL PRIMARY KEY,
+    "token_hash" TEXT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT NOW()
 );
 
 -- Индексы для производительности
@@ -74,17 +258,57 @@ CREATE INDEX idx_training_planned_date ON training(planned_date);
 CREATE INDEX idx_training_is_done ON training(is_done);
 CREATE INDEX idx_trained_exercise_training_id ON trained_exercise(training_id);
 CREATE INDEX idx_trained_exercise_exercise_id ON trained_exercise(exercise_id);
+CREATE INDEX idx_trained_set_trained_exercise_id ON trained_set(trained_exercise_id);
+CREATE INDEX idx_training_pause_training_id ON training_pause(training_id);
+CREATE UNIQUE INDEX idx_training_pause_open ON training_pause(training_id) WHERE ended_at IS NULL;
+CREATE INDEX idx_personal_record_user_exercise ON personal_record(user_id, exercise_id, type);
+CREATE INDEX idx_personal_record_trained_exercise_id ON personal_record(trained_exercise_id);
 CREATE INDEX idx_exercise_to_tag_exercise_id ON exercise_to_tag(exercise_id);
 CREATE INDEX idx_exercise_to_tag_tag_id ON exercise_to_tag(tag_id);
+CREATE INDEX idx_exercise_alias_exercise_id ON exercise_alias(exercise_id);
+CREATE UNIQUE INDEX idx_exercise_alias_alias ON exercise_alias(lower(alias));
 CREATE INDEX idx_global_training_exercise_training_id ON global_training_exercise(global_training_id);
 CREATE INDEX idx_global_training_exercise_exercise_id ON global_training_exercise(exercise_id);
+CREATE UNIQUE INDEX idx_global_training_exercise_position ON global_training_exercise(global_training_id, position);
+CREATE INDEX idx_training_schedule_user_id ON training_schedule(user_id);
+CREATE UNIQUE INDEX idx_training_schedule_occurrence ON training(schedule_id, occurrence_date);
+CREATE UNIQUE INDEX idx_program_session_day ON program_session(program_id, week, day);
+CREATE INDEX idx_program_enrollment_user_id ON program_enrollment(user_id);
+CREATE INDEX idx_training_enrollment_id ON training(enrollment_id);
+CREATE INDEX idx_training_template_user_id ON training_template(user_id);
+CREATE UNIQUE INDEX idx_training_template_exercise_position ON training_template_exercise(template_id, position);
+CREATE UNIQUE INDEX idx_calendar_token_token_hash ON calendar_token(token_hash);
 
 -- Внешние ключи
 ALTER TABLE trained_exercise
     ADD CONSTRAINT trained_exercise_training_id_foreign 
     FOREIGN KEY (training_id) REFERENCES training(id) ON DELETE CASCADE,
     ADD CONSTRAINT trained_exercise_exercise_id_foreign 
-    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE;
+    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE,
+    -- Отложенная проверка позволяет переставлять упражнения внутри транзакции
+    ADD CONSTRAINT trained_exercise_position_unique
+    UNIQUE (training_id, position) DEFERRABLE INITIALLY DEFERRED;
+
+ALTER TABLE trained_exercise_cardio
+    ADD CONSTRAINT trained_exercise_cardio_trained_exercise_id_foreign 
+    FOREIGN KEY (trained_exercise_id) REFERENCES trained_exercise(id) ON DELETE CASCADE;
+
+ALTER TABLE trained_set
+    ADD CONSTRAINT trained_set_trained_exercise_id_foreign 
+    FOREIGN KEY (trained_exercise_id) REFERENCES trained_exercise(id) ON DELETE CASCADE,
+    -- Отложенная проверка позволяет переставлять подходы внутри транзакции
+    ADD CONSTRAINT trained_set_ordinal_unique
+    UNIQUE (trained_exercise_id, ordinal) DEFERRABLE INITIALLY DEFERRED;
+
+ALTER TABLE training_pause
+    ADD CONSTRAINT training_pause_training_id_foreign 
+    FOREIGN KEY (training_id) REFERENCES training(id) ON DELETE CASCADE;
+
+ALTER TABLE personal_record
+    ADD CONSTRAINT personal_record_exercise_id_foreign 
+    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE,
+    ADD CONSTRAINT personal_record_trained_exercise_id_foreign 
+    FOREIGN KEY (trained_exercise_id) REFERENCES trained_exercise(id) ON DELETE CASCADE;
 
 ALTER TABLE global_training_exercise
     ADD CONSTRAINT global_training_exercise_training_id_foreign 
@@ -92,8 +316,50 @@ ALTER TABLE global_training_exercise
     ADD CONSTRAINT global_training_exercise_exercise_id_foreign 
     FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE;
 
+ALTER TABLE exercise_alias
+    ADD CONSTRAINT exercise_alias_exercise_id_foreign 
+    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE;
+
 ALTER TABLE exercise_to_tag
     ADD CONSTRAINT exercise_to_tag_tag_id_foreign 
     FOREIGN KEY (tag_id) REFERENCES tag(id) ON DELETE CASCADE,
     ADD CONSTRAINT exercise_to_tag_exercise_id_foreign 
-    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE;
\ No newline at end of file
+    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE;
+
+ALTER TABLE training
+    ADD CONSTRAINT training_schedule_id_foreign 
+    FOREIGN KEY (schedule_id) REFERENCES training_schedule(id) ON DELETE SET NULL;
+
+ALTER TABLE training_schedule
+    ADD CONSTRAINT training_schedule_global_training_id_foreign 
+    FOREIGN KEY (global_training_id) REFERENCES global_training(id) ON DELETE SET NULL;
+
+ALTER TABLE training_schedule
+    ADD CONSTRAINT training_schedule_template_id_foreign 
+    FOREIGN KEY (template_id) REFERENCES training_template(id) ON DELETE SET NULL;
+
+ALTER TABLE training_schedule_skip
+    ADD CONSTRAINT training_schedule_skip_schedule_id_foreign 
+    FOREIGN KEY (schedule_id) REFERENCES training_schedule(id) ON DELETE CASCADE;
+
+ALTER TABLE training
+    ADD CONSTRAINT training_enrollment_id_foreign 
+    FOREIGN KEY (enrollment_id) REFERENCES program_enrollment(id) ON DELETE SET NULL,
+    ADD CONSTRAINT training_program_session_id_foreign 
+    FOREIGN KEY (program_session_id) REFERENCES program_session(id) ON DELETE SET NULL;
+
+ALTER TABLE program_session
+    ADD CONSTRAINT program_session_program_id_foreign 
+    FOREIGN KEY (program_id) REFERENCES program(id) ON DELETE CASCADE,
+    ADD CONSTRAINT program_session_global_training_id_foreign 
+    FOREIGN KEY (global_training_id) REFERENCES global_training(id) ON DELETE RESTRICT;
+
+ALTER TABLE program_enrollment
+    ADD CONSTRAINT program_enrollment_program_id_foreign 
+    FOREIGN KEY (program_id) REFERENCES program(id) ON DELETE CASCADE;
+
+ALTER TABLE training_template_exercise
+    ADD CONSTRAINT training_template_exercise_template_id_foreign 
+    FOREIGN KEY (template_id) REFERENCES training_template(id) ON DELETE CASCADE,
+    ADD CONSTRAINT training_template_exercise_exercise_id_foreign 
+    FOREIGN KEY (exercise_id) REFERENCES exercise(id) ON DELETE CASCADE;
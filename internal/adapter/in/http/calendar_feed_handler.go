@@ -0,0 +1,111 @@
+package httpin
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+)
+
+// calendarFeedPath - путь календаря без ключа; ключ и расширение .ics дописываются в конец
+const calendarFeedPath = "/api/v1/calendar/"
+
+// RotateCalendarToken выдает новый ключ подписки на календарь
+// @Summary      Выдать ключ подписки на календарь
+// @Description  Создает секретный ключ, по которому календарь тренировок доступен без аутентификации.
+// @Description  Прежний ключ перестает работать. Ключ хранится только в виде хэша и показывается один раз
+// @Tags         calendar
+// @Produce      json
+// @Success      201  {object}  dto.CalendarTokenResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/token [post]
+func (h *TrainingHandler) RotateCalendarToken(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	token, err := h.svc.RotateCalendarToken(c.Request.Context(), uid)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to rotate calendar token")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CalendarTokenResponse{
+		Token: token,
+		URL:   calendarFeedURL(c, token),
+	})
+}
+
+// RevokeCalendarToken отзывает ключ подписки на календарь
+// @Summary      Отозвать ключ подписки на календарь
+// @Description  Удаляет ключ подписки: ссылка на календарь перестает работать
+// @Tags         calendar
+// @Success      204
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /calendar/token [delete]
+func (h *TrainingHandler) RevokeCalendarToken(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	if err := h.svc.RevokeCalendarToken(c.Request.Context(), uid); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to revoke calendar token")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCalendarFeed отдает календарь тренировок по ключу подписки
+// @Summary      Календарь тренировок iCalendar
+// @Description  Календарь для подписки в Google Calendar или Apple Calendar: событие на каждую тренировку
+// @Description  за последние 180 дней и на все запланированные. Описание события - статус и упражнения.
+// @Description  Завершенная тренировка занимает время от фактического начала до конца, остальные - весь
+// @Description  запланированный день. Аутентификация не нужна: доступ дает ключ из POST /calendar/token
+// @Tags         calendar
+// @Produce      text/calendar
+// @Param        token path string true "Ключ подписки"
+// @Success      200  "Календарь в формате iCalendar"
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Router       /calendar/{token}.ics [get]
+func (h *TrainingHandler) GetCalendarFeed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("file"), ".ics")
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{Error: "calendar feed not found"})
+		return
+	}
+
+	feed, err := h.svc.GetCalendarFeed(c.Request.Context(), token)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to build calendar feed")
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", feed)
+}
+
+// calendarFeedURL - ссылка на календарь с учетом схемы и хоста, под которыми пришел запрос
+func calendarFeedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	host := c.Request.Host
+	if forwarded := c.GetHeader("X-Forwarded-Host"); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host + calendarFeedPath + token + ".ics"
+}
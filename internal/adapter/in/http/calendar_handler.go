@@ -0,0 +1,80 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetTrainingCalendar получает календарь тренировок пользователя
+// @Summary      Получить календарь тренировок
+// @Description  Возвращает каждый день периода с краткими сводками запланированных на него тренировок.
+// @Description  Дни без тренировок возвращаются с пустым списком. Период - не больше 366 дней
+// @Tags         trainings
+// @Produce      json
+// @Param        from query string true "Начало периода (YYYY-MM-DD)"
+// @Param        to query string true "Конец периода включительно (YYYY-MM-DD)"
+// @Success      200  {array}   dto.CalendarDayResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trainings/calendar [get]
+func (h *TrainingHandler) GetTrainingCalendar(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from date format, use YYYY-MM-DD"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to date format, use YYYY-MM-DD"})
+		return
+	}
+
+	days, err := h.svc.GetTrainingCalendar(c.Request.Context(), uid, from, to)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get training calendar")
+		return
+	}
+
+	resp := make([]dto.CalendarDayResponse, 0, len(days))
+	for _, day := range days {
+		resp = append(resp, h.calendarDayToResponse(day))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TrainingHandler) calendarDayToResponse(day svctraining.CalendarDay) dto.CalendarDayResponse {
+	trainings := make([]dto.CalendarTrainingResponse, 0, len(day.Trainings))
+	for _, training := range day.Trainings {
+		item := dto.CalendarTrainingResponse{
+			ID:            training.ID,
+			Title:         training.Title,
+			Status:        string(training.Status),
+			Rating:        training.Rating,
+			ExerciseCount: training.ExerciseCount,
+		}
+		if training.TotalDuration > 0 {
+			duration := formatDuration(training.TotalDuration)
+			item.TotalDuration = &duration
+		}
+		trainings = append(trainings, item)
+	}
+
+	return dto.CalendarDayResponse{
+		Date:      day.Date.Format("2006-01-02"),
+		Trainings: trainings,
+	}
+}
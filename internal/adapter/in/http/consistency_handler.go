@@ -0,0 +1,157 @@
+package httpin
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetTrainingConsistency получает регулярность тренировок пользователя
+// @Summary      Получить регулярность тренировок
+// @Description  Возвращает текущую и самую длинную серию тренировок, процент выполнения плана по неделям
+// @Description  и число пропущенных тренировок. Первый день недели и допустимый перерыв берутся из настроек пользователя
+// @Tags         trainings
+// @Produce      json
+// @Param        weeks query int false "За сколько последних недель считать выполнение плана (1-52)" default(12)
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {object}  dto.ConsistencyResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trainings/consistency [get]
+func (h *TrainingHandler) GetTrainingConsistency(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var weeks int32
+	if raw := c.Query("weeks"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid weeks"})
+			return
+		}
+		weeks = int32(n)
+	}
+
+	consistency, err := h.svc.GetTrainingConsistency(c.Request.Context(), uid, weeks)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get training consistency")
+		return
+	}
+
+	resp := dto.ConsistencyResponse{
+		CurrentStreak:    consistency.CurrentStreak,
+		LongestStreak:    consistency.LongestStreak,
+		AdherencePercent: consistency.AdherencePercent,
+		MissedSessions:   consistency.MissedSessions,
+		WeekStart:        int32(consistency.WeekStart),
+		RestDays:         consistency.RestDays,
+		Weeks:            make([]dto.WeeklyAdherenceResponse, 0, len(consistency.Weeks)),
+	}
+	if consistency.LastTrainingDay != nil {
+		day := consistency.LastTrainingDay.Format("2006-01-02")
+		resp.LastTrainingDay = &day
+	}
+	for _, week := range consistency.Weeks {
+		resp.Weeks = append(resp.Weeks, dto.WeeklyAdherenceResponse{
+			WeekStart: week.WeekStart.Format("2006-01-02"),
+			Planned:   week.Planned,
+			Completed: week.Completed,
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetUserSettings получает настройки пользователя
+// @Summary      Получить настройки
+// @Description  Возвращает настройки пользователя. Если пользователь их не сохранял, возвращаются значения по умолчанию
+// @Tags         settings
+// @Produce      json
+// @Success      200  {object}  dto.UserSettingsResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /settings [get]
+func (h *TrainingHandler) GetUserSettings(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	settings, err := h.svc.GetUserSettings(c.Request.Context(), uid)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.userSettingsToResponse(settings))
+}
+
+// UpdateUserSettings изменяет настройки пользователя
+// @Summary      Изменить настройки
+// @Description  Обновляет переданные поля настроек пользователя
+// @Tags         settings
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.UpdateUserSettingsRequest true "Изменяемые поля"
+// @Success      200  {object}  dto.UserSettingsResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /settings [put]
+func (h *TrainingHandler) UpdateUserSettings(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var req dto.UpdateUserSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd := svctraining.UpdateUserSettingsCmd{
+		UserID:   uid,
+		RestDays: req.RestDays,
+		Timezone: req.Timezone,
+	}
+	if req.WeekStart != nil {
+		weekStart := time.Weekday(*req.WeekStart)
+		cmd.WeekStart = &weekStart
+	}
+
+	settings, err := h.svc.UpdateUserSettings(c.Request.Context(), cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.userSettingsToResponse(settings))
+}
+
+func (h *TrainingHandler) userSettingsToResponse(settings *svctraining.UserSettings) dto.UserSettingsResponse {
+	resp := dto.UserSettingsResponse{
+		WeekStart: int32(settings.WeekStart),
+		RestDays:  settings.RestDays,
+		Timezone:  settings.Timezone,
+	}
+	if !settings.UpdatedAt.IsZero() {
+		updatedAt := settings.UpdatedAt.Format(time.RFC3339)
+		resp.UpdatedAt = &updatedAt
+	}
+	return resp
+}
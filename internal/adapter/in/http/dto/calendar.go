@@ -0,0 +1,7 @@
+package dto
+
+// CalendarTokenResponse представляет новый ключ подписки на календарь тренировок
+type CalendarTokenResponse struct {
+	Token string `json:"token" example:"q3Jb8m0Vf1kq9xZ2..." description:"Секретный ключ подписки; показывается один раз"`
+	URL   string `json:"url" example:"https://example.com/api/v1/calendar/q3Jb8m0Vf1kq9xZ2....ics" description:"Ссылка для подписки в Google Calendar или Apple Calendar"`
+}
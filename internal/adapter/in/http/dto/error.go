@@ -1,5 +1,6 @@
 package dto
 // ErrorResponse представляет ответ об ошибке
 type ErrorResponse struct {
-	Error string `json:"error" example:"error message" description:"Описание ошибки"`
+	Error  string `json:"error" example:"error message" description:"Описание ошибки"`
+	Reason string `json:"reason,omitempty" example:"training_already_started" description:"Машиночитаемая причина конфликта"`
 }
\ No newline at end of file
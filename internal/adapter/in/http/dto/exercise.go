@@ -7,6 +7,7 @@ type ExerciseResponse struct {
 	Description string        `json:"description" example:"Базовое упражнение для развития грудных мышц" description:"Описание упражнения"`
 	VideoURL    *string       `json:"video_url,omitempty" example:"https://example.com/video.mp4" description:"Ссылка на видео с техникой выполнения"`
 	ImageURL    *string       `json:"image_url,omitempty" example:"https://example.com/video.mp4" description:"Ссылка на картинку"`
+	Type        string        `json:"type" example:"strength" enums:"strength,running,walking,cycling,rowing,swimming,cardio" description:"Тип упражнения; от него зависят допустимые кардио-показатели"`
 	Tags        []TagResponse `json:"tags,omitempty" description:"Теги упражнения"`
 }
 
@@ -31,3 +32,37 @@ type GetExercisesByMultipleTagsRequest struct {
 type GetPopularTagsRequest struct {
 	Limit int `json:"limit" form:"limit" binding:"min=1,max=50" example:"10" description:"Лимит тегов (1-50)"`
 }
+
+// CreateExerciseRequest представляет запрос на создание упражнения в каталоге
+type CreateExerciseRequest struct {
+	Title       string `json:"title" binding:"required" example:"Жим лежа" description:"Название упражнения"`
+	Description string `json:"description" example:"Базовое упражнение для развития грудных мышц" description:"Описание упражнения"`
+	VideoURL    string `json:"video_url" example:"https://example.com/video.mp4" description:"Ссылка на видео (http/https)"`
+	ImageURL    string `json:"image_url" example:"https://example.com/image.png" description:"Ссылка на картинку (http/https)"`
+	Type        string `json:"type" example:"running" enums:"strength,running,walking,cycling,rowing,swimming,cardio" description:"Тип упражнения (по умолчанию strength)"`
+}
+
+// UpdateExerciseRequest представляет запрос на изменение упражнения в каталоге
+type UpdateExerciseRequest struct {
+	Title       *string `json:"title,omitempty" example:"Жим лежа" description:"Название упражнения"`
+	Description *string `json:"description,omitempty" example:"Базовое упражнение для развития грудных мышц" description:"Описание упражнения"`
+	VideoURL    *string `json:"video_url,omitempty" example:"https://example.com/video.mp4" description:"Ссылка на видео (http/https)"`
+	ImageURL    *string `json:"image_url,omitempty" example:"https://example.com/image.png" description:"Ссылка на картинку (http/https)"`
+	Type        *string `json:"type,omitempty" example:"running" enums:"strength,running,walking,cycling,rowing,swimming,cardio" description:"Тип упражнения"`
+}
+
+// TagRequest представляет запрос на создание или изменение тега
+type TagRequest struct {
+	Type string `json:"type" binding:"required" example:"силовое" description:"Название тега"`
+}
+
+// ExerciseAliasesRequest представляет запрос на замену других названий упражнения
+type ExerciseAliasesRequest struct {
+	Aliases []string `json:"aliases" example:"Bench Press (Barbell),Жим штанги лежа" description:"Другие названия упражнения, например из Strong или Hevy"`
+}
+
+// ExerciseAliasesResponse представляет другие названия упражнения
+type ExerciseAliasesResponse struct {
+	ExerciseID int64    `json:"exercise_id" example:"1" description:"ID упражнения"`
+	Aliases    []string `json:"aliases" example:"Bench Press (Barbell),Жим штанги лежа" description:"Другие названия упражнения"`
+}
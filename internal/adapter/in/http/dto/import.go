@@ -0,0 +1,45 @@
+package dto
+
+// ImportReportResponse представляет итог импорта истории тренировок или пробного запуска
+type ImportReportResponse struct {
+	Format      string                      `json:"format" example:"strong" description:"Формат выгрузки: strong или hevy"`
+	DryRun      bool                        `json:"dry_run" example:"false" description:"Пробный запуск: тренировки не сохранены"`
+	Workouts    int                         `json:"workouts" example:"42" description:"Тренировок в файле"`
+	Imported    int                         `json:"imported" example:"40" description:"Импортировано тренировок (при пробном запуске - сколько будет импортировано)"`
+	Sets        int                         `json:"sets" example:"612" description:"Подходов в импортированных тренировках"`
+	Matches     []ExerciseMatchResponse     `json:"matches" description:"Сопоставление названий из файла с каталогом"`
+	Unmatched   []UnmatchedExerciseResponse `json:"unmatched" description:"Названия без пары в каталоге; их подходы пропущены"`
+	Skipped     []SkippedWorkoutResponse    `json:"skipped" description:"Пропущенные тренировки"`
+	Cardio      []RejectedCardioResponse    `json:"rejected_cardio" description:"Кардио-показатели, не подходящие упражнению каталога; упражнения импортированы без них"`
+	TrainingIDs []int64                     `json:"training_ids" example:"101,102" description:"ID созданных тренировок"`
+}
+
+// ExerciseMatchResponse представляет упражнение каталога, сопоставленное названию из выгрузки
+type ExerciseMatchResponse struct {
+	Name          string  `json:"name" example:"Bench Press (Barbell)" description:"Название в выгрузке"`
+	ExerciseID    int64   `json:"exercise_id" example:"1" description:"ID упражнения каталога"`
+	ExerciseTitle string  `json:"exercise_title" example:"Жим штанги лежа" description:"Название упражнения каталога"`
+	Kind          string  `json:"kind" example:"alias" description:"Способ сопоставления: exact, alias или fuzzy"`
+	Similarity    float64 `json:"similarity" example:"1" description:"Похожесть названий от 0 до 1"`
+}
+
+// UnmatchedExerciseResponse представляет название из выгрузки, не найденное в каталоге
+type UnmatchedExerciseResponse struct {
+	Name string `json:"name" example:"Cable Crossover" description:"Название в выгрузке"`
+	Sets int    `json:"sets" example:"12" description:"Пропущено подходов"`
+}
+
+// SkippedWorkoutResponse представляет тренировку из выгрузки, которая не импортирована
+type SkippedWorkoutResponse struct {
+	Title  string `json:"title" example:"Грудь и трицепс" description:"Название тренировки"`
+	Date   string `json:"date" example:"2024-03-15" description:"День тренировки"`
+	Reason string `json:"reason" example:"duplicate" description:"Причина: duplicate (уже импортирована) или no_matched_exercises (нет упражнений из каталога)"`
+}
+
+// RejectedCardioResponse представляет кардио-показатели из файла, не сохраненные при импорте
+type RejectedCardioResponse struct {
+	Name          string `json:"name" example:"Morning Run" description:"Название активности в файле"`
+	ExerciseTitle string `json:"exercise_title" example:"Бег" description:"Сопоставленное упражнение каталога"`
+	Date          string `json:"date" example:"2024-03-15" description:"День тренировки"`
+	Reason        string `json:"reason" example:"invalid cardio metrics: strength exercises have no cardio metrics" description:"Причина"`
+}
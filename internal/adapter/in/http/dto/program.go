@@ -0,0 +1,72 @@
+package dto
+
+// ProgramRequest представляет запрос на создание или изменение программы
+type ProgramRequest struct {
+	Title       string                  `json:"title" binding:"required" example:"Сила за 8 недель" description:"Название программы"`
+	Description string                  `json:"description" example:"Линейная прогрессия в базовых упражнениях" description:"Описание программы"`
+	Level       string                  `json:"level" binding:"required" example:"beginner" enums:"beginner,intermediate,advanced" description:"Уровень сложности"`
+	Weeks       int32                   `json:"weeks" binding:"required" example:"8" minimum:"1" maximum:"52" description:"Длительность в неделях"`
+	Sessions    []ProgramSessionRequest `json:"sessions" description:"Тренировочные дни программы"`
+}
+
+// ProgramSessionRequest представляет тренировочный день программы с правилом прогрессии
+type ProgramSessionRequest struct {
+	Week             int32    `json:"week" binding:"required" example:"1" minimum:"1" description:"Неделя программы"`
+	Day              int32    `json:"day" binding:"required" example:"1" minimum:"1" maximum:"7" description:"Порядковый номер тренировки внутри недели"`
+	GlobalTrainingID int64    `json:"global_training_id" binding:"required" example:"1" description:"Глобальная тренировка этого дня"`
+	WeightIncrement  *float64 `json:"weight_increment,omitempty" example:"2.5" minimum:"0" description:"Прибавка веса за каждую неделю после первой, кг (опционально)"`
+	RepsIncrement    *int32   `json:"reps_increment,omitempty" example:"1" minimum:"0" description:"Прибавка повторений за каждую неделю после первой (опционально)"`
+}
+
+// ProgramResponse представляет программу; тренировочные дни возвращаются только для одной программы
+type ProgramResponse struct {
+	ID          int64                    `json:"id" example:"1" description:"ID программы"`
+	Title       string                   `json:"title" example:"Сила за 8 недель" description:"Название программы"`
+	Description string                   `json:"description" example:"Линейная прогрессия в базовых упражнениях" description:"Описание программы"`
+	Level       string                   `json:"level" example:"beginner" description:"Уровень сложности"`
+	Weeks       int32                    `json:"weeks" example:"8" description:"Длительность в неделях"`
+	Sessions    []ProgramSessionResponse `json:"sessions,omitempty" description:"Тренировочные дни по порядку недель и дней"`
+}
+
+// ProgramSessionResponse представляет тренировочный день программы
+type ProgramSessionResponse struct {
+	ID                  int64    `json:"id" example:"1" description:"ID тренировочного дня"`
+	Week                int32    `json:"week" example:"1" description:"Неделя программы"`
+	Day                 int32    `json:"day" example:"1" description:"Порядковый номер тренировки внутри недели"`
+	GlobalTrainingID    int64    `json:"global_training_id" example:"1" description:"ID глобальной тренировки"`
+	GlobalTrainingTitle string   `json:"global_training_title" example:"Фулбоди A" description:"Название глобальной тренировки"`
+	WeightIncrement     *float64 `json:"weight_increment,omitempty" example:"2.5" description:"Прибавка веса за неделю, кг"`
+	RepsIncrement       *int32   `json:"reps_increment,omitempty" example:"1" description:"Прибавка повторений за неделю"`
+}
+
+// EnrollProgramRequest представляет запрос на запись на программу
+type EnrollProgramRequest struct {
+	StartDate *string `json:"start_date,omitempty" example:"2023-10-02" description:"Дата начала (YYYY-MM-DD), по умолчанию - сегодня"`
+	Weekdays  []int   `json:"weekdays" binding:"required" example:"1,3,5" description:"Предпочитаемые дни недели: 0 - воскресенье, 1 - понедельник, ..., 6 - суббота"`
+}
+
+// EnrollmentResponse представляет запись на программу с прогрессом прохождения
+type EnrollmentResponse struct {
+	ID                int64                       `json:"id" example:"1" description:"ID записи"`
+	ProgramID         int64                       `json:"program_id" example:"1" description:"ID программы"`
+	ProgramTitle      string                      `json:"program_title" example:"Сила за 8 недель" description:"Название программы"`
+	Weeks             int32                       `json:"weeks" example:"8" description:"Длительность программы в неделях"`
+	StartDate         string                      `json:"start_date" example:"2023-10-02" description:"Дата начала"`
+	Weekdays          []int                       `json:"weekdays" example:"1,3,5" description:"Дни недели тренировок (0 - воскресенье)"`
+	CreatedAt         string                      `json:"created_at" example:"2023-10-01T12:00:00Z" description:"Время записи"`
+	TotalSessions     int32                       `json:"total_sessions" example:"24" description:"Всего тренировок программы"`
+	CompletedSessions int32                       `json:"completed_sessions" example:"10" description:"Выполнено тренировок"`
+	SkippedSessions   int32                       `json:"skipped_sessions" example:"1" description:"Пропущено или отменено тренировок"`
+	CompletionPercent float64                     `json:"completion_percent" example:"41.7" description:"Процент выполненных тренировок"`
+	CurrentWeek       int32                       `json:"current_week" example:"4" description:"Текущая неделя программы (0 - программа еще не началась)"`
+	NextTraining      *EnrollmentTrainingResponse `json:"next_training,omitempty" description:"Ближайшая невыполненная тренировка"`
+}
+
+// EnrollmentTrainingResponse представляет тренировку программы
+type EnrollmentTrainingResponse struct {
+	TrainingID  int64  `json:"training_id" example:"42" description:"ID тренировки"`
+	Title       string `json:"title" example:"Фулбоди A" description:"Название тренировки"`
+	PlannedDate string `json:"planned_date" example:"2023-10-23" description:"Запланированная дата"`
+	Week        int32  `json:"week" example:"4" description:"Неделя программы"`
+	Day         int32  `json:"day" example:"1" description:"Порядковый номер тренировки внутри недели"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+// ScheduleRequest представляет запрос на создание или изменение расписания
+type ScheduleRequest struct {
+	Title            string  `json:"title" binding:"required" example:"Силовая" description:"Название тренировок по расписанию"`
+	RRule            string  `json:"rrule" binding:"required" example:"FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE,FR;COUNT=24" description:"Правило повторения RRULE (RFC 5545): FREQ=WEEKLY, INTERVAL, BYDAY, UNTIL или COUNT"`
+	StartDate        *string `json:"start_date,omitempty" example:"2023-10-02" description:"Дата начала (YYYY-MM-DD), по умолчанию - сегодня. При изменении расписания не используется"`
+	GlobalTrainingID *int64  `json:"global_training_id,omitempty" example:"1" description:"Глобальная тренировка, упражнения которой копируются в каждое повторение"`
+	TemplateID       *int64  `json:"template_id,omitempty" example:"3" description:"Шаблон пользователя, упражнения которого копируются в каждое повторение; не вместе с global_training_id"`
+}
+
+// ScheduleResponse представляет расписание повторяющейся тренировки
+type ScheduleResponse struct {
+	ID                int64   `json:"id" example:"1" description:"ID расписания"`
+	Title             string  `json:"title" example:"Силовая" description:"Название тренировок по расписанию"`
+	RRule             string  `json:"rrule" example:"FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=24" description:"Правило повторения в нормализованном виде"`
+	StartDate         string  `json:"start_date" example:"2023-10-02" description:"Дата начала"`
+	GlobalTrainingID  *int64  `json:"global_training_id,omitempty" example:"1" description:"Глобальная тренировка - источник упражнений"`
+	TemplateID        *int64  `json:"template_id,omitempty" example:"3" description:"Шаблон пользователя - источник упражнений"`
+	MaterializedUntil *string `json:"materialized_until,omitempty" example:"2023-10-30" description:"День, до которого тренировки по расписанию уже созданы"`
+	CreatedAt         string  `json:"created_at" example:"2023-10-01T12:00:00Z" description:"Время создания"`
+}
+
+// ScheduleOccurrenceResponse представляет повторение расписания
+type ScheduleOccurrenceResponse struct {
+	Date        string  `json:"date" example:"2023-10-04" description:"День повторения по правилу"`
+	Skipped     bool    `json:"skipped" example:"false" description:"Повторение пропущено"`
+	TrainingID  *int64  `json:"training_id,omitempty" example:"42" description:"ID созданной тренировки"`
+	PlannedDate *string `json:"planned_date,omitempty" example:"2023-10-05" description:"Дата тренировки, если повторение перенесли"`
+	Status      *string `json:"status,omitempty" example:"planned" description:"Статус созданной тренировки"`
+}
+
+// UpdateScheduleOccurrenceRequest представляет изменение одного повторения
+type UpdateScheduleOccurrenceRequest struct {
+	Title       *string `json:"title,omitempty" example:"Силовая (легкая)" description:"Название тренировки этого повторения"`
+	PlannedDate *string `json:"planned_date,omitempty" example:"2023-10-05" description:"Перенос тренировки на другой день (YYYY-MM-DD)"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// UserSettingsResponse представляет настройки пользователя
+type UserSettingsResponse struct {
+	WeekStart int32   `json:"week_start" example:"1" minimum:"0" maximum:"6" description:"Первый день недели (0 - воскресенье, 1 - понедельник)"`
+	RestDays  int32   `json:"rest_days" example:"2" minimum:"0" maximum:"6" description:"Сколько дней отдыха подряд не прерывают серию тренировок"`
+	Timezone  string  `json:"timezone" example:"Asia/Novosibirsk" description:"Часовой пояс IANA, по которому определяется текущий день (пусто - UTC)"`
+	UpdatedAt *string `json:"updated_at,omitempty" example:"2023-10-05T16:30:00Z" description:"Когда настройки изменялись (не задано - действуют значения по умолчанию)"`
+}
+
+// UpdateUserSettingsRequest представляет запрос на изменение настроек пользователя
+type UpdateUserSettingsRequest struct {
+	WeekStart *int32  `json:"week_start,omitempty" example:"1" minimum:"0" maximum:"6" description:"Первый день недели (0 - воскресенье, 1 - понедельник)"`
+	RestDays  *int32  `json:"rest_days,omitempty" example:"2" minimum:"0" maximum:"6" description:"Сколько дней отдыха подряд не прерывают серию тренировок"`
+	Timezone  *string `json:"timezone,omitempty" example:"Asia/Novosibirsk" description:"Часовой пояс IANA; пустая строка сбрасывает пояс на UTC"`
+}
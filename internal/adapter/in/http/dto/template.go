@@ -0,0 +1,38 @@
+package dto
+
+// TemplateRequest представляет запрос на создание или изменение шаблона тренировки
+type TemplateRequest struct {
+	Title       string                          `json:"title" binding:"required" example:"Моя тренировка ног" description:"Название шаблона"`
+	Description string                          `json:"description" example:"Присед, выпады, икры" description:"Описание шаблона"`
+	Exercises   []GlobalTrainingExerciseRequest `json:"exercises" description:"Упражнения с целевой нагрузкой в порядке выполнения"`
+}
+
+// TemplateFromTrainingRequest представляет запрос на сохранение завершенной тренировки как шаблона
+type TemplateFromTrainingRequest struct {
+	Title       string `json:"title,omitempty" example:"Моя тренировка ног" description:"Название шаблона (по умолчанию - название тренировки)"`
+	Description string `json:"description,omitempty" example:"Присед, выпады, икры" description:"Описание шаблона"`
+}
+
+// TemplateResponse представляет шаблон тренировки; упражнения возвращаются только для одного шаблона
+type TemplateResponse struct {
+	ID          int64                      `json:"id" example:"1" description:"ID шаблона"`
+	Title       string                     `json:"title" example:"Моя тренировка ног" description:"Название шаблона"`
+	Description string                     `json:"description" example:"Присед, выпады, икры" description:"Описание шаблона"`
+	CreatedAt   string                     `json:"created_at" example:"2023-10-01T12:00:00Z" description:"Время создания"`
+	Exercises   []TemplateExerciseResponse `json:"exercises,omitempty" description:"Упражнения в порядке выполнения"`
+}
+
+// TemplateExerciseResponse представляет упражнение шаблона с целевой нагрузкой
+type TemplateExerciseResponse struct {
+	ID            int64    `json:"id" example:"1" description:"ID упражнения шаблона"`
+	ExerciseID    int64    `json:"exercise_id" example:"1" description:"ID упражнения"`
+	ExerciseTitle string   `json:"exercise_title" example:"Приседания со штангой" description:"Название упражнения"`
+	Position      int32    `json:"position" example:"1" description:"Порядковый номер упражнения в шаблоне"`
+	Approaches    *int32   `json:"approaches,omitempty" example:"3" description:"Целевое количество подходов"`
+	Reps          *int32   `json:"reps,omitempty" example:"10" description:"Целевое количество повторений"`
+	Weight        *float64 `json:"weight,omitempty" example:"60" description:"Целевой вес в килограммах"`
+	Duration      *string  `json:"duration,omitempty" example:"1m" description:"Целевое время выполнения"`
+	Rest          *string  `json:"rest,omitempty" example:"90s" description:"Время отдыха"`
+	GroupID       *int32   `json:"group_id,omitempty" example:"1" description:"Номер группы упражнений; отдых - после всей группы"`
+	GroupType     *string  `json:"group_type,omitempty" example:"superset" enums:"superset,circuit,giant_set" description:"Вид группы"`
+}
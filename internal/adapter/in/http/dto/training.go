@@ -2,7 +2,6 @@ package dto
 
 // CreateTrainingRequest представляет запрос на создание тренировки
 type CreateTrainingRequest struct {
-	UserID            string  `json:"user_id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000" format:"uuid" description:"UUID пользователя"`
 	Title             string  `json:"title" binding:"required" example:"Жим жопой" description:"Название тренировки"`
 	IsDone            bool    `json:"is_done" example:"false" description:"Завершена ли тренировка"`
 	PlannedDate       string  `json:"planned_date" binding:"required" example:"2023-10-05T15:00:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Запланированная дата и время тренировки"`
@@ -18,11 +17,11 @@ type CreateTrainingRequest struct {
 // UpdateTrainingRequest представляет запрос на обновление тренировки
 type UpdateTrainingRequest struct {
 	Title             string  `json:"title" binding:"required" example:"Жим жопой" description:"Название тренировки"`
-	IsDone            *bool   `json:"is_done,omitempty" example:"true" description:"Завершена ли тренировка (опционально)"`
+	IsDone            *bool   `json:"is_done,omitempty" example:"true" description:"Не изменяется через PUT: используйте эндпоинты жизненного цикла, иначе 409"`
 	PlannedDate       string  `json:"planned_date" binding:"required" example:"2023-10-05T15:00:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Запланированная дата и время тренировки"`
-	ActualDate        *string `json:"actual_date,omitempty" example:"2023-10-05T16:30:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Фактическая дата и время выполнения тренировки (опционально)"`
-	StartedAt         *string `json:"started_at,omitempty" example:"2023-10-05T15:00:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Время начала тренировки (опционально)"`
-	FinishedAt        *string `json:"finished_at,omitempty" example:"2023-10-05T16:30:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Время окончания тренировки (опционально)"`
+	ActualDate        *string `json:"actual_date,omitempty" example:"2023-10-05T16:30:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Не изменяется через PUT: используйте эндпоинты жизненного цикла, иначе 409"`
+	StartedAt         *string `json:"started_at,omitempty" example:"2023-10-05T15:00:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Не изменяется через PUT: используйте эндпоинты жизненного цикла, иначе 409"`
+	FinishedAt        *string `json:"finished_at,omitempty" example:"2023-10-05T16:30:00Z" pattern:"^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$" description:"Не изменяется через PUT: используйте эндпоинты жизненного цикла, иначе 409"`
 	TotalDuration     *string `json:"total_duration,omitempty" example:"1h30m" description:"Общее время тренировки (опционально)"`
 	TotalRestTime     *string `json:"total_rest_time,omitempty" example:"30m" description:"Общее время отдыха (опционально)"`
 	TotalExerciseTime *string `json:"total_exercise_time,omitempty" example:"1h" description:"Общее время выполнения упражнений (опционально)"`
@@ -40,6 +39,26 @@ type AddExerciseToTrainingRequest struct {
 	Doing      *string  `json:"doing,omitempty" example:"1h" description:"Время выполнения упражнения в формате duration (опционально)"`
 	Rest       *string  `json:"rest,omitempty" example:"30m" description:"Время отдыха в формате duration (опционально)"`
 	Notes      *string  `json:"notes,omitempty" example:"Тяжело далось" description:"Заметки к упражнению (опционально)"`
+	GroupID    *int32   `json:"group_id,omitempty" example:"1" minimum:"1" description:"Номер группы упражнений в тренировке (опционально, вместе с group_type)"`
+	GroupType  *string  `json:"group_type,omitempty" example:"superset" enums:"superset,circuit,giant_set" description:"Вид группы: суперсет, круг или гигантский сет (опционально, вместе с group_id)"`
+
+	Cardio *CardioMetricsRequest `json:"cardio,omitempty" description:"Показатели кардио-упражнения (только для кардио-упражнений каталога)"`
+}
+
+// CardioMetricsRequest представляет показатели кардио-упражнения. Темп и скорость, если не заданы,
+// считаются по дистанции и времени в движении
+type CardioMetricsRequest struct {
+	Distance        *float64 `json:"distance,omitempty" example:"5.2" description:"Дистанция в единице distance_unit"`
+	DistanceUnit    *string  `json:"distance_unit,omitempty" example:"km" enums:"m,km,mi" description:"Единица дистанции (по умолчанию - м для гребли и плавания, км для остальных)"`
+	MovingTime      *string  `json:"moving_time,omitempty" example:"28m40s" description:"Время в движении в формате duration (по умолчанию - время выполнения упражнения)"`
+	AvgPace         *string  `json:"avg_pace,omitempty" example:"5m30s" description:"Средний темп - время на километр в формате duration"`
+	AvgSpeed        *float64 `json:"avg_speed,omitempty" example:"10.9" description:"Средняя скорость, км/ч"`
+	Calories        *int32   `json:"calories,omitempty" example:"420" minimum:"1" maximum:"10000" description:"Калории"`
+	AvgHeartRate    *int32   `json:"avg_heart_rate,omitempty" example:"148" minimum:"20" maximum:"250" description:"Средний пульс, уд/мин"`
+	MaxHeartRate    *int32   `json:"max_heart_rate,omitempty" example:"176" minimum:"20" maximum:"250" description:"Максимальный пульс, уд/мин"`
+	ElevationGain   *float64 `json:"elevation_gain,omitempty" example:"120" description:"Набор высоты в метрах (бег, ходьба, велосипед)"`
+	Incline         *float64 `json:"incline,omitempty" example:"2.5" minimum:"-10" maximum:"40" description:"Наклон беговой дорожки в процентах (бег, ходьба)"`
+	ResistanceLevel *int32   `json:"resistance_level,omitempty" example:"8" minimum:"0" maximum:"100" description:"Уровень сопротивления тренажера (велосипед, гребля)"`
 }
 
 // UpdateTrainedExerciseRequest представляет запрос на обновление выполненного упражнения
@@ -51,6 +70,8 @@ type UpdateTrainedExerciseRequest struct {
 	Doing      *string  `json:"doing,omitempty" example:"1h15m" description:"Время выполнения упражнения в формате duration (опционально)"`
 	Rest       *string  `json:"rest,omitempty" example:"30m" description:"Время отдыха в формате duration (опционально)"`
 	Notes      *string  `json:"notes,omitempty" example:"Стало легче" description:"Заметки к упражнению (опционально)"`
+
+	Cardio *CardioMetricsRequest `json:"cardio,omitempty" description:"Изменяемые показатели кардио-упражнения; незаданные показатели не меняются"`
 }
 
 // TrainingResponse представляет ответ с информацией о тренировке
@@ -67,7 +88,12 @@ type TrainingResponse struct {
 	TotalRestTime     *string                   `json:"total_rest_time,omitempty" example:"30m" description:"Общее время отдыха"`
 	TotalExerciseTime *string                   `json:"total_exercise_time,omitempty" example:"1h" description:"Общее время выполнения упражнений"`
 	Rating            *int32                    `json:"rating,omitempty" example:"5" description:"Оценка тренировки"`
+	Status            string                    `json:"status" example:"in_progress" enums:"planned,in_progress,paused,completed,skipped,cancelled" description:"Статус тренировки"`
+	IsPaused          bool                      `json:"is_paused" example:"false" description:"Стоит ли тренировка на паузе"`
 	Exercises         []TrainedExerciseResponse `json:"exercises,omitempty" description:"Упражнения в тренировке"`
+
+	// Новые рекорды (только в ответе на завершение тренировки)
+	PersonalRecords []PersonalRecordResponse `json:"personal_records,omitempty" description:"Рекорды, установленные в тренировке"`
 }
 
 type UserTrainingsResponse struct {
@@ -83,6 +109,8 @@ type UserTrainingsResponse struct {
 	TotalRestTime     *string                   `json:"total_rest_time,omitempty" example:"30m" description:"Общее время отдыха"`
 	TotalExerciseTime *string                   `json:"total_exercise_time,omitempty" example:"1h" description:"Общее время выполнения упражнений"`
 	Rating            *int32                    `json:"rating,omitempty" example:"5" description:"Оценка тренировки"`
+	Status            string                    `json:"status" example:"in_progress" enums:"planned,in_progress,paused,completed,skipped,cancelled" description:"Статус тренировки"`
+	IsPaused          bool                      `json:"is_paused" example:"false" description:"Стоит ли тренировка на паузе"`
 }
 
 // TrainedExerciseResponse представляет ответ с информацией о выполненном упражнении
@@ -97,15 +125,111 @@ type TrainedExerciseResponse struct {
 	Doing      *string  `json:"doing,omitempty" example:"1h" description:"Время выполнения упражнения"`
 	Rest       *string  `json:"rest,omitempty" example:"30m" description:"Время отдыха"`
 	Notes      *string  `json:"notes,omitempty" example:"Тяжело далось" description:"Заметки"`
+	Position   int32    `json:"position" example:"1" description:"Порядковый номер упражнения в тренировке"`
+	GroupID    *int32   `json:"group_id,omitempty" example:"1" description:"Номер группы упражнений; упражнения группы выполняются подряд, отдых - после всей группы"`
+	GroupType  *string  `json:"group_type,omitempty" example:"superset" enums:"superset,circuit,giant_set" description:"Вид группы"`
+
+	// Подходы упражнения; если они есть, Weight/Approaches/Reps агрегированы по рабочим подходам
+	Sets []TrainedSetResponse `json:"sets,omitempty" description:"Подходы упражнения в порядке выполнения"`
+
+	Cardio *CardioMetricsResponse `json:"cardio,omitempty" description:"Показатели кардио-упражнения"`
+}
+
+// CardioMetricsResponse представляет показатели кардио-упражнения
+type CardioMetricsResponse struct {
+	Distance        *float64 `json:"distance,omitempty" example:"10250.5" description:"Дистанция в метрах"`
+	DistanceUnit    string   `json:"distance_unit,omitempty" example:"km" enums:"m,km,mi" description:"Единица, в которой дистанция показывается пользователю"`
+	DistanceInUnit  *float64 `json:"distance_in_unit,omitempty" example:"10.251" description:"Дистанция в единице distance_unit"`
+	MovingTime      *string  `json:"moving_time,omitempty" example:"52m30s" description:"Время в движении, без остановок"`
+	ElevationGain   *float64 `json:"elevation_gain,omitempty" example:"120" description:"Набор высоты в метрах"`
+	AvgHeartRate    *int32   `json:"avg_heart_rate,omitempty" example:"148" description:"Средний пульс, уд/мин"`
+	MaxHeartRate    *int32   `json:"max_heart_rate,omitempty" example:"176" description:"Максимальный пульс, уд/мин"`
+	AvgPace         *string  `json:"avg_pace,omitempty" example:"5m7s" description:"Средний темп - время на километр"`
+	AvgSpeed        *float64 `json:"avg_speed,omitempty" example:"11.7" description:"Средняя скорость, км/ч"`
+	Calories        *int32   `json:"calories,omitempty" example:"640" description:"Калории"`
+	Incline         *float64 `json:"incline,omitempty" example:"2.5" description:"Наклон беговой дорожки в процентах"`
+	ResistanceLevel *int32   `json:"resistance_level,omitempty" example:"8" description:"Уровень сопротивления тренажера"`
+}
+
+// TrainedSetResponse представляет ответ с информацией о подходе
+type TrainedSetResponse struct {
+	ID                int64    `json:"id" example:"1" description:"ID подхода"`
+	TrainedExerciseID int64    `json:"trained_exercise_id" example:"1" description:"ID выполненного упражнения"`
+	Ordinal           int32    `json:"ordinal" example:"1" description:"Порядковый номер подхода"`
+	Weight            *float64 `json:"weight,omitempty" example:"60" description:"Вес в килограммах"`
+	Reps              *int32   `json:"reps,omitempty" example:"10" description:"Количество повторений"`
+	Duration          *string  `json:"duration,omitempty" example:"45s" description:"Длительность подхода"`
+	RPE               *float64 `json:"rpe,omitempty" example:"8.5" description:"Субъективная тяжесть подхода (1-10)"`
+	IsWarmup          bool     `json:"is_warmup" example:"false" description:"Разминочный подход"`
+}
+
+// AddTrainedSetRequest представляет запрос на добавление подхода
+type AddTrainedSetRequest struct {
+	Weight   *float64 `json:"weight,omitempty" example:"60" minimum:"0" description:"Вес в килограммах (опционально)"`
+	Reps     *int32   `json:"reps,omitempty" example:"10" minimum:"0" description:"Количество повторений (опционально)"`
+	Duration *string  `json:"duration,omitempty" example:"45s" description:"Длительность подхода в формате duration (опционально)"`
+	RPE      *float64 `json:"rpe,omitempty" example:"8.5" minimum:"1" maximum:"10" description:"Субъективная тяжесть подхода (опционально)"`
+	IsWarmup bool     `json:"is_warmup,omitempty" example:"false" description:"Разминочный подход"`
+}
+
+// UpdateTrainedSetRequest представляет запрос на изменение подхода
+type UpdateTrainedSetRequest struct {
+	Weight   *float64 `json:"weight,omitempty" example:"62.5" minimum:"0" description:"Вес в килограммах (опционально)"`
+	Reps     *int32   `json:"reps,omitempty" example:"8" minimum:"0" description:"Количество повторений (опционально)"`
+	Duration *string  `json:"duration,omitempty" example:"40s" description:"Длительность подхода в формате duration (опционально)"`
+	RPE      *float64 `json:"rpe,omitempty" example:"9" minimum:"1" maximum:"10" description:"Субъективная тяжесть подхода (опционально)"`
+	IsWarmup *bool    `json:"is_warmup,omitempty" example:"false" description:"Разминочный подход (опционально)"`
+}
+
+// ReorderTrainedSetsRequest представляет запрос на изменение порядка подходов
+type ReorderTrainedSetsRequest struct {
+	SetIDs []int64 `json:"set_ids" binding:"required" example:"3,1,2" description:"ID всех подходов упражнения в новом порядке"`
+}
+
+// ReorderTrainedExercisesRequest представляет запрос на изменение порядка упражнений тренировки
+type ReorderTrainedExercisesRequest struct {
+	TrainedExerciseIDs []int64 `json:"trained_exercise_ids" binding:"required" example:"3,1,2" description:"ID всех упражнений тренировки в новом порядке"`
+}
+
+// ExerciseGroupRequest представляет запрос на включение упражнения в группу
+type ExerciseGroupRequest struct {
+	GroupID   int32  `json:"group_id" binding:"required" example:"1" minimum:"1" description:"Номер группы упражнений в тренировке"`
+	GroupType string `json:"group_type" binding:"required" example:"superset" enums:"superset,circuit,giant_set" description:"Вид группы: суперсет, круг или гигантский сет"`
 }
 
 // TrainingStatsResponse представляет ответ со статистикой тренировок
 type TrainingStatsResponse struct {
 	TotalTrainings     int64   `json:"total_trainings" example:"15" description:"Общее количество тренировок"`
 	CompletedTrainings int64   `json:"completed_trainings" example:"12" description:"Количество завершенных тренировок"`
+	SkippedTrainings   int64   `json:"skipped_trainings" example:"1" description:"Количество пропущенных тренировок"`
+	CompletionRate     float64 `json:"completion_rate" example:"0.8" description:"Доля завершенных тренировок от 0 до 1"`
 	AverageRating      float64 `json:"average_rating" example:"4.5" description:"Средний рейтинг тренировок"`
 	TotalDuration      string  `json:"total_duration" example:"45h30m" description:"Общее время тренировок"`
+	TotalRestTime      string  `json:"total_rest_time,omitempty" example:"10h15m" description:"Общее время отдыха"`
+	TotalExerciseTime  string  `json:"total_exercise_time,omitempty" example:"35h15m" description:"Общее время выполнения упражнений"`
 	LastTrainingDate   *string `json:"last_training_date,omitempty" example:"2023-10-05T16:30:00Z" description:"Дата последней тренировки"`
+	TotalApproaches    int64   `json:"total_approaches,omitempty" example:"12" description:"Количество рабочих подходов"`
+	TotalReps          int64   `json:"total_reps,omitempty" example:"96" description:"Количество повторений в рабочих подходах"`
+	TotalVolume        float64 `json:"total_volume,omitempty" example:"5400" description:"Тоннаж рабочих подходов (вес × повторения)"`
+	TotalDistance      float64 `json:"total_distance,omitempty" example:"42195" description:"Дистанция кардио-упражнений завершенных тренировок в метрах"`
+	CardioTime         string  `json:"cardio_time,omitempty" example:"3h50m0s" description:"Время в движении кардио-упражнений с дистанцией"`
+	AvgPace            string  `json:"avg_pace,omitempty" example:"5m27s" description:"Средний темп кардио-упражнений - время на километр"`
+	TotalCalories      int64   `json:"total_calories,omitempty" example:"2800" description:"Калории кардио-упражнений"`
+}
+
+// UserTrainingStatsResponse представляет статистику тренировок пользователя за период
+type UserTrainingStatsResponse struct {
+	From    *string                       `json:"from,omitempty" example:"2023-01-01" description:"Начало периода (не задано - вся история)"`
+	To      string                        `json:"to" example:"2023-12-31" description:"Конец периода"`
+	GroupBy string                        `json:"group_by" example:"month" enums:"day,week,month" description:"Шаг разбивки"`
+	Totals  TrainingStatsResponse         `json:"totals" description:"Статистика за весь период"`
+	Buckets []TrainingStatsBucketResponse `json:"buckets" description:"Статистика по периодам, в которых были тренировки"`
+}
+
+// TrainingStatsBucketResponse представляет статистику тренировок за день, неделю или месяц
+type TrainingStatsBucketResponse struct {
+	PeriodStart string `json:"period_start" example:"2023-10-01" description:"Начало периода (неделя начинается с понедельника)"`
+	TrainingStatsResponse
 }
 
 // CompleteTrainingRequest представляет запрос на завершение тренировки
@@ -163,6 +287,16 @@ type ExerciseWithTagsResponse struct {
 	VideoURL    *string       `json:"video_url,omitempty" example:"https://example.com/video.mp4" description:"Ссылка на видео с техникой выполнения"`
 	ImageURL    *string       `json:"image_url,omitempty" example:"https://example.com/video.mp4" description:"Ссылка на картинку"`
 	Tags        []TagResponse `json:"tags" description:"Теги упражнения"`
+
+	// Предписанная нагрузка слота глобальной тренировки
+	Position   *int32   `json:"position,omitempty" example:"1" description:"Порядковый номер упражнения в тренировке"`
+	Approaches *int32   `json:"approaches,omitempty" example:"3" description:"Предписанное количество подходов"`
+	Reps       *int32   `json:"reps,omitempty" example:"10" description:"Предписанное количество повторений"`
+	Weight     *float64 `json:"weight,omitempty" example:"50.5" description:"Предписанный вес в килограммах"`
+	Duration   *string  `json:"duration,omitempty" example:"1m" description:"Предписанное время выполнения"`
+	Rest       *string  `json:"rest,omitempty" example:"90s" description:"Предписанное время отдыха"`
+	GroupID    *int32   `json:"group_id,omitempty" example:"1" description:"Номер группы упражнений; отдых - после всей группы"`
+	GroupType  *string  `json:"group_type,omitempty" example:"superset" enums:"superset,circuit,giant_set" description:"Вид группы"`
 }
 
 // UpdateExerciseRestTimeRequest представляет запрос на обновление времени отдыха упражнения
@@ -183,7 +317,138 @@ type CopyGlobalTrainingRequest struct {
 
 // AssignGlobalTrainingRequest представляет запрос на назначение глобальной тренировки
 type AssignGlobalTrainingRequest struct {
-	UserID           string `json:"user_id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000" description:"UUID пользователя"`
 	GlobalTrainingID int64  `json:"global_training_id" binding:"required" example:"1" description:"ID глобальной тренировки"`
 	PlannedDate      string `json:"planned_date" binding:"required" example:"2023-10-05T15:00:00Z" description:"Запланированная дата тренировки"`
 }
+
+// GlobalTrainingRequest представляет запрос на создание или изменение глобальной тренировки
+type GlobalTrainingRequest struct {
+	Title       string                          `json:"title" binding:"required" example:"Фулбоди для новичков" description:"Название тренировки"`
+	Description string                          `json:"description" example:"Эта тренировка направлена на ..." description:"Описание тренировки"`
+	Level       string                          `json:"level" binding:"required" example:"beginner" enums:"beginner,intermediate,advanced" description:"Уровень сложности"`
+	Exercises   []GlobalTrainingExerciseRequest `json:"exercises" description:"Упражнения в порядке выполнения"`
+}
+
+// GlobalTrainingExerciseRequest представляет слот глобальной тренировки с предписанной нагрузкой
+type GlobalTrainingExerciseRequest struct {
+	ExerciseID int64    `json:"exercise_id" binding:"required" example:"1" minimum:"1" description:"ID упражнения"`
+	Approaches *int32   `json:"approaches,omitempty" example:"3" minimum:"1" description:"Количество подходов (опционально)"`
+	Reps       *int32   `json:"reps,omitempty" example:"10" minimum:"1" description:"Количество повторений (опционально)"`
+	Weight     *float64 `json:"weight,omitempty" example:"50.5" minimum:"0" description:"Вес в килограммах (опционально)"`
+	Duration   *string  `json:"duration,omitempty" example:"1m" description:"Время выполнения в формате duration (опционально)"`
+	Rest       *string  `json:"rest,omitempty" example:"90s" description:"Время отдыха в формате duration (опционально)"`
+	GroupID    *int32   `json:"group_id,omitempty" example:"1" minimum:"1" description:"Номер группы упражнений (опционально, вместе с group_type)"`
+	GroupType  *string  `json:"group_type,omitempty" example:"superset" enums:"superset,circuit,giant_set" description:"Вид группы (опционально, вместе с group_id)"`
+}
+
+// LiveEventResponse представляет событие живой сессии тренировки (поле data в SSE).
+// Заполняются только поля, относящиеся к типу события
+type LiveEventResponse struct {
+	Type              string                   `json:"type" example:"set.added" enums:"training.started,training.paused,training.resumed,training.completed,exercise.added,exercise.updated,exercise.removed,exercises.reordered,set.added,set.updated,set.removed,sets.reordered" description:"Тип события"`
+	TrainingID        int64                    `json:"training_id" example:"1" description:"ID тренировки"`
+	OccurredAt        string                   `json:"occurred_at" example:"2023-10-05T15:10:00Z" description:"Время события"`
+	Training          *TrainingResponse        `json:"training,omitempty" description:"Тренировка после изменения статуса или порядка упражнений"`
+	TrainedExerciseID int64                    `json:"trained_exercise_id,omitempty" example:"1" description:"ID выполненного упражнения"`
+	Exercise          *TrainedExerciseResponse `json:"exercise,omitempty" description:"Упражнение после изменения"`
+	SetID             int64                    `json:"set_id,omitempty" example:"1" description:"ID подхода"`
+	Set               *TrainedSetResponse      `json:"set,omitempty" description:"Подход после изменения"`
+	Sets              []TrainedSetResponse     `json:"sets,omitempty" description:"Подходы в новом порядке"`
+}
+
+// PersonalRecordResponse представляет личный рекорд в упражнении
+type PersonalRecordResponse struct {
+	ID                int64    `json:"id" example:"1" description:"ID рекорда"`
+	ExerciseID        int64    `json:"exercise_id" example:"1" description:"ID упражнения"`
+	ExerciseTitle     string   `json:"exercise_title,omitempty" example:"Жим лежа" description:"Название упражнения"`
+	TrainedExerciseID int64    `json:"trained_exercise_id" example:"1" description:"ID выполненного упражнения, в котором установлен рекорд"`
+	TrainingID        int64    `json:"training_id" example:"1" description:"ID тренировки"`
+	Type              string   `json:"type" example:"max_weight" enums:"max_weight,max_reps,estimated_1rm,max_duration,max_volume" description:"Вид рекорда"`
+	Value             float64  `json:"value" example:"100" description:"Значение: кг, повторения, секунды (max_duration) или кг x повторения (max_volume)"`
+	Weight            *float64 `json:"weight,omitempty" example:"80" description:"Вес, при котором установлен рекорд повторений (только для max_reps)"`
+	AchievedAt        string   `json:"achieved_at" example:"2023-10-05T16:30:00Z" description:"Когда установлен рекорд"`
+}
+
+// StrengthProgressResponse представляет прогресс пользователя в упражнении
+type StrengthProgressResponse struct {
+	ExerciseID  int64                           `json:"exercise_id" example:"1" description:"ID упражнения"`
+	From        string                          `json:"from" example:"2023-07-01" description:"Начало периода"`
+	To          string                          `json:"to" example:"2023-10-01" description:"Конец периода"`
+	Granularity string                          `json:"granularity" example:"week" enums:"day,week,month" description:"Шаг ряда"`
+	Formula     string                          `json:"formula" example:"epley" enums:"epley,brzycki,lombardi" description:"Формула оценки 1ПМ"`
+	Points      []StrengthProgressPointResponse `json:"points" description:"Показатели по периодам, в которых были тренировки"`
+}
+
+// StrengthProgressPointResponse представляет показатели упражнения за период
+type StrengthProgressPointResponse struct {
+	PeriodStart  string  `json:"period_start" example:"2023-09-25" description:"Начало периода (неделя начинается с понедельника)"`
+	BestE1RM     float64 `json:"best_e1rm" example:"112.5" description:"Лучшая оценка 1ПМ за период"`
+	TopSetWeight float64 `json:"top_set_weight" example:"100" description:"Максимальный рабочий вес за период"`
+	TotalVolume  float64 `json:"total_volume" example:"4500" description:"Суммарный объем (вес x повторения)"`
+	Sets         int32   `json:"sets" example:"12" description:"Количество рабочих подходов"`
+}
+
+// LoadSuggestionResponse представляет предлагаемую нагрузку на следующую тренировку
+type LoadSuggestionResponse struct {
+	ExerciseID     int64    `json:"exercise_id" example:"1" description:"ID упражнения"`
+	Weight         *float64 `json:"weight,omitempty" example:"62.5" description:"Предлагаемый вес, кг"`
+	Approaches     *int32   `json:"approaches,omitempty" example:"3" description:"Предлагаемое количество подходов"`
+	Reps           *int32   `json:"reps,omitempty" example:"8" description:"Предлагаемое количество повторений"`
+	Reason         string   `json:"reason" example:"increase" enums:"no_history,increase,repeat,deload" description:"Почему предложена такая нагрузка"`
+	FailedSessions int32    `json:"failed_sessions" example:"0" description:"Невыполненных тренировок подряд с последним весом"`
+	LastSession    *string  `json:"last_session,omitempty" example:"2023-10-05" description:"Дата последнего выполнения упражнения"`
+}
+
+// ConsistencyResponse представляет регулярность тренировок пользователя
+type ConsistencyResponse struct {
+	CurrentStreak    int32                     `json:"current_streak" example:"5" description:"Текущая серия: дни с тренировками без перерывов длиннее rest_days"`
+	LongestStreak    int32                     `json:"longest_streak" example:"14" description:"Самая длинная серия"`
+	LastTrainingDay  *string                   `json:"last_training_day,omitempty" example:"2023-10-05" description:"День последней выполненной тренировки"`
+	AdherencePercent float64                   `json:"adherence_percent" example:"83.3" description:"Процент выполненных тренировок из запланированных за период"`
+	MissedSessions   int32                     `json:"missed_sessions" example:"3" description:"Не начатые или пропущенные тренировки, запланированные на прошедшие дни за последние weeks недель"`
+	WeekStart        int32                     `json:"week_start" example:"1" description:"Первый день недели (0 - воскресенье, 1 - понедельник)"`
+	RestDays         int32                     `json:"rest_days" example:"2" description:"Сколько дней отдыха подряд не прерывают серию"`
+	Weeks            []WeeklyAdherenceResponse `json:"weeks" description:"Запланированные и выполненные тренировки по неделям"`
+}
+
+// WeeklyAdherenceResponse представляет выполнение плана за неделю
+type WeeklyAdherenceResponse struct {
+	WeekStart string `json:"week_start" example:"2023-10-02" description:"Первый день недели"`
+	Planned   int32  `json:"planned" example:"3" description:"Запланировано тренировок"`
+	Completed int32  `json:"completed" example:"2" description:"Выполнено из запланированных"`
+}
+
+// ListTrainingsRequest представляет параметры списка тренировок
+type ListTrainingsRequest struct {
+	PlannedFrom *string `form:"planned_from" example:"2023-10-01" description:"Запланированы не раньше даты (YYYY-MM-DD)"`
+	PlannedTo   *string `form:"planned_to" example:"2023-10-31" description:"Запланированы не позже даты (YYYY-MM-DD)"`
+	IsDone      *bool   `form:"is_done" example:"true" description:"Только завершенные или только незавершенные"`
+	MinRating   *int32  `form:"min_rating" example:"3" description:"Минимальный рейтинг"`
+	MaxRating   *int32  `form:"max_rating" example:"5" description:"Максимальный рейтинг"`
+	Search      *string `form:"search" example:"ноги" description:"Подстрока названия"`
+	Sort        string  `form:"sort" example:"planned_date" enums:"planned_date,actual_date,rating" description:"Поле сортировки"`
+	Order       string  `form:"order" binding:"omitempty,oneof=asc desc" example:"desc" enums:"asc,desc" description:"Направление сортировки"`
+	Limit       int32   `form:"limit" example:"20" description:"Размер страницы (1-100)"`
+	Cursor      string  `form:"cursor" description:"next_cursor предыдущей страницы"`
+}
+
+// TrainingsPageResponse представляет страницу списка тренировок
+type TrainingsPageResponse struct {
+	Trainings  []UserTrainingsResponse `json:"trainings" description:"Тренировки страницы"`
+	NextCursor *string                 `json:"next_cursor,omitempty" example:"eyJzIjoicGxhbm5lZF9kYXRlIiwiZCI6dHJ1ZSwiayI6MTk2MzAsImkiOjQyfQ" description:"Курсор следующей страницы; отсутствует на последней странице"`
+}
+
+// CalendarDayResponse представляет день календаря тренировок
+type CalendarDayResponse struct {
+	Date      string                     `json:"date" example:"2023-10-01" description:"День (YYYY-MM-DD)"`
+	Trainings []CalendarTrainingResponse `json:"trainings" description:"Тренировки, запланированные на день; пустой список, если их нет"`
+}
+
+// CalendarTrainingResponse представляет краткую сводку тренировки в календаре
+type CalendarTrainingResponse struct {
+	ID            int64   `json:"id" example:"1" description:"ID тренировки"`
+	Title         string  `json:"title" example:"Утренняя тренировка" description:"Название тренировки"`
+	Status        string  `json:"status" example:"completed" enums:"planned,in_progress,paused,completed,skipped,cancelled" description:"Статус тренировки"`
+	Rating        *int32  `json:"rating,omitempty" example:"5" description:"Рейтинг тренировки"`
+	TotalDuration *string `json:"total_duration,omitempty" example:"1h30m" description:"Общее время тренировки"`
+	ExerciseCount int32   `json:"exercise_count" example:"6" description:"Количество упражнений"`
+}
@@ -0,0 +1,105 @@
+package httpin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/service"
+)
+
+// abortWithServiceError переводит типизированные ошибки сервиса в HTTP-статусы.
+// Остальные ошибки отдаются со статусом status и сообщением message
+func abortWithServiceError(c *gin.Context, err error, status int, message string) {
+	// Недопустимый переход статуса тренировки отдается вместе с причиной
+	var transitionErr *svctraining.TransitionError
+	if errors.As(err, &transitionErr) {
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error(), Reason: transitionErr.Reason})
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUnauthenticated):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+	case errors.Is(err, service.ErrForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTrainingNotFound),
+		errors.Is(err, service.ErrTrainedExerciseNotFound),
+		errors.Is(err, service.ErrGlobalTrainingNotFound),
+		errors.Is(err, service.ErrExerciseNotFound),
+		errors.Is(err, service.ErrTagNotFound),
+		errors.Is(err, service.ErrTrainedSetNotFound),
+		errors.Is(err, service.ErrScheduleNotFound),
+		errors.Is(err, service.ErrProgramNotFound),
+		errors.Is(err, service.ErrEnrollmentNotFound),
+		errors.Is(err, service.ErrTemplateNotFound),
+		errors.Is(err, service.ErrCalendarFeedNotFound),
+		errors.Is(err, service.ErrCalendarTokenNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrTrainingNotActive),
+		errors.Is(err, service.ErrExerciseInUse),
+		errors.Is(err, service.ErrGlobalTrainingInUse),
+		errors.Is(err, service.ErrOccurrenceSkipped),
+		errors.Is(err, service.ErrOccurrenceStarted),
+		errors.Is(err, service.ErrEmptyProgram),
+		errors.Is(err, service.ErrTrainingNotCompleted),
+		errors.Is(err, service.ErrExerciseAliasTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, service.ErrEmptyExerciseTitle),
+		errors.Is(err, service.ErrEmptyTagType),
+		errors.Is(err, service.ErrInvalidVideoURL),
+		errors.Is(err, service.ErrInvalidImageURL),
+		errors.Is(err, service.ErrInvalidExerciseType),
+		errors.Is(err, service.ErrInvalidCardioMetrics),
+		errors.Is(err, service.ErrInvalidGlobalTrainingID),
+		errors.Is(err, service.ErrEmptyGlobalTrainingTitle),
+		errors.Is(err, service.ErrInvalidGlobalTrainingLevel),
+		errors.Is(err, service.ErrInvalidPrescription),
+		errors.Is(err, service.ErrInvalidExerciseID),
+		errors.Is(err, service.ErrInvalidTrainedSetID),
+		errors.Is(err, service.ErrInvalidTrainedSet),
+		errors.Is(err, service.ErrInvalidSetOrder),
+		errors.Is(err, service.ErrInvalidProgressRange),
+		errors.Is(err, service.ErrInvalidGranularity),
+		errors.Is(err, service.ErrInvalidOneRepMaxFormula),
+		errors.Is(err, service.ErrInvalidStatsRange),
+		errors.Is(err, service.ErrInvalidStatsGroupBy),
+		errors.Is(err, service.ErrInvalidConsistencyWeeks),
+		errors.Is(err, service.ErrInvalidUserSettings),
+		errors.Is(err, service.ErrInvalidTimezone),
+		errors.Is(err, service.ErrInvalidTrainingSort),
+		errors.Is(err, service.ErrInvalidPageLimit),
+		errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidTrainingFilter),
+		errors.Is(err, service.ErrInvalidCalendarRange),
+		errors.Is(err, service.ErrInvalidScheduleID),
+		errors.Is(err, service.ErrEmptyScheduleTitle),
+		errors.Is(err, service.ErrInvalidRecurrenceRule),
+		errors.Is(err, service.ErrInvalidOccurrenceDate),
+		errors.Is(err, service.ErrScheduleSourceConflict),
+		errors.Is(err, service.ErrInvalidProgramID),
+		errors.Is(err, service.ErrEmptyProgramTitle),
+		errors.Is(err, service.ErrInvalidProgramWeeks),
+		errors.Is(err, service.ErrInvalidProgramSession),
+		errors.Is(err, service.ErrInvalidEnrollmentID),
+		errors.Is(err, service.ErrInvalidProgramWeekdays),
+		errors.Is(err, service.ErrNotEnoughWeekdays),
+		errors.Is(err, service.ErrInvalidProgramStartDate),
+		errors.Is(err, service.ErrInvalidTemplateID),
+		errors.Is(err, service.ErrEmptyTemplateTitle),
+		errors.Is(err, service.ErrInvalidExerciseOrder),
+		errors.Is(err, service.ErrInvalidExerciseGroup),
+		errors.Is(err, service.ErrEmptyExerciseAlias),
+		errors.Is(err, service.ErrInvalidImportFormat),
+		errors.Is(err, service.ErrInvalidImportFile),
+		errors.Is(err, service.ErrEmptyImportFile),
+		errors.Is(err, service.ErrInvalidExportFormat),
+		errors.Is(err, service.ErrInvalidExportRange):
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.AbortWithStatusJSON(status, dto.ErrorResponse{Error: message})
+	}
+}
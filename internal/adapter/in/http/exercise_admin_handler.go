@@ -0,0 +1,326 @@
+package httpin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svcexercise "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// CreateExercise создает упражнение в каталоге
+// @Summary      Создать упражнение
+// @Description  Добавляет упражнение в каталог. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.CreateExerciseRequest true "Данные упражнения"
+// @Success      201  {object}  dto.ExerciseResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/exercises [post]
+func (h *ExerciseHandler) CreateExercise(c *gin.Context) {
+	var req dto.CreateExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	exercise, err := h.svc.CreateExercise(c.Request.Context(), svcexercise.CreateExerciseCmd{
+		Title:       req.Title,
+		Description: req.Description,
+		VideoUrl:    req.VideoURL,
+		ImageUrl:    req.ImageURL,
+		Type:        svcexercise.ExerciseType(req.Type),
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create exercise")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.exerciseToResponse(exercise))
+}
+
+// UpdateExercise изменяет упражнение в каталоге
+// @Summary      Изменить упражнение
+// @Description  Обновляет переданные поля упражнения. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        request body dto.UpdateExerciseRequest true "Изменяемые поля"
+// @Success      200  {object}  dto.ExerciseResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/exercises/{id} [put]
+func (h *ExerciseHandler) UpdateExercise(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	var req dto.UpdateExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd := svcexercise.UpdateExerciseCmd{
+		ID:          exerciseID,
+		Title:       req.Title,
+		Description: req.Description,
+		VideoUrl:    req.VideoURL,
+		ImageUrl:    req.ImageURL,
+	}
+	if req.Type != nil {
+		exerciseType := svcexercise.ExerciseType(*req.Type)
+		cmd.Type = &exerciseType
+	}
+	exercise, err := h.svc.UpdateExercise(c.Request.Context(), cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update exercise")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.exerciseToResponse(exercise))
+}
+
+// DeleteExercise удаляет упражнение из каталога
+// @Summary      Удалить упражнение
+// @Description  Удаляет упражнение, если оно не используется в тренировках, шаблонах и глобальных тренировках. Доступно только администратору
+// @Tags         admin
+// @Param        id path int64 true "Exercise ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/exercises/{id} [delete]
+func (h *ExerciseHandler) DeleteExercise(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	if err := h.svc.DeleteExercise(c.Request.Context(), exerciseID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete exercise")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AttachTagToExercise привязывает тег к упражнению
+// @Summary      Привязать тег к упражнению
+// @Description  Связывает тег с упражнением. Повторная привязка не считается ошибкой. Доступно только администратору
+// @Tags         admin
+// @Param        id path int64 true "Exercise ID"
+// @Param        tag_id path int64 true "Tag ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/exercises/{id}/tags/{tag_id} [post]
+func (h *ExerciseHandler) AttachTagToExercise(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+	tagID, err := parseInt64Param(c, "tag_id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid tag id"})
+		return
+	}
+
+	if err := h.svc.AttachTagToExercise(c.Request.Context(), exerciseID, tagID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to attach tag")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DetachTagFromExercise отвязывает тег от упражнения
+// @Summary      Отвязать тег от упражнения
+// @Description  Удаляет связь тега с упражнением. Доступно только администратору
+// @Tags         admin
+// @Param        id path int64 true "Exercise ID"
+// @Param        tag_id path int64 true "Tag ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/exercises/{id}/tags/{tag_id} [delete]
+func (h *ExerciseHandler) DetachTagFromExercise(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+	tagID, err := parseInt64Param(c, "tag_id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid tag id"})
+		return
+	}
+
+	if err := h.svc.DetachTagFromExercise(c.Request.Context(), exerciseID, tagID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to detach tag")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CreateTag создает тег
+// @Summary      Создать тег
+// @Description  Добавляет новый тег упражнений. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.TagRequest true "Данные тега"
+// @Success      201  {object}  dto.TagResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/tags [post]
+func (h *ExerciseHandler) CreateTag(c *gin.Context) {
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	tag, err := h.svc.CreateTag(c.Request.Context(), req.Type)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.tagToResponse(tag))
+}
+
+// UpdateTag изменяет тег
+// @Summary      Изменить тег
+// @Description  Переименовывает тег. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Tag ID"
+// @Param        request body dto.TagRequest true "Данные тега"
+// @Success      200  {object}  dto.TagResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/tags/{id} [put]
+func (h *ExerciseHandler) UpdateTag(c *gin.Context) {
+	tagID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid tag id"})
+		return
+	}
+
+	var req dto.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	tag, err := h.svc.UpdateTag(c.Request.Context(), tagID, req.Type)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.tagToResponse(tag))
+}
+
+// DeleteTag удаляет тег
+// @Summary      Удалить тег
+// @Description  Удаляет тег и его связи с упражнениями. Доступно только администратору
+// @Tags         admin
+// @Param        id path int64 true "Tag ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/tags/{id} [delete]
+func (h *ExerciseHandler) DeleteTag(c *gin.Context) {
+	tagID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid tag id"})
+		return
+	}
+
+	if err := h.svc.DeleteTag(c.Request.Context(), tagID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete tag")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetExerciseAliases заменяет другие названия упражнения
+// @Summary      Задать другие названия упражнения
+// @Description  Заменяет другие названия упражнения, по которым сопоставляются упражнения при импорте истории.
+// @Description  Название не может принадлежать двум упражнениям (без учета регистра). Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        request body dto.ExerciseAliasesRequest true "Другие названия"
+// @Success      200  {object}  dto.ExerciseAliasesResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/exercises/{id}/aliases [put]
+func (h *ExerciseHandler) SetExerciseAliases(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	var req dto.ExerciseAliasesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	aliases, err := h.svc.SetExerciseAliases(c.Request.Context(), exerciseID, req.Aliases)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to set exercise aliases")
+		return
+	}
+
+	c.JSON(http.StatusOK, exerciseAliasesToResponse(exerciseID, aliases))
+}
+
+func exerciseAliasesToResponse(exerciseID int64, aliases []string) dto.ExerciseAliasesResponse {
+	if aliases == nil {
+		aliases = []string{}
+	}
+	return dto.ExerciseAliasesResponse{ExerciseID: exerciseID, Aliases: aliases}
+}
@@ -24,6 +24,7 @@ func NewExerciseHandler(svc svcexercise.ExerciseService) *ExerciseHandler {
 // @Produce      json
 // @Success      200  {array}   dto.ExerciseResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /exercises [get]
 func (h *ExerciseHandler) GetAllExercises(c *gin.Context) {
 	exercises, err := h.svc.GetAllExercises(c.Request.Context())
@@ -50,6 +51,7 @@ func (h *ExerciseHandler) GetAllExercises(c *gin.Context) {
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /exercises/{id} [get]
 func (h *ExerciseHandler) GetExerciseByID(c *gin.Context) {
 	exerciseID, err := parseInt64Param(c, "id")
@@ -77,6 +79,7 @@ func (h *ExerciseHandler) GetExerciseByID(c *gin.Context) {
 // @Success      200  {array}   dto.ExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /exercises/search [get]
 func (h *ExerciseHandler) SearchExercises(c *gin.Context) {
 	var req dto.SearchExercisesRequest
@@ -111,6 +114,7 @@ func (h *ExerciseHandler) SearchExercises(c *gin.Context) {
 // @Produce      json
 // @Success      200  {array}   dto.TagResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /tags [get]
 func (h *ExerciseHandler) GetAllTags(c *gin.Context) {
 	tags, err := h.svc.GetAllTags(c.Request.Context())
@@ -137,6 +141,7 @@ func (h *ExerciseHandler) GetAllTags(c *gin.Context) {
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /tags/{id} [get]
 func (h *ExerciseHandler) GetTagByID(c *gin.Context) {
 	tagID, err := parseInt64Param(c, "id")
@@ -163,6 +168,7 @@ func (h *ExerciseHandler) GetTagByID(c *gin.Context) {
 // @Success      200  {array}   dto.TagResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /exercises/{id}/tags [get]
 func (h *ExerciseHandler) GetExerciseTags(c *gin.Context) {
 	exerciseID, err := parseInt64Param(c, "id") // Используем "exercise_id"
@@ -185,6 +191,34 @@ func (h *ExerciseHandler) GetExerciseTags(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// GetExerciseAliases получает другие названия упражнения
+// @Summary      Получить другие названия упражнения
+// @Description  Возвращает другие названия упражнения, по которым сопоставляются упражнения при импорте истории
+// @Tags         exercises
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Success      200  {object}  dto.ExerciseAliasesResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /exercises/{id}/aliases [get]
+func (h *ExerciseHandler) GetExerciseAliases(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	aliases, err := h.svc.GetExerciseAliases(c.Request.Context(), exerciseID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get exercise aliases")
+		return
+	}
+
+	c.JSON(http.StatusOK, exerciseAliasesToResponse(exerciseID, aliases))
+}
+
 // GetExercisesByMultipleTags получает упражнения по нескольким тегам
 // @Summary      Получить упражнения по нескольким тегам
 // @Description  Возвращает список упражнений, связанных со всеми указанными тегами
@@ -195,6 +229,7 @@ func (h *ExerciseHandler) GetExerciseTags(c *gin.Context) {
 // @Success      200  {array}   dto.ExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /exercises/by-tags [post]
 func (h *ExerciseHandler) GetExercisesByMultipleTags(c *gin.Context) {
 	var req dto.GetExercisesByMultipleTagsRequest
@@ -231,6 +266,7 @@ func (h *ExerciseHandler) GetExercisesByMultipleTags(c *gin.Context) {
 // @Success      200  {array}   dto.TagResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /tags/popular [get]
 func (h *ExerciseHandler) GetPopularTags(c *gin.Context) {
 	var req dto.GetPopularTagsRequest
@@ -279,6 +315,7 @@ func (h *ExerciseHandler) exerciseToResponse(exercise *svcexercise.Exercise) dto
 		Description: exercise.Description,
 		VideoURL:    &exercise.VideoUrl,
 		ImageURL:    &exercise.ImageUrl,
+		Type:        string(exercise.Type),
 		Tags:        tags,
 	}
 }
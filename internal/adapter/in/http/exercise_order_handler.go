@@ -0,0 +1,141 @@
+package httpin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// ReorderTrainedExercises меняет порядок упражнений тренировки
+// @Summary      Изменить порядок упражнений
+// @Description  Задает новый порядок упражнений тренировки одной транзакцией. В запросе должны быть перечислены
+// @Description  все упражнения тренировки ровно по одному разу
+// @Tags         trainings
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Training ID"
+// @Param        request body dto.ReorderTrainedExercisesRequest true "Новый порядок упражнений"
+// @Success      200  {object}  dto.TrainingResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trainings/{id}/exercises/order [put]
+func (h *TrainingHandler) ReorderTrainedExercises(c *gin.Context) {
+	trainingID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid training id"})
+		return
+	}
+
+	var req dto.ReorderTrainedExercisesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	training, err := h.svc.ReorderTrainedExercises(c.Request.Context(), trainingID, req.TrainedExerciseIDs)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to reorder exercises")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.trainingToResponse(training))
+}
+
+// SetTrainedExerciseGroup включает упражнение в группу
+// @Summary      Объединить упражнение в группу
+// @Description  Включает упражнение в суперсет, круг или гигантский сет. Упражнения тренировки с одинаковым group_id
+// @Description  составляют группу одного вида: они выполняются подряд, а отдых берется после всей группы
+// @Tags         training-exercises
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        request body dto.ExerciseGroupRequest true "Группа упражнения"
+// @Success      200  {object}  dto.TrainedExerciseResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/group [put]
+func (h *TrainingHandler) SetTrainedExerciseGroup(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	var req dto.ExerciseGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	group := &svctraining.ExerciseGroup{
+		ID:   req.GroupID,
+		Type: svctraining.ExerciseGroupType(req.GroupType),
+	}
+	exercise, err := h.svc.SetTrainedExerciseGroup(c.Request.Context(), exerciseID, group)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to set exercise group")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.trainedExerciseToResponse(exercise))
+}
+
+// ClearTrainedExerciseGroup исключает упражнение из группы
+// @Summary      Исключить упражнение из группы
+// @Description  Исключает упражнение из суперсета, круга или гигантского сета; отдых снова берется после упражнения
+// @Tags         training-exercises
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Success      200  {object}  dto.TrainedExerciseResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/group [delete]
+func (h *TrainingHandler) ClearTrainedExerciseGroup(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	exercise, err := h.svc.SetTrainedExerciseGroup(c.Request.Context(), exerciseID, nil)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to clear exercise group")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.trainedExerciseToResponse(exercise))
+}
+
+// exerciseGroupFromRequest собирает группу из необязательных полей group_id и group_type запроса.
+// Поля задаются только вместе; при ошибке отвечает 400 и возвращает false
+func exerciseGroupFromRequest(c *gin.Context, groupID *int32, groupType *string) (*svctraining.ExerciseGroup, bool) {
+	if groupID == nil && groupType == nil {
+		return nil, true
+	}
+	if groupID == nil || groupType == nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "group_id and group_type must be set together"})
+		return nil, false
+	}
+	return &svctraining.ExerciseGroup{ID: *groupID, Type: svctraining.ExerciseGroupType(*groupType)}, true
+}
+
+func exerciseGroupToResponse(group *svctraining.ExerciseGroup) (*int32, *string) {
+	if group == nil {
+		return nil, nil
+	}
+	id := group.ID
+	groupType := string(group.Type)
+	return &id, &groupType
+}
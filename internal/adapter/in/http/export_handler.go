@@ -0,0 +1,97 @@
+package httpin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+// exportContentTypes - тип содержимого ответа для каждого формата выгрузки
+var exportContentTypes = map[svctraining.ExportFormat]string{
+	svctraining.ExportFormatJSON:   "application/json; charset=utf-8",
+	svctraining.ExportFormatCSV:    "text/csv; charset=utf-8",
+	svctraining.ExportFormatNDJSON: "application/x-ndjson",
+}
+
+// ExportTrainings выгружает все тренировки пользователя
+// @Summary      Выгрузить тренировки
+// @Description  Выгружает тренировки пользователя с упражнениями, подходами, оценками и таймерами. Ответ пишется
+// @Description  по мере чтения из базы. json - документ {version, exported_at, trainings}, который принимает
+// @Description  POST /import/json; ndjson - тренировка на каждой строке; csv - строка на каждый подход
+// @Description  (упражнение без подходов или тренировка без упражнений занимает одну строку). Длительности - в секундах
+// @Tags         export
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param        format query string false "Формат: json (по умолчанию), csv или ndjson"
+// @Param        from query string false "Запланированы не раньше даты (YYYY-MM-DD)"
+// @Param        to query string false "Запланированы не позже даты (YYYY-MM-DD)"
+// @Success      200  "Выгрузка в выбранном формате"
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /export [get]
+func (h *TrainingHandler) ExportTrainings(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	from, ok := parseDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseDateQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	cmd := svctraining.ExportCmd{
+		UserID: uid,
+		Format: svctraining.ExportFormat(c.DefaultQuery("format", string(svctraining.ExportFormatJSON))),
+		From:   from,
+		To:     to,
+	}
+
+	w := &exportResponseWriter{c: c, format: cmd.Format}
+	if err := h.svc.ExportTrainings(c.Request.Context(), cmd, w); err != nil {
+		if !w.started {
+			abortWithServiceError(c, err, http.StatusInternalServerError, "failed to export trainings")
+			return
+		}
+		// Заголовки уже отправлены: остается оборвать выгрузку
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": uid.String(),
+			"format":  cmd.Format,
+		})
+		logging.Error(err, "ExportTrainings", jsonData, "export interrupted")
+		c.Abort()
+	}
+}
+
+// exportResponseWriter отправляет заголовки выгрузки при первой записи, чтобы ошибки,
+// возникшие до начала выгрузки, отдавались обычным ответом с ошибкой
+type exportResponseWriter struct {
+	c       *gin.Context
+	format  svctraining.ExportFormat
+	started bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		filename := fmt.Sprintf("trainings-%s.%s", time.Now().Format("2006-01-02"), w.format)
+		w.c.Header("Content-Type", exportContentTypes[w.format])
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
@@ -0,0 +1,179 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// CreateGlobalTraining создает глобальную тренировку
+// @Summary      Создать глобальную тренировку
+// @Description  Создает глобальную тренировку с упорядоченным списком упражнений и предписанной нагрузкой. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.GlobalTrainingRequest true "Данные глобальной тренировки"
+// @Success      201  {object}  dto.GlobalTrainingWithTagsResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/global-trainings [post]
+func (h *TrainingHandler) CreateGlobalTraining(c *gin.Context) {
+	var req dto.GlobalTrainingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd, ok := globalTrainingRequestToCmd(c, req)
+	if !ok {
+		return
+	}
+
+	globalTraining, err := h.svc.CreateGlobalTraining(c.Request.Context(), cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create global training")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.globalTrainingWithTagsToResponse(globalTraining))
+}
+
+// UpdateGlobalTraining изменяет глобальную тренировку
+// @Summary      Изменить глобальную тренировку
+// @Description  Полностью заменяет данные и список упражнений глобальной тренировки. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Global Training ID"
+// @Param        request body dto.GlobalTrainingRequest true "Данные глобальной тренировки"
+// @Success      200  {object}  dto.GlobalTrainingWithTagsResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/global-trainings/{id} [put]
+func (h *TrainingHandler) UpdateGlobalTraining(c *gin.Context) {
+	globalTrainingID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid global training id"})
+		return
+	}
+
+	var req dto.GlobalTrainingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd, ok := globalTrainingRequestToCmd(c, req)
+	if !ok {
+		return
+	}
+
+	globalTraining, err := h.svc.UpdateGlobalTraining(c.Request.Context(), globalTrainingID, cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update global training")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.globalTrainingWithTagsToResponse(globalTraining))
+}
+
+// DeleteGlobalTraining удаляет глобальную тренировку
+// @Summary      Удалить глобальную тренировку
+// @Description  Удаляет глобальную тренировку вместе со слотами. Уже назначенные пользователям тренировки не затрагиваются. Тренировку, входящую в программы, удалить нельзя. Доступно только администратору
+// @Tags         admin
+// @Param        id path int64 true "Global Training ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/global-trainings/{id} [delete]
+func (h *TrainingHandler) DeleteGlobalTraining(c *gin.Context) {
+	globalTrainingID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid global training id"})
+		return
+	}
+
+	if err := h.svc.DeleteGlobalTraining(c.Request.Context(), globalTrainingID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete global training")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// globalTrainingRequestToCmd переводит запрос в команду сервиса.
+// При ошибке разбора длительностей отвечает 400 и возвращает false
+func globalTrainingRequestToCmd(c *gin.Context, req dto.GlobalTrainingRequest) (svctraining.GlobalTrainingCmd, bool) {
+	cmd := svctraining.GlobalTrainingCmd{
+		Title:       req.Title,
+		Description: req.Description,
+		Level:       req.Level,
+	}
+
+	exercises, ok := exerciseSlotRequestsToCmd(c, req.Exercises)
+	cmd.Exercises = exercises
+	return cmd, ok
+}
+
+// exerciseSlotRequestsToCmd переводит упражнения с предписанной нагрузкой в команды сервиса.
+// При ошибке разбора длительностей отвечает 400 и возвращает false
+func exerciseSlotRequestsToCmd(c *gin.Context, reqs []dto.GlobalTrainingExerciseRequest) ([]svctraining.GlobalTrainingExerciseCmd, bool) {
+	exercises := make([]svctraining.GlobalTrainingExerciseCmd, 0, len(reqs))
+	for _, ex := range reqs {
+		var weight *decimal.Decimal
+		if ex.Weight != nil {
+			w := decimal.NewFromFloat(*ex.Weight)
+			weight = &w
+		}
+
+		var duration, rest *time.Duration
+		if ex.Duration != nil {
+			d, err := time.ParseDuration(*ex.Duration)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid duration format, use duration format like '1h30m'"})
+				return nil, false
+			}
+			duration = &d
+		}
+		if ex.Rest != nil {
+			d, err := time.ParseDuration(*ex.Rest)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid rest format, use duration format like '1h30m'"})
+				return nil, false
+			}
+			rest = &d
+		}
+
+		group, ok := exerciseGroupFromRequest(c, ex.GroupID, ex.GroupType)
+		if !ok {
+			return nil, false
+		}
+
+		exercises = append(exercises, svctraining.GlobalTrainingExerciseCmd{
+			ExerciseID: ex.ExerciseID,
+			Approaches: ex.Approaches,
+			Reps:       ex.Reps,
+			Weight:     weight,
+			Duration:   duration,
+			Rest:       rest,
+			Group:      group,
+		})
+	}
+
+	return exercises, true
+}
@@ -0,0 +1,172 @@
+package httpin
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// maxImportFileSize - наибольший размер загружаемой выгрузки
+const maxImportFileSize = 10 << 20
+
+// ImportHistory импортирует историю тренировок из выгрузки
+// @Summary      Импортировать историю тренировок
+// @Description  Загружает CSV-выгрузку Strong или Hevy, выгрузку json этого сервиса или файл кардио-активности
+// @Description  GPX, TCX или FIT с часов (поле file формы multipart или тело запроса, до 10 МБ). Активность из файла
+// @Description  GPX, TCX или FIT становится тренировкой с одним кардио-упражнением (дистанция, время в движении,
+// @Description  набор высоты, пульс, темп), время начала и конца берется из файла; упражнение ищется в каталоге
+// @Description  по виду активности ("Бег", "Велосипед", "Ходьба", "Поход", "Плавание" или "Кардио").
+// @Description  Упражнения сопоставляются с каталогом точно по названию, по другим названиям и приблизительно;
+// @Description  подходы несопоставленных упражнений пропускаются. Тренировка с тем же названием в тот же день
+// @Description  считается уже импортированной. Все тренировки создаются завершенными одной транзакцией.
+// @Description  При dry_run=true ничего не сохраняется, возвращается только отчет
+// @Tags         import
+// @Accept       mpfd
+// @Accept       text/csv
+// @Accept       json
+// @Accept       application/gpx+xml
+// @Accept       application/vnd.garmin.tcx+xml
+// @Accept       octet-stream
+// @Produce      json
+// @Param        format path string true "Формат выгрузки: strong, hevy, json, gpx, tcx или fit"
+// @Param        file formData file false "Файл выгрузки"
+// @Param        dry_run query bool false "Пробный запуск без сохранения"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {object}  dto.ImportReportResponse "Пробный запуск"
+// @Success      201  {object}  dto.ImportReportResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      413  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /import/{format} [post]
+func (h *TrainingHandler) ImportHistory(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	dryRun := false
+	if value := c.Query("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid dry_run value, use true or false"})
+			return
+		}
+		dryRun = parsed
+	}
+
+	file, ok := readImportFile(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.ImportHistory(c.Request.Context(), svctraining.ImportHistoryCmd{
+		UserID: uid,
+		Format: svctraining.ImportFormat(c.Param("format")),
+		File:   file,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to import training history")
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, importReportToResponse(report))
+}
+
+// readImportFile читает выгрузку из поля file формы multipart или из тела запроса.
+// Выгрузка больше maxImportFileSize отклоняется с 413; при ошибке возвращает false
+func readImportFile(c *gin.Context) (io.Reader, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	var data []byte
+	var err error
+	if c.ContentType() == gin.MIMEMultipartPOSTForm {
+		var header *multipart.FileHeader
+		header, err = c.FormFile("file")
+		if err == nil {
+			var f multipart.File
+			if f, err = header.Open(); err == nil {
+				defer f.Close()
+				data, err = io.ReadAll(f)
+			}
+		}
+	} else {
+		data, err = io.ReadAll(c.Request.Body)
+	}
+
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: "import file must not exceed 10 MB"})
+		return nil, false
+	case errors.Is(err, http.ErrMissingFile):
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "file is required"})
+		return nil, false
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "cannot read import file"})
+		return nil, false
+	}
+	return bytes.NewReader(data), true
+}
+
+func importReportToResponse(report *svctraining.ImportReport) dto.ImportReportResponse {
+	resp := dto.ImportReportResponse{
+		Format:      string(report.Format),
+		DryRun:      report.DryRun,
+		Workouts:    report.Workouts,
+		Imported:    report.Imported,
+		Sets:        report.Sets,
+		Matches:     make([]dto.ExerciseMatchResponse, 0, len(report.Matches)),
+		Unmatched:   make([]dto.UnmatchedExerciseResponse, 0, len(report.Unmatched)),
+		Skipped:     make([]dto.SkippedWorkoutResponse, 0, len(report.Skipped)),
+		Cardio:      make([]dto.RejectedCardioResponse, 0, len(report.Cardio)),
+		TrainingIDs: report.TrainingIDs,
+	}
+	if resp.TrainingIDs == nil {
+		resp.TrainingIDs = []int64{}
+	}
+	for _, m := range report.Matches {
+		resp.Matches = append(resp.Matches, dto.ExerciseMatchResponse{
+			Name:          m.Name,
+			ExerciseID:    m.ExerciseID,
+			ExerciseTitle: m.ExerciseTitle,
+			Kind:          string(m.Kind),
+			Similarity:    math.Round(m.Similarity*100) / 100,
+		})
+	}
+	for _, u := range report.Unmatched {
+		resp.Unmatched = append(resp.Unmatched, dto.UnmatchedExerciseResponse{Name: u.Name, Sets: u.Sets})
+	}
+	for _, w := range report.Skipped {
+		resp.Skipped = append(resp.Skipped, dto.SkippedWorkoutResponse{
+			Title:  w.Title,
+			Date:   w.Date.Format("2006-01-02"),
+			Reason: string(w.Reason),
+		})
+	}
+	for _, r := range report.Cardio {
+		resp.Cardio = append(resp.Cardio, dto.RejectedCardioResponse{
+			Name:          r.Name,
+			ExerciseTitle: r.ExerciseTitle,
+			Date:          r.Date.Format("2006-01-02"),
+			Reason:        r.Reason,
+		})
+	}
+	return resp
+}
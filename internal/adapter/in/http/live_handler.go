@@ -0,0 +1,113 @@
+package httpin
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// liveHeartbeatInterval - как часто в простаивающий поток пишется heartbeat,
+// чтобы прокси и балансировщики не закрывали соединение
+const liveHeartbeatInterval = 15 * time.Second
+
+// StreamTraining транслирует живую сессию тренировки
+// @Summary      Живая сессия тренировки
+// @Description  Server-Sent Events поток изменений тренировки. Первым приходит событие snapshot с текущим состоянием тренировки,
+// @Description  затем события training.started, training.paused, training.resumed, training.completed, exercise.added, exercise.updated,
+// @Description  exercise.removed, exercises.reordered, set.added, set.updated, set.removed и sets.reordered с данными dto.LiveEventResponse.
+// @Description  В простое раз в 15 секунд приходит событие heartbeat. Если клиент не успевает читать события, а также при остановке сервера
+// @Description  поток закрывается: клиент переподключается и получает новый snapshot
+// @Tags         trainings
+// @Produce      text/event-stream
+// @Param        id path int64 true "Training ID"
+// @Success      200  {object}  dto.LiveEventResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trainings/{id}/live [get]
+func (h *TrainingHandler) StreamTraining(c *gin.Context) {
+	trainingID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid training id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	events, unsubscribe, err := h.svc.SubscribeTraining(ctx, trainingID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to subscribe to training")
+		return
+	}
+	defer unsubscribe()
+
+	// Снимок читается после подписки, поэтому изменения между ними не теряются
+	training, err := h.svc.GetTrainingWithExercises(ctx, trainingID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get training")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("snapshot", h.trainingToResponse(training))
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(liveHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-h.shutdown:
+			return false
+		case event, ok := <-events:
+			// Шина закрывает подписку, если клиент не успевает читать события;
+			// после переподключения он получит новый snapshot
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), h.liveEventToResponse(event))
+			return true
+		case t := <-heartbeat.C:
+			c.SSEvent("heartbeat", t.UTC().Format(time.RFC3339))
+			return true
+		}
+	})
+}
+
+func (h *TrainingHandler) liveEventToResponse(event svctraining.LiveEvent) dto.LiveEventResponse {
+	resp := dto.LiveEventResponse{
+		Type:              string(event.Type),
+		TrainingID:        event.TrainingID,
+		OccurredAt:        event.OccurredAt.Format(time.RFC3339),
+		TrainedExerciseID: event.TrainedExerciseID,
+		SetID:             event.SetID,
+	}
+
+	if event.Training != nil {
+		training := h.trainingToResponse(event.Training)
+		resp.Training = &training
+	}
+	if event.Exercise != nil {
+		exercise := h.trainedExerciseToResponse(event.Exercise)
+		resp.Exercise = &exercise
+	}
+	if event.Set != nil {
+		set := h.trainedSetToResponse(event.Set)
+		resp.Set = &set
+	}
+	for _, set := range event.Sets {
+		resp.Sets = append(resp.Sets, h.trainedSetToResponse(set))
+	}
+
+	return resp
+}
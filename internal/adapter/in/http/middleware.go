@@ -1 +1,255 @@
 package httpin
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+// AuthConfig описывает ключи для проверки JWT
+type AuthConfig struct {
+	HMACSecret string // Секрет для HS256
+	JWKSFile   string // Путь к JWKS с публичными ключами для RS256
+	Issuer     string // Ожидаемый iss (опционально)
+	Audience   string // Ожидаемый aud (опционально)
+}
+
+// authClaims - стандартные claims токена и роли пользователя
+type authClaims struct {
+	jwt.RegisteredClaims
+	Role  string   `json:"role,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+func (c *authClaims) roles() []string {
+	roles := c.Roles
+	if c.Role != "" {
+		roles = append(roles, c.Role)
+	}
+	return roles
+}
+
+// minHMACSecretLen - минимальная длина секрета HS256 (RFC 7518: не короче выхода SHA-256)
+const minHMACSecretLen = 32
+
+// placeholderSecrets - значения-заглушки из примеров конфигурации, с которыми сервис не стартует
+var placeholderSecrets = map[string]bool{
+	"change-me": true,
+	"changeme":  true,
+	"secret":    true,
+}
+
+type authenticator struct {
+	hmacSecret []byte
+	rsaKeys    map[string]*rsa.PublicKey
+	parser     *jwt.Parser
+}
+
+// NewAuthMiddleware создает middleware, которое проверяет Bearer JWT
+// и кладет subject токена (UUID пользователя) в контекст запроса
+func NewAuthMiddleware(cfg AuthConfig) (gin.HandlerFunc, error) {
+	a := &authenticator{
+		rsaKeys: make(map[string]*rsa.PublicKey),
+	}
+
+	var methods []string
+	if cfg.HMACSecret != "" {
+		if err := checkHMACSecret(cfg.HMACSecret); err != nil {
+			return nil, err
+		}
+		a.hmacSecret = []byte(cfg.HMACSecret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		a.rsaKeys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("auth: neither hmac secret nor jwks file is configured")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(opts...)
+
+	return a.handle, nil
+}
+
+// checkHMACSecret не дает запустить сервис с публично известным или слишком коротким секретом:
+// по нему любой может выпустить токен на чужой sub или с ролью admin
+func checkHMACSecret(secret string) error {
+	if placeholderSecrets[strings.ToLower(strings.TrimSpace(secret))] {
+		return errors.New("auth: hmac secret is a placeholder, set APP_AUTH_HMACSECRET")
+	}
+	if len(secret) < minHMACSecretLen {
+		return fmt.Errorf("auth: hmac secret must be at least %d bytes", minHMACSecretLen)
+	}
+	return nil
+}
+
+func (a *authenticator) handle(c *gin.Context) {
+	raw, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(raw) == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "missing bearer token"})
+		return
+	}
+
+	token, err := a.parser.ParseWithClaims(strings.TrimSpace(raw), &authClaims{}, a.keyFunc)
+	if err != nil || !token.Valid {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"path": c.FullPath(),
+		})
+		logging.Warn("AuthMiddleware", jsonData, fmt.Sprintf("token rejected: %v", err))
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid token"})
+		return
+	}
+
+	subject, err := token.Claims.GetSubject()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid token subject"})
+		return
+	}
+	userID, err := uuid.Parse(subject)
+	if err != nil || userID == uuid.Nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "invalid token subject"})
+		return
+	}
+
+	ctx := domain.ContextWithUserID(c.Request.Context(), userID)
+	if claims, ok := token.Claims.(*authClaims); ok {
+		ctx = domain.ContextWithRoles(ctx, claims.roles())
+	}
+	c.Request = c.Request.WithContext(ctx)
+	c.Next()
+}
+
+// RequireRole пропускает запрос дальше только если в токене есть нужная роль.
+// Должен стоять после middleware аутентификации
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !domain.HasRole(c.Request.Context(), role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// TimezoneHeader - заголовок, в котором клиент может передать свой часовой пояс IANA
+const TimezoneHeader = "X-Timezone"
+
+// TimezoneMiddleware кладет в контекст запроса часовой пояс из заголовка X-Timezone.
+// Без заголовка используется пояс из настроек пользователя
+func TimezoneMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := strings.TrimSpace(c.GetHeader(TimezoneHeader))
+		if name == "" {
+			c.Next()
+			return
+		}
+
+		loc, err := time.LoadLocation(name)
+		if err != nil || name == "Local" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid X-Timezone header, use IANA time zone name like Asia/Novosibirsk"})
+			return
+		}
+		c.Request = c.Request.WithContext(domain.ContextWithLocation(c.Request.Context(), loc))
+		c.Next()
+	}
+}
+
+func (a *authenticator) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return a.hmacSecret, nil
+	case jwt.SigningMethodRS256.Alg():
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := a.rsaKeys[kid]; ok {
+			return key, nil
+		}
+		// Токен без kid допускаем только при единственном ключе в наборе
+		if kid == "" && len(a.rsaKeys) == 1 {
+			for _, key := range a.rsaKeys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+func loadJWKS(path string) (map[string]*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: read jwks file: %w", err)
+	}
+
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("auth: parse jwks file: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("auth: invalid modulus for key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("auth: invalid exponent for key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("auth: jwks file contains no RSA signing keys")
+	}
+
+	return keys, nil
+}
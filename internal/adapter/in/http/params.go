@@ -4,6 +4,9 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
 )
 
 func parseInt64Param(c *gin.Context, param string) (int64, error) {
@@ -13,3 +16,8 @@ func parseInt64Param(c *gin.Context, param string) (int64, error) {
 func parseInt64Query(c *gin.Context, query string) (int64, error) {
 	return strconv.ParseInt(c.Query(query), 10, 64)
 }
+
+// currentUserID возвращает пользователя, аутентифицированного middleware
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	return domain.UserIDFromContext(c.Request.Context())
+}
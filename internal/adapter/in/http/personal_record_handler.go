@@ -0,0 +1,113 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetPersonalRecords получает личные рекорды пользователя
+// @Summary      Получить личные рекорды
+// @Description  Возвращает текущие рекорды пользователя по всем упражнениям: максимальный вес, максимум повторений
+// @Description  для каждого веса, оценку 1ПМ, максимальную длительность и максимальный объем
+// @Tags         records
+// @Produce      json
+// @Success      200  {array}   dto.PersonalRecordResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /records [get]
+func (h *TrainingHandler) GetPersonalRecords(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	records, err := h.svc.GetPersonalRecords(c.Request.Context(), uid)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get personal records")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.personalRecordPtrsToResponse(records))
+}
+
+// GetExercisePersonalRecords получает личные рекорды пользователя в упражнении
+// @Summary      Получить рекорды по упражнению
+// @Description  Возвращает текущие рекорды пользователя в указанном упражнении
+// @Tags         records
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Success      200  {array}   dto.PersonalRecordResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /exercises/{id}/records [get]
+func (h *TrainingHandler) GetExercisePersonalRecords(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	records, err := h.svc.GetExercisePersonalRecords(c.Request.Context(), uid, exerciseID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get personal records")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.personalRecordPtrsToResponse(records))
+}
+
+func (h *TrainingHandler) personalRecordToResponse(record *svctraining.PersonalRecord) dto.PersonalRecordResponse {
+	value, _ := record.Value.Float64()
+
+	var weight *float64
+	if record.Weight != nil {
+		f, _ := record.Weight.Float64()
+		weight = &f
+	}
+
+	return dto.PersonalRecordResponse{
+		ID:                record.ID,
+		ExerciseID:        record.ExerciseID,
+		ExerciseTitle:     record.ExerciseTitle,
+		TrainedExerciseID: record.TrainedExerciseID,
+		TrainingID:        record.TrainingID,
+		Type:              string(record.Type),
+		Value:             value,
+		Weight:            weight,
+		AchievedAt:        record.AchievedAt.Format(time.RFC3339),
+	}
+}
+
+func (h *TrainingHandler) personalRecordsToResponse(records []svctraining.PersonalRecord) []dto.PersonalRecordResponse {
+	if len(records) == 0 {
+		return nil
+	}
+	resp := make([]dto.PersonalRecordResponse, 0, len(records))
+	for i := range records {
+		resp = append(resp, h.personalRecordToResponse(&records[i]))
+	}
+	return resp
+}
+
+func (h *TrainingHandler) personalRecordPtrsToResponse(records []*svctraining.PersonalRecord) []dto.PersonalRecordResponse {
+	resp := make([]dto.PersonalRecordResponse, 0, len(records))
+	for _, record := range records {
+		resp = append(resp, h.personalRecordToResponse(record))
+	}
+	return resp
+}
@@ -0,0 +1,135 @@
+package httpin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// CreateProgram создает программу
+// @Summary      Создать программу
+// @Description  Создает многонедельную программу из тренировочных дней, ссылающихся на глобальные тренировки,
+// @Description  с прибавками веса и повторений за неделю. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.ProgramRequest true "Данные программы"
+// @Success      201  {object}  dto.ProgramResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/programs [post]
+func (h *TrainingHandler) CreateProgram(c *gin.Context) {
+	var req dto.ProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	program, err := h.svc.CreateProgram(c.Request.Context(), programRequestToCmd(req))
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create program")
+		return
+	}
+
+	c.JSON(http.StatusCreated, programToResponse(program))
+}
+
+// UpdateProgram изменяет программу
+// @Summary      Изменить программу
+// @Description  Полностью заменяет данные и тренировочные дни программы. Тренировки уже записанных пользователей
+// @Description  не меняются. Доступно только администратору
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Program ID"
+// @Param        request body dto.ProgramRequest true "Данные программы"
+// @Success      200  {object}  dto.ProgramResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/programs/{id} [put]
+func (h *TrainingHandler) UpdateProgram(c *gin.Context) {
+	programID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid program id"})
+		return
+	}
+
+	var req dto.ProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	program, err := h.svc.UpdateProgram(c.Request.Context(), programID, programRequestToCmd(req))
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update program")
+		return
+	}
+
+	c.JSON(http.StatusOK, programToResponse(program))
+}
+
+// DeleteProgram удаляет программу
+// @Summary      Удалить программу
+// @Description  Удаляет программу вместе с записями на нее. Созданные пользователям тренировки остаются. Доступно только администратору
+// @Tags         admin
+// @Param        id path int64 true "Program ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /admin/programs/{id} [delete]
+func (h *TrainingHandler) DeleteProgram(c *gin.Context) {
+	programID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid program id"})
+		return
+	}
+
+	if err := h.svc.DeleteProgram(c.Request.Context(), programID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete program")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// programRequestToCmd переводит запрос в команду сервиса
+func programRequestToCmd(req dto.ProgramRequest) svctraining.ProgramCmd {
+	cmd := svctraining.ProgramCmd{
+		Title:       req.Title,
+		Description: req.Description,
+		Level:       req.Level,
+		Weeks:       req.Weeks,
+		Sessions:    make([]svctraining.ProgramSessionCmd, 0, len(req.Sessions)),
+	}
+
+	for _, session := range req.Sessions {
+		var increment *decimal.Decimal
+		if session.WeightIncrement != nil {
+			w := decimal.NewFromFloat(*session.WeightIncrement)
+			increment = &w
+		}
+
+		cmd.Sessions = append(cmd.Sessions, svctraining.ProgramSessionCmd{
+			Week:             session.Week,
+			Day:              session.Day,
+			GlobalTrainingID: session.GlobalTrainingID,
+			WeightIncrement:  increment,
+			RepsIncrement:    session.RepsIncrement,
+		})
+	}
+	return cmd
+}
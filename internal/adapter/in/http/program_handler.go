@@ -0,0 +1,289 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetPrograms получает список программ
+// @Summary      Получить программы
+// @Description  Возвращает все многонедельные программы без тренировочных дней, от простых к сложным
+// @Tags         programs
+// @Produce      json
+// @Success      200  {array}   dto.ProgramResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /programs [get]
+func (h *TrainingHandler) GetPrograms(c *gin.Context) {
+	programs, err := h.svc.GetPrograms(c.Request.Context())
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get programs")
+		return
+	}
+
+	resp := make([]dto.ProgramResponse, 0, len(programs))
+	for _, program := range programs {
+		resp = append(resp, programToResponse(program))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetProgram получает программу
+// @Summary      Получить программу
+// @Description  Возвращает программу с тренировочными днями и правилами прогрессии
+// @Tags         programs
+// @Produce      json
+// @Param        id path int64 true "Program ID"
+// @Success      200  {object}  dto.ProgramResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /programs/{id} [get]
+func (h *TrainingHandler) GetProgram(c *gin.Context) {
+	programID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid program id"})
+		return
+	}
+
+	program, err := h.svc.GetProgram(c.Request.Context(), programID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get program")
+		return
+	}
+
+	c.JSON(http.StatusOK, programToResponse(program))
+}
+
+// EnrollInProgram записывает пользователя на программу
+// @Summary      Записаться на программу
+// @Description  Создает тренировки всех недель программы. Неделя N начинается через 7*(N-1) дней после даты начала,
+// @Description  ее тренировки по порядку ставятся на выбранные дни недели. Нагрузка глобальной тренировки
+// @Description  увеличивается на прибавки дня программы за каждую неделю после первой
+// @Tags         programs
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Program ID"
+// @Param        request body dto.EnrollProgramRequest true "Дата начала и дни недели"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      201  {object}  dto.EnrollmentResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /programs/{id}/enroll [post]
+func (h *TrainingHandler) EnrollInProgram(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	programID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid program id"})
+		return
+	}
+
+	var req dto.EnrollProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd := svctraining.EnrollProgramCmd{
+		UserID:    uid,
+		ProgramID: programID,
+		Weekdays:  make([]time.Weekday, 0, len(req.Weekdays)),
+	}
+	for _, day := range req.Weekdays {
+		cmd.Weekdays = append(cmd.Weekdays, time.Weekday(day))
+	}
+	if req.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid start_date format, use YYYY-MM-DD"})
+			return
+		}
+		cmd.StartDate = &startDate
+	}
+
+	progress, err := h.svc.EnrollInProgram(c.Request.Context(), cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to enroll in program")
+		return
+	}
+
+	c.JSON(http.StatusCreated, enrollmentToResponse(progress))
+}
+
+// GetEnrollments получает записи пользователя на программы
+// @Summary      Получить записи на программы
+// @Description  Возвращает записи пользователя на программы с прогрессом прохождения, начиная с последней
+// @Tags         programs
+// @Produce      json
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {array}   dto.EnrollmentResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /enrollments [get]
+func (h *TrainingHandler) GetEnrollments(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	enrollments, err := h.svc.GetEnrollments(c.Request.Context(), uid)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get enrollments")
+		return
+	}
+
+	resp := make([]dto.EnrollmentResponse, 0, len(enrollments))
+	for _, progress := range enrollments {
+		resp = append(resp, enrollmentToResponse(progress))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetEnrollment получает прогресс прохождения программы
+// @Summary      Получить прогресс по программе
+// @Description  Возвращает запись на программу: число выполненных и пропущенных тренировок, процент выполнения,
+// @Description  текущую неделю и ближайшую невыполненную тренировку
+// @Tags         programs
+// @Produce      json
+// @Param        id path int64 true "Enrollment ID"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {object}  dto.EnrollmentResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /enrollments/{id} [get]
+func (h *TrainingHandler) GetEnrollment(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	enrollmentID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid enrollment id"})
+		return
+	}
+
+	progress, err := h.svc.GetEnrollmentProgress(c.Request.Context(), uid, enrollmentID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get enrollment")
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollmentToResponse(progress))
+}
+
+// Unenroll отменяет запись на программу
+// @Summary      Отменить запись на программу
+// @Description  Удаляет запись и еще не начатые тренировки программы начиная с сегодняшнего дня. Прошедшие тренировки остаются
+// @Tags         programs
+// @Param        id path int64 true "Enrollment ID"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /enrollments/{id} [delete]
+func (h *TrainingHandler) Unenroll(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	enrollmentID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid enrollment id"})
+		return
+	}
+
+	if err := h.svc.Unenroll(c.Request.Context(), uid, enrollmentID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete enrollment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func programToResponse(program *svctraining.Program) dto.ProgramResponse {
+	resp := dto.ProgramResponse{
+		ID:          program.ID,
+		Title:       program.Title,
+		Description: program.Description,
+		Level:       program.Level,
+		Weeks:       program.Weeks,
+	}
+	for _, session := range program.Sessions {
+		s := dto.ProgramSessionResponse{
+			ID:                  session.ID,
+			Week:                session.Week,
+			Day:                 session.Day,
+			GlobalTrainingID:    session.GlobalTrainingID,
+			GlobalTrainingTitle: session.GlobalTrainingTitle,
+			RepsIncrement:       session.RepsIncrement,
+		}
+		if session.WeightIncrement != nil {
+			increment, _ := session.WeightIncrement.Float64()
+			s.WeightIncrement = &increment
+		}
+		resp.Sessions = append(resp.Sessions, s)
+	}
+	return resp
+}
+
+func enrollmentToResponse(progress *svctraining.ProgramProgress) dto.EnrollmentResponse {
+	enrollment := progress.Enrollment
+	resp := dto.EnrollmentResponse{
+		ID:                enrollment.ID,
+		ProgramID:         enrollment.ProgramID,
+		ProgramTitle:      enrollment.ProgramTitle,
+		Weeks:             enrollment.Weeks,
+		StartDate:         enrollment.StartDate.Format("2006-01-02"),
+		Weekdays:          make([]int, 0, len(enrollment.Weekdays)),
+		CreatedAt:         enrollment.CreatedAt.Format(time.RFC3339),
+		TotalSessions:     progress.TotalSessions,
+		CompletedSessions: progress.CompletedSessions,
+		SkippedSessions:   progress.SkippedSessions,
+		CompletionPercent: progress.CompletionPercent,
+		CurrentWeek:       progress.CurrentWeek,
+	}
+	for _, day := range enrollment.Weekdays {
+		resp.Weekdays = append(resp.Weekdays, int(day))
+	}
+	if next := progress.NextTraining; next != nil {
+		resp.NextTraining = &dto.EnrollmentTrainingResponse{
+			TrainingID:  next.TrainingID,
+			Title:       next.Title,
+			PlannedDate: next.PlannedDate.Format("2006-01-02"),
+			Week:        next.Week,
+			Day:         next.Day,
+		}
+	}
+	return resp
+}
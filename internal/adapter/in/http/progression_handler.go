@@ -0,0 +1,66 @@
+package httpin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetExerciseSuggestion предлагает нагрузку на следующую тренировку
+// @Summary      Получить предлагаемую нагрузку
+// @Description  Подбирает вес, подходы и повторения по последним завершенным тренировкам с упражнением.
+// @Description  Если в последней тренировке выполнены все целевые повторения, вес растет на шаг прогрессии;
+// @Description  если нет - нагрузка повторяется, а после нескольких неудач подряд вес снижается
+// @Tags         records
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Success      200  {object}  dto.LoadSuggestionResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /exercises/{id}/suggestion [get]
+func (h *TrainingHandler) GetExerciseSuggestion(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	suggestion, err := h.svc.SuggestExerciseLoad(c.Request.Context(), uid, exerciseID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get exercise suggestion")
+		return
+	}
+
+	c.JSON(http.StatusOK, loadSuggestionToResponse(suggestion))
+}
+
+func loadSuggestionToResponse(suggestion *svctraining.LoadSuggestion) dto.LoadSuggestionResponse {
+	resp := dto.LoadSuggestionResponse{
+		ExerciseID:     suggestion.ExerciseID,
+		Approaches:     suggestion.Approaches,
+		Reps:           suggestion.Reps,
+		Reason:         string(suggestion.Reason),
+		FailedSessions: suggestion.FailedSessions,
+	}
+	if suggestion.Weight != nil {
+		weight, _ := suggestion.Weight.Float64()
+		resp.Weight = &weight
+	}
+	if suggestion.LastSession != nil {
+		lastSession := suggestion.LastSession.Format("2006-01-02")
+		resp.LastSession = &lastSession
+	}
+	return resp
+}
@@ -2,12 +2,17 @@
 // @version 1.0
 // @description Сервис информации о тренировках и упражнения
 // @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer JWT (HS256 или RS256), subject - UUID пользователя
 package httpin
 
 import (
 	"github.com/gin-gonic/gin"
 
 	_ "github.com/EnduranNSU/trainings/docs"
+	"github.com/EnduranNSU/trainings/internal/domain"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -17,7 +22,7 @@ import (
 // @version 1.0
 // @description Сервис информации о тренировках и упражнения
 // @BasePath /api/v1
-func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Engine {
+func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler, auth gin.HandlerFunc) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger(), gin.Recovery())
 
@@ -25,7 +30,10 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	api := r.Group("/api/v1")
+	// Календарь по ключу подписки открывается приложениями календаря без аутентификации
+	r.GET(calendarFeedPath+":file", training.GetCalendarFeed)
+
+	api := r.Group("/api/v1", auth, TimezoneMiddleware())
 	{
 		// Training routes
 		trainings := api.Group("/trainings")
@@ -34,6 +42,8 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 			trainings.GET("", training.GetTrainingsByUser)
 			trainings.POST("", training.CreateTraining)
 			trainings.GET("/stats", training.GetUserTrainingStats)
+			trainings.GET("/consistency", training.GetTrainingConsistency)
+			trainings.GET("/calendar", training.GetTrainingCalendar)
 			trainings.GET("/current", training.GetCurrentTraining)
 			trainings.GET("/today", training.GetTodaysTraining)
 			
@@ -43,6 +53,8 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 			trainings.DELETE("/:id", training.DeleteTraining)
 			trainings.GET("/:id/stats", training.GetTrainingStats)
 			trainings.GET("/:id/calculate-time", training.CalculateTrainingTotalTime)
+			trainings.GET("/:id/live", training.StreamTraining)
+			trainings.PUT("/:id/exercises/order", training.ReorderTrainedExercises)
 			
 			// Действия с тренировкой
 			trainings.PATCH("/:id/complete", training.CompleteTraining)
@@ -50,6 +62,8 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 			trainings.PATCH("/:id/start", training.StartTraining)
 			trainings.PATCH("/:id/pause", training.PauseTraining)
 			trainings.PATCH("/:id/resume", training.ResumeTraining)
+			trainings.POST("/:id/clone", training.CloneTraining)
+			trainings.POST("/:id/template", training.CreateTemplateFromTraining)
 			
 			// Таймеры тренировки
 			trainings.PATCH("/:id/timers", training.UpdateTrainingTimers)
@@ -61,11 +75,20 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 			trainingExercises.POST("", training.AddExerciseToTraining)
 			trainingExercises.PUT("/:id", training.UpdateTrainedExercise)
 			trainingExercises.DELETE("", training.RemoveExerciseFromTraining)
+			trainingExercises.PUT("/:id/group", training.SetTrainedExerciseGroup)
+			trainingExercises.DELETE("/:id/group", training.ClearTrainedExerciseGroup)
 			
 			// Действия с упражнениями
 			trainingExercises.PATCH("/:id/time", training.UpdateExerciseTime)
 			trainingExercises.PATCH("/:id/rest-time", training.UpdateExerciseRestTime)
 			trainingExercises.PATCH("/:id/doing-time", training.UpdateExerciseDoingTime)
+
+			// Подходы упражнения
+			trainingExercises.GET("/:id/sets", training.GetTrainedSets)
+			trainingExercises.POST("/:id/sets", training.AddTrainedSet)
+			trainingExercises.PUT("/:id/sets/order", training.ReorderTrainedSets)
+			trainingExercises.PUT("/:id/sets/:set_id", training.UpdateTrainedSet)
+			trainingExercises.DELETE("/:id/sets/:set_id", training.DeleteTrainedSet)
 		}
 
 		// Global trainings routes
@@ -79,6 +102,48 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 			globalTrainings.GET("/:id", training.GetGlobalTrainingById)
 		}
 
+		// Schedule routes
+		schedules := api.Group("/schedules")
+		{
+			schedules.GET("", training.GetSchedules)
+			schedules.POST("", training.CreateSchedule)
+			schedules.GET("/:id", training.GetSchedule)
+			schedules.PUT("/:id", training.UpdateSchedule)
+			schedules.DELETE("/:id", training.DeleteSchedule)
+
+			// Повторения расписания
+			schedules.GET("/:id/occurrences", training.GetScheduleOccurrences)
+			schedules.PUT("/:id/occurrences/:date", training.UpdateScheduleOccurrence)
+			schedules.POST("/:id/occurrences/:date/skip", training.SkipScheduleOccurrence)
+		}
+
+		// Program routes
+		programs := api.Group("/programs")
+		{
+			programs.GET("", training.GetPrograms)
+			programs.GET("/:id", training.GetProgram)
+			programs.POST("/:id/enroll", training.EnrollInProgram)
+		}
+
+		// Enrollment routes
+		enrollments := api.Group("/enrollments")
+		{
+			enrollments.GET("", training.GetEnrollments)
+			enrollments.GET("/:id", training.GetEnrollment)
+			enrollments.DELETE("/:id", training.Unenroll)
+		}
+
+		// Template routes
+		templates := api.Group("/templates")
+		{
+			templates.GET("", training.GetTemplates)
+			templates.POST("", training.CreateTemplate)
+			templates.GET("/:id", training.GetTemplate)
+			templates.PUT("/:id", training.UpdateTemplate)
+			templates.DELETE("/:id", training.DeleteTemplate)
+			templates.POST("/:id/instantiate", training.InstantiateTemplate)
+		}
+
 		// Exercise routes
 		exercises := api.Group("/exercises")
 		{
@@ -86,14 +151,60 @@ func NewGinRouter(training *TrainingHandler, exercise *ExerciseHandler) *gin.Eng
 			exercises.GET("/search", exercise.SearchExercises)
 			exercises.POST("/by-tags", exercise.GetExercisesByMultipleTags)
 			exercises.GET("/:id/tags", exercise.GetExerciseTags)
+			exercises.GET("/:id/aliases", exercise.GetExerciseAliases)
+			exercises.GET("/:id/records", training.GetExercisePersonalRecords)
+			exercises.GET("/:id/progress", training.GetExerciseProgress)
+			exercises.GET("/:id/suggestion", training.GetExerciseSuggestion)
 			exercises.GET("/:id", exercise.GetExerciseByID)
 		}
 
+		// Import and export routes
+		api.POST("/import/:format", training.ImportHistory)
+		api.GET("/export", training.ExportTrainings)
+
+		// Calendar subscription routes
+		api.POST("/calendar/token", training.RotateCalendarToken)
+		api.DELETE("/calendar/token", training.RevokeCalendarToken)
+
+		// Personal records routes
+		api.GET("/records", training.GetPersonalRecords)
+
+		// User settings routes
+		api.GET("/settings", training.GetUserSettings)
+		api.PUT("/settings", training.UpdateUserSettings)
+
 		// Tag routes
 		tags := api.Group("/tags")
 		{
 			tags.GET("", exercise.GetAllTags)
 		}
+
+		// Admin routes
+		admin := api.Group("/admin", RequireRole(domain.RoleAdmin))
+		{
+			// Каталог упражнений
+			admin.POST("/exercises", exercise.CreateExercise)
+			admin.PUT("/exercises/:id", exercise.UpdateExercise)
+			admin.DELETE("/exercises/:id", exercise.DeleteExercise)
+			admin.POST("/exercises/:id/tags/:tag_id", exercise.AttachTagToExercise)
+			admin.DELETE("/exercises/:id/tags/:tag_id", exercise.DetachTagFromExercise)
+			admin.PUT("/exercises/:id/aliases", exercise.SetExerciseAliases)
+
+			// Теги
+			admin.POST("/tags", exercise.CreateTag)
+			admin.PUT("/tags/:id", exercise.UpdateTag)
+			admin.DELETE("/tags/:id", exercise.DeleteTag)
+
+			// Глобальные тренировки
+			admin.POST("/global-trainings", training.CreateGlobalTraining)
+			admin.PUT("/global-trainings/:id", training.UpdateGlobalTraining)
+			admin.DELETE("/global-trainings/:id", training.DeleteGlobalTraining)
+
+			// Программы
+			admin.POST("/programs", training.CreateProgram)
+			admin.PUT("/programs/:id", training.UpdateProgram)
+			admin.DELETE("/programs/:id", training.DeleteProgram)
+		}
 	}
 
 	return r
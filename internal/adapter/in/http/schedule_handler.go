@@ -0,0 +1,407 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// CreateSchedule создает расписание повторяющейся тренировки
+// @Summary      Создать расписание
+// @Description  Создает расписание с правилом повторения RRULE (FREQ=WEEKLY с INTERVAL, BYDAY и UNTIL или COUNT).
+// @Description  Тренировки на повторения создаются заранее на 4 недели вперед и дальше поддерживаются в фоне.
+// @Description  Упражнения копируются из global_training_id или template_id, но не из обоих
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.ScheduleRequest true "Данные расписания"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      201  {object}  dto.ScheduleResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules [post]
+func (h *TrainingHandler) CreateSchedule(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var req dto.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd := svctraining.CreateScheduleCmd{
+		UserID:           uid,
+		Title:            req.Title,
+		RRule:            req.RRule,
+		GlobalTrainingID: req.GlobalTrainingID,
+		TemplateID:       req.TemplateID,
+	}
+	if req.StartDate != nil {
+		startDate, err := time.Parse("2006-01-02", *req.StartDate)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid start_date format, use YYYY-MM-DD"})
+			return
+		}
+		cmd.StartDate = startDate
+	}
+
+	schedule, err := h.svc.CreateSchedule(c.Request.Context(), cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create schedule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.scheduleToResponse(schedule))
+}
+
+// GetSchedules получает расписания пользователя
+// @Summary      Получить расписания
+// @Description  Возвращает все расписания повторяющихся тренировок пользователя
+// @Tags         schedules
+// @Produce      json
+// @Success      200  {array}   dto.ScheduleResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules [get]
+func (h *TrainingHandler) GetSchedules(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	schedules, err := h.svc.GetSchedules(c.Request.Context(), uid)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get schedules")
+		return
+	}
+
+	resp := make([]dto.ScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		resp = append(resp, h.scheduleToResponse(schedule))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetSchedule получает расписание
+// @Summary      Получить расписание
+// @Description  Возвращает расписание повторяющейся тренировки по ID
+// @Tags         schedules
+// @Produce      json
+// @Param        id path int64 true "Schedule ID"
+// @Success      200  {object}  dto.ScheduleResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules/{id} [get]
+func (h *TrainingHandler) GetSchedule(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	scheduleID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid schedule id"})
+		return
+	}
+
+	schedule, err := h.svc.GetSchedule(c.Request.Context(), uid, scheduleID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.scheduleToResponse(schedule))
+}
+
+// UpdateSchedule изменяет расписание для всех будущих повторений
+// @Summary      Изменить расписание
+// @Description  Заменяет название, правило и источник упражнений расписания. Еще не начатые тренировки
+// @Description  с сегодняшнего дня, включая измененные по отдельности, создаются заново по новому правилу. Дата начала не меняется
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Schedule ID"
+// @Param        request body dto.ScheduleRequest true "Данные расписания"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {object}  dto.ScheduleResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules/{id} [put]
+func (h *TrainingHandler) UpdateSchedule(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	scheduleID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid schedule id"})
+		return
+	}
+
+	var req dto.ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	schedule, err := h.svc.UpdateSchedule(c.Request.Context(), svctraining.UpdateScheduleCmd{
+		ID:               scheduleID,
+		UserID:           uid,
+		Title:            req.Title,
+		RRule:            req.RRule,
+		GlobalTrainingID: req.GlobalTrainingID,
+		TemplateID:       req.TemplateID,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.scheduleToResponse(schedule))
+}
+
+// DeleteSchedule удаляет расписание
+// @Summary      Удалить расписание
+// @Description  Удаляет расписание и еще не начатые тренировки его будущих повторений. Прошедшие и начатые тренировки остаются
+// @Tags         schedules
+// @Param        id path int64 true "Schedule ID"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules/{id} [delete]
+func (h *TrainingHandler) DeleteSchedule(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	scheduleID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid schedule id"})
+		return
+	}
+
+	if err := h.svc.DeleteSchedule(c.Request.Context(), uid, scheduleID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete schedule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetScheduleOccurrences получает повторения расписания за период
+// @Summary      Получить повторения расписания
+// @Description  Возвращает дни повторений расписания за период с признаком пропуска и созданной тренировкой, если она есть.
+// @Description  Период - не больше 366 дней
+// @Tags         schedules
+// @Produce      json
+// @Param        id path int64 true "Schedule ID"
+// @Param        from query string true "Начало периода (YYYY-MM-DD)"
+// @Param        to query string true "Конец периода включительно (YYYY-MM-DD)"
+// @Success      200  {array}   dto.ScheduleOccurrenceResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules/{id}/occurrences [get]
+func (h *TrainingHandler) GetScheduleOccurrences(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	scheduleID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid schedule id"})
+		return
+	}
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from date format, use YYYY-MM-DD"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to date format, use YYYY-MM-DD"})
+		return
+	}
+
+	occurrences, err := h.svc.GetScheduleOccurrences(c.Request.Context(), uid, scheduleID, from, to)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get schedule occurrences")
+		return
+	}
+
+	resp := make([]dto.ScheduleOccurrenceResponse, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		item := dto.ScheduleOccurrenceResponse{
+			Date:       occurrence.Date.Format("2006-01-02"),
+			Skipped:    occurrence.Skipped,
+			TrainingID: occurrence.TrainingID,
+		}
+		if occurrence.PlannedDate != nil {
+			plannedDate := occurrence.PlannedDate.Format("2006-01-02")
+			item.PlannedDate = &plannedDate
+		}
+		if occurrence.Status != nil {
+			status := string(*occurrence.Status)
+			item.Status = &status
+		}
+		resp = append(resp, item)
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SkipScheduleOccurrence пропускает одно повторение расписания
+// @Summary      Пропустить повторение
+// @Description  Пропускает повторение расписания: его еще не начатая тренировка удаляется, а новая на этот день не создается
+// @Tags         schedules
+// @Param        id path int64 true "Schedule ID"
+// @Param        date path string true "День повторения (YYYY-MM-DD)"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules/{id}/occurrences/{date}/skip [post]
+func (h *TrainingHandler) SkipScheduleOccurrence(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	scheduleID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid schedule id"})
+		return
+	}
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid date format, use YYYY-MM-DD"})
+		return
+	}
+
+	if err := h.svc.SkipScheduleOccurrence(c.Request.Context(), uid, scheduleID, date); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to skip schedule occurrence")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateScheduleOccurrence изменяет одно повторение расписания
+// @Summary      Изменить повторение
+// @Description  Меняет название или дату тренировки одного повторения, не затрагивая остальные.
+// @Description  Если тренировка повторения еще не создана, она создается
+// @Tags         schedules
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Schedule ID"
+// @Param        date path string true "День повторения (YYYY-MM-DD)"
+// @Param        request body dto.UpdateScheduleOccurrenceRequest true "Изменяемые поля"
+// @Success      200  {object}  dto.UserTrainingsResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /schedules/{id}/occurrences/{date} [put]
+func (h *TrainingHandler) UpdateScheduleOccurrence(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	scheduleID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid schedule id"})
+		return
+	}
+	date, err := time.Parse("2006-01-02", c.Param("date"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid date format, use YYYY-MM-DD"})
+		return
+	}
+
+	var req dto.UpdateScheduleOccurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	cmd := svctraining.UpdateScheduleOccurrenceCmd{
+		ScheduleID: scheduleID,
+		UserID:     uid,
+		Date:       date,
+		Title:      req.Title,
+	}
+	if req.PlannedDate != nil {
+		plannedDate, err := time.Parse("2006-01-02", *req.PlannedDate)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid planned_date format, use YYYY-MM-DD"})
+			return
+		}
+		cmd.PlannedDate = &plannedDate
+	}
+
+	training, err := h.svc.UpdateScheduleOccurrence(c.Request.Context(), cmd)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update schedule occurrence")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.userTrainingToResponse(training))
+}
+
+func (h *TrainingHandler) scheduleToResponse(schedule *svctraining.TrainingSchedule) dto.ScheduleResponse {
+	resp := dto.ScheduleResponse{
+		ID:               schedule.ID,
+		Title:            schedule.Title,
+		RRule:            schedule.Rule.String(),
+		StartDate:        schedule.StartDate.Format("2006-01-02"),
+		GlobalTrainingID: schedule.GlobalTrainingID,
+		TemplateID:       schedule.TemplateID,
+		CreatedAt:        schedule.CreatedAt.Format(time.RFC3339),
+	}
+	if schedule.MaterializedUntil != nil {
+		materializedUntil := schedule.MaterializedUntil.Format("2006-01-02")
+		resp.MaterializedUntil = &materializedUntil
+	}
+	return resp
+}
@@ -0,0 +1,93 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetExerciseProgress получает прогресс пользователя в упражнении
+// @Summary      Получить прогресс в упражнении
+// @Description  Возвращает временной ряд по завершенным тренировкам: лучшую оценку 1ПМ, максимальный рабочий вес,
+// @Description  суммарный объем и число рабочих подходов за каждый день, неделю или месяц. Разминочные подходы не учитываются
+// @Tags         records
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        from query string false "Начало периода (YYYY-MM-DD), по умолчанию - 3 месяца до to"
+// @Param        to query string false "Конец периода (YYYY-MM-DD), по умолчанию - сегодня"
+// @Param        granularity query string false "Шаг ряда" Enums(day, week, month) default(week)
+// @Param        formula query string false "Формула оценки 1ПМ" Enums(epley, brzycki, lombardi) default(epley)
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {object}  dto.StrengthProgressResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /exercises/{id}/progress [get]
+func (h *TrainingHandler) GetExerciseProgress(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	query := svctraining.StrengthProgressQuery{
+		Granularity: svctraining.ProgressGranularity(c.Query("granularity")),
+		Formula:     svctraining.OneRepMaxFormula(c.Query("formula")),
+	}
+	if from := c.Query("from"); from != "" {
+		if query.From, err = time.Parse("2006-01-02", from); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from date format, use YYYY-MM-DD"})
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = time.Parse("2006-01-02", to); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to date format, use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	progress, err := h.svc.GetExerciseProgress(c.Request.Context(), uid, exerciseID, query)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get exercise progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.strengthProgressToResponse(progress))
+}
+
+func (h *TrainingHandler) strengthProgressToResponse(progress *svctraining.StrengthProgress) dto.StrengthProgressResponse {
+	points := make([]dto.StrengthProgressPointResponse, 0, len(progress.Points))
+	for _, point := range progress.Points {
+		e1rm, _ := point.BestE1RM.Float64()
+		topSet, _ := point.TopSetWeight.Float64()
+		volume, _ := point.TotalVolume.Float64()
+		points = append(points, dto.StrengthProgressPointResponse{
+			PeriodStart:  point.PeriodStart.Format("2006-01-02"),
+			BestE1RM:     e1rm,
+			TopSetWeight: topSet,
+			TotalVolume:  volume,
+			Sets:         point.Sets,
+		})
+	}
+
+	return dto.StrengthProgressResponse{
+		ExerciseID:  progress.ExerciseID,
+		From:        progress.From.Format("2006-01-02"),
+		To:          progress.To.Format("2006-01-02"),
+		Granularity: string(progress.Granularity),
+		Formula:     string(progress.Formula),
+		Points:      points,
+	}
+}
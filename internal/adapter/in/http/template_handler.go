@@ -0,0 +1,400 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetTemplates получает шаблоны пользователя
+// @Summary      Получить шаблоны тренировок
+// @Description  Возвращает шаблоны тренировок пользователя без упражнений, начиная с последнего созданного
+// @Tags         templates
+// @Produce      json
+// @Success      200  {array}   dto.TemplateResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /templates [get]
+func (h *TrainingHandler) GetTemplates(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	templates, err := h.svc.GetTemplates(c.Request.Context(), uid)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get templates")
+		return
+	}
+
+	resp := make([]dto.TemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		resp = append(resp, templateToResponse(template))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetTemplate получает шаблон
+// @Summary      Получить шаблон тренировки
+// @Description  Возвращает шаблон с упражнениями и целевой нагрузкой в порядке выполнения
+// @Tags         templates
+// @Produce      json
+// @Param        id path int64 true "Template ID"
+// @Success      200  {object}  dto.TemplateResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /templates/{id} [get]
+func (h *TrainingHandler) GetTemplate(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	templateID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid template id"})
+		return
+	}
+
+	template, err := h.svc.GetTemplate(c.Request.Context(), uid, templateID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get template")
+		return
+	}
+
+	c.JSON(http.StatusOK, templateToResponse(template))
+}
+
+// CreateTemplate создает шаблон
+// @Summary      Создать шаблон тренировки
+// @Description  Создает шаблон с упорядоченным списком упражнений и целевой нагрузкой
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.TemplateRequest true "Данные шаблона"
+// @Success      201  {object}  dto.TemplateResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /templates [post]
+func (h *TrainingHandler) CreateTemplate(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	var req dto.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	exercises, ok := exerciseSlotRequestsToCmd(c, req.Exercises)
+	if !ok {
+		return
+	}
+
+	template, err := h.svc.CreateTemplate(c.Request.Context(), svctraining.TemplateCmd{
+		UserID:      uid,
+		Title:       req.Title,
+		Description: req.Description,
+		Exercises:   exercises,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, templateToResponse(template))
+}
+
+// UpdateTemplate изменяет шаблон
+// @Summary      Изменить шаблон тренировки
+// @Description  Полностью заменяет данные и список упражнений шаблона. Созданные по шаблону тренировки не меняются
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Template ID"
+// @Param        request body dto.TemplateRequest true "Данные шаблона"
+// @Success      200  {object}  dto.TemplateResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /templates/{id} [put]
+func (h *TrainingHandler) UpdateTemplate(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	templateID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid template id"})
+		return
+	}
+
+	var req dto.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	exercises, ok := exerciseSlotRequestsToCmd(c, req.Exercises)
+	if !ok {
+		return
+	}
+
+	template, err := h.svc.UpdateTemplate(c.Request.Context(), templateID, svctraining.TemplateCmd{
+		UserID:      uid,
+		Title:       req.Title,
+		Description: req.Description,
+		Exercises:   exercises,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update template")
+		return
+	}
+
+	c.JSON(http.StatusOK, templateToResponse(template))
+}
+
+// DeleteTemplate удаляет шаблон
+// @Summary      Удалить шаблон тренировки
+// @Description  Удаляет шаблон. Созданные по шаблону тренировки остаются
+// @Tags         templates
+// @Param        id path int64 true "Template ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /templates/{id} [delete]
+func (h *TrainingHandler) DeleteTemplate(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	templateID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid template id"})
+		return
+	}
+
+	if err := h.svc.DeleteTemplate(c.Request.Context(), uid, templateID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete template")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// InstantiateTemplate создает тренировку по шаблону
+// @Summary      Создать тренировку по шаблону
+// @Description  Создает запланированную тренировку с названием шаблона и его упражнениями; целевая нагрузка переносится как план
+// @Tags         templates
+// @Produce      json
+// @Param        id path int64 true "Template ID"
+// @Param        planned_date query string false "Дата тренировки (YYYY-MM-DD), по умолчанию - сегодня"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      201  {object}  dto.TrainingResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /templates/{id}/instantiate [post]
+func (h *TrainingHandler) InstantiateTemplate(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	templateID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid template id"})
+		return
+	}
+
+	plannedDate, ok := parsePlannedDateQuery(c)
+	if !ok {
+		return
+	}
+
+	training, err := h.svc.InstantiateTemplate(c.Request.Context(), svctraining.InstantiateTemplateCmd{
+		UserID:      uid,
+		TemplateID:  templateID,
+		PlannedDate: plannedDate,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create training from template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.trainingToResponse(training))
+}
+
+// CreateTemplateFromTraining сохраняет тренировку как шаблон
+// @Summary      Сохранить тренировку как шаблон
+// @Description  Создает шаблон из упражнений завершенной тренировки в порядке их добавления: вес, подходы,
+// @Description  повторения и время выполнения становятся целевой нагрузкой
+// @Tags         templates
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Training ID"
+// @Param        request body dto.TemplateFromTrainingRequest false "Название и описание шаблона"
+// @Success      201  {object}  dto.TemplateResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trainings/{id}/template [post]
+func (h *TrainingHandler) CreateTemplateFromTraining(c *gin.Context) {
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
+		return
+	}
+
+	trainingID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid training id"})
+		return
+	}
+
+	// Тело запроса необязательно
+	var req dto.TemplateFromTrainingRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+			return
+		}
+	}
+
+	template, err := h.svc.CreateTemplateFromTraining(c.Request.Context(), svctraining.TemplateFromTrainingCmd{
+		UserID:      uid,
+		TrainingID:  trainingID,
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create template from training")
+		return
+	}
+
+	c.JSON(http.StatusCreated, templateToResponse(template))
+}
+
+// CloneTraining копирует тренировку
+// @Summary      Копировать тренировку
+// @Description  Создает запланированную тренировку с тем же названием и копиями всех упражнений исходной
+// @Tags         trainings
+// @Produce      json
+// @Param        id path int64 true "Training ID"
+// @Param        planned_date query string false "Дата новой тренировки (YYYY-MM-DD), по умолчанию - сегодня"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      201  {object}  dto.TrainingResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /trainings/{id}/clone [post]
+func (h *TrainingHandler) CloneTraining(c *gin.Context) {
+	trainingID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid training id"})
+		return
+	}
+
+	plannedDate, ok := parsePlannedDateQuery(c)
+	if !ok {
+		return
+	}
+
+	training, err := h.svc.CloneTraining(c.Request.Context(), trainingID, plannedDate)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to clone training")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.trainingToResponse(training))
+}
+
+// parsePlannedDateQuery разбирает необязательный параметр planned_date.
+// При ошибке разбора отвечает 400 и возвращает false
+func parsePlannedDateQuery(c *gin.Context) (*time.Time, bool) {
+	return parseDateQuery(c, "planned_date")
+}
+
+// parseDateQuery разбирает необязательную дату YYYY-MM-DD из параметра запроса.
+// При ошибке отвечает 400 и возвращает false
+func parseDateQuery(c *gin.Context, name string) (*time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid " + name + " format, use YYYY-MM-DD"})
+		return nil, false
+	}
+	return &date, true
+}
+
+func templateToResponse(template *svctraining.TrainingTemplate) dto.TemplateResponse {
+	resp := dto.TemplateResponse{
+		ID:          template.ID,
+		Title:       template.Title,
+		Description: template.Description,
+		CreatedAt:   template.CreatedAt.Format(time.RFC3339),
+	}
+	for _, ex := range template.Exercises {
+		exercise := dto.TemplateExerciseResponse{
+			ID:            ex.ID,
+			ExerciseID:    ex.ExerciseID,
+			ExerciseTitle: ex.ExerciseTitle,
+			Position:      ex.Position,
+			Approaches:    ex.Approaches,
+			Reps:          ex.Reps,
+		}
+		exercise.GroupID, exercise.GroupType = exerciseGroupToResponse(ex.Group)
+		if ex.Weight != nil {
+			weight, _ := ex.Weight.Float64()
+			exercise.Weight = &weight
+		}
+		if ex.Duration != nil {
+			s := formatDuration(*ex.Duration)
+			exercise.Duration = &s
+		}
+		if ex.Rest != nil {
+			s := formatDuration(*ex.Rest)
+			exercise.Rest = &s
+		}
+		resp.Exercises = append(resp.Exercises, exercise)
+	}
+	return resp
+}
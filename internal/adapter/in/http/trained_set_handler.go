@@ -0,0 +1,292 @@
+package httpin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
+	svctraining "github.com/EnduranNSU/trainings/internal/domain"
+)
+
+// GetTrainedSets получает подходы упражнения
+// @Summary      Получить подходы упражнения
+// @Description  Возвращает подходы выполненного упражнения в порядке выполнения
+// @Tags         training-exercises
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Success      200  {array}   dto.TrainedSetResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/sets [get]
+func (h *TrainingHandler) GetTrainedSets(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	sets, err := h.svc.GetTrainedSets(c.Request.Context(), exerciseID)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get sets")
+		return
+	}
+
+	resp := make([]dto.TrainedSetResponse, 0, len(sets))
+	for _, set := range sets {
+		resp = append(resp, h.trainedSetToResponse(set))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// AddTrainedSet добавляет подход к упражнению
+// @Summary      Добавить подход
+// @Description  Добавляет подход в конец списка подходов выполненного упражнения
+// @Tags         training-exercises
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        request body dto.AddTrainedSetRequest true "Данные подхода"
+// @Success      201  {object}  dto.TrainedSetResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/sets [post]
+func (h *TrainingHandler) AddTrainedSet(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	var req dto.AddTrainedSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	var duration *time.Duration
+	if req.Duration != nil {
+		d, err := time.ParseDuration(*req.Duration)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid duration format, use duration format like '1h30m'"})
+			return
+		}
+		duration = &d
+	}
+
+	set, err := h.svc.AddTrainedSet(c.Request.Context(), svctraining.AddTrainedSetCmd{
+		TrainedExerciseID: exerciseID,
+		Weight:            decimalFromFloat(req.Weight),
+		Reps:              req.Reps,
+		Duration:          duration,
+		RPE:               decimalFromFloat(req.RPE),
+		IsWarmup:          req.IsWarmup,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to add set")
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.trainedSetToResponse(set))
+}
+
+// UpdateTrainedSet изменяет подход
+// @Summary      Изменить подход
+// @Description  Обновляет переданные поля подхода
+// @Tags         training-exercises
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        set_id path int64 true "Set ID"
+// @Param        request body dto.UpdateTrainedSetRequest true "Изменяемые поля"
+// @Success      200  {object}  dto.TrainedSetResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/sets/{set_id} [put]
+func (h *TrainingHandler) UpdateTrainedSet(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+	setID, err := parseInt64Param(c, "set_id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid set id"})
+		return
+	}
+
+	var req dto.UpdateTrainedSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	var duration *time.Duration
+	if req.Duration != nil {
+		d, err := time.ParseDuration(*req.Duration)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid duration format, use duration format like '1h30m'"})
+			return
+		}
+		duration = &d
+	}
+
+	set, err := h.svc.UpdateTrainedSet(c.Request.Context(), svctraining.UpdateTrainedSetCmd{
+		ID:                setID,
+		TrainedExerciseID: exerciseID,
+		Weight:            decimalFromFloat(req.Weight),
+		Reps:              req.Reps,
+		Duration:          duration,
+		RPE:               decimalFromFloat(req.RPE),
+		IsWarmup:          req.IsWarmup,
+	})
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update set")
+		return
+	}
+
+	c.JSON(http.StatusOK, h.trainedSetToResponse(set))
+}
+
+// DeleteTrainedSet удаляет подход
+// @Summary      Удалить подход
+// @Description  Удаляет подход; оставшиеся подходы нумеруются заново
+// @Tags         training-exercises
+// @Param        id path int64 true "Exercise ID"
+// @Param        set_id path int64 true "Set ID"
+// @Success      204
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/sets/{set_id} [delete]
+func (h *TrainingHandler) DeleteTrainedSet(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+	setID, err := parseInt64Param(c, "set_id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid set id"})
+		return
+	}
+
+	if err := h.svc.DeleteTrainedSet(c.Request.Context(), exerciseID, setID); err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete set")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderTrainedSets меняет порядок подходов
+// @Summary      Изменить порядок подходов
+// @Description  Задает новый порядок подходов. В запросе должны быть перечислены все подходы упражнения
+// @Tags         training-exercises
+// @Accept       json
+// @Produce      json
+// @Param        id path int64 true "Exercise ID"
+// @Param        request body dto.ReorderTrainedSetsRequest true "Новый порядок подходов"
+// @Success      200  {array}   dto.TrainedSetResponse
+// @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
+// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
+// @Router       /training-exercises/{id}/sets/order [put]
+func (h *TrainingHandler) ReorderTrainedSets(c *gin.Context) {
+	exerciseID, err := parseInt64Param(c, "id")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid exercise id"})
+		return
+	}
+
+	var req dto.ReorderTrainedSetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "bad json"})
+		return
+	}
+
+	sets, err := h.svc.ReorderTrainedSets(c.Request.Context(), exerciseID, req.SetIDs)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to reorder sets")
+		return
+	}
+
+	resp := make([]dto.TrainedSetResponse, 0, len(sets))
+	for _, set := range sets {
+		resp = append(resp, h.trainedSetToResponse(set))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *TrainingHandler) trainedSetToResponse(set *svctraining.TrainedSet) dto.TrainedSetResponse {
+	var weight, rpe *float64
+	if set.Weight != nil {
+		f, _ := set.Weight.Float64()
+		weight = &f
+	}
+	if set.RPE != nil {
+		f, _ := set.RPE.Float64()
+		rpe = &f
+	}
+
+	var durationStr *string
+	if set.Duration != nil {
+		s := formatDuration(*set.Duration)
+		durationStr = &s
+	}
+
+	return dto.TrainedSetResponse{
+		ID:                set.ID,
+		TrainedExerciseID: set.TrainedExerciseID,
+		Ordinal:           set.Ordinal,
+		Weight:            weight,
+		Reps:              set.Reps,
+		Duration:          durationStr,
+		RPE:               rpe,
+		IsWarmup:          set.IsWarmup,
+	}
+}
+
+func (h *TrainingHandler) trainedSetsToResponse(sets []svctraining.TrainedSet) []dto.TrainedSetResponse {
+	if len(sets) == 0 {
+		return nil
+	}
+	resp := make([]dto.TrainedSetResponse, 0, len(sets))
+	for i := range sets {
+		resp = append(resp, h.trainedSetToResponse(&sets[i]))
+	}
+	return resp
+}
+
+func decimalFromFloat(f *float64) *decimal.Decimal {
+	if f == nil {
+		return nil
+	}
+	d := decimal.NewFromFloat(*f)
+	return &d
+}
+
+func decimalToFloat(d *decimal.Decimal) *float64 {
+	if d == nil {
+		return nil
+	}
+	f, _ := d.Float64()
+	return &f
+}
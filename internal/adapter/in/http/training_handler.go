@@ -3,10 +3,11 @@ package httpin
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 
 	"github.com/EnduranNSU/trainings/internal/adapter/in/http/dto"
@@ -15,45 +16,105 @@ import (
 
 type TrainingHandler struct {
 	svc svctraining.TrainingService
+
+	shutdown     chan struct{} // Закрывается при остановке сервера, завершая потоки живых сессий
+	shutdownOnce sync.Once
 }
 
 func NewTrainingHandler(svc svctraining.TrainingService) *TrainingHandler {
-	return &TrainingHandler{svc: svc}
+	return &TrainingHandler{svc: svc, shutdown: make(chan struct{})}
+}
+
+// CloseStreams завершает потоки живых сессий. Вызывается при остановке сервера,
+// иначе http.Server.Shutdown ждал бы бесконечные SSE соединения до таймаута
+func (h *TrainingHandler) CloseStreams() {
+	h.shutdownOnce.Do(func() { close(h.shutdown) })
 }
 
-// GetTrainingsByUser получает все тренировки пользователя
+// GetTrainingsByUser получает тренировки пользователя
 // @Summary      Получить тренировки пользователя
-// @Description  Возвращает все тренировки указанного пользователя
+// @Description  Возвращает страницу тренировок аутентифицированного пользователя с фильтрами и сортировкой.
+// @Description  Следующая страница запрашивается с теми же параметрами и cursor из next_cursor
 // @Tags         trainings
 // @Produce      json
-// @Param        user_id query string true "User ID"
-// @Success      200  {array}   dto.UserTrainingsResponse
+// @Param        planned_from query string false "Запланированы не раньше даты (YYYY-MM-DD)"
+// @Param        planned_to query string false "Запланированы не позже даты (YYYY-MM-DD)"
+// @Param        is_done query bool false "Только завершенные или только незавершенные"
+// @Param        min_rating query int false "Минимальный рейтинг (1-5)"
+// @Param        max_rating query int false "Максимальный рейтинг (1-5)"
+// @Param        search query string false "Подстрока названия"
+// @Param        sort query string false "Поле сортировки" Enums(planned_date, actual_date, rating) default(planned_date)
+// @Param        order query string false "Направление сортировки" Enums(asc, desc) default(desc)
+// @Param        limit query int false "Размер страницы (1-100)" default(20)
+// @Param        cursor query string false "next_cursor предыдущей страницы"
+// @Success      200  {object}  dto.TrainingsPageResponse
 // @Failure      400  {object}  dto.ErrorResponse
-// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings [get]
 func (h *TrainingHandler) GetTrainingsByUser(c *gin.Context) {
-	uidStr := c.Query("user_id")
-	uid, err := uuid.Parse(uidStr)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
-	trainings, err := h.svc.GetTrainingsByUser(c.Request.Context(), uid)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get trainings"})
+	var req dto.ListTrainingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid query parameters"})
 		return
 	}
 
-	if len(trainings) == 0 {
-		c.JSON(http.StatusOK, []dto.UserTrainingsResponse{})
+	filter := svctraining.TrainingFilter{
+		IsDone:    req.IsDone,
+		MinRating: req.MinRating,
+		MaxRating: req.MaxRating,
+		Search:    req.Search,
+		SortBy:    svctraining.TrainingSort(req.Sort),
+		SortDesc:  req.Order != "asc",
+		Limit:     req.Limit,
+	}
+	if req.PlannedFrom != nil {
+		from, err := time.Parse("2006-01-02", *req.PlannedFrom)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid planned_from format, use YYYY-MM-DD"})
+			return
+		}
+		filter.PlannedFrom = &from
+	}
+	if req.PlannedTo != nil {
+		to, err := time.Parse("2006-01-02", *req.PlannedTo)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid planned_to format, use YYYY-MM-DD"})
+			return
+		}
+		filter.PlannedTo = &to
+	}
+	if req.Cursor != "" {
+		cursor, err := svctraining.DecodeTrainingCursor(req.Cursor)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid cursor"})
+			return
+		}
+		filter.Cursor = cursor
+	}
+
+	page, err := h.svc.ListTrainings(c.Request.Context(), uid, filter)
+	if err != nil {
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get trainings")
 		return
 	}
 
-	resp := make([]dto.UserTrainingsResponse, 0, len(trainings))
-	for _, training := range trainings {
-		resp = append(resp, h.userTrainingToResponse(training))
+	resp := dto.TrainingsPageResponse{
+		Trainings: make([]dto.UserTrainingsResponse, 0, len(page.Trainings)),
+	}
+	for _, training := range page.Trainings {
+		resp.Trainings = append(resp.Trainings, h.userTrainingToResponse(training))
+	}
+	if page.NextCursor != nil {
+		cursor := page.NextCursor.Encode()
+		resp.NextCursor = &cursor
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -67,8 +128,10 @@ func (h *TrainingHandler) GetTrainingsByUser(c *gin.Context) {
 // @Param        id path int64 true "Training ID"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id} [get]
 func (h *TrainingHandler) GetTrainingWithExercises(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -79,7 +142,7 @@ func (h *TrainingHandler) GetTrainingWithExercises(c *gin.Context) {
 
 	training, err := h.svc.GetTrainingWithExercises(c.Request.Context(), trainingID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{Error: "training not found"})
+		abortWithServiceError(c, err, http.StatusNotFound, "training not found")
 		return
 	}
 
@@ -96,6 +159,7 @@ func (h *TrainingHandler) GetTrainingWithExercises(c *gin.Context) {
 // @Success      201  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings [post]
 func (h *TrainingHandler) CreateTraining(c *gin.Context) {
 	var req dto.CreateTrainingRequest
@@ -104,9 +168,9 @@ func (h *TrainingHandler) CreateTraining(c *gin.Context) {
 		return
 	}
 
-	uid, err := uuid.Parse(req.UserID)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
@@ -187,7 +251,7 @@ func (h *TrainingHandler) CreateTraining(c *gin.Context) {
 
 	training, err := h.svc.CreateTraining(c.Request.Context(), cmd)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to create training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to create training")
 		return
 	}
 
@@ -196,7 +260,8 @@ func (h *TrainingHandler) CreateTraining(c *gin.Context) {
 
 // UpdateTraining обновляет тренировку
 // @Summary      Обновить тренировку
-// @Description  Обновляет информацию о тренировке
+// @Description  Обновляет информацию о тренировке. Статус, is_done, actual_date, started_at и finished_at
+// @Description  меняются только эндпоинтами start, pause, resume, complete, skip и cancel; если они переданы, возвращается 409
 // @Tags         trainings
 // @Accept       json
 // @Produce      json
@@ -204,8 +269,11 @@ func (h *TrainingHandler) CreateTraining(c *gin.Context) {
 // @Param        request body dto.UpdateTrainingRequest true "Данные для обновления"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id} [put]
 func (h *TrainingHandler) UpdateTraining(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -226,34 +294,15 @@ func (h *TrainingHandler) UpdateTraining(c *gin.Context) {
 		return
 	}
 
-	var actualDate, startedAt, finishedAt *time.Time
-	var totalDuration, totalRestTime, totalExerciseTime *time.Duration
-
-	if req.ActualDate != nil {
-		t, err := time.Parse(time.RFC3339, *req.ActualDate)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid actual_date time format"})
-			return
-		}
-		actualDate = &t
-	}
-	if req.StartedAt != nil {
-		t, err := time.Parse(time.RFC3339, *req.StartedAt)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid started_at time format"})
-			return
-		}
-		startedAt = &t
-	}
-	if req.FinishedAt != nil {
-		t, err := time.Parse(time.RFC3339, *req.FinishedAt)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid finished_at time format"})
-			return
-		}
-		finishedAt = &t
+	// Статус и связанные с ним поля меняются только эндпоинтами жизненного цикла,
+	// иначе тренировка может оказаться выполненной со статусом planned
+	if req.IsDone != nil || req.ActualDate != nil || req.StartedAt != nil || req.FinishedAt != nil {
+		c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: "is_done, actual_date, started_at and finished_at are changed only by start, complete, skip and cancel"})
+		return
 	}
 
+	var totalDuration, totalRestTime, totalExerciseTime *time.Duration
+
 	if req.TotalDuration != nil {
 		duration, err := time.ParseDuration(*req.TotalDuration)
 		if err != nil {
@@ -282,11 +331,7 @@ func (h *TrainingHandler) UpdateTraining(c *gin.Context) {
 	cmd := svctraining.UpdateTrainingCmd{
 		ID:                trainingID,
 		Title:             req.Title,
-		IsDone:            req.IsDone,
 		PlannedDate:       plannedDate,
-		ActualDate:        actualDate,
-		StartedAt:         startedAt,
-		FinishedAt:        finishedAt,
 		TotalDuration:     totalDuration,
 		TotalRestTime:     totalRestTime,
 		TotalExerciseTime: totalExerciseTime,
@@ -295,7 +340,7 @@ func (h *TrainingHandler) UpdateTraining(c *gin.Context) {
 
 	training, err := h.svc.UpdateTraining(c.Request.Context(), cmd)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update training")
 		return
 	}
 
@@ -310,8 +355,10 @@ func (h *TrainingHandler) UpdateTraining(c *gin.Context) {
 // @Param        id path int64 true "Training ID"
 // @Success      204
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id} [delete]
 func (h *TrainingHandler) DeleteTraining(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -322,7 +369,7 @@ func (h *TrainingHandler) DeleteTraining(c *gin.Context) {
 
 	err = h.svc.DeleteTraining(c.Request.Context(), trainingID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to delete training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to delete training")
 		return
 	}
 
@@ -331,15 +378,18 @@ func (h *TrainingHandler) DeleteTraining(c *gin.Context) {
 
 // AddExerciseToTraining добавляет упражнение к тренировке
 // @Summary      Добавить упражнение к тренировке
-// @Description  Добавляет упражнение к существующей тренировке
+// @Description  Добавляет упражнение к существующей тренировке. Кардио-показатели (cardio) можно передать только для кардио-упражнений каталога; допустимые показатели зависят от типа упражнения
 // @Tags         training-exercises
 // @Accept       json
 // @Produce      json
 // @Param        request body dto.AddExerciseToTrainingRequest true "Данные упражнения"
+// @Param        suggest query bool false "Заполнить незаданные вес, подходы и повторения предлагаемой нагрузкой (см. /exercises/{id}/suggestion)"
 // @Success      201  {object}  dto.TrainedExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /training-exercises [post]
 func (h *TrainingHandler) AddExerciseToTraining(c *gin.Context) {
 	var req dto.AddExerciseToTrainingRequest
@@ -391,6 +441,15 @@ func (h *TrainingHandler) AddExerciseToTraining(c *gin.Context) {
 		reps = &r
 	}
 
+	group, ok := exerciseGroupFromRequest(c, req.GroupID, req.GroupType)
+	if !ok {
+		return
+	}
+	cardio, ok := cardioFromRequest(c, req.Cardio)
+	if !ok {
+		return
+	}
+
 	cmd := svctraining.AddExerciseToTrainingCmd{
 		TrainingID: req.TrainingID,
 		ExerciseID: req.ExerciseID,
@@ -401,11 +460,21 @@ func (h *TrainingHandler) AddExerciseToTraining(c *gin.Context) {
 		Doing:      doing,
 		Rest:       rest,
 		Notes:      req.Notes,
+		Group:      group,
+		Cardio:     cardio,
+	}
+	if suggest := c.Query("suggest"); suggest != "" {
+		parsed, err := strconv.ParseBool(suggest)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid suggest value, use true or false"})
+			return
+		}
+		cmd.Suggest = parsed
 	}
 
 	exercise, err := h.svc.AddExerciseToTraining(c.Request.Context(), cmd)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to add exercise to training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to add exercise to training")
 		return
 	}
 
@@ -414,7 +483,7 @@ func (h *TrainingHandler) AddExerciseToTraining(c *gin.Context) {
 
 // UpdateTrainedExercise обновляет выполненное упражнение
 // @Summary      Обновить выполненное упражнение
-// @Description  Обновляет информацию о выполненном упражнении в тренировке
+// @Description  Обновляет информацию о выполненном упражнении в тренировке. Переданные кардио-показатели накладываются на сохраненные
 // @Tags         training-exercises
 // @Accept       json
 // @Produce      json
@@ -422,8 +491,10 @@ func (h *TrainingHandler) AddExerciseToTraining(c *gin.Context) {
 // @Param        request body dto.UpdateTrainedExerciseRequest true "Данные для обновления"
 // @Success      200  {object}  dto.TrainedExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /training-exercises/{id} [put]
 func (h *TrainingHandler) UpdateTrainedExercise(c *gin.Context) {
 	exerciseID, err := parseInt64Param(c, "id")
@@ -481,6 +552,11 @@ func (h *TrainingHandler) UpdateTrainedExercise(c *gin.Context) {
 		reps = &r
 	}
 
+	cardio, ok := cardioFromRequest(c, req.Cardio)
+	if !ok {
+		return
+	}
+
 	cmd := svctraining.UpdateTrainedExerciseCmd{
 		ID:         exerciseID,
 		Weight:     weight,
@@ -490,11 +566,12 @@ func (h *TrainingHandler) UpdateTrainedExercise(c *gin.Context) {
 		Doing:      doing,
 		Rest:       rest,
 		Notes:      req.Notes,
+		Cardio:     cardio,
 	}
 
 	exercise, err := h.svc.UpdateTrainedExercise(c.Request.Context(), cmd)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update exercise"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update exercise")
 		return
 	}
 
@@ -510,8 +587,10 @@ func (h *TrainingHandler) UpdateTrainedExercise(c *gin.Context) {
 // @Param        exercise_id query int64 true "Exercise ID"
 // @Success      204
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /training-exercises [delete]
 func (h *TrainingHandler) RemoveExerciseFromTraining(c *gin.Context) {
 	trainingID, err := parseInt64Query(c, "training_id")
@@ -528,7 +607,7 @@ func (h *TrainingHandler) RemoveExerciseFromTraining(c *gin.Context) {
 
 	err = h.svc.RemoveExerciseFromTraining(c.Request.Context(), trainingID, exerciseID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to remove exercise from training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to remove exercise from training")
 		return
 	}
 
@@ -537,34 +616,66 @@ func (h *TrainingHandler) RemoveExerciseFromTraining(c *gin.Context) {
 
 // GetUserTrainingStats получает статистику тренировок пользователя
 // @Summary      Получить статистику тренировок
-// @Description  Возвращает статистику тренировок пользователя
+// @Description  Возвращает статистику тренировок пользователя за период: количество тренировок, долю завершенных,
+// @Description  средний рейтинг, общее время, время отдыха и работы, объем, а для кардио - дистанцию, время в движении,
+// @Description  средний темп и калории. Кроме итога возвращается разбивка по периодам: с group_by=week - дистанция и темп по неделям
 // @Tags         trainings
 // @Produce      json
-// @Param        user_id query string true "User ID"
-// @Success      200  {object}  dto.TrainingStatsResponse
+// @Param        from query string false "Начало периода (YYYY-MM-DD), по умолчанию - вся история"
+// @Param        to query string false "Конец периода (YYYY-MM-DD), по умолчанию - сегодня"
+// @Param        group_by query string false "Шаг разбивки" Enums(day, week, month) default(month)
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
+// @Success      200  {object}  dto.UserTrainingStatsResponse
 // @Failure      400  {object}  dto.ErrorResponse
-// @Failure      404  {object}  dto.ErrorResponse
+// @Failure      401  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/stats [get]
 func (h *TrainingHandler) GetUserTrainingStats(c *gin.Context) {
-	uidStr := c.Query("user_id")
-	uid, err := uuid.Parse(uidStr)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
-	stats, err := h.svc.GetUserTrainingStats(c.Request.Context(), uid)
+	query := svctraining.UserStatsQuery{
+		GroupBy: svctraining.ProgressGranularity(c.Query("group_by")),
+	}
+	var err error
+	if from := c.Query("from"); from != "" {
+		if query.From, err = time.Parse("2006-01-02", from); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid from date format, use YYYY-MM-DD"})
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = time.Parse("2006-01-02", to); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid to date format, use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	stats, err := h.svc.GetUserTrainingStats(c.Request.Context(), uid, query)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get training stats"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get training stats")
 		return
 	}
 
-	resp := dto.TrainingStatsResponse{
-		TotalTrainings:     stats.TotalTrainings,
-		CompletedTrainings: stats.CompletedTrainings,
-		AverageRating:      stats.AverageRating,
-		TotalDuration:      stats.TotalDuration.String(),
+	resp := dto.UserTrainingStatsResponse{
+		To:      stats.To.Format("2006-01-02"),
+		GroupBy: string(stats.GroupBy),
+		Totals:  h.trainingStatsToResponse(&stats.Totals),
+		Buckets: make([]dto.TrainingStatsBucketResponse, 0, len(stats.Buckets)),
+	}
+	if !stats.From.IsZero() {
+		from := stats.From.Format("2006-01-02")
+		resp.From = &from
+	}
+	for i := range stats.Buckets {
+		resp.Buckets = append(resp.Buckets, dto.TrainingStatsBucketResponse{
+			PeriodStart:           stats.Buckets[i].PeriodStart.Format("2006-01-02"),
+			TrainingStatsResponse: h.trainingStatsToResponse(&stats.Buckets[i].TrainingStats),
+		})
 	}
 
 	c.JSON(http.StatusOK, resp)
@@ -572,7 +683,7 @@ func (h *TrainingHandler) GetUserTrainingStats(c *gin.Context) {
 
 // CompleteTraining завершает тренировку
 // @Summary      Завершить тренировку
-// @Description  Отмечает тренировку как завершенную и устанавливает рейтинг
+// @Description  Отмечает тренировку как завершенную и устанавливает рейтинг. Новые личные рекорды возвращаются в personal_records
 // @Tags         trainings
 // @Accept       json
 // @Produce      json
@@ -580,8 +691,11 @@ func (h *TrainingHandler) GetUserTrainingStats(c *gin.Context) {
 // @Param        request body dto.CompleteTrainingRequest true "Данные для завершения"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/complete [patch]
 func (h *TrainingHandler) CompleteTraining(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -598,7 +712,7 @@ func (h *TrainingHandler) CompleteTraining(c *gin.Context) {
 
 	training, err := h.svc.CompleteTraining(c.Request.Context(), trainingID, req.Rating)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to complete training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to complete training")
 		return
 	}
 
@@ -648,6 +762,8 @@ func (h *TrainingHandler) userTrainingToResponse(training *svctraining.Training)
 		TotalRestTime:     totalRestTime,
 		TotalExerciseTime: totalExerciseTime,
 		Rating:            training.Rating,
+		Status:            string(training.Status),
+		IsPaused:          training.IsPaused,
 	}
 }
 
@@ -701,7 +817,10 @@ func (h *TrainingHandler) trainingToResponse(training *svctraining.Training) dto
 		TotalRestTime:     totalRestTime,
 		TotalExerciseTime: totalExerciseTime,
 		Rating:            training.Rating,
+		Status:            string(training.Status),
+		IsPaused:          training.IsPaused,
 		Exercises:         exercises,
+		PersonalRecords:   h.personalRecordsToResponse(training.PersonalRecords),
 	}
 }
 
@@ -726,6 +845,8 @@ func (h *TrainingHandler) trainedExerciseToResponse(exercise *svctraining.Traine
 		restStr = &s
 	}
 
+	groupID, groupType := exerciseGroupToResponse(exercise.Group)
+
 	return dto.TrainedExerciseResponse{
 		ID:         exercise.ID,
 		TrainingID: exercise.TrainingID,
@@ -737,7 +858,84 @@ func (h *TrainingHandler) trainedExerciseToResponse(exercise *svctraining.Traine
 		Doing:      doingStr,
 		Rest:       restStr,
 		Notes:      exercise.Notes,
+		Sets:       h.trainedSetsToResponse(exercise.Sets),
+		Position:   exercise.Position,
+		GroupID:    groupID,
+		GroupType:  groupType,
+		Cardio:     cardioToResponse(exercise.Cardio),
+	}
+}
+
+func cardioToResponse(cardio *svctraining.CardioMetrics) *dto.CardioMetricsResponse {
+	if cardio == nil {
+		return nil
+	}
+	resp := &dto.CardioMetricsResponse{
+		DistanceUnit:    string(cardio.DistanceUnit),
+		AvgHeartRate:    cardio.AvgHeartRate,
+		MaxHeartRate:    cardio.MaxHeartRate,
+		Calories:        cardio.Calories,
+		ResistanceLevel: cardio.Resistance,
+		Distance:        decimalToFloat(cardio.Distance),
+		ElevationGain:   decimalToFloat(cardio.ElevationGain),
+		AvgSpeed:        decimalToFloat(cardio.AvgSpeed),
+		Incline:         decimalToFloat(cardio.Incline),
+	}
+	if cardio.Distance != nil && cardio.DistanceUnit.IsValid() {
+		inUnit := cardio.DistanceUnit.FromMeters(*cardio.Distance)
+		resp.DistanceInUnit = decimalToFloat(&inUnit)
+	}
+	if cardio.MovingTime != nil {
+		s := formatDuration(*cardio.MovingTime)
+		resp.MovingTime = &s
+	}
+	if cardio.AvgPace != nil {
+		s := formatDuration(*cardio.AvgPace)
+		resp.AvgPace = &s
+	}
+	return resp
+}
+
+// cardioFromRequest разбирает кардио-показатели запроса. Дистанция остается в единице пользователя,
+// в метры ее переводит сервис. При ошибке отвечает 400 и возвращает false
+func cardioFromRequest(c *gin.Context, req *dto.CardioMetricsRequest) (*svctraining.CardioMetrics, bool) {
+	if req == nil {
+		return nil, true
+	}
+	cardio := &svctraining.CardioMetrics{
+		AvgHeartRate:  req.AvgHeartRate,
+		MaxHeartRate:  req.MaxHeartRate,
+		Calories:      req.Calories,
+		Resistance:    req.ResistanceLevel,
+		Distance:      decimalFromFloat(req.Distance),
+		ElevationGain: decimalFromFloat(req.ElevationGain),
+		AvgSpeed:      decimalFromFloat(req.AvgSpeed),
+		Incline:       decimalFromFloat(req.Incline),
+	}
+	if req.DistanceUnit != nil {
+		cardio.DistanceUnit = svctraining.DistanceUnit(*req.DistanceUnit)
+		if !cardio.DistanceUnit.IsValid() {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid distance_unit, use m, km or mi"})
+			return nil, false
+		}
+	}
+	if req.MovingTime != nil {
+		duration, err := time.ParseDuration(*req.MovingTime)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid moving_time format, use duration format like '28m40s'"})
+			return nil, false
+		}
+		cardio.MovingTime = &duration
+	}
+	if req.AvgPace != nil {
+		duration, err := time.ParseDuration(*req.AvgPace)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid avg_pace format, use duration format like '5m30s'"})
+			return nil, false
+		}
+		cardio.AvgPace = &duration
 	}
+	return cardio, true
 }
 
 func formatDuration(d time.Duration) string {
@@ -764,8 +962,10 @@ func formatDuration(d time.Duration) string {
 // @Param        request body dto.UpdateExerciseTimeRequest true "Данные для обновления"
 // @Success      200  {object}  dto.TrainedExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /training-exercises/{id}/time [patch]
 func (h *TrainingHandler) UpdateExerciseTime(c *gin.Context) {
 	exerciseID, err := parseInt64Param(c, "id")
@@ -825,7 +1025,7 @@ func (h *TrainingHandler) UpdateExerciseTime(c *gin.Context) {
 
 	exercise, err := h.svc.UpdateExerciseTime(c.Request.Context(), exerciseID, weight, approaches, reps, timeVal, doing, rest)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update exercise time"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update exercise time")
 		return
 	}
 
@@ -842,8 +1042,10 @@ func (h *TrainingHandler) UpdateExerciseTime(c *gin.Context) {
 // @Param        request body dto.UpdateTrainingTimersRequest true "Данные для обновления"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/timers [patch]
 func (h *TrainingHandler) UpdateTrainingTimers(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -887,7 +1089,7 @@ func (h *TrainingHandler) UpdateTrainingTimers(c *gin.Context) {
 
 	training, err := h.svc.UpdateTrainingTimers(c.Request.Context(), trainingID, totalDuration, totalRestTime, totalExerciseTime)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update training timers"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update training timers")
 		return
 	}
 
@@ -902,8 +1104,10 @@ func (h *TrainingHandler) UpdateTrainingTimers(c *gin.Context) {
 // @Param        id path int64 true "Training ID"
 // @Success      200  {object}  dto.TrainingTimeResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/calculate-time [get]
 func (h *TrainingHandler) CalculateTrainingTotalTime(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -914,7 +1118,7 @@ func (h *TrainingHandler) CalculateTrainingTotalTime(c *gin.Context) {
 
 	trainingTime, err := h.svc.CalculateTrainingTotalTime(c.Request.Context(), trainingID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to calculate training time"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to calculate training time")
 		return
 	}
 
@@ -932,18 +1136,18 @@ func (h *TrainingHandler) CalculateTrainingTotalTime(c *gin.Context) {
 // @Description  Возвращает активную тренировку пользователя (если есть)
 // @Tags         trainings
 // @Produce      json
-// @Param        user_id query string true "User ID"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
 // @Success      200  {object}  dto.TrainingResponse
 // @Success      204
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/current [get]
 func (h *TrainingHandler) GetCurrentTraining(c *gin.Context) {
-	uidStr := c.Query("user_id")
-	uid, err := uuid.Parse(uidStr)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
@@ -962,22 +1166,22 @@ func (h *TrainingHandler) GetCurrentTraining(c *gin.Context) {
 // @Description  Возвращает тренировки пользователя, запланированные на сегодня
 // @Tags         trainings
 // @Produce      json
-// @Param        user_id query string true "User ID"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
 // @Success      200  {array}   dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/today [get]
 func (h *TrainingHandler) GetTodaysTraining(c *gin.Context) {
-	uidStr := c.Query("user_id")
-	uid, err := uuid.Parse(uidStr)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
 	trainings, err := h.svc.GetTodaysTraining(c.Request.Context(), uid)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get today's training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get today's training")
 		return
 	}
 
@@ -1001,6 +1205,7 @@ func (h *TrainingHandler) GetTodaysTraining(c *gin.Context) {
 // @Produce      json
 // @Success      200  {array}   dto.GlobalTrainingWithTagsResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /global-trainings [get]
 func (h *TrainingHandler) GetGlobalTrainings(c *gin.Context) {
 	globalTrainings, err := h.svc.GetGlobalTrainings(c.Request.Context())
@@ -1032,6 +1237,7 @@ func (h *TrainingHandler) GetGlobalTrainings(c *gin.Context) {
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /global-trainings/level/{level} [get]
 func (h *TrainingHandler) GetGlobalTrainingByLevel(c *gin.Context) {
 	level := c.Param("level")
@@ -1064,6 +1270,7 @@ func (h *TrainingHandler) GetGlobalTrainingByLevel(c *gin.Context) {
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /global-trainings/{id} [get]
 func (h *TrainingHandler) GetGlobalTrainingById(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -1087,12 +1294,14 @@ func (h *TrainingHandler) GetGlobalTrainingById(c *gin.Context) {
 // @Tags         trainings
 // @Produce      json
 // @Param        id path int64 true "Training ID"
-// @Param        user_id query string true "User ID"
+// @Param        X-Timezone header string false "Часовой пояс IANA, например Asia/Novosibirsk (по умолчанию - из настроек пользователя)"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/mark-done [patch]
 func (h *TrainingHandler) MarkTrainingAsDone(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -1101,20 +1310,15 @@ func (h *TrainingHandler) MarkTrainingAsDone(c *gin.Context) {
 		return
 	}
 
-	uidStr := c.Query("user_id")
-	uid, err := uuid.Parse(uidStr)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
 	training, err := h.svc.MarkTrainingAsDone(c.Request.Context(), trainingID, uid)
 	if err != nil {
-		if err.Error() == "training does not belong to user" {
-			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to mark training as done"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to mark training as done")
 		return
 	}
 
@@ -1129,8 +1333,10 @@ func (h *TrainingHandler) MarkTrainingAsDone(c *gin.Context) {
 // @Param        id path int64 true "Training ID"
 // @Success      200  {object}  dto.TrainingStatsResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/stats [get]
 func (h *TrainingHandler) GetTrainingStats(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -1141,18 +1347,11 @@ func (h *TrainingHandler) GetTrainingStats(c *gin.Context) {
 
 	stats, err := h.svc.GetTrainingStats(c.Request.Context(), trainingID)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get training stats"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to get training stats")
 		return
 	}
 
-	resp := dto.TrainingStatsResponse{
-		TotalTrainings:     stats.TotalTrainings,
-		CompletedTrainings: stats.CompletedTrainings,
-		AverageRating:      stats.AverageRating,
-		TotalDuration:      stats.TotalDuration.String(),
-	}
-
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, h.trainingStatsToResponse(stats))
 }
 
 // StartTraining начинает тренировку
@@ -1161,12 +1360,13 @@ func (h *TrainingHandler) GetTrainingStats(c *gin.Context) {
 // @Tags         trainings
 // @Produce      json
 // @Param        id path int64 true "Training ID"
-// @Param        user_id query string true "User ID"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
+// @Failure      409  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/start [patch]
 func (h *TrainingHandler) StartTraining(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -1175,20 +1375,15 @@ func (h *TrainingHandler) StartTraining(c *gin.Context) {
 		return
 	}
 
-	uidStr := c.Query("user_id")
-	uid, err := uuid.Parse(uidStr)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
 	training, err := h.svc.StartTraining(c.Request.Context(), trainingID, uid)
 	if err != nil {
-		if err.Error() == "training does not belong to user" {
-			c.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to start training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to start training")
 		return
 	}
 
@@ -1205,8 +1400,10 @@ func (h *TrainingHandler) StartTraining(c *gin.Context) {
 // @Param        request body dto.UpdateExerciseRestTimeRequest true "Данные для обновления"
 // @Success      200  {object}  dto.TrainedExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /training-exercises/{id}/rest-time [patch]
 func (h *TrainingHandler) UpdateExerciseRestTime(c *gin.Context) {
 	exerciseID, err := parseInt64Param(c, "id")
@@ -1229,7 +1426,7 @@ func (h *TrainingHandler) UpdateExerciseRestTime(c *gin.Context) {
 
 	exercise, err := h.svc.UpdateExerciseRestTime(c.Request.Context(), exerciseID, restTime)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update exercise rest time"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update exercise rest time")
 		return
 	}
 
@@ -1246,8 +1443,10 @@ func (h *TrainingHandler) UpdateExerciseRestTime(c *gin.Context) {
 // @Param        request body dto.UpdateExerciseDoingTimeRequest true "Данные для обновления"
 // @Success      200  {object}  dto.TrainedExerciseResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /training-exercises/{id}/doing-time [patch]
 func (h *TrainingHandler) UpdateExerciseDoingTime(c *gin.Context) {
 	exerciseID, err := parseInt64Param(c, "id")
@@ -1270,7 +1469,7 @@ func (h *TrainingHandler) UpdateExerciseDoingTime(c *gin.Context) {
 
 	exercise, err := h.svc.UpdateExerciseDoingTime(c.Request.Context(), exerciseID, doingTime)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update exercise doing time"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to update exercise doing time")
 		return
 	}
 
@@ -1279,15 +1478,17 @@ func (h *TrainingHandler) UpdateExerciseDoingTime(c *gin.Context) {
 
 // PauseTraining приостанавливает тренировку
 // @Summary      Приостановить тренировку
-// @Description  Приостанавливает активную тренировку
+// @Description  Приостанавливает активную тренировку. Время паузы не входит в общую длительность
 // @Tags         trainings
 // @Produce      json
 // @Param        id path int64 true "Training ID"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      409  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/pause [patch]
 func (h *TrainingHandler) PauseTraining(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -1298,11 +1499,7 @@ func (h *TrainingHandler) PauseTraining(c *gin.Context) {
 
 	training, err := h.svc.PauseTraining(c.Request.Context(), trainingID)
 	if err != nil {
-		if err.Error() == "training is not active" {
-			c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to pause training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to pause training")
 		return
 	}
 
@@ -1317,9 +1514,11 @@ func (h *TrainingHandler) PauseTraining(c *gin.Context) {
 // @Param        id path int64 true "Training ID"
 // @Success      200  {object}  dto.TrainingResponse
 // @Failure      400  {object}  dto.ErrorResponse
+// @Failure      403  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      409  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /trainings/{id}/resume [patch]
 func (h *TrainingHandler) ResumeTraining(c *gin.Context) {
 	trainingID, err := parseInt64Param(c, "id")
@@ -1330,11 +1529,7 @@ func (h *TrainingHandler) ResumeTraining(c *gin.Context) {
 
 	training, err := h.svc.ResumeTraining(c.Request.Context(), trainingID)
 	if err != nil {
-		if err.Error() == "training is not active" {
-			c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to resume training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to resume training")
 		return
 	}
 
@@ -1352,6 +1547,7 @@ func (h *TrainingHandler) ResumeTraining(c *gin.Context) {
 // @Failure      400  {object}  dto.ErrorResponse
 // @Failure      404  {object}  dto.ErrorResponse
 // @Failure      500  {object}  dto.ErrorResponse
+// @Security     BearerAuth
 // @Router       /global-trainings/assign [post]
 func (h *TrainingHandler) AssignGlobalTraining(c *gin.Context) {
 	var req dto.AssignGlobalTrainingRequest
@@ -1360,9 +1556,9 @@ func (h *TrainingHandler) AssignGlobalTraining(c *gin.Context) {
 		return
 	}
 
-	uid, err := uuid.Parse(req.UserID)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user_id"})
+	uid, ok := currentUserID(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "unauthorized"})
 		return
 	}
 
@@ -1380,7 +1576,7 @@ func (h *TrainingHandler) AssignGlobalTraining(c *gin.Context) {
 
 	training, err := h.svc.AssignGlobalTraining(c.Request.Context(), cmd)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to assign global training"})
+		abortWithServiceError(c, err, http.StatusInternalServerError, "failed to assign global training")
 		return
 	}
 
@@ -1403,6 +1599,24 @@ func (h *TrainingHandler) globalTrainingWithTagsToResponse(gt *svctraining.Globa
 				}
 			}
 
+			var weight *float64
+			if exercise.Weight != nil {
+				f, _ := exercise.Weight.Float64()
+				weight = &f
+			}
+
+			var durationStr, restStr *string
+			if exercise.Duration != nil {
+				s := formatDuration(*exercise.Duration)
+				durationStr = &s
+			}
+			if exercise.Rest != nil {
+				s := formatDuration(*exercise.Rest)
+				restStr = &s
+			}
+
+			position := exercise.Position
+			groupID, groupType := exerciseGroupToResponse(exercise.Group)
 			exercises = append(exercises, dto.ExerciseWithTagsResponse{
 				ID:          exercise.ID,
 				Title:       exercise.Title,
@@ -1410,6 +1624,14 @@ func (h *TrainingHandler) globalTrainingWithTagsToResponse(gt *svctraining.Globa
 				VideoURL:    &exercise.VideoUrl,
 				ImageURL:    &exercise.ImageUrl,
 				Tags:        tags,
+				Position:    &position,
+				Approaches:  exercise.Approaches,
+				Reps:        exercise.Reps,
+				Weight:      weight,
+				Duration:    durationStr,
+				Rest:        restStr,
+				GroupID:     groupID,
+				GroupType:   groupType,
 			})
 		}
 	}
@@ -1422,3 +1644,35 @@ func (h *TrainingHandler) globalTrainingWithTagsToResponse(gt *svctraining.Globa
 		Exercises:   exercises,
 	}
 }
+
+func (h *TrainingHandler) trainingStatsToResponse(stats *svctraining.TrainingStats) dto.TrainingStatsResponse {
+	totalVolume, _ := stats.TotalVolume.Float64()
+	resp := dto.TrainingStatsResponse{
+		TotalTrainings:     stats.TotalTrainings,
+		CompletedTrainings: stats.CompletedTrainings,
+		SkippedTrainings:   stats.SkippedTrainings,
+		CompletionRate:     stats.CompletionRate,
+		AverageRating:      stats.AverageRating,
+		TotalDuration:      stats.TotalDuration.String(),
+		TotalApproaches:    stats.TotalApproaches,
+		TotalReps:          stats.TotalReps,
+		TotalVolume:        totalVolume,
+	}
+	if stats.TotalRestTime > 0 {
+		resp.TotalRestTime = stats.TotalRestTime.String()
+	}
+	if stats.TotalExerciseTime > 0 {
+		resp.TotalExerciseTime = stats.TotalExerciseTime.String()
+	}
+	if stats.TotalDistance.IsPositive() {
+		resp.TotalDistance, _ = stats.TotalDistance.Float64()
+	}
+	if stats.CardioTime > 0 {
+		resp.CardioTime = stats.CardioTime.String()
+	}
+	if stats.AvgPace != nil {
+		resp.AvgPace = formatDuration(*stats.AvgPace)
+	}
+	resp.TotalCalories = stats.TotalCalories
+	return resp
+}
@@ -3,6 +3,8 @@ package postgres
 import (
 	"database/sql"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 func nullTimeFromSQL(st sql.NullTime) *time.Time {
@@ -40,6 +42,17 @@ func nullStringFromSQL(ss sql.NullString) *string {
 	return &ss.String
 }
 
+func nullDecimalFromSQL(sd sql.NullString) *decimal.Decimal {
+	if !sd.Valid {
+		return nil
+	}
+	d, err := decimal.NewFromString(sd.String)
+	if err != nil {
+		return nil
+	}
+	return &d
+}
+
 
 func durationToNullInt64(d *time.Duration) sql.NullInt64 {
 	if d == nil {
@@ -52,6 +65,16 @@ func durationToNullInt64(d *time.Duration) sql.NullInt64 {
 	}
 }
 
+func decimalToNullString(d *decimal.Decimal) sql.NullString {
+	if d == nil {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{
+		String: d.String(),
+		Valid:  true,
+	}
+}
+
 func toDuration(n int64) *time.Duration {
     duration := time.Duration(n) * time.Microsecond
     return &duration
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetTrainingCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.CalendarDay, error) {
+	rows, err := r.q.GetTrainingCalendar(ctx, gen.GetTrainingCalendarParams{
+		FromDate: from,
+		ToDate:   to,
+		UserID:   userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+			"from":    from.Format("2006-01-02"),
+			"to":      to.Format("2006-01-02"),
+		})
+		logging.Error(err, "GetTrainingCalendar", jsonData, "failed to get training calendar")
+		return nil, err
+	}
+
+	// Строки отсортированы по дню, поэтому тренировки дня идут подряд
+	days := make([]domain.CalendarDay, 0, len(rows))
+	for _, row := range rows {
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(row.Day) {
+			days = append(days, domain.CalendarDay{
+				Date:      row.Day,
+				Trainings: []domain.CalendarTraining{},
+			})
+		}
+		if !row.TrainingID.Valid {
+			continue
+		}
+
+		day := &days[len(days)-1]
+		day.Trainings = append(day.Trainings, domain.CalendarTraining{
+			ID:            row.TrainingID.Int64,
+			Title:         row.Title.String,
+			Status:        domain.TrainingStatus(row.Status.String),
+			Rating:        nullIntFromSQL32(row.Rating),
+			TotalDuration: time.Duration(row.TotalDuration) * time.Second,
+			ExerciseCount: int32(row.ExerciseCount),
+		})
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":    userID.String(),
+		"days_count": len(days),
+	})
+	logging.Debug("GetTrainingCalendar", jsonData, "successfully retrieved training calendar")
+
+	return days, nil
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) SetCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	err := r.q.UpsertCalendarToken(ctx, gen.UpsertCalendarTokenParams{
+		UserID:    userID,
+		TokenHash: tokenHash,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "SetCalendarToken", jsonData, "failed to set calendar token")
+		return err
+	}
+	return nil
+}
+
+func (r *TrainingRepositoryImpl) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) (bool, error) {
+	rows, err := r.q.DeleteCalendarToken(ctx, userID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "DeleteCalendarToken", jsonData, "failed to delete calendar token")
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) GetCalendarTokenUser(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	userID, err := r.q.GetCalendarTokenUser(ctx, tokenHash)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logging.Error(err, "GetCalendarTokenUser", nil, "failed to get calendar token owner")
+		}
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
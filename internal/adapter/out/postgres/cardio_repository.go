@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetExerciseType(ctx context.Context, exerciseID int64) (domain.ExerciseType, error) {
+	exerciseType, err := r.q.GetExerciseType(ctx, exerciseID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"exercise_id": exerciseID,
+			})
+			logging.Error(err, "GetExerciseType", jsonData, "failed to get exercise type")
+		}
+		return "", err
+	}
+	return domain.ExerciseType(exerciseType), nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainedExerciseCardio(ctx context.Context, trainedExerciseID int64) (domain.ExerciseType, *domain.CardioMetrics, error) {
+	row, err := r.q.GetTrainedExerciseCardio(ctx, trainedExerciseID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"trained_exercise_id": trainedExerciseID,
+			})
+			logging.Error(err, "GetTrainedExerciseCardio", jsonData, "failed to get trained exercise cardio metrics")
+		}
+		return "", nil, err
+	}
+	return domain.ExerciseType(row.Type), toDomainCardio(row), nil
+}
+
+// saveCardio сохраняет кардио-показатели выполненного упражнения, если они заданы
+func saveCardio(ctx context.Context, q *gen.Queries, trainedExerciseID int64, cardio *domain.CardioMetrics) error {
+	if cardio == nil {
+		return nil
+	}
+	if err := q.UpsertTrainedExerciseCardio(ctx, cardioToSQL(trainedExerciseID, cardio)); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+		})
+		logging.Error(err, "saveCardio", jsonData, "failed to save cardio metrics")
+		return err
+	}
+	return nil
+}
+
+// setCardioStats заполняет кардио-показатели статистики. Время в движении хранится так же,
+// как в trained_exercise_cardio, и переводится через toDuration
+func setCardioStats(stats *domain.TrainingStats, totalDistance, pacedDistance string, pacedTime, calories int64) {
+	stats.TotalDistance, _ = decimal.NewFromString(totalDistance)
+	stats.CardioTime = *toDuration(pacedTime)
+	stats.TotalCalories = calories
+	if distance, err := decimal.NewFromString(pacedDistance); err == nil {
+		stats.AvgPace = domain.CardioPace(&distance, &stats.CardioTime)
+	}
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+// ReorderTrainedExercises присваивает упражнениям тренировки позиции по их порядку в trainedExerciseIDs.
+// Ожидается, что trainedExerciseIDs содержит все упражнения тренировки
+func (r *TrainingRepositoryImpl) ReorderTrainedExercises(ctx context.Context, trainingID int64, trainedExerciseIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "ReorderTrainedExercises", nil, "failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	for i, id := range trainedExerciseIDs {
+		affected, err := q.SetTrainedExercisePosition(ctx, gen.SetTrainedExercisePositionParams{
+			Position:   int32(i + 1),
+			ID:         id,
+			TrainingID: trainingID,
+		})
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"training_id":         trainingID,
+				"trained_exercise_id": id,
+			})
+			logging.Error(err, "ReorderTrainedExercises", jsonData, "failed to set trained exercise position")
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	// Уникальность (training_id, position) проверяется при коммите
+	if err := tx.Commit(); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": trainingID,
+		})
+		logging.Error(err, "ReorderTrainedExercises", jsonData, "failed to commit transaction")
+		return err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"training_id":     trainingID,
+		"exercises_count": len(trainedExerciseIDs),
+	})
+	logging.Debug("ReorderTrainedExercises", jsonData, "successfully reordered trained exercises")
+
+	return nil
+}
+
+// SetTrainedExerciseGroup включает упражнение в группу; при group == nil упражнение исключается из группы
+func (r *TrainingRepositoryImpl) SetTrainedExerciseGroup(ctx context.Context, trainedExerciseID int64, userID uuid.UUID, group *domain.ExerciseGroup) (*domain.TrainedExercise, error) {
+	groupID, groupType := exerciseGroupToSQL(group)
+	updated, err := r.q.SetTrainedExerciseGroup(ctx, gen.SetTrainedExerciseGroupParams{
+		GroupID:   groupID,
+		GroupType: groupType,
+		ID:        trainedExerciseID,
+		UserID:    userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+		})
+		logging.Error(err, "SetTrainedExerciseGroup", jsonData, "failed to set trained exercise group")
+		return nil, err
+	}
+
+	domainExercise := r.toDomainTrainedExercise(gen.AddExerciseToTrainingRow{
+		ID:         updated.ID,
+		TrainingID: updated.TrainingID,
+		ExerciseID: updated.ExerciseID,
+		Weight:     updated.Weight,
+		Approaches: updated.Approaches,
+		Reps:       updated.Reps,
+		Time:       updated.Time,
+		Doing:      updated.Doing,
+		Rest:       updated.Rest,
+		Notes:      updated.Notes,
+		Position:   updated.Position,
+		GroupID:    updated.GroupID,
+		GroupType:  updated.GroupType,
+	})
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": domainExercise.ID,
+		"training_id":         domainExercise.TrainingID,
+		"grouped":             group != nil,
+	})
+	logging.Debug("SetTrainedExerciseGroup", jsonData, "successfully set trained exercise group")
+
+	return domainExercise, nil
+}
@@ -88,6 +88,7 @@ func (r *ExerciseRepositoryImpl) GetExercisesByTag(ctx context.Context, tagID in
 			Description: e.Description,
 			VideoUrl:    e.VideoUrl,
 			ImageUrl:    e.ImageUrl,
+			Type:        domain.ExerciseType(e.Type),
 		}
 	}
 
@@ -166,7 +167,14 @@ func (r *ExerciseRepositoryImpl) GetAllTags(ctx context.Context) ([]*domain.Tag,
 }
 
 func (r *ExerciseRepositoryImpl) GetTagByID(ctx context.Context, id int64) (*domain.Tag, error) {
-	tags, err := r.q.GetAllTags(ctx)
+	tag, err := r.q.GetTagByID(ctx, id)
+	if err == sql.ErrNoRows {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"tag_id": id,
+		})
+		logging.Warn("GetTagByID", jsonData, "tag not found")
+		return nil, err
+	}
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"tag_id": id,
@@ -175,28 +183,13 @@ func (r *ExerciseRepositoryImpl) GetTagByID(ctx context.Context, id int64) (*dom
 		return nil, err
 	}
 
-	for _, tag := range tags {
-		if tag.ID == id {
-			domainTag := &domain.Tag{
-				ID:   tag.ID,
-				Type: tag.Type,
-			}
-
-			jsonData := logging.MarshalLogData(map[string]interface{}{
-				"tag_id": id,
-				"type":   tag.Type,
-			})
-			logging.Debug("GetTagByID", jsonData, "successfully retrieved tag by id")
-
-			return domainTag, nil
-		}
-	}
-
 	jsonData := logging.MarshalLogData(map[string]interface{}{
 		"tag_id": id,
+		"type":   tag.Type,
 	})
-	logging.Warn("GetTagByID", jsonData, "tag not found")
-	return nil, sql.ErrNoRows
+	logging.Debug("GetTagByID", jsonData, "successfully retrieved tag by id")
+
+	return &domain.Tag{ID: tag.ID, Type: tag.Type}, nil
 }
 
 func (r *ExerciseRepositoryImpl) GetExerciseTags(ctx context.Context, exerciseID int64) ([]*domain.Tag, error) {
@@ -231,6 +224,7 @@ func (r *ExerciseRepositoryImpl) toDomainExercise(e gen.GetExercisesWithTagsRow)
 		Description: e.Description,
 		VideoUrl:    e.VideoUrl,
 		ImageUrl:    e.ImageUrl,
+		Type:        domain.ExerciseType(e.Type),
 		Tags:        toDomainTags(e.Tags),
 	}
 }
@@ -242,6 +236,248 @@ func (r *ExerciseRepositoryImpl) toDomainExerciseFromJoined(e gen.GetExerciseByI
 		Description: e.Description,
 		VideoUrl:    e.VideoUrl,
 		ImageUrl:    e.ImageUrl,
+		Type:        domain.ExerciseType(e.Type),
 		Tags:        toDomainTags(e.Tags),
 	}
 }
+
+func (r *ExerciseRepositoryImpl) CreateExercise(ctx context.Context, exercise *domain.Exercise) (*domain.Exercise, error) {
+	created, err := r.q.CreateExercise(ctx, gen.CreateExerciseParams{
+		Title:       exercise.Title,
+		Description: exercise.Description,
+		VideoUrl:    exercise.VideoUrl,
+		ImageUrl:    exercise.ImageUrl,
+		Type:        string(exercise.Type),
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"title": exercise.Title,
+		})
+		logging.Error(err, "CreateExercise", jsonData, "failed to create exercise")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"exercise_id": created.ID,
+	})
+	logging.Info("CreateExercise", jsonData, "exercise created")
+
+	return r.toDomainExerciseFromModel(created), nil
+}
+
+func (r *ExerciseRepositoryImpl) UpdateExercise(ctx context.Context, exercise *domain.Exercise) (*domain.Exercise, error) {
+	updated, err := r.q.UpdateExercise(ctx, gen.UpdateExerciseParams{
+		Title:       exercise.Title,
+		Description: exercise.Description,
+		VideoUrl:    exercise.VideoUrl,
+		ImageUrl:    exercise.ImageUrl,
+		Type:        string(exercise.Type),
+		ID:          exercise.ID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_id": exercise.ID,
+		})
+		logging.Error(err, "UpdateExercise", jsonData, "failed to update exercise")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"exercise_id": updated.ID,
+	})
+	logging.Info("UpdateExercise", jsonData, "exercise updated")
+
+	return r.toDomainExerciseFromModel(updated), nil
+}
+
+func (r *ExerciseRepositoryImpl) DeleteExercise(ctx context.Context, id int64) (bool, error) {
+	affected, err := r.q.DeleteExercise(ctx, id)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_id": id,
+		})
+		logging.Error(err, "DeleteExercise", jsonData, "failed to delete exercise")
+		return false, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"exercise_id": id,
+		"deleted":     affected > 0,
+	})
+	logging.Info("DeleteExercise", jsonData, "exercise delete finished")
+
+	return affected > 0, nil
+}
+
+func (r *ExerciseRepositoryImpl) IsExerciseUsed(ctx context.Context, id int64) (bool, error) {
+	used, err := r.q.IsExerciseUsed(ctx, id)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_id": id,
+		})
+		logging.Error(err, "IsExerciseUsed", jsonData, "failed to check exercise usage")
+		return false, err
+	}
+	return used, nil
+}
+
+func (r *ExerciseRepositoryImpl) CreateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error) {
+	created, err := r.q.CreateTag(ctx, tag.Type)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"type": tag.Type,
+		})
+		logging.Error(err, "CreateTag", jsonData, "failed to create tag")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"tag_id": created.ID,
+	})
+	logging.Info("CreateTag", jsonData, "tag created")
+
+	return &domain.Tag{ID: created.ID, Type: created.Type}, nil
+}
+
+func (r *ExerciseRepositoryImpl) UpdateTag(ctx context.Context, tag *domain.Tag) (*domain.Tag, error) {
+	updated, err := r.q.UpdateTag(ctx, gen.UpdateTagParams{
+		Type: tag.Type,
+		ID:   tag.ID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"tag_id": tag.ID,
+		})
+		logging.Error(err, "UpdateTag", jsonData, "failed to update tag")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"tag_id": updated.ID,
+	})
+	logging.Info("UpdateTag", jsonData, "tag updated")
+
+	return &domain.Tag{ID: updated.ID, Type: updated.Type}, nil
+}
+
+func (r *ExerciseRepositoryImpl) DeleteTag(ctx context.Context, id int64) (bool, error) {
+	affected, err := r.q.DeleteTag(ctx, id)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"tag_id": id,
+		})
+		logging.Error(err, "DeleteTag", jsonData, "failed to delete tag")
+		return false, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"tag_id":  id,
+		"deleted": affected > 0,
+	})
+	logging.Info("DeleteTag", jsonData, "tag delete finished")
+
+	return affected > 0, nil
+}
+
+func (r *ExerciseRepositoryImpl) AttachTagToExercise(ctx context.Context, exerciseID, tagID int64) error {
+	err := r.q.AttachTagToExercise(ctx, gen.AttachTagToExerciseParams{
+		ExerciseID: exerciseID,
+		TagID:      tagID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_id": exerciseID,
+			"tag_id":      tagID,
+		})
+		logging.Error(err, "AttachTagToExercise", jsonData, "failed to attach tag to exercise")
+		return err
+	}
+	return nil
+}
+
+func (r *ExerciseRepositoryImpl) DetachTagFromExercise(ctx context.Context, exerciseID, tagID int64) (bool, error) {
+	affected, err := r.q.DetachTagFromExercise(ctx, gen.DetachTagFromExerciseParams{
+		ExerciseID: exerciseID,
+		TagID:      tagID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_id": exerciseID,
+			"tag_id":      tagID,
+		})
+		logging.Error(err, "DetachTagFromExercise", jsonData, "failed to detach tag from exercise")
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *ExerciseRepositoryImpl) GetExerciseAliases(ctx context.Context, exerciseID int64) ([]string, error) {
+	aliases, err := r.q.GetExerciseAliases(ctx, exerciseID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_id": exerciseID,
+		})
+		logging.Error(err, "GetExerciseAliases", jsonData, "failed to get exercise aliases")
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// SetExerciseAliases заменяет другие названия упражнения одной транзакцией.
+// Если название уже принадлежит другому упражнению, изменения откатываются и возвращается false
+func (r *ExerciseRepositoryImpl) SetExerciseAliases(ctx context.Context, exerciseID int64, aliases []string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "SetExerciseAliases", nil, "failed to begin transaction")
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"exercise_id": exerciseID,
+	})
+
+	if err := q.DeleteExerciseAliases(ctx, exerciseID); err != nil {
+		logging.Error(err, "SetExerciseAliases", jsonData, "failed to delete exercise aliases")
+		return false, err
+	}
+	for _, alias := range aliases {
+		affected, err := q.AddExerciseAlias(ctx, gen.AddExerciseAliasParams{
+			ExerciseID: exerciseID,
+			Alias:      alias,
+		})
+		if err != nil {
+			logging.Error(err, "SetExerciseAliases", jsonData, "failed to add exercise alias")
+			return false, err
+		}
+		if affected == 0 {
+			return false, nil
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "SetExerciseAliases", jsonData, "failed to commit transaction")
+		return false, err
+	}
+
+	jsonData = logging.MarshalLogData(map[string]interface{}{
+		"exercise_id":   exerciseID,
+		"aliases_count": len(aliases),
+	})
+	logging.Info("SetExerciseAliases", jsonData, "exercise aliases updated")
+
+	return true, nil
+}
+
+func (r *ExerciseRepositoryImpl) toDomainExerciseFromModel(e gen.Exercise) *domain.Exercise {
+	return &domain.Exercise{
+		ID:          e.ID,
+		Title:       e.Title,
+		Description: e.Description,
+		VideoUrl:    e.VideoUrl,
+		ImageUrl:    e.ImageUrl,
+		Type:        domain.ExerciseType(e.Type),
+	}
+}
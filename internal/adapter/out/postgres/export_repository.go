@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetTrainingsForExport(ctx context.Context, userID uuid.UUID, from, to *time.Time, after *domain.ExportCursor, limit int32) ([]*domain.Training, error) {
+	params := gen.GetTrainingsForExportParams{
+		UserID:    userID,
+		FromDate:  null.TimeFromPtr(from).NullTime,
+		ToDate:    null.TimeFromPtr(to).NullTime,
+		BatchSize: limit,
+	}
+	if after != nil {
+		params.AfterDate = sql.NullTime{Time: after.PlannedDate, Valid: true}
+		params.AfterID = sql.NullInt64{Int64: after.ID, Valid: true}
+	}
+
+	rows, err := r.q.GetTrainingsForExport(ctx, params)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+			"after":   after,
+		})
+		logging.Error(err, "GetTrainingsForExport", jsonData, "failed to get trainings for export")
+		return nil, err
+	}
+
+	trainings := make([]*domain.Training, len(rows))
+	for i, row := range rows {
+		trainings[i] = r.toDomainTrainingFromJoined(gen.GetTrainingWithExercisesRow(row))
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":         userID.String(),
+		"trainings_count": len(trainings),
+	})
+	logging.Debug("GetTrainingsForExport", jsonData, "successfully retrieved trainings for export")
+
+	return trainings, nil
+}
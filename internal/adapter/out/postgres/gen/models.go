@@ -11,12 +11,24 @@ import (
 	"github.com/google/uuid"
 )
 
+type CalendarToken struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Exercise struct {
 	ID          int64  `json:"id"`
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	VideoUrl    string `json:"video_url"`
 	ImageUrl    string `json:"image_url"`
+	Type        string `json:"type"`
+}
+
+type ExerciseAlias struct {
+	ExerciseID int64  `json:"exercise_id"`
+	Alias      string `json:"alias"`
 }
 
 type ExerciseToTag struct {
@@ -32,9 +44,55 @@ type GlobalTraining struct {
 }
 
 type GlobalTrainingExercise struct {
-	ID               int64 `json:"id"`
-	GlobalTrainingID int64 `json:"global_training_id"`
-	ExerciseID       int64 `json:"exercise_id"`
+	ID               int64          `json:"id"`
+	GlobalTrainingID int64          `json:"global_training_id"`
+	ExerciseID       int64          `json:"exercise_id"`
+	Position         int32          `json:"position"`
+	Approaches       sql.NullInt32  `json:"approaches"`
+	Reps             sql.NullInt32  `json:"reps"`
+	Weight           sql.NullString `json:"weight"`
+	Duration         sql.NullInt64  `json:"duration"`
+	Rest             sql.NullInt64  `json:"rest"`
+	GroupID          sql.NullInt32  `json:"group_id"`
+	GroupType        sql.NullString `json:"group_type"`
+}
+
+type PersonalRecord struct {
+	ID                int64          `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	ExerciseID        int64          `json:"exercise_id"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Type              string         `json:"type"`
+	Value             string         `json:"value"`
+	Weight            sql.NullString `json:"weight"`
+	AchievedAt        time.Time      `json:"achieved_at"`
+}
+
+type Program struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	Weeks       int32  `json:"weeks"`
+}
+
+type ProgramEnrollment struct {
+	ID        int64     `json:"id"`
+	ProgramID int64     `json:"program_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	StartDate time.Time `json:"start_date"`
+	Weekdays  []int32   `json:"weekdays"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ProgramSession struct {
+	ID               int64          `json:"id"`
+	ProgramID        int64          `json:"program_id"`
+	Week             int32          `json:"week"`
+	Day              int32          `json:"day"`
+	GlobalTrainingID int64          `json:"global_training_id"`
+	WeightIncrement  sql.NullString `json:"weight_increment"`
+	RepsIncrement    sql.NullInt32  `json:"reps_increment"`
 }
 
 type Tag struct {
@@ -53,6 +111,35 @@ type TrainedExercise struct {
 	Doing      sql.NullInt64  `json:"doing"`
 	Rest       sql.NullInt64  `json:"rest"`
 	Notes      sql.NullString `json:"notes"`
+	Position   int32          `json:"position"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
+}
+
+type TrainedExerciseCardio struct {
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Distance          sql.NullString `json:"distance"`
+	MovingTime        sql.NullInt64  `json:"moving_time"`
+	ElevationGain     sql.NullString `json:"elevation_gain"`
+	AvgHeartRate      sql.NullInt32  `json:"avg_heart_rate"`
+	MaxHeartRate      sql.NullInt32  `json:"max_heart_rate"`
+	AvgPace           sql.NullInt64  `json:"avg_pace"`
+	DistanceUnit      sql.NullString `json:"distance_unit"`
+	AvgSpeed          sql.NullString `json:"avg_speed"`
+	Calories          sql.NullInt32  `json:"calories"`
+	Incline           sql.NullString `json:"incline"`
+	ResistanceLevel   sql.NullInt32  `json:"resistance_level"`
+}
+
+type TrainedSet struct {
+	ID                int64          `json:"id"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Ordinal           int32          `json:"ordinal"`
+	Weight            sql.NullString `json:"weight"`
+	Reps              sql.NullInt32  `json:"reps"`
+	Duration          sql.NullInt64  `json:"duration"`
+	Rpe               sql.NullString `json:"rpe"`
+	IsWarmup          bool           `json:"is_warmup"`
 }
 
 type Training struct {
@@ -68,4 +155,63 @@ type Training struct {
 	TotalRestTime     sql.NullInt64 `json:"total_rest_time"`
 	TotalExerciseTime sql.NullInt64 `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	ScheduleID        sql.NullInt64 `json:"schedule_id"`
+	OccurrenceDate    sql.NullTime  `json:"occurrence_date"`
+	EnrollmentID      sql.NullInt64 `json:"enrollment_id"`
+	ProgramSessionID  sql.NullInt64 `json:"program_session_id"`
+}
+
+type TrainingPause struct {
+	ID         int64        `json:"id"`
+	TrainingID int64        `json:"training_id"`
+	StartedAt  time.Time    `json:"started_at"`
+	EndedAt    sql.NullTime `json:"ended_at"`
+}
+
+type TrainingSchedule struct {
+	ID                int64         `json:"id"`
+	UserID            uuid.UUID     `json:"user_id"`
+	Title             string        `json:"title"`
+	Rrule             string        `json:"rrule"`
+	StartDate         time.Time     `json:"start_date"`
+	GlobalTrainingID  sql.NullInt64 `json:"global_training_id"`
+	TemplateID        sql.NullInt64 `json:"template_id"`
+	MaterializedUntil sql.NullTime  `json:"materialized_until"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
+
+type TrainingScheduleSkip struct {
+	ScheduleID     int64     `json:"schedule_id"`
+	OccurrenceDate time.Time `json:"occurrence_date"`
+}
+
+type TrainingTemplate struct {
+	ID          int64     `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TrainingTemplateExercise struct {
+	ID         int64          `json:"id"`
+	TemplateID int64          `json:"template_id"`
+	ExerciseID int64          `json:"exercise_id"`
+	Position   int32          `json:"position"`
+	Approaches sql.NullInt32  `json:"approaches"`
+	Reps       sql.NullInt32  `json:"reps"`
+	Weight     sql.NullString `json:"weight"`
+	Duration   sql.NullInt64  `json:"duration"`
+	Rest       sql.NullInt64  `json:"rest"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
+}
+
+type UserSetting struct {
+	UserID    uuid.UUID      `json:"user_id"`
+	WeekStart int32          `json:"week_start"`
+	RestDays  int32          `json:"rest_days"`
+	Timezone  sql.NullString `json:"timezone"`
+	UpdatedAt time.Time      `json:"updated_at"`
 }
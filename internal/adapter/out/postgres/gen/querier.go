@@ -6,21 +6,102 @@ package gen
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	// Название, которое уже занято другим упражнением (без учета регистра), не добавляется
+	AddExerciseAlias(ctx context.Context, arg AddExerciseAliasParams) (int64, error)
+	// Добавление упражнения только в тренировку, принадлежащую пользователю
 	AddExerciseToTraining(ctx context.Context, arg AddExerciseToTrainingParams) (AddExerciseToTrainingRow, error)
+	AddGlobalTrainingExercise(ctx context.Context, arg AddGlobalTrainingExerciseParams) error
+	// Упражнения шаблона с целевой нагрузкой добавляются в тренировку в порядке шаблона
+	AddTemplateExercisesToTraining(ctx context.Context, arg AddTemplateExercisesToTrainingParams) error
+	// Новый подход добавляется в конец списка
+	AddTrainedSet(ctx context.Context, arg AddTrainedSetParams) (AddTrainedSetRow, error)
+	AddTrainingTemplateExercise(ctx context.Context, arg AddTrainingTemplateExerciseParams) error
+	AttachTagToExercise(ctx context.Context, arg AttachTagToExerciseParams) error
 	// Расчет общего времени тренировки на основе всех упражнений
-	CalculateTrainingTotalTime(ctx context.Context, trainingID int64) (CalculateTrainingTotalTimeRow, error)
+	CalculateTrainingTotalTime(ctx context.Context, arg CalculateTrainingTotalTimeParams) (CalculateTrainingTotalTimeRow, error)
+	// Завершение тренировки с оценкой и итоговой продолжительностью. Условие на статус защищает
+	// от параллельного пропуска, отмены или повторного завершения
+	CompleteTraining(ctx context.Context, arg CompleteTrainingParams) (CompleteTrainingRow, error)
+	// Копирование всех упражнений тренировки в другую тренировку в том же порядке
+	CopyTrainedExercises(ctx context.Context, arg CopyTrainedExercisesParams) error
+	// Упражнения тренировки становятся упражнениями шаблона в порядке добавления,
+	// время выполнения упражнения - целевой длительностью
+	CopyTrainedExercisesToTemplate(ctx context.Context, arg CopyTrainedExercisesToTemplateParams) error
+	CountExercisesByIDs(ctx context.Context, ids []int64) (int64, error)
+	CountGlobalTrainingsByIDs(ctx context.Context, ids []int64) (int64, error)
+	// Администрирование каталога упражнений
+	CreateExercise(ctx context.Context, arg CreateExerciseParams) (Exercise, error)
+	// Администрирование глобальных тренировок
+	CreateGlobalTraining(ctx context.Context, arg CreateGlobalTrainingParams) (GlobalTraining, error)
+	// Сохранить результат завершенной тренировки, если он лучше текущего рекорда того же типа
+	// (для max_reps - при том же весе). Если рекорд не побит, строка не вставляется
+	CreatePersonalRecord(ctx context.Context, arg CreatePersonalRecordParams) (PersonalRecord, error)
+	// Запланированная тренировка без упражнений: копия тренировки или тренировка по шаблону
+	CreatePlannedTraining(ctx context.Context, arg CreatePlannedTrainingParams) (int64, error)
+	// Администрирование программ
+	CreateProgram(ctx context.Context, arg CreateProgramParams) (Program, error)
+	CreateProgramEnrollment(ctx context.Context, arg CreateProgramEnrollmentParams) (int64, error)
+	// Тренировка тренировочного дня программы
+	CreateProgramTraining(ctx context.Context, arg CreateProgramTrainingParams) (int64, error)
+	// Тренировка повторения расписания; если тренировка на этот день повторения уже есть, строка не возвращается
+	CreateScheduledTraining(ctx context.Context, arg CreateScheduledTrainingParams) (int64, error)
+	CreateTag(ctx context.Context, type_ string) (Tag, error)
 	CreateTraining(ctx context.Context, arg CreateTrainingParams) (CreateTrainingRow, error)
+	CreateTrainingSchedule(ctx context.Context, arg CreateTrainingScheduleParams) (TrainingSchedule, error)
+	CreateTrainingScheduleSkip(ctx context.Context, arg CreateTrainingScheduleSkipParams) error
+	CreateTrainingTemplate(ctx context.Context, arg CreateTrainingTemplateParams) (int64, error)
+	DeleteCalendarToken(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteExercise(ctx context.Context, id int64) (int64, error)
+	DeleteExerciseAliases(ctx context.Context, exerciseID int64) error
 	DeleteExerciseFromTraining(ctx context.Context, arg DeleteExerciseFromTrainingParams) error
-	DeleteTrainingAndExercises(ctx context.Context, id int64) error
+	DeleteGlobalTraining(ctx context.Context, id int64) (int64, error)
+	DeleteGlobalTrainingExercises(ctx context.Context, globalTrainingID int64) error
+	// Удалить рекорды, установленные выполненным упражнением, перед их пересчетом
+	DeletePersonalRecordsByTrainedExercise(ctx context.Context, arg DeletePersonalRecordsByTrainedExerciseParams) error
+	// Удаление еще не начатых тренировок программы начиная с $2
+	DeletePlannedEnrollmentTrainings(ctx context.Context, arg DeletePlannedEnrollmentTrainingsParams) (int64, error)
+	// Удаление еще не начатых тренировок расписания для повторений начиная с from_date (и до to_date, если задано)
+	DeletePlannedScheduledTrainings(ctx context.Context, arg DeletePlannedScheduledTrainingsParams) (int64, error)
+	DeleteProgram(ctx context.Context, id int64) (int64, error)
+	DeleteProgramEnrollment(ctx context.Context, arg DeleteProgramEnrollmentParams) (int64, error)
+	// Удаление тренировочных дней, которых нет в новом списке (пары week[i], day[i])
+	DeleteRemovedProgramSessions(ctx context.Context, arg DeleteRemovedProgramSessionsParams) error
+	DeleteTag(ctx context.Context, id int64) (int64, error)
+	DeleteTrainedSet(ctx context.Context, arg DeleteTrainedSetParams) (int64, error)
+	DeleteTrainingAndExercises(ctx context.Context, arg DeleteTrainingAndExercisesParams) error
+	DeleteTrainingSchedule(ctx context.Context, arg DeleteTrainingScheduleParams) (int64, error)
+	DeleteTrainingTemplate(ctx context.Context, arg DeleteTrainingTemplateParams) (int64, error)
+	DeleteTrainingTemplateExercises(ctx context.Context, templateID int64) error
+	DetachTagFromExercise(ctx context.Context, arg DetachTagFromExerciseParams) (int64, error)
+	// Вернуть тренировку в статус in_progress и закрыть открытую паузу.
+	// Число строк - по обновлению тренировки, то есть по фактической смене статуса
+	EndTrainingPause(ctx context.Context, arg EndTrainingPauseParams) (int64, error)
 	GetAllTags(ctx context.Context) ([]Tag, error)
-	// Получение тренировки на сегодня для пользователя
-	GetCurrentTraining(ctx context.Context, userID uuid.UUID) (GetCurrentTrainingRow, error)
+	GetCalendarTokenUser(ctx context.Context, tokenHash string) (uuid.UUID, error)
+	// Получение тренировки на сегодня для пользователя.
+	// $2 - сегодняшняя дата в часовом поясе пользователя
+	GetCurrentTraining(ctx context.Context, arg GetCurrentTrainingParams) (GetCurrentTrainingRow, error)
+	// Прохождение программы: число тренировок по статусам и ближайшая невыполненная тренировка
+	GetEnrollmentProgress(ctx context.Context, id int64) (GetEnrollmentProgressRow, error)
+	GetExerciseAliases(ctx context.Context, exerciseID int64) ([]string, error)
 	GetExerciseByID(ctx context.Context, id int64) (GetExerciseByIDRow, error)
+	// Названия и типы упражнений каталога с другими названиями для сопоставления при импорте истории
+	GetExerciseCatalogNames(ctx context.Context) ([]GetExerciseCatalogNamesRow, error)
+	// Текущие рекорды пользователя по одному упражнению
+	GetExercisePersonalRecords(ctx context.Context, arg GetExercisePersonalRecordsParams) ([]GetExercisePersonalRecordsRow, error)
+	// Рабочие подходы упражнения в завершенных тренировках пользователя за период.
+	// Для упражнений без подходов берутся вес и повторения самого упражнения на все его подходы.
+	// Упражнения, в которых записаны только разминочные подходы, не учитываются
+	GetExerciseStrengthEfforts(ctx context.Context, arg GetExerciseStrengthEffortsParams) ([]GetExerciseStrengthEffortsRow, error)
+	// Тип упражнения каталога для проверки кардио-показателей
+	GetExerciseType(ctx context.Context, id int64) (string, error)
 	GetExercisesByTag(ctx context.Context, tagID int64) ([]Exercise, error)
 	GetExercisesWithTags(ctx context.Context) ([]GetExercisesWithTagsRow, error)
 	// Получение глобальной тренировки по ID с упражнениями и их тегами
@@ -28,25 +109,123 @@ type Querier interface {
 	GetGlobalTrainingById(ctx context.Context, id int64) (GetGlobalTrainingByIdRow, error)
 	// Получение глобальных тренировок по уровню с упражнениями и их тегами
 	GetGlobalTrainingByLevel(ctx context.Context, level string) ([]GetGlobalTrainingByLevelRow, error)
-	GetGlobalTrainingExercises(ctx context.Context, globalTrainingID int64) ([]GlobalTrainingExercise, error)
+	// Слоты глобальной тренировки с предписанной нагрузкой в порядке выполнения
+	GetGlobalTrainingExercises(ctx context.Context, globalTrainingID int64) ([]GetGlobalTrainingExercisesRow, error)
 	// Получение всех глобальных тренировок с упражнениями и их тегами
 	GetGlobalTrainings(ctx context.Context) ([]GetGlobalTrainingsRow, error)
-	// Получение всех тренировок на сегодня для пользователя
-	GetTodaysTraining(ctx context.Context, userID uuid.UUID) ([]GetTodaysTrainingRow, error)
-	// Получение статистики по тренировке (общее время выполнения и отдыха)
-	GetTrainingStats(ctx context.Context, id int64) (GetTrainingStatsRow, error)
-	GetTrainingWithExercises(ctx context.Context, id int64) (GetTrainingWithExercisesRow, error)
+	// Текущие рекорды пользователя: лучший результат по упражнению, типу и весу
+	GetPersonalRecords(ctx context.Context, userID uuid.UUID) ([]GetPersonalRecordsRow, error)
+	GetProgramByID(ctx context.Context, id int64) (Program, error)
+	GetProgramEnrollment(ctx context.Context, arg GetProgramEnrollmentParams) (GetProgramEnrollmentRow, error)
+	GetProgramEnrollments(ctx context.Context, userID uuid.UUID) ([]GetProgramEnrollmentsRow, error)
+	// Тренировочные дни программы по порядку недель и дней
+	GetProgramSessions(ctx context.Context, programID int64) ([]GetProgramSessionsRow, error)
+	GetPrograms(ctx context.Context) ([]Program, error)
+	// Последние выполнения упражнения в завершенных тренировках пользователя, начиная с последнего
+	GetRecentExerciseSessions(ctx context.Context, arg GetRecentExerciseSessionsParams) ([]GetRecentExerciseSessionsRow, error)
+	// Тренировки, созданные по расписанию для повторений с from_date по to_date
+	GetScheduledTrainings(ctx context.Context, arg GetScheduledTrainingsParams) ([]GetScheduledTrainingsRow, error)
+	// Расписания, тренировки по которым созданы не до конца горизонта $1
+	GetSchedulesToMaterialize(ctx context.Context, materializedUntil sql.NullTime) ([]TrainingSchedule, error)
+	GetTagByID(ctx context.Context, id int64) (Tag, error)
+	// Получение всех тренировок на сегодня для пользователя.
+	// $2 - сегодняшняя дата в часовом поясе пользователя
+	GetTodaysTraining(ctx context.Context, arg GetTodaysTrainingParams) ([]GetTodaysTrainingRow, error)
+	// Тип упражнения каталога и сохраненные кардио-показатели выполненного упражнения
+	GetTrainedExerciseCardio(ctx context.Context, id int64) (GetTrainedExerciseCardioRow, error)
+	// Владелец и тренировка, в которую входит выполненное упражнение
+	GetTrainedExerciseOwner(ctx context.Context, id int64) (GetTrainedExerciseOwnerRow, error)
+	// Календарь тренировок пользователя по запланированным датам с from_date по to_date: строка на каждую тренировку
+	// и по одной строке с пустыми полями тренировки на дни без тренировок
+	GetTrainingCalendar(ctx context.Context, arg GetTrainingCalendarParams) ([]GetTrainingCalendarRow, error)
+	// Владелец тренировки (для проверки доступа)
+	GetTrainingOwner(ctx context.Context, id int64) (uuid.UUID, error)
+	// Суммарное время пауз в микросекундах; открытая пауза считается до текущего момента
+	GetTrainingPausedTime(ctx context.Context, arg GetTrainingPausedTimeParams) (int64, error)
+	GetTrainingSchedule(ctx context.Context, arg GetTrainingScheduleParams) (TrainingSchedule, error)
+	GetTrainingScheduleSkips(ctx context.Context, scheduleID int64) ([]time.Time, error)
+	GetTrainingSchedules(ctx context.Context, userID uuid.UUID) ([]TrainingSchedule, error)
+	// Получение статистики по тренировке (общее время выполнения и отдыха).
+	// Для упражнений с подходами считаются только рабочие подходы, для остальных — агрегаты упражнения
+	GetTrainingStats(ctx context.Context, arg GetTrainingStatsParams) (GetTrainingStatsRow, error)
+	GetTrainingTemplate(ctx context.Context, arg GetTrainingTemplateParams) (TrainingTemplate, error)
+	// Упражнения шаблона с целевой нагрузкой в порядке выполнения
+	GetTrainingTemplateExercises(ctx context.Context, templateID int64) ([]GetTrainingTemplateExercisesRow, error)
+	// Шаблоны пользователя, начиная с последнего созданного
+	GetTrainingTemplates(ctx context.Context, userID uuid.UUID) ([]TrainingTemplate, error)
+	// Даты и названия тренировок пользователя с from по to: по ним импорт находит уже загруженные тренировки
+	GetTrainingTitlesInRange(ctx context.Context, arg GetTrainingTitlesInRangeParams) ([]GetTrainingTitlesInRangeRow, error)
+	// Подходы агрегируются в approaches/reps/weight упражнения, если они есть:
+	// approaches — число рабочих подходов, reps — сумма повторений, weight — максимальный вес
+	GetTrainingWithExercises(ctx context.Context, arg GetTrainingWithExercisesParams) (GetTrainingWithExercisesRow, error)
 	GetTrainingsByUser(ctx context.Context, userID uuid.UUID) ([]GetTrainingsByUserRow, error)
-	// Отметить тренировку как выполненную
+	// Страница тренировок пользователя с фильтрами и keyset-пагинацией.
+	// sort_key - числовой ключ сортировки (sort_by: 'planned_date', 'actual_date' или 'rating'): даты - дни от 1970-01-01,
+	// тренировки без фактической даты или рейтинга идут в конце при сортировке по убыванию.
+	// cursor_key и cursor_id - ключ и ID последней записи предыдущей страницы
+	GetTrainingsFiltered(ctx context.Context, arg GetTrainingsFilteredParams) ([]GetTrainingsFilteredRow, error)
+	// Пачка тренировок пользователя с упражнениями и подходами для выгрузки, по planned_date и id.
+	// after_date и after_id - последняя тренировка предыдущей пачки; для первой пачки не задаются
+	GetTrainingsForExport(ctx context.Context, arg GetTrainingsForExportParams) ([]GetTrainingsForExportRow, error)
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (UserSetting, error)
+	// Даты и статусы всех тренировок пользователя для расчета серий и регулярности
+	GetUserTrainingDates(ctx context.Context, userID uuid.UUID) ([]GetUserTrainingDatesRow, error)
+	// Статистика тренировок пользователя за период с разбивкой по дням, неделям или месяцам (group_by - 'day', 'week' или 'month').
+	// Объем и кардио-показатели, как и в GetUserTrainingStatsTotals, считаются по завершенным тренировкам.
+	// Периоды без тренировок не возвращаются
+	GetUserTrainingStatsByPeriod(ctx context.Context, arg GetUserTrainingStatsByPeriodParams) ([]GetUserTrainingStatsByPeriodRow, error)
+	// Статистика тренировок пользователя за период.
+	// Объем считается так же, как в GetTrainingStats: по рабочим подходам, а без подходов - по агрегатам упражнения.
+	// Объем и кардио-показатели считаются по завершенным тренировкам; темп - по упражнениям, у которых есть и дистанция, и время
+	GetUserTrainingStatsTotals(ctx context.Context, arg GetUserTrainingStatsTotalsParams) (GetUserTrainingStatsTotalsRow, error)
+	// Рабочие подходы нескольких выполненных упражнений в порядке выполнения
+	GetWorkingSetsByTrainedExercises(ctx context.Context, ids []int64) ([]GetWorkingSetsByTrainedExercisesRow, error)
+	// Используется ли упражнение в тренировках и шаблонах пользователей или глобальных тренировках
+	IsExerciseUsed(ctx context.Context, exerciseID int64) (bool, error)
+	// Входит ли глобальная тренировка в сессии программ
+	IsGlobalTrainingUsedByProgram(ctx context.Context, globalTrainingID int64) (bool, error)
+	// Подходы выполненного упражнения в порядке выполнения
+	ListTrainedSets(ctx context.Context, arg ListTrainedSetsParams) ([]ListTrainedSetsRow, error)
+	// Блокировка тренировки пользователя до конца транзакции: добавления упражнений в одну тренировку
+	// выполняются по очереди и не получают одинаковый position
+	LockTraining(ctx context.Context, arg LockTrainingParams) (int64, error)
+	// Отметить тренировку как выполненную. actual_date - дата в часовом поясе пользователя
 	MarkTrainingAsDone(ctx context.Context, arg MarkTrainingAsDoneParams) (MarkTrainingAsDoneRow, error)
+	// Сплошная нумерация упражнений тренировки после удаления
+	RenumberTrainedExercises(ctx context.Context, trainingID int64) error
+	// Сплошная нумерация подходов после удаления
+	RenumberTrainedSets(ctx context.Context, trainedExerciseID int64) error
+	SetScheduleMaterializedUntil(ctx context.Context, arg SetScheduleMaterializedUntilParams) error
+	// Включение упражнения в группу или исключение из нее (NULL)
+	SetTrainedExerciseGroup(ctx context.Context, arg SetTrainedExerciseGroupParams) (SetTrainedExerciseGroupRow, error)
+	SetTrainedExercisePosition(ctx context.Context, arg SetTrainedExercisePositionParams) (int64, error)
+	SetTrainedSetOrdinal(ctx context.Context, arg SetTrainedSetOrdinalParams) (int64, error)
 	// Начать тренировку (установить время начала)
 	StartTraining(ctx context.Context, arg StartTrainingParams) (StartTrainingRow, error)
+	// Перевести идущую тренировку в статус paused и открыть паузу
+	StartTrainingPause(ctx context.Context, arg StartTrainingPauseParams) (int64, error)
+	UpdateExercise(ctx context.Context, arg UpdateExerciseParams) (Exercise, error)
 	// Обновление времени выполнения упражнения (doing) и времени отдыха (rest)
 	UpdateExerciseTime(ctx context.Context, arg UpdateExerciseTimeParams) (UpdateExerciseTimeRow, error)
+	UpdateGlobalTraining(ctx context.Context, arg UpdateGlobalTrainingParams) (int64, error)
+	UpdateProgram(ctx context.Context, arg UpdateProgramParams) (int64, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateTrainedExercise(ctx context.Context, arg UpdateTrainedExerciseParams) (UpdateTrainedExerciseRow, error)
+	UpdateTrainedSet(ctx context.Context, arg UpdateTrainedSetParams) (UpdateTrainedSetRow, error)
+	// Обновление полей тренировки. Статус, is_done, время начала и окончания и фактическая дата
+	// меняются только переходами жизненного цикла
 	UpdateTraining(ctx context.Context, arg UpdateTrainingParams) (UpdateTrainingRow, error)
+	UpdateTrainingSchedule(ctx context.Context, arg UpdateTrainingScheduleParams) (TrainingSchedule, error)
+	UpdateTrainingTemplate(ctx context.Context, arg UpdateTrainingTemplateParams) (int64, error)
 	// Обновление времени тренировки (старт, финиш, общая продолжительность)
 	UpdateTrainingTimers(ctx context.Context, arg UpdateTrainingTimersParams) (UpdateTrainingTimersRow, error)
+	// Новый ключ заменяет прежний: старая ссылка на календарь перестает работать
+	UpsertCalendarToken(ctx context.Context, arg UpsertCalendarTokenParams) error
+	// Тренировочный день программы. Существующий день с той же неделей и номером обновляется,
+	// сохраняя id, на который ссылаются тренировки записанных пользователей
+	UpsertProgramSession(ctx context.Context, arg UpsertProgramSessionParams) error
+	UpsertTrainedExerciseCardio(ctx context.Context, arg UpsertTrainedExerciseCardioParams) error
+	UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) (UserSetting, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,8 +11,29 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const addExerciseAlias = `-- name: AddExerciseAlias :execrows
+INSERT INTO exercise_alias (exercise_id, alias)
+VALUES ($1, $2)
+ON CONFLICT ((lower(alias))) DO NOTHING
+`
+
+type AddExerciseAliasParams struct {
+	ExerciseID int64  `json:"exercise_id"`
+	Alias      string `json:"alias"`
+}
+
+// Название, которое уже занято другим упражнением (без учета регистра), не добавляется
+func (q *Queries) AddExerciseAlias(ctx context.Context, arg AddExerciseAliasParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addExerciseAlias, arg.ExerciseID, arg.Alias)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const addExerciseToTraining = `-- name: AddExerciseToTraining :one
 INSERT INTO trained_exercise (
     training_id,
@@ -23,10 +44,17 @@ INSERT INTO trained_exercise (
     time,
     doing,
     rest,
-    notes
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    notes,
+    position,
+    group_id,
+    group_type
 )
+SELECT t.id, $2, $3, $4, $5, $6, $7, $8, $9,
+    -- Новое упражнение встает в конец тренировки
+    (SELECT COALESCE(MAX(te.position), 0) + 1 FROM trained_exercise te WHERE te.training_id = t.id),
+    $11, $12
+FROM training t
+WHERE t.id = $1 AND t.user_id = $10
 RETURNING 
     id,
     training_id,
@@ -37,7 +65,10 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM time)::bigint, 0) as bigint) as time,
     CAST(COALESCE(EXTRACT(EPOCH FROM doing)::bigint, 0)as bigint) as doing,
     CAST(COALESCE(EXTRACT(EPOCH FROM rest)::bigint, 0)as bigint) as rest,
-    notes
+    notes,
+    position,
+    group_id,
+    group_type
 `
 
 type AddExerciseToTrainingParams struct {
@@ -50,6 +81,9 @@ type AddExerciseToTrainingParams struct {
 	Doing      sql.NullInt64  `json:"doing"`
 	Rest       sql.NullInt64  `json:"rest"`
 	Notes      sql.NullString `json:"notes"`
+	UserID     uuid.UUID      `json:"user_id"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
 }
 
 type AddExerciseToTrainingRow struct {
@@ -63,8 +97,12 @@ type AddExerciseToTrainingRow struct {
 	Doing      int64          `json:"doing"`
 	Rest       int64          `json:"rest"`
 	Notes      sql.NullString `json:"notes"`
+	Position   int32          `json:"position"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
 }
 
+// Добавление упражнения только в тренировку, принадлежащую пользователю
 func (q *Queries) AddExerciseToTraining(ctx context.Context, arg AddExerciseToTrainingParams) (AddExerciseToTrainingRow, error) {
 	row := q.db.QueryRowContext(ctx, addExerciseToTraining,
 		arg.TrainingID,
@@ -76,6 +114,9 @@ func (q *Queries) AddExerciseToTraining(ctx context.Context, arg AddExerciseToTr
 		arg.Doing,
 		arg.Rest,
 		arg.Notes,
+		arg.UserID,
+		arg.GroupID,
+		arg.GroupType,
 	)
 	var i AddExerciseToTrainingRow
 	err := row.Scan(
@@ -89,19 +130,229 @@ func (q *Queries) AddExerciseToTraining(ctx context.Context, arg AddExerciseToTr
 		&i.Doing,
 		&i.Rest,
 		&i.Notes,
+		&i.Position,
+		&i.GroupID,
+		&i.GroupType,
+	)
+	return i, err
+}
+
+const addGlobalTrainingExercise = `-- name: AddGlobalTrainingExercise :exec
+INSERT INTO global_training_exercise (
+    global_training_id,
+    exercise_id,
+    position,
+    approaches,
+    reps,
+    weight,
+    duration,
+    rest,
+    group_id,
+    group_type
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+`
+
+type AddGlobalTrainingExerciseParams struct {
+	GlobalTrainingID int64          `json:"global_training_id"`
+	ExerciseID       int64          `json:"exercise_id"`
+	Position         int32          `json:"position"`
+	Approaches       sql.NullInt32  `json:"approaches"`
+	Reps             sql.NullInt32  `json:"reps"`
+	Weight           sql.NullString `json:"weight"`
+	Duration         sql.NullInt64  `json:"duration"`
+	Rest             sql.NullInt64  `json:"rest"`
+	GroupID          sql.NullInt32  `json:"group_id"`
+	GroupType        sql.NullString `json:"group_type"`
+}
+
+func (q *Queries) AddGlobalTrainingExercise(ctx context.Context, arg AddGlobalTrainingExerciseParams) error {
+	_, err := q.db.ExecContext(ctx, addGlobalTrainingExercise,
+		arg.GlobalTrainingID,
+		arg.ExerciseID,
+		arg.Position,
+		arg.Approaches,
+		arg.Reps,
+		arg.Weight,
+		arg.Duration,
+		arg.Rest,
+		arg.GroupID,
+		arg.GroupType,
+	)
+	return err
+}
+
+const addTemplateExercisesToTraining = `-- name: AddTemplateExercisesToTraining :exec
+INSERT INTO trained_exercise (training_id, exercise_id, weight, approaches, reps, doing, rest, position, group_id, group_type)
+SELECT $1::bigint, exercise_id, weight, approaches, reps, duration, rest, position, group_id, group_type
+FROM training_template_exercise
+WHERE template_id = $2
+ORDER BY position
+`
+
+type AddTemplateExercisesToTrainingParams struct {
+	TrainingID int64 `json:"training_id"`
+	TemplateID int64 `json:"template_id"`
+}
+
+// Упражнения шаблона с целевой нагрузкой добавляются в тренировку в порядке шаблона
+func (q *Queries) AddTemplateExercisesToTraining(ctx context.Context, arg AddTemplateExercisesToTrainingParams) error {
+	_, err := q.db.ExecContext(ctx, addTemplateExercisesToTraining, arg.TrainingID, arg.TemplateID)
+	return err
+}
+
+const addTrainedSet = `-- name: AddTrainedSet :one
+INSERT INTO trained_set (
+    trained_exercise_id,
+    ordinal,
+    weight,
+    reps,
+    duration,
+    rpe,
+    is_warmup
+)
+SELECT
+    te.id,
+    COALESCE((SELECT MAX(ordinal) FROM trained_set WHERE trained_exercise_id = te.id), 0) + 1,
+    $2, $3, $4, $5, $6
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE te.id = $1 AND t.user_id = $7
+RETURNING
+    id,
+    trained_exercise_id,
+    ordinal,
+    weight,
+    reps,
+    EXTRACT(EPOCH FROM duration)::bigint as duration,
+    rpe,
+    is_warmup
+`
+
+type AddTrainedSetParams struct {
+	ID       int64          `json:"id"`
+	Weight   sql.NullString `json:"weight"`
+	Reps     sql.NullInt32  `json:"reps"`
+	Duration sql.NullInt64  `json:"duration"`
+	Rpe      sql.NullString `json:"rpe"`
+	IsWarmup bool           `json:"is_warmup"`
+	UserID   uuid.UUID      `json:"user_id"`
+}
+
+type AddTrainedSetRow struct {
+	ID                int64          `json:"id"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Ordinal           int32          `json:"ordinal"`
+	Weight            sql.NullString `json:"weight"`
+	Reps              sql.NullInt32  `json:"reps"`
+	Duration          sql.NullInt64  `json:"duration"`
+	Rpe               sql.NullString `json:"rpe"`
+	IsWarmup          bool           `json:"is_warmup"`
+}
+
+// Новый подход добавляется в конец списка
+func (q *Queries) AddTrainedSet(ctx context.Context, arg AddTrainedSetParams) (AddTrainedSetRow, error) {
+	row := q.db.QueryRowContext(ctx, addTrainedSet,
+		arg.ID,
+		arg.Weight,
+		arg.Reps,
+		arg.Duration,
+		arg.Rpe,
+		arg.IsWarmup,
+		arg.UserID,
+	)
+	var i AddTrainedSetRow
+	err := row.Scan(
+		&i.ID,
+		&i.TrainedExerciseID,
+		&i.Ordinal,
+		&i.Weight,
+		&i.Reps,
+		&i.Duration,
+		&i.Rpe,
+		&i.IsWarmup,
 	)
 	return i, err
 }
 
+const addTrainingTemplateExercise = `-- name: AddTrainingTemplateExercise :exec
+INSERT INTO training_template_exercise (
+    template_id,
+    exercise_id,
+    position,
+    approaches,
+    reps,
+    weight,
+    duration,
+    rest,
+    group_id,
+    group_type
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+`
+
+type AddTrainingTemplateExerciseParams struct {
+	TemplateID int64          `json:"template_id"`
+	ExerciseID int64          `json:"exercise_id"`
+	Position   int32          `json:"position"`
+	Approaches sql.NullInt32  `json:"approaches"`
+	Reps       sql.NullInt32  `json:"reps"`
+	Weight     sql.NullString `json:"weight"`
+	Duration   sql.NullInt64  `json:"duration"`
+	Rest       sql.NullInt64  `json:"rest"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
+}
+
+func (q *Queries) AddTrainingTemplateExercise(ctx context.Context, arg AddTrainingTemplateExerciseParams) error {
+	_, err := q.db.ExecContext(ctx, addTrainingTemplateExercise,
+		arg.TemplateID,
+		arg.ExerciseID,
+		arg.Position,
+		arg.Approaches,
+		arg.Reps,
+		arg.Weight,
+		arg.Duration,
+		arg.Rest,
+		arg.GroupID,
+		arg.GroupType,
+	)
+	return err
+}
+
+const attachTagToExercise = `-- name: AttachTagToExercise :exec
+INSERT INTO exercise_to_tag (exercise_id, tag_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type AttachTagToExerciseParams struct {
+	ExerciseID int64 `json:"exercise_id"`
+	TagID      int64 `json:"tag_id"`
+}
+
+func (q *Queries) AttachTagToExercise(ctx context.Context, arg AttachTagToExerciseParams) error {
+	_, err := q.db.ExecContext(ctx, attachTagToExercise, arg.ExerciseID, arg.TagID)
+	return err
+}
+
 const calculateTrainingTotalTime = `-- name: CalculateTrainingTotalTime :one
 SELECT 
     COALESCE(SUM(EXTRACT(EPOCH FROM te.doing)), 0) as total_exercise_seconds,
     COALESCE(SUM(EXTRACT(EPOCH FROM te.rest)), 0) as total_rest_seconds,
     COALESCE(SUM(EXTRACT(EPOCH FROM te.doing)) + SUM(EXTRACT(EPOCH FROM te.rest)), 0) as total_seconds
 FROM trained_exercise te
-WHERE te.training_id = $1
+JOIN training t ON t.id = te.training_id
+WHERE te.training_id = $1 AND t.user_id = $2
 `
 
+type CalculateTrainingTotalTimeParams struct {
+	TrainingID int64     `json:"training_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
 type CalculateTrainingTotalTimeRow struct {
 	TotalExerciseSeconds interface{} `json:"total_exercise_seconds"`
 	TotalRestSeconds     interface{} `json:"total_rest_seconds"`
@@ -109,29 +360,23 @@ type CalculateTrainingTotalTimeRow struct {
 }
 
 // Расчет общего времени тренировки на основе всех упражнений
-func (q *Queries) CalculateTrainingTotalTime(ctx context.Context, trainingID int64) (CalculateTrainingTotalTimeRow, error) {
-	row := q.db.QueryRowContext(ctx, calculateTrainingTotalTime, trainingID)
+func (q *Queries) CalculateTrainingTotalTime(ctx context.Context, arg CalculateTrainingTotalTimeParams) (CalculateTrainingTotalTimeRow, error) {
+	row := q.db.QueryRowContext(ctx, calculateTrainingTotalTime, arg.TrainingID, arg.UserID)
 	var i CalculateTrainingTotalTimeRow
 	err := row.Scan(&i.TotalExerciseSeconds, &i.TotalRestSeconds, &i.TotalSeconds)
 	return i, err
 }
 
-const createTraining = `-- name: CreateTraining :one
-INSERT INTO training (
-    title,
-    user_id,
-    is_done,
-    planned_date,
-    actual_date,
-    started_at,
-    finished_at,
-    total_duration,
-    total_rest_time,
-    total_exercise_time,
-    rating
-) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-)
+const completeTraining = `-- name: CompleteTraining :one
+UPDATE training
+SET
+    is_done = true,
+    status = 'completed',
+    actual_date = $1,
+    finished_at = $2,
+    rating = COALESCE($3, rating),
+    total_duration = COALESCE($4, total_duration)
+WHERE id = $5 AND user_id = $6 AND status IN ('planned', 'in_progress', 'paused')
 RETURNING 
     id,
     title,
@@ -144,24 +389,20 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating
+    rating,
+    status
 `
 
-type CreateTrainingParams struct {
-	Title             string        `json:"title"`
-	UserID            uuid.UUID     `json:"user_id"`
-	IsDone            bool          `json:"is_done"`
-	PlannedDate       time.Time     `json:"planned_date"`
-	ActualDate        sql.NullTime  `json:"actual_date"`
-	StartedAt         sql.NullTime  `json:"started_at"`
-	FinishedAt        sql.NullTime  `json:"finished_at"`
-	TotalDuration     sql.NullInt64 `json:"total_duration"`
-	TotalRestTime     sql.NullInt64 `json:"total_rest_time"`
-	TotalExerciseTime sql.NullInt64 `json:"total_exercise_time"`
-	Rating            sql.NullInt32 `json:"rating"`
+type CompleteTrainingParams struct {
+	ActualDate    sql.NullTime  `json:"actual_date"`
+	FinishedAt    sql.NullTime  `json:"finished_at"`
+	Rating        sql.NullInt32 `json:"rating"`
+	TotalDuration sql.NullInt64 `json:"total_duration"`
+	ID            int64         `json:"id"`
+	UserID        uuid.UUID     `json:"user_id"`
 }
 
-type CreateTrainingRow struct {
+type CompleteTrainingRow struct {
 	ID                int64         `json:"id"`
 	Title             string        `json:"title"`
 	UserID            uuid.UUID     `json:"user_id"`
@@ -174,23 +415,21 @@ type CreateTrainingRow struct {
 	TotalRestTime     int64         `json:"total_rest_time"`
 	TotalExerciseTime int64         `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
 }
 
-func (q *Queries) CreateTraining(ctx context.Context, arg CreateTrainingParams) (CreateTrainingRow, error) {
-	row := q.db.QueryRowContext(ctx, createTraining,
-		arg.Title,
-		arg.UserID,
-		arg.IsDone,
-		arg.PlannedDate,
+// Завершение тренировки с оценкой и итоговой продолжительностью. Условие на статус защищает
+// от параллельного пропуска, отмены или повторного завершения
+func (q *Queries) CompleteTraining(ctx context.Context, arg CompleteTrainingParams) (CompleteTrainingRow, error) {
+	row := q.db.QueryRowContext(ctx, completeTraining,
 		arg.ActualDate,
-		arg.StartedAt,
 		arg.FinishedAt,
-		arg.TotalDuration,
-		arg.TotalRestTime,
-		arg.TotalExerciseTime,
 		arg.Rating,
+		arg.TotalDuration,
+		arg.ID,
+		arg.UserID,
 	)
-	var i CreateTrainingRow
+	var i CompleteTrainingRow
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
@@ -204,217 +443,3055 @@ func (q *Queries) CreateTraining(ctx context.Context, arg CreateTrainingParams)
 		&i.TotalRestTime,
 		&i.TotalExerciseTime,
 		&i.Rating,
+		&i.Status,
 	)
 	return i, err
 }
 
-const deleteExerciseFromTraining = `-- name: DeleteExerciseFromTraining :exec
-DELETE FROM trained_exercise 
-WHERE id = $1 AND training_id = $2
+const copyTrainedExercises = `-- name: CopyTrainedExercises :exec
+INSERT INTO trained_exercise (training_id, exercise_id, weight, approaches, reps, time, doing, rest, notes, position, group_id, group_type)
+SELECT $1::bigint, exercise_id, weight, approaches, reps, time, doing, rest, notes, position, group_id, group_type
+FROM trained_exercise
+WHERE training_id = $2
+ORDER BY position, id
 `
 
-type DeleteExerciseFromTrainingParams struct {
-	ID         int64 `json:"id"`
-	TrainingID int64 `json:"training_id"`
+type CopyTrainedExercisesParams struct {
+	TargetTrainingID int64 `json:"target_training_id"`
+	SourceTrainingID int64 `json:"source_training_id"`
 }
 
-func (q *Queries) DeleteExerciseFromTraining(ctx context.Context, arg DeleteExerciseFromTrainingParams) error {
-	_, err := q.db.ExecContext(ctx, deleteExerciseFromTraining, arg.ID, arg.TrainingID)
+// Копирование всех упражнений тренировки в другую тренировку в том же порядке
+func (q *Queries) CopyTrainedExercises(ctx context.Context, arg CopyTrainedExercisesParams) error {
+	_, err := q.db.ExecContext(ctx, copyTrainedExercises, arg.TargetTrainingID, arg.SourceTrainingID)
 	return err
 }
 
-const deleteTrainingAndExercises = `-- name: DeleteTrainingAndExercises :exec
-WITH deleted_exercises AS (
-    DELETE FROM trained_exercise WHERE training_id = $1
-)
-DELETE FROM training WHERE training.id = $1
+const copyTrainedExercisesToTemplate = `-- name: CopyTrainedExercisesToTemplate :exec
+INSERT INTO training_template_exercise (template_id, exercise_id, position, approaches, reps, weight, duration, rest, group_id, group_type)
+SELECT
+    $1::bigint,
+    te.exercise_id,
+    ROW_NUMBER() OVER (ORDER BY te.position, te.id),
+    NULLIF(te.approaches, 0),
+    NULLIF(te.reps, 0),
+    te.weight,
+    te.doing,
+    te.rest,
+    te.group_id,
+    te.group_type
+FROM trained_exercise te
+WHERE te.training_id = $2
 `
 
-func (q *Queries) DeleteTrainingAndExercises(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteTrainingAndExercises, id)
+type CopyTrainedExercisesToTemplateParams struct {
+	TemplateID int64 `json:"template_id"`
+	TrainingID int64 `json:"training_id"`
+}
+
+// Упражнения тренировки становятся упражнениями шаблона в порядке добавления,
+// время выполнения упражнения - целевой длительностью
+func (q *Queries) CopyTrainedExercisesToTemplate(ctx context.Context, arg CopyTrainedExercisesToTemplateParams) error {
+	_, err := q.db.ExecContext(ctx, copyTrainedExercisesToTemplate, arg.TemplateID, arg.TrainingID)
 	return err
 }
 
-const getAllTags = `-- name: GetAllTags :many
-SELECT id, type FROM tag ORDER BY id
+const countExercisesByIDs = `-- name: CountExercisesByIDs :one
+SELECT COUNT(*) FROM exercise WHERE id = ANY($1::bigint[])
 `
 
-func (q *Queries) GetAllTags(ctx context.Context) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getAllTags)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []Tag{}
-	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.Type); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) CountExercisesByIDs(ctx context.Context, ids []int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countExercisesByIDs, pq.Array(ids))
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-const getCurrentTraining = `-- name: GetCurrentTraining :one
-SELECT 
-    t.id,
-    t.title,
-    t.user_id,
-    t.is_done,
-    t.planned_date,
-    t.actual_date,
-    t.started_at,
-    t.finished_at,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    t.rating,
-    COALESCE(
-        json_agg(
-            json_build_object(
-                'id', te.id,
-                'training_id', te.training_id, 
-                'exercise_id', te.exercise_id,
-                'weight', te.weight,
-                'approaches', te.approaches,
-                'reps', te.reps,
-                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
-                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
-                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
-                'notes', te.notes
-            )
-        ) FILTER (WHERE te.id IS NOT NULL),
-        '[]'
-    ) as exercises
-FROM training t
-LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.user_id = $1 
-    AND t.planned_date = CURRENT_DATE
-    AND t.is_done = false
-GROUP BY t.id
-ORDER BY t.planned_date DESC
-LIMIT 1
+const countGlobalTrainingsByIDs = `-- name: CountGlobalTrainingsByIDs :one
+SELECT COUNT(*) FROM global_training WHERE id = ANY($1::bigint[])
 `
 
-type GetCurrentTrainingRow struct {
-	ID                int64         `json:"id"`
-	Title             string        `json:"title"`
-	UserID            uuid.UUID     `json:"user_id"`
-	IsDone            bool          `json:"is_done"`
-	PlannedDate       time.Time     `json:"planned_date"`
-	ActualDate        sql.NullTime  `json:"actual_date"`
-	StartedAt         sql.NullTime  `json:"started_at"`
-	FinishedAt        sql.NullTime  `json:"finished_at"`
-	TotalDuration     int64         `json:"total_duration"`
-	TotalRestTime     int64         `json:"total_rest_time"`
-	TotalExerciseTime int64         `json:"total_exercise_time"`
-	Rating            sql.NullInt32 `json:"rating"`
-	Exercises         interface{}   `json:"exercises"`
+func (q *Queries) CountGlobalTrainingsByIDs(ctx context.Context, ids []int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countGlobalTrainingsByIDs, pq.Array(ids))
+	var count int64
+	err := row.Scan(&count)
+	return count, err
 }
 
-// Получение тренировки на сегодня для пользователя
-func (q *Queries) GetCurrentTraining(ctx context.Context, userID uuid.UUID) (GetCurrentTrainingRow, error) {
-	row := q.db.QueryRowContext(ctx, getCurrentTraining, userID)
-	var i GetCurrentTrainingRow
+const createExercise = `-- name: CreateExercise :one
+INSERT INTO exercise (
+    title,
+    description,
+    video_url,
+    image_url,
+    type
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING id, title, description, video_url, image_url, type
+`
+
+type CreateExerciseParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	VideoUrl    string `json:"video_url"`
+	ImageUrl    string `json:"image_url"`
+	Type        string `json:"type"`
+}
+
+// Администрирование каталога упражнений
+func (q *Queries) CreateExercise(ctx context.Context, arg CreateExerciseParams) (Exercise, error) {
+	row := q.db.QueryRowContext(ctx, createExercise,
+		arg.Title,
+		arg.Description,
+		arg.VideoUrl,
+		arg.ImageUrl,
+		arg.Type,
+	)
+	var i Exercise
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
-		&i.UserID,
-		&i.IsDone,
-		&i.PlannedDate,
-		&i.ActualDate,
-		&i.StartedAt,
-		&i.FinishedAt,
-		&i.TotalDuration,
-		&i.TotalRestTime,
-		&i.TotalExerciseTime,
-		&i.Rating,
-		&i.Exercises,
+		&i.Description,
+		&i.VideoUrl,
+		&i.ImageUrl,
+		&i.Type,
 	)
 	return i, err
 }
 
-const getExerciseByID = `-- name: GetExerciseByID :one
-SELECT 
-    e.id,
+const createGlobalTraining = `-- name: CreateGlobalTraining :one
+INSERT INTO global_training (title, description, level)
+VALUES ($1, $2, $3)
+RETURNING id, title, description, level
+`
+
+type CreateGlobalTrainingParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+}
+
+// Администрирование глобальных тренировок
+func (q *Queries) CreateGlobalTraining(ctx context.Context, arg CreateGlobalTrainingParams) (GlobalTraining, error) {
+	row := q.db.QueryRowContext(ctx, createGlobalTraining, arg.Title, arg.Description, arg.Level)
+	var i GlobalTraining
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Level,
+	)
+	return i, err
+}
+
+const createPersonalRecord = `-- name: CreatePersonalRecord :one
+INSERT INTO personal_record (user_id, exercise_id, trained_exercise_id, type, value, weight, achieved_at)
+SELECT
+    t.user_id,
+    te.exercise_id,
+    te.id,
+    CAST($3 AS VARCHAR(20)),
+    CAST($4 AS DECIMAL(12,2)),
+    CAST($5 AS DECIMAL(5,2)),
+    COALESCE(t.finished_at, t.started_at, t.planned_date::timestamp)
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE te.id = $1 AND t.user_id = $2 AND t.status = 'completed'
+    AND NOT EXISTS (
+        SELECT 1 FROM personal_record pr
+        WHERE pr.user_id = t.user_id
+            AND pr.exercise_id = te.exercise_id
+            AND pr.type = $3
+            AND pr.weight IS NOT DISTINCT FROM CAST($5 AS DECIMAL(5,2))
+            AND pr.value >= $4
+    )
+RETURNING id, user_id, exercise_id, trained_exercise_id, type, value, weight, achieved_at
+`
+
+type CreatePersonalRecordParams struct {
+	ID     int64          `json:"id"`
+	UserID uuid.UUID      `json:"user_id"`
+	Type   string         `json:"type"`
+	Value  string         `json:"value"`
+	Weight sql.NullString `json:"weight"`
+}
+
+// Сохранить результат завершенной тренировки, если он лучше текущего рекорда того же типа
+// (для max_reps - при том же весе). Если рекорд не побит, строка не вставляется
+func (q *Queries) CreatePersonalRecord(ctx context.Context, arg CreatePersonalRecordParams) (PersonalRecord, error) {
+	row := q.db.QueryRowContext(ctx, createPersonalRecord,
+		arg.ID,
+		arg.UserID,
+		arg.Type,
+		arg.Value,
+		arg.Weight,
+	)
+	var i PersonalRecord
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExerciseID,
+		&i.TrainedExerciseID,
+		&i.Type,
+		&i.Value,
+		&i.Weight,
+		&i.AchievedAt,
+	)
+	return i, err
+}
+
+const createPlannedTraining = `-- name: CreatePlannedTraining :one
+INSERT INTO training (title, user_id, planned_date, status)
+VALUES ($1, $2, $3, 'planned')
+RETURNING id
+`
+
+type CreatePlannedTrainingParams struct {
+	Title       string    `json:"title"`
+	UserID      uuid.UUID `json:"user_id"`
+	PlannedDate time.Time `json:"planned_date"`
+}
+
+// Запланированная тренировка без упражнений: копия тренировки или тренировка по шаблону
+func (q *Queries) CreatePlannedTraining(ctx context.Context, arg CreatePlannedTrainingParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createPlannedTraining, arg.Title, arg.UserID, arg.PlannedDate)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createProgram = `-- name: CreateProgram :one
+INSERT INTO program (title, description, level, weeks)
+VALUES ($1, $2, $3, $4)
+RETURNING id, title, description, level, weeks
+`
+
+type CreateProgramParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	Weeks       int32  `json:"weeks"`
+}
+
+// Администрирование программ
+func (q *Queries) CreateProgram(ctx context.Context, arg CreateProgramParams) (Program, error) {
+	row := q.db.QueryRowContext(ctx, createProgram,
+		arg.Title,
+		arg.Description,
+		arg.Level,
+		arg.Weeks,
+	)
+	var i Program
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Level,
+		&i.Weeks,
+	)
+	return i, err
+}
+
+const createProgramEnrollment = `-- name: CreateProgramEnrollment :one
+INSERT INTO program_enrollment (program_id, user_id, start_date, weekdays)
+VALUES ($1, $2, $3, $4)
+RETURNING id
+`
+
+type CreateProgramEnrollmentParams struct {
+	ProgramID int64     `json:"program_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	StartDate time.Time `json:"start_date"`
+	Weekdays  []int32   `json:"weekdays"`
+}
+
+func (q *Queries) CreateProgramEnrollment(ctx context.Context, arg CreateProgramEnrollmentParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createProgramEnrollment,
+		arg.ProgramID,
+		arg.UserID,
+		arg.StartDate,
+		pq.Array(arg.Weekdays),
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createProgramTraining = `-- name: CreateProgramTraining :one
+INSERT INTO training (title, user_id, planned_date, status, enrollment_id, program_session_id)
+VALUES ($1, $2, $3, 'planned', $4, $5)
+RETURNING id
+`
+
+type CreateProgramTrainingParams struct {
+	Title            string        `json:"title"`
+	UserID           uuid.UUID     `json:"user_id"`
+	PlannedDate      time.Time     `json:"planned_date"`
+	EnrollmentID     sql.NullInt64 `json:"enrollment_id"`
+	ProgramSessionID sql.NullInt64 `json:"program_session_id"`
+}
+
+// Тренировка тренировочного дня программы
+func (q *Queries) CreateProgramTraining(ctx context.Context, arg CreateProgramTrainingParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createProgramTraining,
+		arg.Title,
+		arg.UserID,
+		arg.PlannedDate,
+		arg.EnrollmentID,
+		arg.ProgramSessionID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createScheduledTraining = `-- name: CreateScheduledTraining :one
+INSERT INTO training (title, user_id, planned_date, status, schedule_id, occurrence_date)
+VALUES ($1, $2, $3, 'planned', $4, $3)
+ON CONFLICT (schedule_id, occurrence_date) DO NOTHING
+RETURNING id
+`
+
+type CreateScheduledTrainingParams struct {
+	Title       string        `json:"title"`
+	UserID      uuid.UUID     `json:"user_id"`
+	PlannedDate time.Time     `json:"planned_date"`
+	ScheduleID  sql.NullInt64 `json:"schedule_id"`
+}
+
+// Тренировка повторения расписания; если тренировка на этот день повторения уже есть, строка не возвращается
+func (q *Queries) CreateScheduledTraining(ctx context.Context, arg CreateScheduledTrainingParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createScheduledTraining,
+		arg.Title,
+		arg.UserID,
+		arg.PlannedDate,
+		arg.ScheduleID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createTag = `-- name: CreateTag :one
+INSERT INTO tag (type) VALUES ($1)
+RETURNING id, type
+`
+
+func (q *Queries) CreateTag(ctx context.Context, type_ string) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, createTag, type_)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Type)
+	return i, err
+}
+
+const createTraining = `-- name: CreateTraining :one
+INSERT INTO training (
+    title,
+    user_id,
+    is_done,
+    planned_date,
+    actual_date,
+    started_at,
+    finished_at,
+    total_duration,
+    total_rest_time,
+    total_exercise_time,
+    rating,
+    status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+)
+RETURNING 
+    id,
+    title,
+    user_id,
+    is_done,
+    planned_date,
+    actual_date,
+    started_at,
+    finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    rating,
+    status
+`
+
+type CreateTrainingParams struct {
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     sql.NullInt64 `json:"total_duration"`
+	TotalRestTime     sql.NullInt64 `json:"total_rest_time"`
+	TotalExerciseTime sql.NullInt64 `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+}
+
+type CreateTrainingRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+}
+
+func (q *Queries) CreateTraining(ctx context.Context, arg CreateTrainingParams) (CreateTrainingRow, error) {
+	row := q.db.QueryRowContext(ctx, createTraining,
+		arg.Title,
+		arg.UserID,
+		arg.IsDone,
+		arg.PlannedDate,
+		arg.ActualDate,
+		arg.StartedAt,
+		arg.FinishedAt,
+		arg.TotalDuration,
+		arg.TotalRestTime,
+		arg.TotalExerciseTime,
+		arg.Rating,
+		arg.Status,
+	)
+	var i CreateTrainingRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.UserID,
+		&i.IsDone,
+		&i.PlannedDate,
+		&i.ActualDate,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.TotalDuration,
+		&i.TotalRestTime,
+		&i.TotalExerciseTime,
+		&i.Rating,
+		&i.Status,
+	)
+	return i, err
+}
+
+const createTrainingSchedule = `-- name: CreateTrainingSchedule :one
+INSERT INTO training_schedule (user_id, title, rrule, start_date, global_training_id, template_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+`
+
+type CreateTrainingScheduleParams struct {
+	UserID           uuid.UUID     `json:"user_id"`
+	Title            string        `json:"title"`
+	Rrule            string        `json:"rrule"`
+	StartDate        time.Time     `json:"start_date"`
+	GlobalTrainingID sql.NullInt64 `json:"global_training_id"`
+	TemplateID       sql.NullInt64 `json:"template_id"`
+}
+
+func (q *Queries) CreateTrainingSchedule(ctx context.Context, arg CreateTrainingScheduleParams) (TrainingSchedule, error) {
+	row := q.db.QueryRowContext(ctx, createTrainingSchedule,
+		arg.UserID,
+		arg.Title,
+		arg.Rrule,
+		arg.StartDate,
+		arg.GlobalTrainingID,
+		arg.TemplateID,
+	)
+	var i TrainingSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Rrule,
+		&i.StartDate,
+		&i.GlobalTrainingID,
+		&i.TemplateID,
+		&i.MaterializedUntil,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createTrainingScheduleSkip = `-- name: CreateTrainingScheduleSkip :exec
+INSERT INTO training_schedule_skip (schedule_id, occurrence_date)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateTrainingScheduleSkipParams struct {
+	ScheduleID     int64     `json:"schedule_id"`
+	OccurrenceDate time.Time `json:"occurrence_date"`
+}
+
+func (q *Queries) CreateTrainingScheduleSkip(ctx context.Context, arg CreateTrainingScheduleSkipParams) error {
+	_, err := q.db.ExecContext(ctx, createTrainingScheduleSkip, arg.ScheduleID, arg.OccurrenceDate)
+	return err
+}
+
+const createTrainingTemplate = `-- name: CreateTrainingTemplate :one
+INSERT INTO training_template (user_id, title, description)
+VALUES ($1, $2, $3)
+RETURNING id
+`
+
+type CreateTrainingTemplateParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+}
+
+func (q *Queries) CreateTrainingTemplate(ctx context.Context, arg CreateTrainingTemplateParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createTrainingTemplate, arg.UserID, arg.Title, arg.Description)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteCalendarToken = `-- name: DeleteCalendarToken :execrows
+DELETE FROM calendar_token
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCalendarToken, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExercise = `-- name: DeleteExercise :execrows
+DELETE FROM exercise WHERE id = $1
+`
+
+func (q *Queries) DeleteExercise(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExercise, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExerciseAliases = `-- name: DeleteExerciseAliases :exec
+DELETE FROM exercise_alias
+WHERE exercise_id = $1
+`
+
+func (q *Queries) DeleteExerciseAliases(ctx context.Context, exerciseID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteExerciseAliases, exerciseID)
+	return err
+}
+
+const deleteExerciseFromTraining = `-- name: DeleteExerciseFromTraining :exec
+DELETE FROM trained_exercise te
+USING training t
+WHERE te.id = $1 AND te.training_id = $2
+    AND t.id = te.training_id AND t.user_id = $3
+`
+
+type DeleteExerciseFromTrainingParams struct {
+	ID         int64     `json:"id"`
+	TrainingID int64     `json:"training_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteExerciseFromTraining(ctx context.Context, arg DeleteExerciseFromTrainingParams) error {
+	_, err := q.db.ExecContext(ctx, deleteExerciseFromTraining, arg.ID, arg.TrainingID, arg.UserID)
+	return err
+}
+
+const deleteGlobalTraining = `-- name: DeleteGlobalTraining :execrows
+DELETE FROM global_training WHERE id = $1
+`
+
+func (q *Queries) DeleteGlobalTraining(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteGlobalTraining, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteGlobalTrainingExercises = `-- name: DeleteGlobalTrainingExercises :exec
+DELETE FROM global_training_exercise WHERE global_training_id = $1
+`
+
+func (q *Queries) DeleteGlobalTrainingExercises(ctx context.Context, globalTrainingID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteGlobalTrainingExercises, globalTrainingID)
+	return err
+}
+
+const deletePersonalRecordsByTrainedExercise = `-- name: DeletePersonalRecordsByTrainedExercise :exec
+DELETE FROM personal_record
+WHERE trained_exercise_id = $1 AND user_id = $2
+`
+
+type DeletePersonalRecordsByTrainedExerciseParams struct {
+	TrainedExerciseID int64     `json:"trained_exercise_id"`
+	UserID            uuid.UUID `json:"user_id"`
+}
+
+// Удалить рекорды, установленные выполненным упражнением, перед их пересчетом
+func (q *Queries) DeletePersonalRecordsByTrainedExercise(ctx context.Context, arg DeletePersonalRecordsByTrainedExerciseParams) error {
+	_, err := q.db.ExecContext(ctx, deletePersonalRecordsByTrainedExercise, arg.TrainedExerciseID, arg.UserID)
+	return err
+}
+
+const deletePlannedEnrollmentTrainings = `-- name: DeletePlannedEnrollmentTrainings :execrows
+DELETE FROM training
+WHERE enrollment_id = $1
+    AND planned_date >= $2
+    AND status = 'planned'
+    AND started_at IS NULL
+`
+
+type DeletePlannedEnrollmentTrainingsParams struct {
+	EnrollmentID sql.NullInt64 `json:"enrollment_id"`
+	PlannedDate  time.Time     `json:"planned_date"`
+}
+
+// Удаление еще не начатых тренировок программы начиная с $2
+func (q *Queries) DeletePlannedEnrollmentTrainings(ctx context.Context, arg DeletePlannedEnrollmentTrainingsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePlannedEnrollmentTrainings, arg.EnrollmentID, arg.PlannedDate)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePlannedScheduledTrainings = `-- name: DeletePlannedScheduledTrainings :execrows
+DELETE FROM training
+WHERE schedule_id = $1::bigint
+    AND occurrence_date >= $2::date
+    AND ($3::date IS NULL OR occurrence_date <= $3::date)
+    AND status = 'planned'
+    AND started_at IS NULL
+`
+
+type DeletePlannedScheduledTrainingsParams struct {
+	ScheduleID int64        `json:"schedule_id"`
+	FromDate   time.Time    `json:"from_date"`
+	ToDate     sql.NullTime `json:"to_date"`
+}
+
+// Удаление еще не начатых тренировок расписания для повторений начиная с from_date (и до to_date, если задано)
+func (q *Queries) DeletePlannedScheduledTrainings(ctx context.Context, arg DeletePlannedScheduledTrainingsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePlannedScheduledTrainings, arg.ScheduleID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteProgram = `-- name: DeleteProgram :execrows
+DELETE FROM program WHERE id = $1
+`
+
+func (q *Queries) DeleteProgram(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProgram, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteProgramEnrollment = `-- name: DeleteProgramEnrollment :execrows
+DELETE FROM program_enrollment WHERE id = $1 AND user_id = $2
+`
+
+type DeleteProgramEnrollmentParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteProgramEnrollment(ctx context.Context, arg DeleteProgramEnrollmentParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteProgramEnrollment, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRemovedProgramSessions = `-- name: DeleteRemovedProgramSessions :exec
+DELETE FROM program_session ps
+WHERE ps.program_id = $1
+    AND NOT EXISTS (
+        SELECT 1
+        FROM unnest($2::int[], $3::int[]) AS k(week, day)
+        WHERE k.week = ps.week AND k.day = ps.day
+    )
+`
+
+type DeleteRemovedProgramSessionsParams struct {
+	ProgramID int64   `json:"program_id"`
+	Weeks     []int32 `json:"weeks"`
+	Days      []int32 `json:"days"`
+}
+
+// Удаление тренировочных дней, которых нет в новом списке (пары week[i], day[i])
+func (q *Queries) DeleteRemovedProgramSessions(ctx context.Context, arg DeleteRemovedProgramSessionsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteRemovedProgramSessions, arg.ProgramID, pq.Array(arg.Weeks), pq.Array(arg.Days))
+	return err
+}
+
+const deleteTag = `-- name: DeleteTag :execrows
+DELETE FROM tag WHERE id = $1
+`
+
+func (q *Queries) DeleteTag(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTag, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTrainedSet = `-- name: DeleteTrainedSet :execrows
+DELETE FROM trained_set ts
+USING trained_exercise te, training t
+WHERE ts.id = $1 AND ts.trained_exercise_id = $2
+    AND te.id = ts.trained_exercise_id AND t.id = te.training_id AND t.user_id = $3
+`
+
+type DeleteTrainedSetParams struct {
+	ID                int64     `json:"id"`
+	TrainedExerciseID int64     `json:"trained_exercise_id"`
+	UserID            uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteTrainedSet(ctx context.Context, arg DeleteTrainedSetParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTrainedSet, arg.ID, arg.TrainedExerciseID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTrainingAndExercises = `-- name: DeleteTrainingAndExercises :exec
+WITH deleted_exercises AS (
+    DELETE FROM trained_exercise
+    WHERE training_id IN (SELECT id FROM training WHERE id = $1 AND user_id = $2)
+)
+DELETE FROM training WHERE training.id = $1 AND training.user_id = $2
+`
+
+type DeleteTrainingAndExercisesParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteTrainingAndExercises(ctx context.Context, arg DeleteTrainingAndExercisesParams) error {
+	_, err := q.db.ExecContext(ctx, deleteTrainingAndExercises, arg.ID, arg.UserID)
+	return err
+}
+
+const deleteTrainingSchedule = `-- name: DeleteTrainingSchedule :execrows
+DELETE FROM training_schedule WHERE id = $1 AND user_id = $2
+`
+
+type DeleteTrainingScheduleParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteTrainingSchedule(ctx context.Context, arg DeleteTrainingScheduleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTrainingSchedule, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTrainingTemplate = `-- name: DeleteTrainingTemplate :execrows
+DELETE FROM training_template
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteTrainingTemplateParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) DeleteTrainingTemplate(ctx context.Context, arg DeleteTrainingTemplateParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTrainingTemplate, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTrainingTemplateExercises = `-- name: DeleteTrainingTemplateExercises :exec
+DELETE FROM training_template_exercise
+WHERE template_id = $1
+`
+
+func (q *Queries) DeleteTrainingTemplateExercises(ctx context.Context, templateID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTrainingTemplateExercises, templateID)
+	return err
+}
+
+const detachTagFromExercise = `-- name: DetachTagFromExercise :execrows
+DELETE FROM exercise_to_tag
+WHERE exercise_id = $1 AND tag_id = $2
+`
+
+type DetachTagFromExerciseParams struct {
+	ExerciseID int64 `json:"exercise_id"`
+	TagID      int64 `json:"tag_id"`
+}
+
+func (q *Queries) DetachTagFromExercise(ctx context.Context, arg DetachTagFromExerciseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, detachTagFromExercise, arg.ExerciseID, arg.TagID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const endTrainingPause = `-- name: EndTrainingPause :execrows
+WITH closed AS (
+    UPDATE training_pause tp
+    SET ended_at = CURRENT_TIMESTAMP
+    FROM training t
+    WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+        AND t.id = $1 AND t.user_id = $2 AND t.status = 'paused'
+    RETURNING tp.id
+)
+UPDATE training
+SET status = 'in_progress'
+WHERE id = $1 AND user_id = $2 AND status = 'paused'
+`
+
+type EndTrainingPauseParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Вернуть тренировку в статус in_progress и закрыть открытую паузу.
+// Число строк - по обновлению тренировки, то есть по фактической смене статуса
+func (q *Queries) EndTrainingPause(ctx context.Context, arg EndTrainingPauseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, endTrainingPause, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAllTags = `-- name: GetAllTags :many
+SELECT id, type FROM tag ORDER BY id
+`
+
+func (q *Queries) GetAllTags(ctx context.Context) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getAllTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Tag{}
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(&i.ID, &i.Type); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCalendarTokenUser = `-- name: GetCalendarTokenUser :one
+SELECT user_id
+FROM calendar_token
+WHERE token_hash = $1
+`
+
+func (q *Queries) GetCalendarTokenUser(ctx context.Context, tokenHash string) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getCalendarTokenUser, tokenHash)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getCurrentTraining = `-- name: GetCurrentTraining :one
+SELECT 
+    t.id,
+    t.title,
+    t.user_id,
+    t.is_done,
+    t.planned_date,
+    t.actual_date,
+    t.started_at,
+    t.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', te.id,
+                'training_id', te.training_id, 
+                'exercise_id', te.exercise_id,
+                'weight', te.weight,
+                'approaches', te.approaches,
+                'reps', te.reps,
+                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
+                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
+                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
+                'notes', te.notes,
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
+        ) FILTER (WHERE te.id IS NOT NULL),
+        '[]'
+    ) as exercises
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+WHERE t.user_id = $1 
+    AND t.planned_date = $2
+    AND t.is_done = false
+GROUP BY t.id
+ORDER BY t.planned_date DESC
+LIMIT 1
+`
+
+type GetCurrentTrainingParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	PlannedDate time.Time `json:"planned_date"`
+}
+
+type GetCurrentTrainingRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	IsPaused          bool          `json:"is_paused"`
+	Exercises         interface{}   `json:"exercises"`
+}
+
+// Получение тренировки на сегодня для пользователя.
+// $2 - сегодняшняя дата в часовом поясе пользователя
+func (q *Queries) GetCurrentTraining(ctx context.Context, arg GetCurrentTrainingParams) (GetCurrentTrainingRow, error) {
+	row := q.db.QueryRowContext(ctx, getCurrentTraining, arg.UserID, arg.PlannedDate)
+	var i GetCurrentTrainingRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.UserID,
+		&i.IsDone,
+		&i.PlannedDate,
+		&i.ActualDate,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.TotalDuration,
+		&i.TotalRestTime,
+		&i.TotalExerciseTime,
+		&i.Rating,
+		&i.Status,
+		&i.IsPaused,
+		&i.Exercises,
+	)
+	return i, err
+}
+
+const getEnrollmentProgress = `-- name: GetEnrollmentProgress :one
+SELECT
+    COUNT(t.id) as total_sessions,
+    COUNT(t.id) FILTER (WHERE t.is_done) as completed_sessions,
+    COUNT(t.id) FILTER (WHERE t.status IN ('skipped', 'cancelled')) as skipped_sessions,
+    n.id as next_training_id,
+    n.title as next_title,
+    n.planned_date as next_planned_date,
+    n.week as next_week,
+    n.day as next_day
+FROM program_enrollment e
+LEFT JOIN training t ON t.enrollment_id = e.id
+LEFT JOIN LATERAL (
+    SELECT nt.id, nt.title, nt.planned_date, ps.week, ps.day
+    FROM training nt
+    JOIN program_session ps ON ps.id = nt.program_session_id
+    WHERE nt.enrollment_id = e.id
+        AND NOT nt.is_done
+        AND nt.status NOT IN ('skipped', 'cancelled')
+    ORDER BY nt.planned_date, ps.week, ps.day
+    LIMIT 1
+) n ON TRUE
+WHERE e.id = $1
+GROUP BY n.id, n.title, n.planned_date, n.week, n.day
+`
+
+type GetEnrollmentProgressRow struct {
+	TotalSessions     int64          `json:"total_sessions"`
+	CompletedSessions int64          `json:"completed_sessions"`
+	SkippedSessions   int64          `json:"skipped_sessions"`
+	NextTrainingID    sql.NullInt64  `json:"next_training_id"`
+	NextTitle         sql.NullString `json:"next_title"`
+	NextPlannedDate   sql.NullTime   `json:"next_planned_date"`
+	NextWeek          sql.NullInt32  `json:"next_week"`
+	NextDay           sql.NullInt32  `json:"next_day"`
+}
+
+// Прохождение программы: число тренировок по статусам и ближайшая невыполненная тренировка
+func (q *Queries) GetEnrollmentProgress(ctx context.Context, id int64) (GetEnrollmentProgressRow, error) {
+	row := q.db.QueryRowContext(ctx, getEnrollmentProgress, id)
+	var i GetEnrollmentProgressRow
+	err := row.Scan(
+		&i.TotalSessions,
+		&i.CompletedSessions,
+		&i.SkippedSessions,
+		&i.NextTrainingID,
+		&i.NextTitle,
+		&i.NextPlannedDate,
+		&i.NextWeek,
+		&i.NextDay,
+	)
+	return i, err
+}
+
+const getExerciseAliases = `-- name: GetExerciseAliases :many
+SELECT alias
+FROM exercise_alias
+WHERE exercise_id = $1
+ORDER BY alias
+`
+
+func (q *Queries) GetExerciseAliases(ctx context.Context, exerciseID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getExerciseAliases, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, err
+		}
+		items = append(items, alias)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExerciseByID = `-- name: GetExerciseByID :one
+SELECT 
+    e.id,
+    e.title,
+    e.description,
+    e.video_url,
+    e.image_url,
+    e.type,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', t.id,
+                'type', t.type
+            )
+        ) FILTER (WHERE t.id IS NOT NULL),
+        '[]'
+    ) as tags
+FROM exercise e
+LEFT JOIN exercise_to_tag et ON e.id = et.exercise_id
+LEFT JOIN tag t ON et.tag_id = t.id
+WHERE e.id = $1
+GROUP BY e.id, e.description
+`
+
+type GetExerciseByIDRow struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	VideoUrl    string      `json:"video_url"`
+	ImageUrl    string      `json:"image_url"`
+	Type        string      `json:"type"`
+	Tags        interface{} `json:"tags"`
+}
+
+func (q *Queries) GetExerciseByID(ctx context.Context, id int64) (GetExerciseByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getExerciseByID, id)
+	var i GetExerciseByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.VideoUrl,
+		&i.ImageUrl,
+		&i.Type,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const getExerciseCatalogNames = `-- name: GetExerciseCatalogNames :many
+SELECT
+    e.id,
+    e.title,
+    e.type,
+    CAST(COALESCE(array_agg(a.alias ORDER BY a.alias) FILTER (WHERE a.alias IS NOT NULL), '{}') AS TEXT[]) as aliases
+FROM exercise e
+LEFT JOIN exercise_alias a ON a.exercise_id = e.id
+GROUP BY e.id
+ORDER BY e.id
+`
+
+type GetExerciseCatalogNamesRow struct {
+	ID      int64    `json:"id"`
+	Title   string   `json:"title"`
+	Type    string   `json:"type"`
+	Aliases []string `json:"aliases"`
+}
+
+// Названия и типы упражнений каталога с другими названиями для сопоставления при импорте истории
+func (q *Queries) GetExerciseCatalogNames(ctx context.Context) ([]GetExerciseCatalogNamesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExerciseCatalogNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExerciseCatalogNamesRow
+	for rows.Next() {
+		var i GetExerciseCatalogNamesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Type,
+			pq.Array(&i.Aliases),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExercisePersonalRecords = `-- name: GetExercisePersonalRecords :many
+SELECT DISTINCT ON (pr.type, pr.weight)
+    pr.id,
+    pr.user_id,
+    pr.exercise_id,
+    e.title as exercise_title,
+    pr.trained_exercise_id,
+    te.training_id,
+    pr.type,
+    pr.value,
+    pr.weight,
+    pr.achieved_at
+FROM personal_record pr
+JOIN exercise e ON e.id = pr.exercise_id
+JOIN trained_exercise te ON te.id = pr.trained_exercise_id
+WHERE pr.user_id = $1 AND pr.exercise_id = $2
+ORDER BY pr.type, pr.weight, pr.value DESC, pr.achieved_at
+`
+
+type GetExercisePersonalRecordsParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	ExerciseID int64     `json:"exercise_id"`
+}
+
+type GetExercisePersonalRecordsRow struct {
+	ID                int64          `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	ExerciseID        int64          `json:"exercise_id"`
+	ExerciseTitle     string         `json:"exercise_title"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	TrainingID        int64          `json:"training_id"`
+	Type              string         `json:"type"`
+	Value             string         `json:"value"`
+	Weight            sql.NullString `json:"weight"`
+	AchievedAt        time.Time      `json:"achieved_at"`
+}
+
+// Текущие рекорды пользователя по одному упражнению
+func (q *Queries) GetExercisePersonalRecords(ctx context.Context, arg GetExercisePersonalRecordsParams) ([]GetExercisePersonalRecordsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExercisePersonalRecords, arg.UserID, arg.ExerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExercisePersonalRecordsRow
+	for rows.Next() {
+		var i GetExercisePersonalRecordsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ExerciseID,
+			&i.ExerciseTitle,
+			&i.TrainedExerciseID,
+			&i.TrainingID,
+			&i.Type,
+			&i.Value,
+			&i.Weight,
+			&i.AchievedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExerciseStrengthEfforts = `-- name: GetExerciseStrengthEfforts :many
+SELECT
+    CAST(COALESCE(t.actual_date, t.planned_date) AS DATE) as training_date,
+    CAST(COALESCE(CASE WHEN ts.id IS NULL THEN te.weight ELSE ts.weight END, 0) AS TEXT) as weight,
+    CAST(COALESCE(CASE WHEN ts.id IS NULL THEN te.reps ELSE ts.reps END, 0) AS INTEGER) as reps,
+    CAST(CASE WHEN ts.id IS NULL THEN COALESCE(te.approaches, 1) ELSE 1 END AS INTEGER) as sets
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+LEFT JOIN trained_set ts ON ts.trained_exercise_id = te.id AND NOT ts.is_warmup
+WHERE t.user_id = $1 AND te.exercise_id = $2 AND t.status = 'completed'
+    AND (ts.id IS NOT NULL OR NOT EXISTS (
+        SELECT 1 FROM trained_set ws WHERE ws.trained_exercise_id = te.id
+    ))
+    AND COALESCE(t.actual_date, t.planned_date) BETWEEN $3 AND $4
+ORDER BY training_date, te.id, ts.ordinal
+`
+
+type GetExerciseStrengthEffortsParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	ExerciseID int64     `json:"exercise_id"`
+	FromDate   time.Time `json:"from_date"`
+	ToDate     time.Time `json:"to_date"`
+}
+
+type GetExerciseStrengthEffortsRow struct {
+	TrainingDate time.Time `json:"training_date"`
+	Weight       string    `json:"weight"`
+	Reps         int32     `json:"reps"`
+	Sets         int32     `json:"sets"`
+}
+
+// Рабочие подходы упражнения в завершенных тренировках пользователя за период.
+// Для упражнений без подходов берутся вес и повторения самого упражнения на все его подходы.
+// Упражнения, в которых записаны только разминочные подходы, не учитываются
+func (q *Queries) GetExerciseStrengthEfforts(ctx context.Context, arg GetExerciseStrengthEffortsParams) ([]GetExerciseStrengthEffortsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExerciseStrengthEfforts,
+		arg.UserID,
+		arg.ExerciseID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetExerciseStrengthEffortsRow
+	for rows.Next() {
+		var i GetExerciseStrengthEffortsRow
+		if err := rows.Scan(
+			&i.TrainingDate,
+			&i.Weight,
+			&i.Reps,
+			&i.Sets,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExerciseType = `-- name: GetExerciseType :one
+SELECT type FROM exercise WHERE id = $1
+`
+
+// Тип упражнения каталога для проверки кардио-показателей
+func (q *Queries) GetExerciseType(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getExerciseType, id)
+	var type_ string
+	err := row.Scan(&type_)
+	return type_, err
+}
+
+const getExercisesByTag = `-- name: GetExercisesByTag :many
+SELECT 
+    e.id,
+    e.title,
+    e.description,
+    e.video_url,
+    e.image_url,
+    e.type
+FROM exercise e
+INNER JOIN exercise_to_tag et ON e.id = et.exercise_id
+WHERE et.tag_id = $1
+ORDER BY e.id
+`
+
+func (q *Queries) GetExercisesByTag(ctx context.Context, tagID int64) ([]Exercise, error) {
+	rows, err := q.db.QueryContext(ctx, getExercisesByTag, tagID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Exercise{}
+	for rows.Next() {
+		var i Exercise
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.VideoUrl,
+			&i.ImageUrl,
+			&i.Type,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExercisesWithTags = `-- name: GetExercisesWithTags :many
+SELECT 
+    e.id,
     e.title,
     e.description,
     e.video_url,
     e.image_url,
+    e.type,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', t.id,
+                'type', t.type
+            )
+        ) FILTER (WHERE t.id IS NOT NULL),
+        '[]'
+    ) as tags
+FROM exercise e
+LEFT JOIN exercise_to_tag et ON e.id = et.exercise_id
+LEFT JOIN tag t ON et.tag_id = t.id
+GROUP BY e.id, e.description
+ORDER BY e.id
+`
+
+type GetExercisesWithTagsRow struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	VideoUrl    string      `json:"video_url"`
+	ImageUrl    string      `json:"image_url"`
+	Type        string      `json:"type"`
+	Tags        interface{} `json:"tags"`
+}
+
+func (q *Queries) GetExercisesWithTags(ctx context.Context) ([]GetExercisesWithTagsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getExercisesWithTags)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetExercisesWithTagsRow{}
+	for rows.Next() {
+		var i GetExercisesWithTagsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.VideoUrl,
+			&i.ImageUrl,
+			&i.Type,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGlobalTrainingByID = `-- name: GetGlobalTrainingByID :one
+SELECT 
+    gt.id,
+    gt.title,
+    gt.description,
+    gt.level,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', e.id,
+                'title', e.title,
+                'description', e.description,
+                'video_url', e.video_url,
+                'image_url', e.image_url,
+                'tags', COALESCE(
+                    (
+                        SELECT json_agg(
+                            json_build_object(
+                                'id', t.id,
+                                'type', t.type
+                            )
+                        )
+                        FROM exercise_to_tag et2
+                        JOIN tag t ON et2.tag_id = t.id
+                        WHERE et2.exercise_id = e.id
+                    ),
+                    '[]'
+                ),
+                'slot_id', gte.id,
+                'position', gte.position,
+                'approaches', gte.approaches,
+                'reps', gte.reps,
+                'weight', gte.weight,
+                'duration', EXTRACT(EPOCH FROM gte.duration)::bigint,
+                'rest', EXTRACT(EPOCH FROM gte.rest)::bigint,
+                'group_id', gte.group_id,
+                'group_type', gte.group_type
+            ) ORDER BY gte.position
+        ) FILTER (WHERE e.id IS NOT NULL),
+        '[]'
+    ) as exercises
+FROM global_training gt
+LEFT JOIN global_training_exercise gte ON gt.id = gte.global_training_id
+LEFT JOIN exercise e ON gte.exercise_id = e.id
+WHERE gt.id = $1
+GROUP BY gt.id, gt.level
+`
+
+type GetGlobalTrainingByIDRow struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Level       string      `json:"level"`
+	Exercises   interface{} `json:"exercises"`
+}
+
+// Получение глобальной тренировки по ID с упражнениями и их тегами
+func (q *Queries) GetGlobalTrainingByID(ctx context.Context, id int64) (GetGlobalTrainingByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getGlobalTrainingByID, id)
+	var i GetGlobalTrainingByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Level,
+		&i.Exercises,
+	)
+	return i, err
+}
+
+const getGlobalTrainingById = `-- name: GetGlobalTrainingById :one
+SELECT id, level, title
+FROM global_training
+WHERE id = $1
+`
+
+type GetGlobalTrainingByIdRow struct {
+	ID    int64  `json:"id"`
+	Level string `json:"level"`
+	Title string `json:"title"`
+}
+
+func (q *Queries) GetGlobalTrainingById(ctx context.Context, id int64) (GetGlobalTrainingByIdRow, error) {
+	row := q.db.QueryRowContext(ctx, getGlobalTrainingById, id)
+	var i GetGlobalTrainingByIdRow
+	err := row.Scan(&i.ID, &i.Level, &i.Title)
+	return i, err
+}
+
+const getGlobalTrainingByLevel = `-- name: GetGlobalTrainingByLevel :many
+SELECT 
+    gt.id,
+    gt.title,
+    gt.description,
+    gt.level,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', e.id,
+                'title', e.title,
+                'description', e.description,
+                'video_url', e.video_url,
+                'image_url', e.image_url,
+                'tags', COALESCE(
+                    (
+                        SELECT json_agg(
+                            json_build_object(
+                                'id', t.id,
+                                'type', t.type
+                            )
+                        )
+                        FROM exercise_to_tag et2
+                        JOIN tag t ON et2.tag_id = t.id
+                        WHERE et2.exercise_id = e.id
+                    ),
+                    '[]'
+                ),
+                'slot_id', gte.id,
+                'position', gte.position,
+                'approaches', gte.approaches,
+                'reps', gte.reps,
+                'weight', gte.weight,
+                'duration', EXTRACT(EPOCH FROM gte.duration)::bigint,
+                'rest', EXTRACT(EPOCH FROM gte.rest)::bigint,
+                'group_id', gte.group_id,
+                'group_type', gte.group_type
+            ) ORDER BY gte.position
+        ) FILTER (WHERE e.id IS NOT NULL),
+        '[]'
+    ) as exercises
+FROM global_training gt
+LEFT JOIN global_training_exercise gte ON gt.id = gte.global_training_id
+LEFT JOIN exercise e ON gte.exercise_id = e.id
+WHERE gt.level = $1
+GROUP BY gt.id, gt.level
+ORDER BY gt.id
+`
+
+type GetGlobalTrainingByLevelRow struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Level       string      `json:"level"`
+	Exercises   interface{} `json:"exercises"`
+}
+
+// Получение глобальных тренировок по уровню с упражнениями и их тегами
+func (q *Queries) GetGlobalTrainingByLevel(ctx context.Context, level string) ([]GetGlobalTrainingByLevelRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGlobalTrainingByLevel, level)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetGlobalTrainingByLevelRow{}
+	for rows.Next() {
+		var i GetGlobalTrainingByLevelRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Level,
+			&i.Exercises,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGlobalTrainingExercises = `-- name: GetGlobalTrainingExercises :many
+SELECT
+    gte.id,
+    gte.global_training_id,
+    gte.exercise_id,
+    gte.position,
+    gte.approaches,
+    gte.reps,
+    gte.weight,
+    EXTRACT(EPOCH FROM gte.duration)::bigint as duration,
+    EXTRACT(EPOCH FROM gte.rest)::bigint as rest,
+    gte.group_id,
+    gte.group_type
+FROM global_training_exercise gte
+WHERE gte.global_training_id = $1
+ORDER BY gte.position
+`
+
+type GetGlobalTrainingExercisesRow struct {
+	ID               int64          `json:"id"`
+	GlobalTrainingID int64          `json:"global_training_id"`
+	ExerciseID       int64          `json:"exercise_id"`
+	Position         int32          `json:"position"`
+	Approaches       sql.NullInt32  `json:"approaches"`
+	Reps             sql.NullInt32  `json:"reps"`
+	Weight           sql.NullString `json:"weight"`
+	Duration         sql.NullInt64  `json:"duration"`
+	Rest             sql.NullInt64  `json:"rest"`
+	GroupID          sql.NullInt32  `json:"group_id"`
+	GroupType        sql.NullString `json:"group_type"`
+}
+
+// Слоты глобальной тренировки с предписанной нагрузкой в порядке выполнения
+func (q *Queries) GetGlobalTrainingExercises(ctx context.Context, globalTrainingID int64) ([]GetGlobalTrainingExercisesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGlobalTrainingExercises, globalTrainingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetGlobalTrainingExercisesRow{}
+	for rows.Next() {
+		var i GetGlobalTrainingExercisesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.GlobalTrainingID,
+			&i.ExerciseID,
+			&i.Position,
+			&i.Approaches,
+			&i.Reps,
+			&i.Weight,
+			&i.Duration,
+			&i.Rest,
+			&i.GroupID,
+			&i.GroupType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGlobalTrainings = `-- name: GetGlobalTrainings :many
+SELECT 
+    gt.id,
+    gt.title,
+    gt.description,
+    gt.level,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', e.id,
+                'title', e.title,
+                'description', e.description,
+                'video_url', e.video_url,
+                'image_url', e.image_url,
+                'tags', COALESCE(
+                    (
+                        SELECT json_agg(
+                            json_build_object(
+                                'id', t.id,
+                                'type', t.type
+                            )
+                        )
+                        FROM exercise_to_tag et2
+                        JOIN tag t ON et2.tag_id = t.id
+                        WHERE et2.exercise_id = e.id
+                    ),
+                    '[]'
+                ),
+                'slot_id', gte.id,
+                'position', gte.position,
+                'approaches', gte.approaches,
+                'reps', gte.reps,
+                'weight', gte.weight,
+                'duration', EXTRACT(EPOCH FROM gte.duration)::bigint,
+                'rest', EXTRACT(EPOCH FROM gte.rest)::bigint,
+                'group_id', gte.group_id,
+                'group_type', gte.group_type
+            ) ORDER BY gte.position
+        ) FILTER (WHERE e.id IS NOT NULL),
+        '[]'
+    ) as exercises
+FROM global_training gt
+LEFT JOIN global_training_exercise gte ON gt.id = gte.global_training_id
+LEFT JOIN exercise e ON gte.exercise_id = e.id
+GROUP BY gt.id, gt.level
+ORDER BY 
+    CASE gt.level 
+        WHEN 'beginner' THEN 1
+        WHEN 'intermediate' THEN 2
+        WHEN 'advanced' THEN 3
+    END
+`
+
+type GetGlobalTrainingsRow struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Level       string      `json:"level"`
+	Exercises   interface{} `json:"exercises"`
+}
+
+// Получение всех глобальных тренировок с упражнениями и их тегами
+func (q *Queries) GetGlobalTrainings(ctx context.Context) ([]GetGlobalTrainingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGlobalTrainings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetGlobalTrainingsRow{}
+	for rows.Next() {
+		var i GetGlobalTrainingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Level,
+			&i.Exercises,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPersonalRecords = `-- name: GetPersonalRecords :many
+SELECT DISTINCT ON (pr.exercise_id, pr.type, pr.weight)
+    pr.id,
+    pr.user_id,
+    pr.exercise_id,
+    e.title as exercise_title,
+    pr.trained_exercise_id,
+    te.training_id,
+    pr.type,
+    pr.value,
+    pr.weight,
+    pr.achieved_at
+FROM personal_record pr
+JOIN exercise e ON e.id = pr.exercise_id
+JOIN trained_exercise te ON te.id = pr.trained_exercise_id
+WHERE pr.user_id = $1
+ORDER BY pr.exercise_id, pr.type, pr.weight, pr.value DESC, pr.achieved_at
+`
+
+type GetPersonalRecordsRow struct {
+	ID                int64          `json:"id"`
+	UserID            uuid.UUID      `json:"user_id"`
+	ExerciseID        int64          `json:"exercise_id"`
+	ExerciseTitle     string         `json:"exercise_title"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	TrainingID        int64          `json:"training_id"`
+	Type              string         `json:"type"`
+	Value             string         `json:"value"`
+	Weight            sql.NullString `json:"weight"`
+	AchievedAt        time.Time      `json:"achieved_at"`
+}
+
+// Текущие рекорды пользователя: лучший результат по упражнению, типу и весу
+func (q *Queries) GetPersonalRecords(ctx context.Context, userID uuid.UUID) ([]GetPersonalRecordsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPersonalRecords, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPersonalRecordsRow
+	for rows.Next() {
+		var i GetPersonalRecordsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.ExerciseID,
+			&i.ExerciseTitle,
+			&i.TrainedExerciseID,
+			&i.TrainingID,
+			&i.Type,
+			&i.Value,
+			&i.Weight,
+			&i.AchievedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProgramByID = `-- name: GetProgramByID :one
+SELECT id, title, description, level, weeks
+FROM program
+WHERE id = $1
+`
+
+func (q *Queries) GetProgramByID(ctx context.Context, id int64) (Program, error) {
+	row := q.db.QueryRowContext(ctx, getProgramByID, id)
+	var i Program
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.Level,
+		&i.Weeks,
+	)
+	return i, err
+}
+
+const getProgramEnrollment = `-- name: GetProgramEnrollment :one
+SELECT e.id, e.program_id, p.title as program_title, p.weeks, e.user_id, e.start_date, e.weekdays, e.created_at
+FROM program_enrollment e
+JOIN program p ON p.id = e.program_id
+WHERE e.id = $1 AND e.user_id = $2
+`
+
+type GetProgramEnrollmentParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetProgramEnrollmentRow struct {
+	ID           int64     `json:"id"`
+	ProgramID    int64     `json:"program_id"`
+	ProgramTitle string    `json:"program_title"`
+	Weeks        int32     `json:"weeks"`
+	UserID       uuid.UUID `json:"user_id"`
+	StartDate    time.Time `json:"start_date"`
+	Weekdays     []int32   `json:"weekdays"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetProgramEnrollment(ctx context.Context, arg GetProgramEnrollmentParams) (GetProgramEnrollmentRow, error) {
+	row := q.db.QueryRowContext(ctx, getProgramEnrollment, arg.ID, arg.UserID)
+	var i GetProgramEnrollmentRow
+	err := row.Scan(
+		&i.ID,
+		&i.ProgramID,
+		&i.ProgramTitle,
+		&i.Weeks,
+		&i.UserID,
+		&i.StartDate,
+		pq.Array(&i.Weekdays),
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getProgramEnrollments = `-- name: GetProgramEnrollments :many
+SELECT e.id, e.program_id, p.title as program_title, p.weeks, e.user_id, e.start_date, e.weekdays, e.created_at
+FROM program_enrollment e
+JOIN program p ON p.id = e.program_id
+WHERE e.user_id = $1
+ORDER BY e.created_at DESC, e.id DESC
+`
+
+type GetProgramEnrollmentsRow struct {
+	ID           int64     `json:"id"`
+	ProgramID    int64     `json:"program_id"`
+	ProgramTitle string    `json:"program_title"`
+	Weeks        int32     `json:"weeks"`
+	UserID       uuid.UUID `json:"user_id"`
+	StartDate    time.Time `json:"start_date"`
+	Weekdays     []int32   `json:"weekdays"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetProgramEnrollments(ctx context.Context, userID uuid.UUID) ([]GetProgramEnrollmentsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProgramEnrollments, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProgramEnrollmentsRow
+	for rows.Next() {
+		var i GetProgramEnrollmentsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProgramID,
+			&i.ProgramTitle,
+			&i.Weeks,
+			&i.UserID,
+			&i.StartDate,
+			pq.Array(&i.Weekdays),
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProgramSessions = `-- name: GetProgramSessions :many
+SELECT
+    ps.id,
+    ps.week,
+    ps.day,
+    ps.global_training_id,
+    gt.title as global_training_title,
+    ps.weight_increment,
+    ps.reps_increment
+FROM program_session ps
+JOIN global_training gt ON gt.id = ps.global_training_id
+WHERE ps.program_id = $1
+ORDER BY ps.week, ps.day
+`
+
+type GetProgramSessionsRow struct {
+	ID                  int64          `json:"id"`
+	Week                int32          `json:"week"`
+	Day                 int32          `json:"day"`
+	GlobalTrainingID    int64          `json:"global_training_id"`
+	GlobalTrainingTitle string         `json:"global_training_title"`
+	WeightIncrement     sql.NullString `json:"weight_increment"`
+	RepsIncrement       sql.NullInt32  `json:"reps_increment"`
+}
+
+// Тренировочные дни программы по порядку недель и дней
+func (q *Queries) GetProgramSessions(ctx context.Context, programID int64) ([]GetProgramSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getProgramSessions, programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetProgramSessionsRow
+	for rows.Next() {
+		var i GetProgramSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Week,
+			&i.Day,
+			&i.GlobalTrainingID,
+			&i.GlobalTrainingTitle,
+			&i.WeightIncrement,
+			&i.RepsIncrement,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPrograms = `-- name: GetPrograms :many
+SELECT id, title, description, level, weeks
+FROM program
+ORDER BY
+    CASE level
+        WHEN 'beginner' THEN 1
+        WHEN 'intermediate' THEN 2
+        WHEN 'advanced' THEN 3
+    END,
+    id
+`
+
+func (q *Queries) GetPrograms(ctx context.Context) ([]Program, error) {
+	rows, err := q.db.QueryContext(ctx, getPrograms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Program
+	for rows.Next() {
+		var i Program
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.Level,
+			&i.Weeks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRecentExerciseSessions = `-- name: GetRecentExerciseSessions :many
+SELECT
+    te.id,
+    te.training_id,
+    CAST(COALESCE(t.actual_date, t.planned_date) AS DATE) as training_date,
+    te.weight,
+    te.approaches,
+    te.reps
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE t.user_id = $1 AND te.exercise_id = $2 AND t.status = 'completed'
+ORDER BY training_date DESC, te.id DESC
+LIMIT $3
+`
+
+type GetRecentExerciseSessionsParams struct {
+	UserID     uuid.UUID `json:"user_id"`
+	ExerciseID int64     `json:"exercise_id"`
+	Limit      int32     `json:"limit"`
+}
+
+type GetRecentExerciseSessionsRow struct {
+	ID           int64          `json:"id"`
+	TrainingID   int64          `json:"training_id"`
+	TrainingDate time.Time      `json:"training_date"`
+	Weight       sql.NullString `json:"weight"`
+	Approaches   sql.NullInt32  `json:"approaches"`
+	Reps         sql.NullInt32  `json:"reps"`
+}
+
+// Последние выполнения упражнения в завершенных тренировках пользователя, начиная с последнего
+func (q *Queries) GetRecentExerciseSessions(ctx context.Context, arg GetRecentExerciseSessionsParams) ([]GetRecentExerciseSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRecentExerciseSessions, arg.UserID, arg.ExerciseID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRecentExerciseSessionsRow
+	for rows.Next() {
+		var i GetRecentExerciseSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TrainingID,
+			&i.TrainingDate,
+			&i.Weight,
+			&i.Approaches,
+			&i.Reps,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getScheduledTrainings = `-- name: GetScheduledTrainings :many
+SELECT id, planned_date, occurrence_date, status
+FROM training
+WHERE schedule_id = $1::bigint
+    AND occurrence_date BETWEEN $2::date AND $3::date
+ORDER BY occurrence_date
+`
+
+type GetScheduledTrainingsParams struct {
+	ScheduleID int64     `json:"schedule_id"`
+	FromDate   time.Time `json:"from_date"`
+	ToDate     time.Time `json:"to_date"`
+}
+
+type GetScheduledTrainingsRow struct {
+	ID             int64        `json:"id"`
+	PlannedDate    time.Time    `json:"planned_date"`
+	OccurrenceDate sql.NullTime `json:"occurrence_date"`
+	Status         string       `json:"status"`
+}
+
+// Тренировки, созданные по расписанию для повторений с from_date по to_date
+func (q *Queries) GetScheduledTrainings(ctx context.Context, arg GetScheduledTrainingsParams) ([]GetScheduledTrainingsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getScheduledTrainings, arg.ScheduleID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetScheduledTrainingsRow
+	for rows.Next() {
+		var i GetScheduledTrainingsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PlannedDate,
+			&i.OccurrenceDate,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSchedulesToMaterialize = `-- name: GetSchedulesToMaterialize :many
+SELECT id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+FROM training_schedule
+WHERE materialized_until IS NULL OR materialized_until < $1
+ORDER BY id
+`
+
+// Расписания, тренировки по которым созданы не до конца горизонта $1
+func (q *Queries) GetSchedulesToMaterialize(ctx context.Context, materializedUntil sql.NullTime) ([]TrainingSchedule, error) {
+	rows, err := q.db.QueryContext(ctx, getSchedulesToMaterialize, materializedUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrainingSchedule
+	for rows.Next() {
+		var i TrainingSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Rrule,
+			&i.StartDate,
+			&i.GlobalTrainingID,
+			&i.TemplateID,
+			&i.MaterializedUntil,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagByID = `-- name: GetTagByID :one
+SELECT id, type FROM tag WHERE id = $1
+`
+
+func (q *Queries) GetTagByID(ctx context.Context, id int64) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, getTagByID, id)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Type)
+	return i, err
+}
+
+const getTodaysTraining = `-- name: GetTodaysTraining :many
+SELECT 
+    t.id,
+    t.title,
+    t.user_id,
+    t.is_done,
+    t.planned_date,
+    t.actual_date,
+    t.started_at,
+    t.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
+    COALESCE(
+        json_agg(
+            json_build_object(
+                'id', te.id,
+                'training_id', te.training_id, 
+                'exercise_id', te.exercise_id,
+                'weight', te.weight,
+                'approaches', te.approaches,
+                'reps', te.reps,
+                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
+                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
+                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
+                'notes', te.notes,
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
+        ) FILTER (WHERE te.id IS NOT NULL),
+        '[]'
+    ) as exercises
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+WHERE t.user_id = $1 
+    AND t.planned_date = $2
+GROUP BY t.id
+ORDER BY t.planned_date DESC
+`
+
+type GetTodaysTrainingParams struct {
+	UserID      uuid.UUID `json:"user_id"`
+	PlannedDate time.Time `json:"planned_date"`
+}
+
+type GetTodaysTrainingRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	IsPaused          bool          `json:"is_paused"`
+	Exercises         interface{}   `json:"exercises"`
+}
+
+// Получение всех тренировок на сегодня для пользователя.
+// $2 - сегодняшняя дата в часовом поясе пользователя
+func (q *Queries) GetTodaysTraining(ctx context.Context, arg GetTodaysTrainingParams) ([]GetTodaysTrainingRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTodaysTraining, arg.UserID, arg.PlannedDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetTodaysTrainingRow{}
+	for rows.Next() {
+		var i GetTodaysTrainingRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.UserID,
+			&i.IsDone,
+			&i.PlannedDate,
+			&i.ActualDate,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.TotalDuration,
+			&i.TotalRestTime,
+			&i.TotalExerciseTime,
+			&i.Rating,
+			&i.Status,
+			&i.IsPaused,
+			&i.Exercises,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainedExerciseCardio = `-- name: GetTrainedExerciseCardio :one
+SELECT
+    e.type,
+    tc.trained_exercise_id IS NOT NULL as has_cardio,
+    tc.distance,
+    CAST(EXTRACT(EPOCH FROM tc.moving_time)::bigint as bigint) as moving_time,
+    tc.elevation_gain,
+    tc.avg_heart_rate,
+    tc.max_heart_rate,
+    CAST(EXTRACT(EPOCH FROM tc.avg_pace)::bigint as bigint) as avg_pace,
+    tc.distance_unit,
+    tc.avg_speed,
+    tc.calories,
+    tc.incline,
+    tc.resistance_level
+FROM trained_exercise te
+JOIN exercise e ON e.id = te.exercise_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+WHERE te.id = $1
+`
+
+type GetTrainedExerciseCardioRow struct {
+	Type            string         `json:"type"`
+	HasCardio       bool           `json:"has_cardio"`
+	Distance        sql.NullString `json:"distance"`
+	MovingTime      sql.NullInt64  `json:"moving_time"`
+	ElevationGain   sql.NullString `json:"elevation_gain"`
+	AvgHeartRate    sql.NullInt32  `json:"avg_heart_rate"`
+	MaxHeartRate    sql.NullInt32  `json:"max_heart_rate"`
+	AvgPace         sql.NullInt64  `json:"avg_pace"`
+	DistanceUnit    sql.NullString `json:"distance_unit"`
+	AvgSpeed        sql.NullString `json:"avg_speed"`
+	Calories        sql.NullInt32  `json:"calories"`
+	Incline         sql.NullString `json:"incline"`
+	ResistanceLevel sql.NullInt32  `json:"resistance_level"`
+}
+
+// Тип упражнения каталога и сохраненные кардио-показатели выполненного упражнения
+func (q *Queries) GetTrainedExerciseCardio(ctx context.Context, id int64) (GetTrainedExerciseCardioRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainedExerciseCardio, id)
+	var i GetTrainedExerciseCardioRow
+	err := row.Scan(
+		&i.Type,
+		&i.HasCardio,
+		&i.Distance,
+		&i.MovingTime,
+		&i.ElevationGain,
+		&i.AvgHeartRate,
+		&i.MaxHeartRate,
+		&i.AvgPace,
+		&i.DistanceUnit,
+		&i.AvgSpeed,
+		&i.Calories,
+		&i.Incline,
+		&i.ResistanceLevel,
+	)
+	return i, err
+}
+
+const getTrainedExerciseOwner = `-- name: GetTrainedExerciseOwner :one
+SELECT t.user_id, te.training_id
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE te.id = $1
+`
+
+type GetTrainedExerciseOwnerRow struct {
+	UserID     uuid.UUID `json:"user_id"`
+	TrainingID int64     `json:"training_id"`
+}
+
+// Владелец и тренировка, в которую входит выполненное упражнение
+func (q *Queries) GetTrainedExerciseOwner(ctx context.Context, id int64) (GetTrainedExerciseOwnerRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainedExerciseOwner, id)
+	var i GetTrainedExerciseOwnerRow
+	err := row.Scan(&i.UserID, &i.TrainingID)
+	return i, err
+}
+
+const getTrainingCalendar = `-- name: GetTrainingCalendar :many
+SELECT
+    CAST(d.day AS DATE) as day,
+    t.id as training_id,
+    t.title,
+    t.status,
+    t.rating,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) AS BIGINT) as total_duration,
+    COUNT(te.id) as exercise_count
+FROM generate_series($1::date, $2::date, INTERVAL '1 day') d(day)
+LEFT JOIN training t ON t.user_id = $3 AND t.planned_date = CAST(d.day AS DATE)
+LEFT JOIN trained_exercise te ON te.training_id = t.id
+GROUP BY d.day, t.id
+ORDER BY d.day, t.id
+`
+
+type GetTrainingCalendarParams struct {
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+	UserID   uuid.UUID `json:"user_id"`
+}
+
+type GetTrainingCalendarRow struct {
+	Day           time.Time      `json:"day"`
+	TrainingID    sql.NullInt64  `json:"training_id"`
+	Title         sql.NullString `json:"title"`
+	Status        sql.NullString `json:"status"`
+	Rating        sql.NullInt32  `json:"rating"`
+	TotalDuration int64          `json:"total_duration"`
+	ExerciseCount int64          `json:"exercise_count"`
+}
+
+// Календарь тренировок пользователя по запланированным датам с from_date по to_date: строка на каждую тренировку
+// и по одной строке с пустыми полями тренировки на дни без тренировок
+func (q *Queries) GetTrainingCalendar(ctx context.Context, arg GetTrainingCalendarParams) ([]GetTrainingCalendarRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingCalendar, arg.FromDate, arg.ToDate, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrainingCalendarRow
+	for rows.Next() {
+		var i GetTrainingCalendarRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.TrainingID,
+			&i.Title,
+			&i.Status,
+			&i.Rating,
+			&i.TotalDuration,
+			&i.ExerciseCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingOwner = `-- name: GetTrainingOwner :one
+SELECT user_id FROM training WHERE id = $1
+`
+
+// Владелец тренировки (для проверки доступа)
+func (q *Queries) GetTrainingOwner(ctx context.Context, id int64) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingOwner, id)
+	var user_id uuid.UUID
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getTrainingPausedTime = `-- name: GetTrainingPausedTime :one
+SELECT CAST(COALESCE(
+    EXTRACT(EPOCH FROM SUM(COALESCE(tp.ended_at, CURRENT_TIMESTAMP) - tp.started_at)) * 1000000,
+    0
+) as bigint) as paused_microseconds
+FROM training_pause tp
+JOIN training t ON t.id = tp.training_id
+WHERE tp.training_id = $1 AND t.user_id = $2
+`
+
+type GetTrainingPausedTimeParams struct {
+	TrainingID int64     `json:"training_id"`
+	UserID     uuid.UUID `json:"user_id"`
+}
+
+// Суммарное время пауз в микросекундах; открытая пауза считается до текущего момента
+func (q *Queries) GetTrainingPausedTime(ctx context.Context, arg GetTrainingPausedTimeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingPausedTime, arg.TrainingID, arg.UserID)
+	var paused_microseconds int64
+	err := row.Scan(&paused_microseconds)
+	return paused_microseconds, err
+}
+
+const getTrainingSchedule = `-- name: GetTrainingSchedule :one
+SELECT id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+FROM training_schedule
+WHERE id = $1 AND user_id = $2
+`
+
+type GetTrainingScheduleParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetTrainingSchedule(ctx context.Context, arg GetTrainingScheduleParams) (TrainingSchedule, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingSchedule, arg.ID, arg.UserID)
+	var i TrainingSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Rrule,
+		&i.StartDate,
+		&i.GlobalTrainingID,
+		&i.TemplateID,
+		&i.MaterializedUntil,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTrainingScheduleSkips = `-- name: GetTrainingScheduleSkips :many
+SELECT occurrence_date
+FROM training_schedule_skip
+WHERE schedule_id = $1
+ORDER BY occurrence_date
+`
+
+func (q *Queries) GetTrainingScheduleSkips(ctx context.Context, scheduleID int64) ([]time.Time, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingScheduleSkips, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []time.Time
+	for rows.Next() {
+		var occurrence_date time.Time
+		if err := rows.Scan(&occurrence_date); err != nil {
+			return nil, err
+		}
+		items = append(items, occurrence_date)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingSchedules = `-- name: GetTrainingSchedules :many
+SELECT id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+FROM training_schedule
+WHERE user_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) GetTrainingSchedules(ctx context.Context, userID uuid.UUID) ([]TrainingSchedule, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingSchedules, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrainingSchedule
+	for rows.Next() {
+		var i TrainingSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Rrule,
+			&i.StartDate,
+			&i.GlobalTrainingID,
+			&i.TemplateID,
+			&i.MaterializedUntil,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingStats = `-- name: GetTrainingStats :one
+SELECT 
+    t.id,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    COUNT(te.id) as exercise_count,
+    CAST(COALESCE(SUM(CASE WHEN s.set_count > 0 THEN s.approaches ELSE te.approaches END), 0) as bigint) as total_approaches,
+    CAST(COALESCE(SUM(CASE WHEN s.set_count > 0 THEN s.reps ELSE te.reps END), 0) as bigint) as total_reps,
+    CAST(COALESCE(SUM(CASE WHEN s.set_count > 0 THEN s.volume ELSE te.weight * te.reps * te.approaches END), 0) as text) as total_volume,
+    CAST(COALESCE(SUM(tc.distance), 0) as text) as total_distance,
+    CAST(COALESCE(SUM(tc.distance) FILTER (WHERE tc.moving_time IS NOT NULL), 0) as text) as paced_distance,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(tc.moving_time) FILTER (WHERE tc.distance > 0))::bigint, 0) as bigint) as paced_time,
+    CAST(COALESCE(SUM(tc.calories), 0) as bigint) as total_calories
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) as set_count,
+        COUNT(*) FILTER (WHERE NOT ts.is_warmup) as approaches,
+        SUM(ts.reps) FILTER (WHERE NOT ts.is_warmup) as reps,
+        SUM(ts.weight * ts.reps) FILTER (WHERE NOT ts.is_warmup) as volume
+    FROM trained_set ts
+    WHERE ts.trained_exercise_id = te.id
+) s ON TRUE
+WHERE t.id = $1 AND t.user_id = $2
+GROUP BY t.id
+`
+
+type GetTrainingStatsParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type GetTrainingStatsRow struct {
+	ID                int64  `json:"id"`
+	TotalDuration     int64  `json:"total_duration"`
+	TotalRestTime     int64  `json:"total_rest_time"`
+	TotalExerciseTime int64  `json:"total_exercise_time"`
+	ExerciseCount     int64  `json:"exercise_count"`
+	TotalApproaches   int64  `json:"total_approaches"`
+	TotalReps         int64  `json:"total_reps"`
+	TotalVolume       string `json:"total_volume"`
+	TotalDistance     string `json:"total_distance"`
+	PacedDistance     string `json:"paced_distance"`
+	PacedTime         int64  `json:"paced_time"`
+	TotalCalories     int64  `json:"total_calories"`
+}
+
+// Получение статистики по тренировке (общее время выполнения и отдыха).
+// Для упражнений с подходами считаются только рабочие подходы, для остальных — агрегаты упражнения
+func (q *Queries) GetTrainingStats(ctx context.Context, arg GetTrainingStatsParams) (GetTrainingStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingStats, arg.ID, arg.UserID)
+	var i GetTrainingStatsRow
+	err := row.Scan(
+		&i.ID,
+		&i.TotalDuration,
+		&i.TotalRestTime,
+		&i.TotalExerciseTime,
+		&i.ExerciseCount,
+		&i.TotalApproaches,
+		&i.TotalReps,
+		&i.TotalVolume,
+		&i.TotalDistance,
+		&i.PacedDistance,
+		&i.PacedTime,
+		&i.TotalCalories,
+	)
+	return i, err
+}
+
+const getTrainingTemplate = `-- name: GetTrainingTemplate :one
+SELECT id, user_id, title, description, created_at
+FROM training_template
+WHERE id = $1 AND user_id = $2
+`
+
+type GetTrainingTemplateParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetTrainingTemplate(ctx context.Context, arg GetTrainingTemplateParams) (TrainingTemplate, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingTemplate, arg.ID, arg.UserID)
+	var i TrainingTemplate
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTrainingTemplateExercises = `-- name: GetTrainingTemplateExercises :many
+SELECT
+    tte.id,
+    tte.exercise_id,
+    e.title as exercise_title,
+    tte.position,
+    tte.approaches,
+    tte.reps,
+    tte.weight,
+    EXTRACT(EPOCH FROM tte.duration)::bigint as duration,
+    EXTRACT(EPOCH FROM tte.rest)::bigint as rest,
+    tte.group_id,
+    tte.group_type
+FROM training_template_exercise tte
+JOIN exercise e ON e.id = tte.exercise_id
+WHERE tte.template_id = $1
+ORDER BY tte.position
+`
+
+type GetTrainingTemplateExercisesRow struct {
+	ID            int64          `json:"id"`
+	ExerciseID    int64          `json:"exercise_id"`
+	ExerciseTitle string         `json:"exercise_title"`
+	Position      int32          `json:"position"`
+	Approaches    sql.NullInt32  `json:"approaches"`
+	Reps          sql.NullInt32  `json:"reps"`
+	Weight        sql.NullString `json:"weight"`
+	Duration      sql.NullInt64  `json:"duration"`
+	Rest          sql.NullInt64  `json:"rest"`
+	GroupID       sql.NullInt32  `json:"group_id"`
+	GroupType     sql.NullString `json:"group_type"`
+}
+
+// Упражнения шаблона с целевой нагрузкой в порядке выполнения
+func (q *Queries) GetTrainingTemplateExercises(ctx context.Context, templateID int64) ([]GetTrainingTemplateExercisesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingTemplateExercises, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrainingTemplateExercisesRow
+	for rows.Next() {
+		var i GetTrainingTemplateExercisesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExerciseID,
+			&i.ExerciseTitle,
+			&i.Position,
+			&i.Approaches,
+			&i.Reps,
+			&i.Weight,
+			&i.Duration,
+			&i.Rest,
+			&i.GroupID,
+			&i.GroupType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingTemplates = `-- name: GetTrainingTemplates :many
+SELECT id, user_id, title, description, created_at
+FROM training_template
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+`
+
+// Шаблоны пользователя, начиная с последнего созданного
+func (q *Queries) GetTrainingTemplates(ctx context.Context, userID uuid.UUID) ([]TrainingTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingTemplates, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TrainingTemplate
+	for rows.Next() {
+		var i TrainingTemplate
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingTitlesInRange = `-- name: GetTrainingTitlesInRange :many
+SELECT planned_date, title
+FROM training
+WHERE user_id = $1 AND planned_date BETWEEN $2::date AND $3::date
+ORDER BY planned_date
+`
+
+type GetTrainingTitlesInRangeParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+}
+
+type GetTrainingTitlesInRangeRow struct {
+	PlannedDate time.Time `json:"planned_date"`
+	Title       string    `json:"title"`
+}
+
+// Даты и названия тренировок пользователя с from по to: по ним импорт находит уже загруженные тренировки
+func (q *Queries) GetTrainingTitlesInRange(ctx context.Context, arg GetTrainingTitlesInRangeParams) ([]GetTrainingTitlesInRangeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingTitlesInRange, arg.UserID, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrainingTitlesInRangeRow
+	for rows.Next() {
+		var i GetTrainingTitlesInRangeRow
+		if err := rows.Scan(&i.PlannedDate, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTrainingWithExercises = `-- name: GetTrainingWithExercises :one
+SELECT 
+    t.id,
+    t.title,
+    t.user_id,
+    t.is_done,
+    t.planned_date,
+    t.actual_date,
+    t.started_at,
+    t.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
     COALESCE(
         json_agg(
             json_build_object(
-                'id', t.id,
-                'type', t.type
-            )
-        ) FILTER (WHERE t.id IS NOT NULL),
+                'id', te.id,
+                'training_id', te.training_id, 
+                'exercise_id', te.exercise_id,
+                'weight', CASE WHEN s.sets IS NULL THEN te.weight ELSE s.weight END,
+                'approaches', CASE WHEN s.sets IS NULL THEN te.approaches ELSE s.approaches END,
+                'reps', CASE WHEN s.sets IS NULL THEN te.reps ELSE s.reps END,
+                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
+                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
+                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
+                'notes', te.notes,
+                'sets', COALESCE(s.sets, '[]'::json),
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
+        ) FILTER (WHERE te.id IS NOT NULL),
         '[]'
-    ) as tags
-FROM exercise e
-LEFT JOIN exercise_to_tag et ON e.id = et.exercise_id
-LEFT JOIN tag t ON et.tag_id = t.id
-WHERE e.id = $1
-GROUP BY e.id, e.description
+    ) as exercises
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) FILTER (WHERE NOT ts.is_warmup) as approaches,
+        SUM(ts.reps) FILTER (WHERE NOT ts.is_warmup) as reps,
+        MAX(ts.weight) FILTER (WHERE NOT ts.is_warmup) as weight,
+        json_agg(
+            json_build_object(
+                'id', ts.id,
+                'trained_exercise_id', ts.trained_exercise_id,
+                'ordinal', ts.ordinal,
+                'weight', ts.weight,
+                'reps', ts.reps,
+                'duration', EXTRACT(EPOCH FROM ts.duration)::bigint,
+                'rpe', ts.rpe,
+                'is_warmup', ts.is_warmup
+            ) ORDER BY ts.ordinal
+        ) as sets
+    FROM trained_set ts
+    WHERE ts.trained_exercise_id = te.id
+) s ON TRUE
+WHERE t.id = $1 AND t.user_id = $2
+GROUP BY t.id
 `
 
-type GetExerciseByIDRow struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	VideoUrl    string      `json:"video_url"`
-	ImageUrl    string      `json:"image_url"`
-	Tags        interface{} `json:"tags"`
+type GetTrainingWithExercisesParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
 }
 
-func (q *Queries) GetExerciseByID(ctx context.Context, id int64) (GetExerciseByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getExerciseByID, id)
-	var i GetExerciseByIDRow
+type GetTrainingWithExercisesRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	IsPaused          bool          `json:"is_paused"`
+	Exercises         interface{}   `json:"exercises"`
+}
+
+// Подходы агрегируются в approaches/reps/weight упражнения, если они есть:
+// approaches — число рабочих подходов, reps — сумма повторений, weight — максимальный вес
+func (q *Queries) GetTrainingWithExercises(ctx context.Context, arg GetTrainingWithExercisesParams) (GetTrainingWithExercisesRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrainingWithExercises, arg.ID, arg.UserID)
+	var i GetTrainingWithExercisesRow
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
-		&i.Description,
-		&i.VideoUrl,
-		&i.ImageUrl,
-		&i.Tags,
+		&i.UserID,
+		&i.IsDone,
+		&i.PlannedDate,
+		&i.ActualDate,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.TotalDuration,
+		&i.TotalRestTime,
+		&i.TotalExerciseTime,
+		&i.Rating,
+		&i.Status,
+		&i.IsPaused,
+		&i.Exercises,
 	)
 	return i, err
 }
 
-const getExercisesByTag = `-- name: GetExercisesByTag :many
+const getTrainingsByUser = `-- name: GetTrainingsByUser :many
 SELECT 
-    e.id,
-    e.title,
-    e.description,
-    e.video_url,
-    e.image_url
-FROM exercise e
-INNER JOIN exercise_to_tag et ON e.id = et.exercise_id
-WHERE et.tag_id = $1
-ORDER BY e.id
+    t.id,
+    t.title,
+    t.user_id,
+    t.is_done,
+    t.planned_date,
+    t.actual_date,
+    t.started_at,
+    t.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+WHERE t.user_id = $1
+GROUP BY t.id
+ORDER BY t.planned_date DESC
 `
 
-func (q *Queries) GetExercisesByTag(ctx context.Context, tagID int64) ([]Exercise, error) {
-	rows, err := q.db.QueryContext(ctx, getExercisesByTag, tagID)
+type GetTrainingsByUserRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	IsPaused          bool          `json:"is_paused"`
+}
+
+func (q *Queries) GetTrainingsByUser(ctx context.Context, userID uuid.UUID) ([]GetTrainingsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingsByUser, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Exercise{}
+	items := []GetTrainingsByUserRow{}
 	for rows.Next() {
-		var i Exercise
+		var i GetTrainingsByUserRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
-			&i.Description,
-			&i.VideoUrl,
-			&i.ImageUrl,
+			&i.UserID,
+			&i.IsDone,
+			&i.PlannedDate,
+			&i.ActualDate,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.TotalDuration,
+			&i.TotalRestTime,
+			&i.TotalExerciseTime,
+			&i.Rating,
+			&i.Status,
+			&i.IsPaused,
 		); err != nil {
 			return nil, err
 		}
@@ -429,54 +3506,129 @@ func (q *Queries) GetExercisesByTag(ctx context.Context, tagID int64) ([]Exercis
 	return items, nil
 }
 
-const getExercisesWithTags = `-- name: GetExercisesWithTags :many
+const getTrainingsFiltered = `-- name: GetTrainingsFiltered :many
 SELECT 
-    e.id,
-    e.title,
-    e.description,
-    e.video_url,
-    e.image_url,
-    COALESCE(
-        json_agg(
-            json_build_object(
-                'id', t.id,
-                'type', t.type
-            )
-        ) FILTER (WHERE t.id IS NOT NULL),
-        '[]'
-    ) as tags
-FROM exercise e
-LEFT JOIN exercise_to_tag et ON e.id = et.exercise_id
-LEFT JOIN tag t ON et.tag_id = t.id
-GROUP BY e.id, e.description
-ORDER BY e.id
+    f.id,
+    f.title,
+    f.user_id,
+    f.is_done,
+    f.planned_date,
+    f.actual_date,
+    f.started_at,
+    f.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM f.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM f.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM f.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    f.rating,
+    f.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = f.id AND tp.ended_at IS NULL
+    ) as is_paused,
+    f.sort_key
+FROM (
+    SELECT
+        t.*,
+        CAST(CASE $1::text
+            WHEN 'actual_date' THEN COALESCE(t.actual_date - DATE '1970-01-01', -1000000)
+            WHEN 'rating' THEN COALESCE(t.rating, 0)
+            ELSE t.planned_date - DATE '1970-01-01'
+        END AS BIGINT) as sort_key
+    FROM training t
+    WHERE t.user_id = $2
+        AND ($3::date IS NULL OR t.planned_date >= $3::date)
+        AND ($4::date IS NULL OR t.planned_date <= $4::date)
+        AND ($5::boolean IS NULL OR t.is_done = $5::boolean)
+        AND ($6::integer IS NULL OR t.rating >= $6::integer)
+        AND ($7::integer IS NULL OR t.rating <= $7::integer)
+        AND ($8::text IS NULL OR strpos(lower(t.title), lower($8::text)) > 0)
+) f
+WHERE $9::bigint IS NULL
+    OR ($10::boolean AND (f.sort_key, f.id) < ($11::bigint, $9::bigint))
+    OR (NOT $10::boolean AND (f.sort_key, f.id) > ($11::bigint, $9::bigint))
+ORDER BY
+    CASE WHEN $10::boolean THEN f.sort_key END DESC,
+    CASE WHEN $10::boolean THEN f.id END DESC,
+    f.sort_key,
+    f.id
+LIMIT $12
 `
 
-type GetExercisesWithTagsRow struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	VideoUrl    string      `json:"video_url"`
-	ImageUrl    string      `json:"image_url"`
-	Tags        interface{} `json:"tags"`
+type GetTrainingsFilteredParams struct {
+	SortBy      string         `json:"sort_by"`
+	UserID      uuid.UUID      `json:"user_id"`
+	PlannedFrom sql.NullTime   `json:"planned_from"`
+	PlannedTo   sql.NullTime   `json:"planned_to"`
+	IsDone      sql.NullBool   `json:"is_done"`
+	MinRating   sql.NullInt32  `json:"min_rating"`
+	MaxRating   sql.NullInt32  `json:"max_rating"`
+	Search      sql.NullString `json:"search"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	SortDesc    bool           `json:"sort_desc"`
+	CursorKey   sql.NullInt64  `json:"cursor_key"`
+	PageLimit   int32          `json:"page_limit"`
 }
 
-func (q *Queries) GetExercisesWithTags(ctx context.Context) ([]GetExercisesWithTagsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getExercisesWithTags)
+type GetTrainingsFilteredRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	IsPaused          bool          `json:"is_paused"`
+	SortKey           int64         `json:"sort_key"`
+}
+
+// Страница тренировок пользователя с фильтрами и keyset-пагинацией.
+// sort_key - числовой ключ сортировки (sort_by: 'planned_date', 'actual_date' или 'rating'): даты - дни от 1970-01-01,
+// тренировки без фактической даты или рейтинга идут в конце при сортировке по убыванию.
+// cursor_key и cursor_id - ключ и ID последней записи предыдущей страницы
+func (q *Queries) GetTrainingsFiltered(ctx context.Context, arg GetTrainingsFilteredParams) ([]GetTrainingsFilteredRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingsFiltered,
+		arg.SortBy,
+		arg.UserID,
+		arg.PlannedFrom,
+		arg.PlannedTo,
+		arg.IsDone,
+		arg.MinRating,
+		arg.MaxRating,
+		arg.Search,
+		arg.CursorID,
+		arg.SortDesc,
+		arg.CursorKey,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetExercisesWithTagsRow{}
+	var items []GetTrainingsFilteredRow
 	for rows.Next() {
-		var i GetExercisesWithTagsRow
+		var i GetTrainingsFilteredRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
-			&i.Description,
-			&i.VideoUrl,
-			&i.ImageUrl,
-			&i.Tags,
+			&i.UserID,
+			&i.IsDone,
+			&i.PlannedDate,
+			&i.ActualDate,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.TotalDuration,
+			&i.TotalRestTime,
+			&i.TotalExerciseTime,
+			&i.Rating,
+			&i.Status,
+			&i.IsPaused,
+			&i.SortKey,
 		); err != nil {
 			return nil, err
 		}
@@ -491,149 +3643,152 @@ func (q *Queries) GetExercisesWithTags(ctx context.Context) ([]GetExercisesWithT
 	return items, nil
 }
 
-const getGlobalTrainingByID = `-- name: GetGlobalTrainingByID :one
+const getTrainingsForExport = `-- name: GetTrainingsForExport :many
 SELECT 
-    gt.id,
-    gt.title,
-    gt.description,
-    gt.level,
+    t.id,
+    t.title,
+    t.user_id,
+    t.is_done,
+    t.planned_date,
+    t.actual_date,
+    t.started_at,
+    t.finished_at,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
+    t.rating,
+    t.status,
+    EXISTS (
+        SELECT 1 FROM training_pause tp
+        WHERE tp.training_id = t.id AND tp.ended_at IS NULL
+    ) as is_paused,
     COALESCE(
         json_agg(
             json_build_object(
-                'id', e.id,
-                'title', e.title,
-                'description', e.description,
-                'video_url', e.video_url,
-                'image_url', e.image_url,
-                'tags', COALESCE(
-                    (
-                        SELECT json_agg(
-                            json_build_object(
-                                'id', t.id,
-                                'type', t.type
-                            )
-                        )
-                        FROM exercise_to_tag et2
-                        JOIN tag t ON et2.tag_id = t.id
-                        WHERE et2.exercise_id = e.id
-                    ),
-                    '[]'
-                )
-            )
-        ) FILTER (WHERE e.id IS NOT NULL),
+                'id', te.id,
+                'training_id', te.training_id, 
+                'exercise_id', te.exercise_id,
+                'weight', CASE WHEN s.sets IS NULL THEN te.weight ELSE s.weight END,
+                'approaches', CASE WHEN s.sets IS NULL THEN te.approaches ELSE s.approaches END,
+                'reps', CASE WHEN s.sets IS NULL THEN te.reps ELSE s.reps END,
+                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
+                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
+                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
+                'notes', te.notes,
+                'sets', COALESCE(s.sets, '[]'::json),
+                'position', te.position,
+                'group_id', te.group_id,
+                'group_type', te.group_type,
+                'cardio', CASE WHEN tc.trained_exercise_id IS NULL THEN NULL ELSE json_build_object(
+                    'distance', tc.distance,
+                    'moving_time', EXTRACT(EPOCH FROM tc.moving_time)::bigint,
+                    'elevation_gain', tc.elevation_gain,
+                    'avg_heart_rate', tc.avg_heart_rate,
+                    'max_heart_rate', tc.max_heart_rate,
+                    'avg_pace', EXTRACT(EPOCH FROM tc.avg_pace)::bigint,
+                    'distance_unit', tc.distance_unit,
+                    'avg_speed', tc.avg_speed,
+                    'calories', tc.calories,
+                    'incline', tc.incline,
+                    'resistance_level', tc.resistance_level
+                ) END
+            ) ORDER BY te.position, te.id
+        ) FILTER (WHERE te.id IS NOT NULL),
         '[]'
     ) as exercises
-FROM global_training gt
-LEFT JOIN global_training_exercise gte ON gt.id = gte.global_training_id
-LEFT JOIN exercise e ON gte.exercise_id = e.id
-WHERE gt.id = $1
-GROUP BY gt.id, gt.level
-`
-
-type GetGlobalTrainingByIDRow struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Level       string      `json:"level"`
-	Exercises   interface{} `json:"exercises"`
-}
-
-// Получение глобальной тренировки по ID с упражнениями и их тегами
-func (q *Queries) GetGlobalTrainingByID(ctx context.Context, id int64) (GetGlobalTrainingByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getGlobalTrainingByID, id)
-	var i GetGlobalTrainingByIDRow
-	err := row.Scan(
-		&i.ID,
-		&i.Title,
-		&i.Description,
-		&i.Level,
-		&i.Exercises,
-	)
-	return i, err
-}
-
-const getGlobalTrainingById = `-- name: GetGlobalTrainingById :one
-SELECT id, level, title
-FROM global_training
-WHERE id = $1
-`
-
-type GetGlobalTrainingByIdRow struct {
-	ID    int64  `json:"id"`
-	Level string `json:"level"`
-	Title string `json:"title"`
-}
-
-func (q *Queries) GetGlobalTrainingById(ctx context.Context, id int64) (GetGlobalTrainingByIdRow, error) {
-	row := q.db.QueryRowContext(ctx, getGlobalTrainingById, id)
-	var i GetGlobalTrainingByIdRow
-	err := row.Scan(&i.ID, &i.Level, &i.Title)
-	return i, err
-}
-
-const getGlobalTrainingByLevel = `-- name: GetGlobalTrainingByLevel :many
-SELECT 
-    gt.id,
-    gt.title,
-    gt.description,
-    gt.level,
-    COALESCE(
+FROM training t
+LEFT JOIN trained_exercise te ON t.id = te.training_id
+LEFT JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+LEFT JOIN LATERAL (
+    SELECT
+        COUNT(*) FILTER (WHERE NOT ts.is_warmup) as approaches,
+        SUM(ts.reps) FILTER (WHERE NOT ts.is_warmup) as reps,
+        MAX(ts.weight) FILTER (WHERE NOT ts.is_warmup) as weight,
         json_agg(
             json_build_object(
-                'id', e.id,
-                'title', e.title,
-                'description', e.description,
-                'video_url', e.video_url,
-                'image_url', e.image_url,
-                'tags', COALESCE(
-                    (
-                        SELECT json_agg(
-                            json_build_object(
-                                'id', t.id,
-                                'type', t.type
-                            )
-                        )
-                        FROM exercise_to_tag et2
-                        JOIN tag t ON et2.tag_id = t.id
-                        WHERE et2.exercise_id = e.id
-                    ),
-                    '[]'
-                )
-            )
-        ) FILTER (WHERE e.id IS NOT NULL),
-        '[]'
-    ) as exercises
-FROM global_training gt
-LEFT JOIN global_training_exercise gte ON gt.id = gte.global_training_id
-LEFT JOIN exercise e ON gte.exercise_id = e.id
-WHERE gt.level = $1
-GROUP BY gt.id, gt.level
-ORDER BY gt.id
+                'id', ts.id,
+                'trained_exercise_id', ts.trained_exercise_id,
+                'ordinal', ts.ordinal,
+                'weight', ts.weight,
+                'reps', ts.reps,
+                'duration', EXTRACT(EPOCH FROM ts.duration)::bigint,
+                'rpe', ts.rpe,
+                'is_warmup', ts.is_warmup
+            ) ORDER BY ts.ordinal
+        ) as sets
+    FROM trained_set ts
+    WHERE ts.trained_exercise_id = te.id
+) s ON TRUE
+WHERE t.user_id = $1
+    AND ($2::date IS NULL OR t.planned_date >= $2::date)
+    AND ($3::date IS NULL OR t.planned_date <= $3::date)
+    AND ($4::bigint IS NULL
+        OR (t.planned_date, t.id) > ($5::date, $4::bigint))
+GROUP BY t.id
+ORDER BY t.planned_date, t.id
+LIMIT $6
 `
 
-type GetGlobalTrainingByLevelRow struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Level       string      `json:"level"`
-	Exercises   interface{} `json:"exercises"`
+type GetTrainingsForExportParams struct {
+	UserID    uuid.UUID     `json:"user_id"`
+	FromDate  sql.NullTime  `json:"from_date"`
+	ToDate    sql.NullTime  `json:"to_date"`
+	AfterID   sql.NullInt64 `json:"after_id"`
+	AfterDate sql.NullTime  `json:"after_date"`
+	BatchSize int32         `json:"batch_size"`
 }
 
-// Получение глобальных тренировок по уровню с упражнениями и их тегами
-func (q *Queries) GetGlobalTrainingByLevel(ctx context.Context, level string) ([]GetGlobalTrainingByLevelRow, error) {
-	rows, err := q.db.QueryContext(ctx, getGlobalTrainingByLevel, level)
+type GetTrainingsForExportRow struct {
+	ID                int64         `json:"id"`
+	Title             string        `json:"title"`
+	UserID            uuid.UUID     `json:"user_id"`
+	IsDone            bool          `json:"is_done"`
+	PlannedDate       time.Time     `json:"planned_date"`
+	ActualDate        sql.NullTime  `json:"actual_date"`
+	StartedAt         sql.NullTime  `json:"started_at"`
+	FinishedAt        sql.NullTime  `json:"finished_at"`
+	TotalDuration     int64         `json:"total_duration"`
+	TotalRestTime     int64         `json:"total_rest_time"`
+	TotalExerciseTime int64         `json:"total_exercise_time"`
+	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
+	IsPaused          bool          `json:"is_paused"`
+	Exercises         interface{}   `json:"exercises"`
+}
+
+// Пачка тренировок пользователя с упражнениями и подходами для выгрузки, по planned_date и id.
+// after_date и after_id - последняя тренировка предыдущей пачки; для первой пачки не задаются
+func (q *Queries) GetTrainingsForExport(ctx context.Context, arg GetTrainingsForExportParams) ([]GetTrainingsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrainingsForExport,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+		arg.AfterID,
+		arg.AfterDate,
+		arg.BatchSize,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetGlobalTrainingByLevelRow{}
+	var items []GetTrainingsForExportRow
 	for rows.Next() {
-		var i GetGlobalTrainingByLevelRow
+		var i GetTrainingsForExportRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
-			&i.Description,
-			&i.Level,
+			&i.UserID,
+			&i.IsDone,
+			&i.PlannedDate,
+			&i.ActualDate,
+			&i.StartedAt,
+			&i.FinishedAt,
+			&i.TotalDuration,
+			&i.TotalRestTime,
+			&i.TotalExerciseTime,
+			&i.Rating,
+			&i.Status,
+			&i.IsPaused,
 			&i.Exercises,
 		); err != nil {
 			return nil, err
@@ -649,22 +3804,55 @@ func (q *Queries) GetGlobalTrainingByLevel(ctx context.Context, level string) ([
 	return items, nil
 }
 
-const getGlobalTrainingExercises = `-- name: GetGlobalTrainingExercises :many
-SELECT gte.id, gte.global_training_id, gte.exercise_id
-FROM global_training_exercise gte
-WHERE gte.global_training_id = $1
+const getUserSettings = `-- name: GetUserSettings :one
+SELECT user_id, week_start, rest_days, timezone, updated_at
+FROM user_settings
+WHERE user_id = $1
 `
 
-func (q *Queries) GetGlobalTrainingExercises(ctx context.Context, globalTrainingID int64) ([]GlobalTrainingExercise, error) {
-	rows, err := q.db.QueryContext(ctx, getGlobalTrainingExercises, globalTrainingID)
+func (q *Queries) GetUserSettings(ctx context.Context, userID uuid.UUID) (UserSetting, error) {
+	row := q.db.QueryRowContext(ctx, getUserSettings, userID)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.WeekStart,
+		&i.RestDays,
+		&i.Timezone,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTrainingDates = `-- name: GetUserTrainingDates :many
+SELECT planned_date, actual_date, is_done, status
+FROM training
+WHERE user_id = $1
+ORDER BY COALESCE(actual_date, planned_date), id
+`
+
+type GetUserTrainingDatesRow struct {
+	PlannedDate time.Time    `json:"planned_date"`
+	ActualDate  sql.NullTime `json:"actual_date"`
+	IsDone      bool         `json:"is_done"`
+	Status      string       `json:"status"`
+}
+
+// Даты и статусы всех тренировок пользователя для расчета серий и регулярности
+func (q *Queries) GetUserTrainingDates(ctx context.Context, userID uuid.UUID) ([]GetUserTrainingDatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserTrainingDates, userID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GlobalTrainingExercise{}
+	var items []GetUserTrainingDatesRow
 	for rows.Next() {
-		var i GlobalTrainingExercise
-		if err := rows.Scan(&i.ID, &i.GlobalTrainingID, &i.ExerciseID); err != nil {
+		var i GetUserTrainingDatesRow
+		if err := rows.Scan(
+			&i.PlannedDate,
+			&i.ActualDate,
+			&i.IsDone,
+			&i.Status,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -678,75 +3866,245 @@ func (q *Queries) GetGlobalTrainingExercises(ctx context.Context, globalTraining
 	return items, nil
 }
 
-const getGlobalTrainings = `-- name: GetGlobalTrainings :many
-SELECT 
-    gt.id,
-    gt.title,
-    gt.description,
-    gt.level,
-    COALESCE(
-        json_agg(
-            json_build_object(
-                'id', e.id,
-                'title', e.title,
-                'description', e.description,
-                'video_url', e.video_url,
-                'image_url', e.image_url,
-                'tags', COALESCE(
-                    (
-                        SELECT json_agg(
-                            json_build_object(
-                                'id', t.id,
-                                'type', t.type
-                            )
-                        )
-                        FROM exercise_to_tag et2
-                        JOIN tag t ON et2.tag_id = t.id
-                        WHERE et2.exercise_id = e.id
-                    ),
-                    '[]'
-                )
-            )
-        ) FILTER (WHERE e.id IS NOT NULL),
-        '[]'
-    ) as exercises
-FROM global_training gt
-LEFT JOIN global_training_exercise gte ON gt.id = gte.global_training_id
-LEFT JOIN exercise e ON gte.exercise_id = e.id
-GROUP BY gt.id, gt.level
-ORDER BY 
-    CASE gt.level 
-        WHEN 'beginner' THEN 1
-        WHEN 'intermediate' THEN 2
-        WHEN 'advanced' THEN 3
-    END
+const getUserTrainingStatsByPeriod = `-- name: GetUserTrainingStatsByPeriod :many
+SELECT
+    CAST(date_trunc($1::text, CAST(COALESCE(t.actual_date, t.planned_date) AS TIMESTAMP)) AS DATE) as period_start,
+    COUNT(t.id) as total_trainings,
+    COUNT(t.id) FILTER (WHERE t.is_done) as completed_trainings,
+    COUNT(t.id) FILTER (WHERE t.status = 'skipped') as skipped_trainings,
+    CAST(COALESCE(AVG(t.rating) FILTER (WHERE t.is_done), 0) AS FLOAT8) as average_rating,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_duration))::bigint, 0) AS BIGINT) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_rest_time))::bigint, 0) AS BIGINT) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_exercise_time))::bigint, 0) AS BIGINT) as total_exercise_time,
+    CAST(COALESCE(SUM(v.volume), 0) AS TEXT) as total_volume,
+    CAST(COALESCE(SUM(c.distance), 0) AS TEXT) as total_distance,
+    CAST(COALESCE(SUM(c.paced_distance), 0) AS TEXT) as paced_distance,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(c.paced_time))::bigint, 0) AS BIGINT) as paced_time,
+    CAST(COALESCE(SUM(c.calories), 0) AS BIGINT) as total_calories
+FROM training t
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(CASE WHEN s.set_count > 0 THEN s.volume ELSE te.weight * te.reps * te.approaches END) as volume
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    LEFT JOIN LATERAL (
+        SELECT
+            COUNT(*) as set_count,
+            SUM(ts.weight * ts.reps) FILTER (WHERE NOT ts.is_warmup) as volume
+        FROM trained_set ts
+        WHERE ts.trained_exercise_id = te.id
+    ) s ON TRUE
+    WHERE t.user_id = $2 AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN $3::date AND $4::date
+    GROUP BY te.training_id
+) v ON v.training_id = t.id
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(tc.distance) as distance,
+        SUM(tc.distance) FILTER (WHERE tc.moving_time IS NOT NULL) as paced_distance,
+        SUM(tc.moving_time) FILTER (WHERE tc.distance > 0) as paced_time,
+        SUM(tc.calories) as calories
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+    WHERE t.user_id = $2 AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN $3::date AND $4::date
+    GROUP BY te.training_id
+) c ON c.training_id = t.id
+WHERE t.user_id = $2
+    AND COALESCE(t.actual_date, t.planned_date) BETWEEN $3::date AND $4::date
+GROUP BY period_start
+ORDER BY period_start
+`
+
+type GetUserTrainingStatsByPeriodParams struct {
+	GroupBy  string    `json:"group_by"`
+	UserID   uuid.UUID `json:"user_id"`
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
+}
+
+type GetUserTrainingStatsByPeriodRow struct {
+	PeriodStart        time.Time `json:"period_start"`
+	TotalTrainings     int64     `json:"total_trainings"`
+	CompletedTrainings int64     `json:"completed_trainings"`
+	SkippedTrainings   int64     `json:"skipped_trainings"`
+	AverageRating      float64   `json:"average_rating"`
+	TotalDuration      int64     `json:"total_duration"`
+	TotalRestTime      int64     `json:"total_rest_time"`
+	TotalExerciseTime  int64     `json:"total_exercise_time"`
+	TotalVolume        string    `json:"total_volume"`
+	TotalDistance      string    `json:"total_distance"`
+	PacedDistance      string    `json:"paced_distance"`
+	PacedTime          int64     `json:"paced_time"`
+	TotalCalories      int64     `json:"total_calories"`
+}
+
+// Статистика тренировок пользователя за период с разбивкой по дням, неделям или месяцам (group_by - 'day', 'week' или 'month').
+// Объем и кардио-показатели, как и в GetUserTrainingStatsTotals, считаются по завершенным тренировкам.
+// Периоды без тренировок не возвращаются
+func (q *Queries) GetUserTrainingStatsByPeriod(ctx context.Context, arg GetUserTrainingStatsByPeriodParams) ([]GetUserTrainingStatsByPeriodRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserTrainingStatsByPeriod,
+		arg.GroupBy,
+		arg.UserID,
+		arg.FromDate,
+		arg.ToDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserTrainingStatsByPeriodRow
+	for rows.Next() {
+		var i GetUserTrainingStatsByPeriodRow
+		if err := rows.Scan(
+			&i.PeriodStart,
+			&i.TotalTrainings,
+			&i.CompletedTrainings,
+			&i.SkippedTrainings,
+			&i.AverageRating,
+			&i.TotalDuration,
+			&i.TotalRestTime,
+			&i.TotalExerciseTime,
+			&i.TotalVolume,
+			&i.TotalDistance,
+			&i.PacedDistance,
+			&i.PacedTime,
+			&i.TotalCalories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserTrainingStatsTotals = `-- name: GetUserTrainingStatsTotals :one
+SELECT
+    COUNT(t.id) as total_trainings,
+    COUNT(t.id) FILTER (WHERE t.is_done) as completed_trainings,
+    COUNT(t.id) FILTER (WHERE t.status = 'skipped') as skipped_trainings,
+    CAST(COALESCE(AVG(t.rating) FILTER (WHERE t.is_done), 0) AS FLOAT8) as average_rating,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_duration))::bigint, 0) AS BIGINT) as total_duration,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_rest_time))::bigint, 0) AS BIGINT) as total_rest_time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(t.total_exercise_time))::bigint, 0) AS BIGINT) as total_exercise_time,
+    CAST(COALESCE(SUM(v.volume), 0) AS TEXT) as total_volume,
+    CAST(COALESCE(SUM(c.distance), 0) AS TEXT) as total_distance,
+    CAST(COALESCE(SUM(c.paced_distance), 0) AS TEXT) as paced_distance,
+    CAST(COALESCE(EXTRACT(EPOCH FROM SUM(c.paced_time))::bigint, 0) AS BIGINT) as paced_time,
+    CAST(COALESCE(SUM(c.calories), 0) AS BIGINT) as total_calories
+FROM training t
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(CASE WHEN s.set_count > 0 THEN s.volume ELSE te.weight * te.reps * te.approaches END) as volume
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    LEFT JOIN LATERAL (
+        SELECT
+            COUNT(*) as set_count,
+            SUM(ts.weight * ts.reps) FILTER (WHERE NOT ts.is_warmup) as volume
+        FROM trained_set ts
+        WHERE ts.trained_exercise_id = te.id
+    ) s ON TRUE
+    WHERE t.user_id = $1 AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN $2::date AND $3::date
+    GROUP BY te.training_id
+) v ON v.training_id = t.id
+LEFT JOIN (
+    SELECT
+        te.training_id,
+        SUM(tc.distance) as distance,
+        SUM(tc.distance) FILTER (WHERE tc.moving_time IS NOT NULL) as paced_distance,
+        SUM(tc.moving_time) FILTER (WHERE tc.distance > 0) as paced_time,
+        SUM(tc.calories) as calories
+    FROM trained_exercise te
+    JOIN training t ON t.id = te.training_id
+    JOIN trained_exercise_cardio tc ON tc.trained_exercise_id = te.id
+    WHERE t.user_id = $1 AND t.is_done
+        AND COALESCE(t.actual_date, t.planned_date) BETWEEN $2::date AND $3::date
+    GROUP BY te.training_id
+) c ON c.training_id = t.id
+WHERE t.user_id = $1
+    AND COALESCE(t.actual_date, t.planned_date) BETWEEN $2::date AND $3::date
 `
 
-type GetGlobalTrainingsRow struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title"`
-	Description string      `json:"description"`
-	Level       string      `json:"level"`
-	Exercises   interface{} `json:"exercises"`
+type GetUserTrainingStatsTotalsParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	FromDate time.Time `json:"from_date"`
+	ToDate   time.Time `json:"to_date"`
 }
 
-// Получение всех глобальных тренировок с упражнениями и их тегами
-func (q *Queries) GetGlobalTrainings(ctx context.Context) ([]GetGlobalTrainingsRow, error) {
-	rows, err := q.db.QueryContext(ctx, getGlobalTrainings)
+type GetUserTrainingStatsTotalsRow struct {
+	TotalTrainings     int64   `json:"total_trainings"`
+	CompletedTrainings int64   `json:"completed_trainings"`
+	SkippedTrainings   int64   `json:"skipped_trainings"`
+	AverageRating      float64 `json:"average_rating"`
+	TotalDuration      int64   `json:"total_duration"`
+	TotalRestTime      int64   `json:"total_rest_time"`
+	TotalExerciseTime  int64   `json:"total_exercise_time"`
+	TotalVolume        string  `json:"total_volume"`
+	TotalDistance      string  `json:"total_distance"`
+	PacedDistance      string  `json:"paced_distance"`
+	PacedTime          int64   `json:"paced_time"`
+	TotalCalories      int64   `json:"total_calories"`
+}
+
+// Статистика тренировок пользователя за период.
+// Объем считается так же, как в GetTrainingStats: по рабочим подходам, а без подходов - по агрегатам упражнения.
+// Объем и кардио-показатели считаются по завершенным тренировкам; темп - по упражнениям, у которых есть и дистанция, и время
+func (q *Queries) GetUserTrainingStatsTotals(ctx context.Context, arg GetUserTrainingStatsTotalsParams) (GetUserTrainingStatsTotalsRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserTrainingStatsTotals, arg.UserID, arg.FromDate, arg.ToDate)
+	var i GetUserTrainingStatsTotalsRow
+	err := row.Scan(
+		&i.TotalTrainings,
+		&i.CompletedTrainings,
+		&i.SkippedTrainings,
+		&i.AverageRating,
+		&i.TotalDuration,
+		&i.TotalRestTime,
+		&i.TotalExerciseTime,
+		&i.TotalVolume,
+		&i.TotalDistance,
+		&i.PacedDistance,
+		&i.PacedTime,
+		&i.TotalCalories,
+	)
+	return i, err
+}
+
+const getWorkingSetsByTrainedExercises = `-- name: GetWorkingSetsByTrainedExercises :many
+SELECT trained_exercise_id, weight, reps
+FROM trained_set
+WHERE trained_exercise_id = ANY($1::bigint[]) AND NOT is_warmup
+ORDER BY trained_exercise_id, ordinal
+`
+
+type GetWorkingSetsByTrainedExercisesRow struct {
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Weight            sql.NullString `json:"weight"`
+	Reps              sql.NullInt32  `json:"reps"`
+}
+
+// Рабочие подходы нескольких выполненных упражнений в порядке выполнения
+func (q *Queries) GetWorkingSetsByTrainedExercises(ctx context.Context, ids []int64) ([]GetWorkingSetsByTrainedExercisesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkingSetsByTrainedExercises, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetGlobalTrainingsRow{}
+	var items []GetWorkingSetsByTrainedExercisesRow
 	for rows.Next() {
-		var i GetGlobalTrainingsRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.Description,
-			&i.Level,
-			&i.Exercises,
-		); err != nil {
+		var i GetWorkingSetsByTrainedExercisesRow
+		if err := rows.Scan(&i.TrainedExerciseID, &i.Weight, &i.Reps); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -760,85 +4118,90 @@ func (q *Queries) GetGlobalTrainings(ctx context.Context) ([]GetGlobalTrainingsR
 	return items, nil
 }
 
-const getTodaysTraining = `-- name: GetTodaysTraining :many
-SELECT 
-    t.id,
-    t.title,
-    t.user_id,
-    t.is_done,
-    t.planned_date,
-    t.actual_date,
-    t.started_at,
-    t.finished_at,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    t.rating,
-    COALESCE(
-        json_agg(
-            json_build_object(
-                'id', te.id,
-                'training_id', te.training_id, 
-                'exercise_id', te.exercise_id,
-                'weight', te.weight,
-                'approaches', te.approaches,
-                'reps', te.reps,
-                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
-                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
-                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
-                'notes', te.notes
-            )
-        ) FILTER (WHERE te.id IS NOT NULL),
-        '[]'
-    ) as exercises
-FROM training t
-LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.user_id = $1 
-    AND t.planned_date = CURRENT_DATE
-GROUP BY t.id
-ORDER BY t.planned_date DESC
+const isExerciseUsed = `-- name: IsExerciseUsed :one
+SELECT EXISTS (
+    SELECT 1 FROM trained_exercise WHERE exercise_id = $1
+    UNION ALL
+    SELECT 1 FROM global_training_exercise WHERE exercise_id = $1
+    UNION ALL
+    SELECT 1 FROM training_template_exercise WHERE exercise_id = $1
+) as used
 `
 
-type GetTodaysTrainingRow struct {
-	ID                int64         `json:"id"`
-	Title             string        `json:"title"`
-	UserID            uuid.UUID     `json:"user_id"`
-	IsDone            bool          `json:"is_done"`
-	PlannedDate       time.Time     `json:"planned_date"`
-	ActualDate        sql.NullTime  `json:"actual_date"`
-	StartedAt         sql.NullTime  `json:"started_at"`
-	FinishedAt        sql.NullTime  `json:"finished_at"`
-	TotalDuration     int64         `json:"total_duration"`
-	TotalRestTime     int64         `json:"total_rest_time"`
-	TotalExerciseTime int64         `json:"total_exercise_time"`
-	Rating            sql.NullInt32 `json:"rating"`
-	Exercises         interface{}   `json:"exercises"`
+// Используется ли упражнение в тренировках и шаблонах пользователей или глобальных тренировках
+func (q *Queries) IsExerciseUsed(ctx context.Context, exerciseID int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isExerciseUsed, exerciseID)
+	var used bool
+	err := row.Scan(&used)
+	return used, err
+}
+
+const isGlobalTrainingUsedByProgram = `-- name: IsGlobalTrainingUsedByProgram :one
+SELECT EXISTS (
+    SELECT 1 FROM program_session WHERE global_training_id = $1
+) as used
+`
+
+// Входит ли глобальная тренировка в сессии программ
+func (q *Queries) IsGlobalTrainingUsedByProgram(ctx context.Context, globalTrainingID int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isGlobalTrainingUsedByProgram, globalTrainingID)
+	var used bool
+	err := row.Scan(&used)
+	return used, err
+}
+
+const listTrainedSets = `-- name: ListTrainedSets :many
+SELECT
+    ts.id,
+    ts.trained_exercise_id,
+    ts.ordinal,
+    ts.weight,
+    ts.reps,
+    EXTRACT(EPOCH FROM ts.duration)::bigint as duration,
+    ts.rpe,
+    ts.is_warmup
+FROM trained_set ts
+JOIN trained_exercise te ON te.id = ts.trained_exercise_id
+JOIN training t ON t.id = te.training_id
+WHERE ts.trained_exercise_id = $1 AND t.user_id = $2
+ORDER BY ts.ordinal
+`
+
+type ListTrainedSetsParams struct {
+	TrainedExerciseID int64     `json:"trained_exercise_id"`
+	UserID            uuid.UUID `json:"user_id"`
+}
+
+type ListTrainedSetsRow struct {
+	ID                int64          `json:"id"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Ordinal           int32          `json:"ordinal"`
+	Weight            sql.NullString `json:"weight"`
+	Reps              sql.NullInt32  `json:"reps"`
+	Duration          sql.NullInt64  `json:"duration"`
+	Rpe               sql.NullString `json:"rpe"`
+	IsWarmup          bool           `json:"is_warmup"`
 }
 
-// Получение всех тренировок на сегодня для пользователя
-func (q *Queries) GetTodaysTraining(ctx context.Context, userID uuid.UUID) ([]GetTodaysTrainingRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTodaysTraining, userID)
+// Подходы выполненного упражнения в порядке выполнения
+func (q *Queries) ListTrainedSets(ctx context.Context, arg ListTrainedSetsParams) ([]ListTrainedSetsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listTrainedSets, arg.TrainedExerciseID, arg.UserID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []GetTodaysTrainingRow{}
+	items := []ListTrainedSetsRow{}
 	for rows.Next() {
-		var i GetTodaysTrainingRow
+		var i ListTrainedSetsRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Title,
-			&i.UserID,
-			&i.IsDone,
-			&i.PlannedDate,
-			&i.ActualDate,
-			&i.StartedAt,
-			&i.FinishedAt,
-			&i.TotalDuration,
-			&i.TotalRestTime,
-			&i.TotalExerciseTime,
-			&i.Rating,
-			&i.Exercises,
+			&i.TrainedExerciseID,
+			&i.Ordinal,
+			&i.Weight,
+			&i.Reps,
+			&i.Duration,
+			&i.Rpe,
+			&i.IsWarmup,
 		); err != nil {
 			return nil, err
 		}
@@ -853,200 +4216,193 @@ func (q *Queries) GetTodaysTraining(ctx context.Context, userID uuid.UUID) ([]Ge
 	return items, nil
 }
 
-const getTrainingStats = `-- name: GetTrainingStats :one
-SELECT 
-    t.id,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    COUNT(te.id) as exercise_count,
-    COALESCE(SUM(te.approaches), 0) as total_approaches,
-    COALESCE(SUM(te.reps), 0) as total_reps
-FROM training t
-LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.id = $1
-GROUP BY t.id
+const renumberTrainedExercises = `-- name: RenumberTrainedExercises :exec
+UPDATE trained_exercise te
+SET position = n.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY position, id) as rn
+    FROM trained_exercise
+    WHERE training_id = $1
+) n
+WHERE te.id = n.id AND te.position <> n.rn
 `
 
-type GetTrainingStatsRow struct {
-	ID                int64       `json:"id"`
-	TotalDuration     int64       `json:"total_duration"`
-	TotalRestTime     int64       `json:"total_rest_time"`
-	TotalExerciseTime int64       `json:"total_exercise_time"`
-	ExerciseCount     int64       `json:"exercise_count"`
-	TotalApproaches   interface{} `json:"total_approaches"`
-	TotalReps         interface{} `json:"total_reps"`
-}
-
-// Получение статистики по тренировке (общее время выполнения и отдыха)
-func (q *Queries) GetTrainingStats(ctx context.Context, id int64) (GetTrainingStatsRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingStats, id)
-	var i GetTrainingStatsRow
-	err := row.Scan(
-		&i.ID,
-		&i.TotalDuration,
-		&i.TotalRestTime,
-		&i.TotalExerciseTime,
-		&i.ExerciseCount,
-		&i.TotalApproaches,
-		&i.TotalReps,
-	)
-	return i, err
+// Сплошная нумерация упражнений тренировки после удаления
+func (q *Queries) RenumberTrainedExercises(ctx context.Context, trainingID int64) error {
+	_, err := q.db.ExecContext(ctx, renumberTrainedExercises, trainingID)
+	return err
 }
 
-const getTrainingWithExercises = `-- name: GetTrainingWithExercises :one
-SELECT 
-    t.id,
-    t.title,
-    t.user_id,
-    t.is_done,
-    t.planned_date,
-    t.actual_date,
-    t.started_at,
-    t.finished_at,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    t.rating,
-    COALESCE(
-        json_agg(
-            json_build_object(
-                'id', te.id,
-                'training_id', te.training_id, 
-                'exercise_id', te.exercise_id,
-                'weight', te.weight,
-                'approaches', te.approaches,
-                'reps', te.reps,
-                'time', CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint),
-                'doing', CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0) as bigint),
-                'rest', CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0) as bigint),
-                'notes', te.notes
-            )
-        ) FILTER (WHERE te.id IS NOT NULL),
-        '[]'
-    ) as exercises
+const renumberTrainedSets = `-- name: RenumberTrainedSets :exec
+UPDATE trained_set ts
+SET ordinal = n.rn
+FROM (
+    SELECT id, ROW_NUMBER() OVER (ORDER BY ordinal) as rn
+    FROM trained_set
+    WHERE trained_exercise_id = $1
+) n
+WHERE ts.id = n.id AND ts.ordinal <> n.rn
+`
+
+// Сплошная нумерация подходов после удаления
+func (q *Queries) RenumberTrainedSets(ctx context.Context, trainedExerciseID int64) error {
+	_, err := q.db.ExecContext(ctx, renumberTrainedSets, trainedExerciseID)
+	return err
+}
+
+const setScheduleMaterializedUntil = `-- name: SetScheduleMaterializedUntil :exec
+UPDATE training_schedule SET materialized_until = $2 WHERE id = $1
+`
+
+type SetScheduleMaterializedUntilParams struct {
+	ID                int64        `json:"id"`
+	MaterializedUntil sql.NullTime `json:"materialized_until"`
+}
+
+func (q *Queries) SetScheduleMaterializedUntil(ctx context.Context, arg SetScheduleMaterializedUntilParams) error {
+	_, err := q.db.ExecContext(ctx, setScheduleMaterializedUntil, arg.ID, arg.MaterializedUntil)
+	return err
+}
+
+const setTrainedExerciseGroup = `-- name: SetTrainedExerciseGroup :one
+UPDATE trained_exercise te
+SET group_id = $1, group_type = $2
 FROM training t
-LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.id = $1
-GROUP BY t.id
+WHERE te.id = $3 AND te.training_id = t.id AND t.user_id = $4
+RETURNING 
+    te.id,
+    te.training_id,
+    te.exercise_id,
+    te.weight,
+    te.approaches,
+    te.reps,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint) as time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0)as bigint) as doing,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0)as bigint) as rest,
+    te.notes,
+    te.position,
+    te.group_id,
+    te.group_type
 `
 
-type GetTrainingWithExercisesRow struct {
-	ID                int64         `json:"id"`
-	Title             string        `json:"title"`
-	UserID            uuid.UUID     `json:"user_id"`
-	IsDone            bool          `json:"is_done"`
-	PlannedDate       time.Time     `json:"planned_date"`
-	ActualDate        sql.NullTime  `json:"actual_date"`
-	StartedAt         sql.NullTime  `json:"started_at"`
-	FinishedAt        sql.NullTime  `json:"finished_at"`
-	TotalDuration     int64         `json:"total_duration"`
-	TotalRestTime     int64         `json:"total_rest_time"`
-	TotalExerciseTime int64         `json:"total_exercise_time"`
-	Rating            sql.NullInt32 `json:"rating"`
-	Exercises         interface{}   `json:"exercises"`
+type SetTrainedExerciseGroupParams struct {
+	GroupID   sql.NullInt32  `json:"group_id"`
+	GroupType sql.NullString `json:"group_type"`
+	ID        int64          `json:"id"`
+	UserID    uuid.UUID      `json:"user_id"`
 }
 
-func (q *Queries) GetTrainingWithExercises(ctx context.Context, id int64) (GetTrainingWithExercisesRow, error) {
-	row := q.db.QueryRowContext(ctx, getTrainingWithExercises, id)
-	var i GetTrainingWithExercisesRow
+type SetTrainedExerciseGroupRow struct {
+	ID         int64          `json:"id"`
+	TrainingID int64          `json:"training_id"`
+	ExerciseID int64          `json:"exercise_id"`
+	Weight     sql.NullString `json:"weight"`
+	Approaches sql.NullInt32  `json:"approaches"`
+	Reps       sql.NullInt32  `json:"reps"`
+	Time       int64          `json:"time"`
+	Doing      int64          `json:"doing"`
+	Rest       int64          `json:"rest"`
+	Notes      sql.NullString `json:"notes"`
+	Position   int32          `json:"position"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
+}
+
+// Включение упражнения в группу или исключение из нее (NULL)
+func (q *Queries) SetTrainedExerciseGroup(ctx context.Context, arg SetTrainedExerciseGroupParams) (SetTrainedExerciseGroupRow, error) {
+	row := q.db.QueryRowContext(ctx, setTrainedExerciseGroup,
+		arg.GroupID,
+		arg.GroupType,
+		arg.ID,
+		arg.UserID,
+	)
+	var i SetTrainedExerciseGroupRow
 	err := row.Scan(
 		&i.ID,
-		&i.Title,
-		&i.UserID,
-		&i.IsDone,
-		&i.PlannedDate,
-		&i.ActualDate,
-		&i.StartedAt,
-		&i.FinishedAt,
-		&i.TotalDuration,
-		&i.TotalRestTime,
-		&i.TotalExerciseTime,
-		&i.Rating,
-		&i.Exercises,
+		&i.TrainingID,
+		&i.ExerciseID,
+		&i.Weight,
+		&i.Approaches,
+		&i.Reps,
+		&i.Time,
+		&i.Doing,
+		&i.Rest,
+		&i.Notes,
+		&i.Position,
+		&i.GroupID,
+		&i.GroupType,
 	)
 	return i, err
-}
-
-const getTrainingsByUser = `-- name: GetTrainingsByUser :many
-SELECT 
-    t.id,
-    t.title,
-    t.user_id,
-    t.is_done,
-    t.planned_date,
-    t.actual_date,
-    t.started_at,
-    t.finished_at,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_duration)::bigint, 0) as bigint) as total_duration,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_rest_time)::bigint, 0)as bigint) as total_rest_time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM t.total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    t.rating
-FROM training t
-LEFT JOIN trained_exercise te ON t.id = te.training_id
-WHERE t.user_id = $1
-GROUP BY t.id
-ORDER BY t.planned_date DESC
+}
+
+const setTrainedExercisePosition = `-- name: SetTrainedExercisePosition :execrows
+UPDATE trained_exercise
+SET position = $1
+WHERE id = $2 AND training_id = $3
 `
 
-type GetTrainingsByUserRow struct {
-	ID                int64         `json:"id"`
-	Title             string        `json:"title"`
-	UserID            uuid.UUID     `json:"user_id"`
-	IsDone            bool          `json:"is_done"`
-	PlannedDate       time.Time     `json:"planned_date"`
-	ActualDate        sql.NullTime  `json:"actual_date"`
-	StartedAt         sql.NullTime  `json:"started_at"`
-	FinishedAt        sql.NullTime  `json:"finished_at"`
-	TotalDuration     int64         `json:"total_duration"`
-	TotalRestTime     int64         `json:"total_rest_time"`
-	TotalExerciseTime int64         `json:"total_exercise_time"`
-	Rating            sql.NullInt32 `json:"rating"`
+type SetTrainedExercisePositionParams struct {
+	Position   int32 `json:"position"`
+	ID         int64 `json:"id"`
+	TrainingID int64 `json:"training_id"`
 }
 
-func (q *Queries) GetTrainingsByUser(ctx context.Context, userID uuid.UUID) ([]GetTrainingsByUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getTrainingsByUser, userID)
+func (q *Queries) SetTrainedExercisePosition(ctx context.Context, arg SetTrainedExercisePositionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setTrainedExercisePosition, arg.Position, arg.ID, arg.TrainingID)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []GetTrainingsByUserRow{}
-	for rows.Next() {
-		var i GetTrainingsByUserRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.Title,
-			&i.UserID,
-			&i.IsDone,
-			&i.PlannedDate,
-			&i.ActualDate,
-			&i.StartedAt,
-			&i.FinishedAt,
-			&i.TotalDuration,
-			&i.TotalRestTime,
-			&i.TotalExerciseTime,
-			&i.Rating,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return result.RowsAffected()
+}
+
+const setTrainedSetOrdinal = `-- name: SetTrainedSetOrdinal :execrows
+UPDATE trained_set
+SET ordinal = $1
+WHERE id = $2 AND trained_exercise_id = $3
+`
+
+type SetTrainedSetOrdinalParams struct {
+	Ordinal           int32 `json:"ordinal"`
+	ID                int64 `json:"id"`
+	TrainedExerciseID int64 `json:"trained_exercise_id"`
+}
+
+func (q *Queries) SetTrainedSetOrdinal(ctx context.Context, arg SetTrainedSetOrdinalParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setTrainedSetOrdinal, arg.Ordinal, arg.ID, arg.TrainedExerciseID)
+	if err != nil {
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected()
+}
+
+const lockTraining = `-- name: LockTraining :one
+SELECT id FROM training
+WHERE id = $1 AND user_id = $2
+FOR UPDATE
+`
+
+type LockTrainingParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Блокировка тренировки пользователя до конца транзакции: добавления упражнений в одну тренировку
+// выполняются по очереди и не получают одинаковый position
+func (q *Queries) LockTraining(ctx context.Context, arg LockTrainingParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, lockTraining, arg.ID, arg.UserID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
 const markTrainingAsDone = `-- name: MarkTrainingAsDone :one
 UPDATE training
 SET 
     is_done = true,
-    actual_date = CURRENT_DATE,
-    finished_at = COALESCE($1, CURRENT_TIMESTAMP)
-WHERE id = $2 AND user_id = $3
+    status = 'completed',
+    actual_date = $1,
+    finished_at = COALESCE($2, CURRENT_TIMESTAMP)
+WHERE id = $3 AND user_id = $4 AND status IN ('planned', 'in_progress')
 RETURNING 
     id,
     title,
@@ -1059,10 +4415,12 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating
+    rating,
+    status
 `
 
 type MarkTrainingAsDoneParams struct {
+	ActualDate sql.NullTime `json:"actual_date"`
 	FinishedAt sql.NullTime `json:"finished_at"`
 	ID         int64        `json:"id"`
 	UserID     uuid.UUID    `json:"user_id"`
@@ -1081,11 +4439,17 @@ type MarkTrainingAsDoneRow struct {
 	TotalRestTime     int64         `json:"total_rest_time"`
 	TotalExerciseTime int64         `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
 }
 
-// Отметить тренировку как выполненную
+// Отметить тренировку как выполненную. actual_date - дата в часовом поясе пользователя
 func (q *Queries) MarkTrainingAsDone(ctx context.Context, arg MarkTrainingAsDoneParams) (MarkTrainingAsDoneRow, error) {
-	row := q.db.QueryRowContext(ctx, markTrainingAsDone, arg.FinishedAt, arg.ID, arg.UserID)
+	row := q.db.QueryRowContext(ctx, markTrainingAsDone,
+		arg.ActualDate,
+		arg.FinishedAt,
+		arg.ID,
+		arg.UserID,
+	)
 	var i MarkTrainingAsDoneRow
 	err := row.Scan(
 		&i.ID,
@@ -1100,6 +4464,7 @@ func (q *Queries) MarkTrainingAsDone(ctx context.Context, arg MarkTrainingAsDone
 		&i.TotalRestTime,
 		&i.TotalExerciseTime,
 		&i.Rating,
+		&i.Status,
 	)
 	return i, err
 }
@@ -1108,8 +4473,9 @@ const startTraining = `-- name: StartTraining :one
 UPDATE training
 SET 
     started_at = COALESCE($1, CURRENT_TIMESTAMP),
-    is_done = false
-WHERE id = $2 AND user_id = $3
+    is_done = false,
+    status = 'in_progress'
+WHERE id = $2 AND user_id = $3 AND status = 'planned'
 RETURNING 
     id,
     title,
@@ -1122,7 +4488,8 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating
+    rating,
+    status
 `
 
 type StartTrainingParams struct {
@@ -1144,6 +4511,7 @@ type StartTrainingRow struct {
 	TotalRestTime     int64         `json:"total_rest_time"`
 	TotalExerciseTime int64         `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
 }
 
 // Начать тренировку (установить время начала)
@@ -1163,35 +4531,109 @@ func (q *Queries) StartTraining(ctx context.Context, arg StartTrainingParams) (S
 		&i.TotalRestTime,
 		&i.TotalExerciseTime,
 		&i.Rating,
+		&i.Status,
+	)
+	return i, err
+}
+
+const startTrainingPause = `-- name: StartTrainingPause :execrows
+WITH paused AS (
+    UPDATE training
+    SET status = 'paused'
+    WHERE id = $1 AND user_id = $2 AND status = 'in_progress'
+    RETURNING id
+)
+INSERT INTO training_pause (training_id, started_at)
+SELECT id, CURRENT_TIMESTAMP
+FROM paused
+`
+
+type StartTrainingPauseParams struct {
+	ID     int64     `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// Перевести идущую тренировку в статус paused и открыть паузу
+func (q *Queries) StartTrainingPause(ctx context.Context, arg StartTrainingPauseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, startTrainingPause, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateExercise = `-- name: UpdateExercise :one
+UPDATE exercise
+SET 
+    title = $1,
+    description = $2,
+    video_url = $3,
+    image_url = $4,
+    type = $5
+WHERE id = $6
+RETURNING id, title, description, video_url, image_url, type
+`
+
+type UpdateExerciseParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	VideoUrl    string `json:"video_url"`
+	ImageUrl    string `json:"image_url"`
+	Type        string `json:"type"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) UpdateExercise(ctx context.Context, arg UpdateExerciseParams) (Exercise, error) {
+	row := q.db.QueryRowContext(ctx, updateExercise,
+		arg.Title,
+		arg.Description,
+		arg.VideoUrl,
+		arg.ImageUrl,
+		arg.Type,
+		arg.ID,
+	)
+	var i Exercise
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Description,
+		&i.VideoUrl,
+		&i.ImageUrl,
+		&i.Type,
 	)
 	return i, err
 }
 
 const updateExerciseTime = `-- name: UpdateExerciseTime :one
-UPDATE trained_exercise
+UPDATE trained_exercise te
 SET 
-    doing = COALESCE($1, doing),
-    rest = COALESCE($2, rest),
-    time = COALESCE($3, time)  -- Общее время упражнения (doing + rest)
-WHERE id = $4
+    doing = COALESCE($1, te.doing),
+    rest = COALESCE($2, te.rest),
+    time = COALESCE($3, te.time)  -- Общее время упражнения (doing + rest)
+FROM training t
+WHERE te.id = $4 AND te.training_id = t.id AND t.user_id = $5
 RETURNING 
-    id,
-    training_id,
-    exercise_id,
-    weight,
-    approaches,
-    reps,
-    CAST(COALESCE(EXTRACT(EPOCH FROM time)::bigint, 0) as bigint) as time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM doing)::bigint, 0)as bigint) as doing,
-    CAST(COALESCE(EXTRACT(EPOCH FROM rest)::bigint, 0)as bigint) as rest,
-    notes
+    te.id,
+    te.training_id,
+    te.exercise_id,
+    te.weight,
+    te.approaches,
+    te.reps,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint) as time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0)as bigint) as doing,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0)as bigint) as rest,
+    te.notes,
+    te.position,
+    te.group_id,
+    te.group_type
 `
 
 type UpdateExerciseTimeParams struct {
-	Doing sql.NullInt64 `json:"doing"`
-	Rest  sql.NullInt64 `json:"rest"`
-	Time  sql.NullInt64 `json:"time"`
-	ID    int64         `json:"id"`
+	Doing  sql.NullInt64 `json:"doing"`
+	Rest   sql.NullInt64 `json:"rest"`
+	Time   sql.NullInt64 `json:"time"`
+	ID     int64         `json:"id"`
+	UserID uuid.UUID     `json:"user_id"`
 }
 
 type UpdateExerciseTimeRow struct {
@@ -1205,6 +4647,9 @@ type UpdateExerciseTimeRow struct {
 	Doing      int64          `json:"doing"`
 	Rest       int64          `json:"rest"`
 	Notes      sql.NullString `json:"notes"`
+	Position   int32          `json:"position"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
 }
 
 // Обновление времени выполнения упражнения (doing) и времени отдыха (rest)
@@ -1214,6 +4659,7 @@ func (q *Queries) UpdateExerciseTime(ctx context.Context, arg UpdateExerciseTime
 		arg.Rest,
 		arg.Time,
 		arg.ID,
+		arg.UserID,
 	)
 	var i UpdateExerciseTimeRow
 	err := row.Scan(
@@ -1227,32 +4673,117 @@ func (q *Queries) UpdateExerciseTime(ctx context.Context, arg UpdateExerciseTime
 		&i.Doing,
 		&i.Rest,
 		&i.Notes,
+		&i.Position,
+		&i.GroupID,
+		&i.GroupType,
+	)
+	return i, err
+}
+
+const updateGlobalTraining = `-- name: UpdateGlobalTraining :execrows
+UPDATE global_training
+SET
+    title = $1,
+    description = $2,
+    level = $3
+WHERE id = $4
+`
+
+type UpdateGlobalTrainingParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) UpdateGlobalTraining(ctx context.Context, arg UpdateGlobalTrainingParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateGlobalTraining,
+		arg.Title,
+		arg.Description,
+		arg.Level,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateProgram = `-- name: UpdateProgram :execrows
+UPDATE program
+SET
+    title = $1,
+    description = $2,
+    level = $3,
+    weeks = $4
+WHERE id = $5
+`
+
+type UpdateProgramParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	Weeks       int32  `json:"weeks"`
+	ID          int64  `json:"id"`
+}
+
+func (q *Queries) UpdateProgram(ctx context.Context, arg UpdateProgramParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateProgram,
+		arg.Title,
+		arg.Description,
+		arg.Level,
+		arg.Weeks,
+		arg.ID,
 	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateTag = `-- name: UpdateTag :one
+UPDATE tag SET type = $1 WHERE id = $2
+RETURNING id, type
+`
+
+type UpdateTagParams struct {
+	Type string `json:"type"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error) {
+	row := q.db.QueryRowContext(ctx, updateTag, arg.Type, arg.ID)
+	var i Tag
+	err := row.Scan(&i.ID, &i.Type)
 	return i, err
 }
 
 const updateTrainedExercise = `-- name: UpdateTrainedExercise :one
-UPDATE trained_exercise
+UPDATE trained_exercise te
 SET 
-    weight = COALESCE($1, weight),
-    approaches = COALESCE($2, approaches),
-    reps = COALESCE($3, reps),
-    time = COALESCE($4, time),
-    doing = COALESCE($5, doing),
-    rest = COALESCE($6, rest),
-    notes = COALESCE($7, notes)
-WHERE id = $8
+    weight = COALESCE($1, te.weight),
+    approaches = COALESCE($2, te.approaches),
+    reps = COALESCE($3, te.reps),
+    time = COALESCE($4, te.time),
+    doing = COALESCE($5, te.doing),
+    rest = COALESCE($6, te.rest),
+    notes = COALESCE($7, te.notes)
+FROM training t
+WHERE te.id = $8 AND te.training_id = t.id AND t.user_id = $9
 RETURNING 
-    id,
-    training_id,
-    exercise_id,
-    weight,
-    approaches,
-    reps,
-    CAST(COALESCE(EXTRACT(EPOCH FROM time)::bigint, 0) as bigint) as time,
-    CAST(COALESCE(EXTRACT(EPOCH FROM doing)::bigint, 0)as bigint) as doing,
-    CAST(COALESCE(EXTRACT(EPOCH FROM rest)::bigint, 0)as bigint) as rest,
-    notes
+    te.id,
+    te.training_id,
+    te.exercise_id,
+    te.weight,
+    te.approaches,
+    te.reps,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.time)::bigint, 0) as bigint) as time,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.doing)::bigint, 0)as bigint) as doing,
+    CAST(COALESCE(EXTRACT(EPOCH FROM te.rest)::bigint, 0)as bigint) as rest,
+    te.notes,
+    te.position,
+    te.group_id,
+    te.group_type
 `
 
 type UpdateTrainedExerciseParams struct {
@@ -1264,6 +4795,7 @@ type UpdateTrainedExerciseParams struct {
 	Rest       sql.NullInt64  `json:"rest"`
 	Notes      sql.NullString `json:"notes"`
 	ID         int64          `json:"id"`
+	UserID     uuid.UUID      `json:"user_id"`
 }
 
 type UpdateTrainedExerciseRow struct {
@@ -1277,6 +4809,9 @@ type UpdateTrainedExerciseRow struct {
 	Doing      int64          `json:"doing"`
 	Rest       int64          `json:"rest"`
 	Notes      sql.NullString `json:"notes"`
+	Position   int32          `json:"position"`
+	GroupID    sql.NullInt32  `json:"group_id"`
+	GroupType  sql.NullString `json:"group_type"`
 }
 
 func (q *Queries) UpdateTrainedExercise(ctx context.Context, arg UpdateTrainedExerciseParams) (UpdateTrainedExerciseRow, error) {
@@ -1289,6 +4824,7 @@ func (q *Queries) UpdateTrainedExercise(ctx context.Context, arg UpdateTrainedEx
 		arg.Rest,
 		arg.Notes,
 		arg.ID,
+		arg.UserID,
 	)
 	var i UpdateTrainedExerciseRow
 	err := row.Scan(
@@ -1302,6 +4838,79 @@ func (q *Queries) UpdateTrainedExercise(ctx context.Context, arg UpdateTrainedEx
 		&i.Doing,
 		&i.Rest,
 		&i.Notes,
+		&i.Position,
+		&i.GroupID,
+		&i.GroupType,
+	)
+	return i, err
+}
+
+const updateTrainedSet = `-- name: UpdateTrainedSet :one
+UPDATE trained_set ts
+SET
+    weight = COALESCE($1, ts.weight),
+    reps = COALESCE($2, ts.reps),
+    duration = COALESCE($3, ts.duration),
+    rpe = COALESCE($4, ts.rpe),
+    is_warmup = COALESCE($5, ts.is_warmup)
+FROM trained_exercise te
+JOIN training t ON t.id = te.training_id
+WHERE ts.id = $6 AND ts.trained_exercise_id = $7
+    AND te.id = ts.trained_exercise_id AND t.user_id = $8
+RETURNING
+    ts.id,
+    ts.trained_exercise_id,
+    ts.ordinal,
+    ts.weight,
+    ts.reps,
+    EXTRACT(EPOCH FROM ts.duration)::bigint as duration,
+    ts.rpe,
+    ts.is_warmup
+`
+
+type UpdateTrainedSetParams struct {
+	Weight            sql.NullString `json:"weight"`
+	Reps              sql.NullInt32  `json:"reps"`
+	Duration          sql.NullInt64  `json:"duration"`
+	Rpe               sql.NullString `json:"rpe"`
+	IsWarmup          sql.NullBool   `json:"is_warmup"`
+	ID                int64          `json:"id"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	UserID            uuid.UUID      `json:"user_id"`
+}
+
+type UpdateTrainedSetRow struct {
+	ID                int64          `json:"id"`
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Ordinal           int32          `json:"ordinal"`
+	Weight            sql.NullString `json:"weight"`
+	Reps              sql.NullInt32  `json:"reps"`
+	Duration          sql.NullInt64  `json:"duration"`
+	Rpe               sql.NullString `json:"rpe"`
+	IsWarmup          bool           `json:"is_warmup"`
+}
+
+func (q *Queries) UpdateTrainedSet(ctx context.Context, arg UpdateTrainedSetParams) (UpdateTrainedSetRow, error) {
+	row := q.db.QueryRowContext(ctx, updateTrainedSet,
+		arg.Weight,
+		arg.Reps,
+		arg.Duration,
+		arg.Rpe,
+		arg.IsWarmup,
+		arg.ID,
+		arg.TrainedExerciseID,
+		arg.UserID,
+	)
+	var i UpdateTrainedSetRow
+	err := row.Scan(
+		&i.ID,
+		&i.TrainedExerciseID,
+		&i.Ordinal,
+		&i.Weight,
+		&i.Reps,
+		&i.Duration,
+		&i.Rpe,
+		&i.IsWarmup,
 	)
 	return i, err
 }
@@ -1309,17 +4918,13 @@ func (q *Queries) UpdateTrainedExercise(ctx context.Context, arg UpdateTrainedEx
 const updateTraining = `-- name: UpdateTraining :one
 UPDATE training
 SET
-    is_done = COALESCE($1, is_done),
-    planned_date = COALESCE($2, planned_date),
-    actual_date = COALESCE($3, actual_date),
-    started_at = COALESCE($4, started_at),
-    finished_at = COALESCE($5, finished_at),
-    total_duration = COALESCE($6, total_duration),
-    total_rest_time = COALESCE($7, total_rest_time),
-    total_exercise_time = COALESCE($8, total_exercise_time),
-    rating = COALESCE($9, rating),
-    title = COALESCE($10, title)
-WHERE id = $11
+    planned_date = COALESCE($1, planned_date),
+    total_duration = COALESCE($2, total_duration),
+    total_rest_time = COALESCE($3, total_rest_time),
+    total_exercise_time = COALESCE($4, total_exercise_time),
+    rating = COALESCE($5, rating),
+    title = COALESCE($6, title)
+WHERE id = $7 AND user_id = $8
 RETURNING 
     id,
     title,
@@ -1332,21 +4937,19 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating
+    rating,
+    status
 `
 
 type UpdateTrainingParams struct {
-	IsDone            bool          `json:"is_done"`
 	PlannedDate       time.Time     `json:"planned_date"`
-	ActualDate        sql.NullTime  `json:"actual_date"`
-	StartedAt         sql.NullTime  `json:"started_at"`
-	FinishedAt        sql.NullTime  `json:"finished_at"`
 	TotalDuration     sql.NullInt64 `json:"total_duration"`
 	TotalRestTime     sql.NullInt64 `json:"total_rest_time"`
 	TotalExerciseTime sql.NullInt64 `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
 	Title             string        `json:"title"`
 	ID                int64         `json:"id"`
+	UserID            uuid.UUID     `json:"user_id"`
 }
 
 type UpdateTrainingRow struct {
@@ -1362,21 +4965,21 @@ type UpdateTrainingRow struct {
 	TotalRestTime     int64         `json:"total_rest_time"`
 	TotalExerciseTime int64         `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
 }
 
+// Обновление полей тренировки. Статус, is_done, время начала и окончания и фактическая дата
+// меняются только переходами жизненного цикла
 func (q *Queries) UpdateTraining(ctx context.Context, arg UpdateTrainingParams) (UpdateTrainingRow, error) {
 	row := q.db.QueryRowContext(ctx, updateTraining,
-		arg.IsDone,
 		arg.PlannedDate,
-		arg.ActualDate,
-		arg.StartedAt,
-		arg.FinishedAt,
 		arg.TotalDuration,
 		arg.TotalRestTime,
 		arg.TotalExerciseTime,
 		arg.Rating,
 		arg.Title,
 		arg.ID,
+		arg.UserID,
 	)
 	var i UpdateTrainingRow
 	err := row.Scan(
@@ -1392,10 +4995,84 @@ func (q *Queries) UpdateTraining(ctx context.Context, arg UpdateTrainingParams)
 		&i.TotalRestTime,
 		&i.TotalExerciseTime,
 		&i.Rating,
+		&i.Status,
+	)
+	return i, err
+}
+
+const updateTrainingSchedule = `-- name: UpdateTrainingSchedule :one
+UPDATE training_schedule
+SET
+    title = $1,
+    rrule = $2,
+    global_training_id = $3,
+    template_id = $4,
+    materialized_until = $5
+WHERE id = $6 AND user_id = $7
+RETURNING id, user_id, title, rrule, start_date, global_training_id, template_id, materialized_until, created_at
+`
+
+type UpdateTrainingScheduleParams struct {
+	Title             string        `json:"title"`
+	Rrule             string        `json:"rrule"`
+	GlobalTrainingID  sql.NullInt64 `json:"global_training_id"`
+	TemplateID        sql.NullInt64 `json:"template_id"`
+	MaterializedUntil sql.NullTime  `json:"materialized_until"`
+	ID                int64         `json:"id"`
+	UserID            uuid.UUID     `json:"user_id"`
+}
+
+func (q *Queries) UpdateTrainingSchedule(ctx context.Context, arg UpdateTrainingScheduleParams) (TrainingSchedule, error) {
+	row := q.db.QueryRowContext(ctx, updateTrainingSchedule,
+		arg.Title,
+		arg.Rrule,
+		arg.GlobalTrainingID,
+		arg.TemplateID,
+		arg.MaterializedUntil,
+		arg.ID,
+		arg.UserID,
+	)
+	var i TrainingSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Rrule,
+		&i.StartDate,
+		&i.GlobalTrainingID,
+		&i.TemplateID,
+		&i.MaterializedUntil,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const updateTrainingTemplate = `-- name: UpdateTrainingTemplate :execrows
+UPDATE training_template
+SET title = $1, description = $2
+WHERE id = $3 AND user_id = $4
+`
+
+type UpdateTrainingTemplateParams struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	ID          int64     `json:"id"`
+	UserID      uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) UpdateTrainingTemplate(ctx context.Context, arg UpdateTrainingTemplateParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateTrainingTemplate,
+		arg.Title,
+		arg.Description,
+		arg.ID,
+		arg.UserID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTrainingTimers = `-- name: UpdateTrainingTimers :one
 UPDATE training
 SET 
@@ -1404,7 +5081,7 @@ SET
     total_duration = COALESCE($3, total_duration),
     total_rest_time = COALESCE($4, total_rest_time),
     total_exercise_time = COALESCE($5, total_exercise_time)
-WHERE id = $6
+WHERE id = $6 AND user_id = $7
 RETURNING 
     id,
     title,
@@ -1417,7 +5094,8 @@ RETURNING
     CAST(COALESCE(EXTRACT(EPOCH FROM total_duration)::bigint, 0) as bigint) as total_duration,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_rest_time)::bigint, 0)as bigint) as total_rest_time,
     CAST(COALESCE(EXTRACT(EPOCH FROM total_exercise_time)::bigint, 0)as bigint) as total_exercise_time,
-    rating
+    rating,
+    status
 `
 
 type UpdateTrainingTimersParams struct {
@@ -1427,6 +5105,7 @@ type UpdateTrainingTimersParams struct {
 	TotalRestTime     sql.NullInt64 `json:"total_rest_time"`
 	TotalExerciseTime sql.NullInt64 `json:"total_exercise_time"`
 	ID                int64         `json:"id"`
+	UserID            uuid.UUID     `json:"user_id"`
 }
 
 type UpdateTrainingTimersRow struct {
@@ -1442,6 +5121,7 @@ type UpdateTrainingTimersRow struct {
 	TotalRestTime     int64         `json:"total_rest_time"`
 	TotalExerciseTime int64         `json:"total_exercise_time"`
 	Rating            sql.NullInt32 `json:"rating"`
+	Status            string        `json:"status"`
 }
 
 // Обновление времени тренировки (старт, финиш, общая продолжительность)
@@ -1453,6 +5133,7 @@ func (q *Queries) UpdateTrainingTimers(ctx context.Context, arg UpdateTrainingTi
 		arg.TotalRestTime,
 		arg.TotalExerciseTime,
 		arg.ID,
+		arg.UserID,
 	)
 	var i UpdateTrainingTimersRow
 	err := row.Scan(
@@ -1468,6 +5149,165 @@ func (q *Queries) UpdateTrainingTimers(ctx context.Context, arg UpdateTrainingTi
 		&i.TotalRestTime,
 		&i.TotalExerciseTime,
 		&i.Rating,
+		&i.Status,
+	)
+	return i, err
+}
+
+const upsertCalendarToken = `-- name: UpsertCalendarToken :exec
+INSERT INTO calendar_token (user_id, token_hash, created_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+    token_hash = EXCLUDED.token_hash,
+    created_at = NOW()
+`
+
+type UpsertCalendarTokenParams struct {
+	UserID    uuid.UUID `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+}
+
+// Новый ключ заменяет прежний: старая ссылка на календарь перестает работать
+func (q *Queries) UpsertCalendarToken(ctx context.Context, arg UpsertCalendarTokenParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCalendarToken, arg.UserID, arg.TokenHash)
+	return err
+}
+
+const upsertProgramSession = `-- name: UpsertProgramSession :exec
+INSERT INTO program_session (
+    program_id,
+    week,
+    day,
+    global_training_id,
+    weight_increment,
+    reps_increment
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (program_id, week, day) DO UPDATE SET
+    global_training_id = EXCLUDED.global_training_id,
+    weight_increment = EXCLUDED.weight_increment,
+    reps_increment = EXCLUDED.reps_increment
+`
+
+type UpsertProgramSessionParams struct {
+	ProgramID        int64          `json:"program_id"`
+	Week             int32          `json:"week"`
+	Day              int32          `json:"day"`
+	GlobalTrainingID int64          `json:"global_training_id"`
+	WeightIncrement  sql.NullString `json:"weight_increment"`
+	RepsIncrement    sql.NullInt32  `json:"reps_increment"`
+}
+
+// Тренировочный день программы. Существующий день с той же неделей и номером обновляется,
+// сохраняя id, на который ссылаются тренировки записанных пользователей
+func (q *Queries) UpsertProgramSession(ctx context.Context, arg UpsertProgramSessionParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProgramSession,
+		arg.ProgramID,
+		arg.Week,
+		arg.Day,
+		arg.GlobalTrainingID,
+		arg.WeightIncrement,
+		arg.RepsIncrement,
+	)
+	return err
+}
+
+const upsertTrainedExerciseCardio = `-- name: UpsertTrainedExerciseCardio :exec
+INSERT INTO trained_exercise_cardio (
+    trained_exercise_id,
+    distance,
+    moving_time,
+    elevation_gain,
+    avg_heart_rate,
+    max_heart_rate,
+    avg_pace,
+    distance_unit,
+    avg_speed,
+    calories,
+    incline,
+    resistance_level
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+ON CONFLICT (trained_exercise_id) DO UPDATE SET
+    distance = EXCLUDED.distance,
+    moving_time = EXCLUDED.moving_time,
+    elevation_gain = EXCLUDED.elevation_gain,
+    avg_heart_rate = EXCLUDED.avg_heart_rate,
+    max_heart_rate = EXCLUDED.max_heart_rate,
+    avg_pace = EXCLUDED.avg_pace,
+    distance_unit = EXCLUDED.distance_unit,
+    avg_speed = EXCLUDED.avg_speed,
+    calories = EXCLUDED.calories,
+    incline = EXCLUDED.incline,
+    resistance_level = EXCLUDED.resistance_level
+`
+
+type UpsertTrainedExerciseCardioParams struct {
+	TrainedExerciseID int64          `json:"trained_exercise_id"`
+	Distance          sql.NullString `json:"distance"`
+	MovingTime        sql.NullInt64  `json:"moving_time"`
+	ElevationGain     sql.NullString `json:"elevation_gain"`
+	AvgHeartRate      sql.NullInt32  `json:"avg_heart_rate"`
+	MaxHeartRate      sql.NullInt32  `json:"max_heart_rate"`
+	AvgPace           sql.NullInt64  `json:"avg_pace"`
+	DistanceUnit      sql.NullString `json:"distance_unit"`
+	AvgSpeed          sql.NullString `json:"avg_speed"`
+	Calories          sql.NullInt32  `json:"calories"`
+	Incline           sql.NullString `json:"incline"`
+	ResistanceLevel   sql.NullInt32  `json:"resistance_level"`
+}
+
+func (q *Queries) UpsertTrainedExerciseCardio(ctx context.Context, arg UpsertTrainedExerciseCardioParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTrainedExerciseCardio,
+		arg.TrainedExerciseID,
+		arg.Distance,
+		arg.MovingTime,
+		arg.ElevationGain,
+		arg.AvgHeartRate,
+		arg.MaxHeartRate,
+		arg.AvgPace,
+		arg.DistanceUnit,
+		arg.AvgSpeed,
+		arg.Calories,
+		arg.Incline,
+		arg.ResistanceLevel,
+	)
+	return err
+}
+
+const upsertUserSettings = `-- name: UpsertUserSettings :one
+INSERT INTO user_settings (user_id, week_start, rest_days, timezone, updated_at)
+VALUES ($1, $2, $3, $4, NOW())
+ON CONFLICT (user_id) DO UPDATE SET
+    week_start = EXCLUDED.week_start,
+    rest_days = EXCLUDED.rest_days,
+    timezone = EXCLUDED.timezone,
+    updated_at = NOW()
+RETURNING user_id, week_start, rest_days, timezone, updated_at
+`
+
+type UpsertUserSettingsParams struct {
+	UserID    uuid.UUID      `json:"user_id"`
+	WeekStart int32          `json:"week_start"`
+	RestDays  int32          `json:"rest_days"`
+	Timezone  sql.NullString `json:"timezone"`
+}
+
+func (q *Queries) UpsertUserSettings(ctx context.Context, arg UpsertUserSettingsParams) (UserSetting, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserSettings,
+		arg.UserID,
+		arg.WeekStart,
+		arg.RestDays,
+		arg.Timezone,
+	)
+	var i UserSetting
+	err := row.Scan(
+		&i.UserID,
+		&i.WeekStart,
+		&i.RestDays,
+		&i.Timezone,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetExerciseCatalog(ctx context.Context) ([]domain.CatalogExercise, error) {
+	rows, err := r.q.GetExerciseCatalogNames(ctx)
+	if err != nil {
+		logging.Error(err, "GetExerciseCatalog", nil, "failed to get exercise catalog")
+		return nil, err
+	}
+
+	catalog := make([]domain.CatalogExercise, len(rows))
+	for i, row := range rows {
+		catalog[i] = domain.CatalogExercise{
+			ID:      row.ID,
+			Title:   row.Title,
+			Type:    domain.ExerciseType(row.Type),
+			Aliases: row.Aliases,
+		}
+	}
+	return catalog, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainingTitles(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.TrainingTitle, error) {
+	rows, err := r.q.GetTrainingTitlesInRange(ctx, gen.GetTrainingTitlesInRangeParams{
+		UserID:   userID,
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+			"from":    from.Format("2006-01-02"),
+			"to":      to.Format("2006-01-02"),
+		})
+		logging.Error(err, "GetTrainingTitles", jsonData, "failed to get training titles")
+		return nil, err
+	}
+
+	titles := make([]domain.TrainingTitle, len(rows))
+	for i, row := range rows {
+		titles[i] = domain.TrainingTitle{Date: row.PlannedDate, Title: row.Title}
+	}
+	return titles, nil
+}
+
+// ImportTrainings создает завершенные тренировки с упражнениями, подходами и показателями кардио одной транзакцией:
+// при ошибке не сохраняется ни одна тренировка. Вес, подходы и повторения упражнения с подходами
+// не заполняются - они считаются по подходам
+func (r *TrainingRepositoryImpl) ImportTrainings(ctx context.Context, userID uuid.UUID, workouts []domain.ImportedWorkout) ([]*domain.Training, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "ImportTrainings", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	trainings := make([]*domain.Training, 0, len(workouts))
+	for _, workout := range workouts {
+		training, err := importTraining(ctx, q, userID, workout)
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"user_id": userID.String(),
+				"title":   workout.Title,
+				"date":    workout.Date().Format("2006-01-02"),
+			})
+			logging.Error(err, "ImportTrainings", jsonData, "failed to import training")
+			return nil, err
+		}
+		trainings = append(trainings, training)
+	}
+
+	if err := tx.Commit(); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "ImportTrainings", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":         userID.String(),
+		"trainings_count": len(trainings),
+	})
+	logging.Info("ImportTrainings", jsonData, "trainings imported")
+
+	return trainings, nil
+}
+
+func importTraining(ctx context.Context, q *gen.Queries, userID uuid.UUID, workout domain.ImportedWorkout) (*domain.Training, error) {
+	date := workout.Date()
+	// Время начала и конца известно, только если выгрузка содержит время тренировки
+	var startedAt, finishedAt *time.Time
+	if !workout.Untimed {
+		start := workout.StartedAt.UTC()
+		startedAt = &start
+		if workout.Duration != nil {
+			finish := start.Add(*workout.Duration)
+			finishedAt = &finish
+		}
+	}
+
+	created, err := q.CreateTraining(ctx, gen.CreateTrainingParams{
+		Title:             workout.Title,
+		UserID:            userID,
+		IsDone:            true,
+		PlannedDate:       date,
+		ActualDate:        sql.NullTime{Time: date, Valid: true},
+		StartedAt:         null.TimeFromPtr(startedAt).NullTime,
+		FinishedAt:        null.TimeFromPtr(finishedAt).NullTime,
+		TotalDuration:     durationToNullInt64(workout.Duration),
+		TotalRestTime:     durationToNullInt64(workout.RestTime),
+		TotalExerciseTime: durationToNullInt64(workout.ExerciseTime),
+		Rating:            null.Int32FromPtr(workout.Rating).NullInt32,
+		Status:            string(domain.TrainingStatusCompleted),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	training := &domain.Training{
+		ID:                created.ID,
+		UserID:            userID,
+		Title:             workout.Title,
+		IsDone:            true,
+		PlannedDate:       date,
+		ActualDate:        &date,
+		StartedAt:         startedAt,
+		FinishedAt:        finishedAt,
+		TotalDuration:     workout.Duration,
+		TotalRestTime:     workout.RestTime,
+		TotalExerciseTime: workout.ExerciseTime,
+		Rating:            workout.Rating,
+		Status:            domain.TrainingStatusCompleted,
+	}
+
+	for _, imported := range workout.Exercises {
+		groupID, groupType := exerciseGroupToSQL(imported.Group)
+		added, err := q.AddExerciseToTraining(ctx, gen.AddExerciseToTrainingParams{
+			TrainingID: created.ID,
+			ExerciseID: imported.ExerciseID,
+			Weight:     decimalToNullString(imported.Weight),
+			Approaches: null.Int32FromPtr(imported.Approaches).NullInt32,
+			Reps:       null.Int32FromPtr(imported.Reps).NullInt32,
+			Time:       durationToNullInt64(imported.Time),
+			Doing:      durationToNullInt64(imported.Doing),
+			Rest:       durationToNullInt64(imported.Rest),
+			Notes:      null.StringFromPtr(imported.Notes).NullString,
+			UserID:     userID,
+			GroupID:    groupID,
+			GroupType:  groupType,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		exercise := domain.TrainedExercise{
+			ID:         added.ID,
+			TrainingID: created.ID,
+			ExerciseID: imported.ExerciseID,
+			Weight:     imported.Weight,
+			Approaches: imported.Approaches,
+			Reps:       imported.Reps,
+			Time:       imported.Time,
+			Doing:      imported.Doing,
+			Rest:       imported.Rest,
+			Notes:      imported.Notes,
+			Position:   added.Position,
+			Group:      imported.Group,
+			Sets:       make([]domain.TrainedSet, 0, len(imported.Sets)),
+		}
+		if imported.Cardio != nil {
+			if err := q.UpsertTrainedExerciseCardio(ctx, cardioToSQL(added.ID, imported.Cardio)); err != nil {
+				return nil, err
+			}
+			exercise.Cardio = imported.Cardio
+		}
+		for _, set := range imported.Sets {
+			row, err := q.AddTrainedSet(ctx, gen.AddTrainedSetParams{
+				ID:       added.ID,
+				Weight:   decimalToNullString(set.Weight),
+				Reps:     null.Int32FromPtr(set.Reps).NullInt32,
+				Duration: durationToNullInt64(set.Duration),
+				Rpe:      decimalToNullString(set.RPE),
+				IsWarmup: set.IsWarmup,
+				UserID:   userID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			exercise.Sets = append(exercise.Sets, *toDomainTrainedSet(gen.TrainedSet(row)))
+		}
+		training.Exercises = append(training.Exercises, exercise)
+	}
+
+	return training, nil
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
 	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/guregu/null/v6"
 	"github.com/shopspring/decimal"
 )
 
@@ -49,8 +51,8 @@ func toDomainTags(genTags interface{}) []domain.Tag {
 	return tags
 }
 
-func toDomainExercise(genExercises interface{}) []domain.Exercise {
-	var tags []domain.Exercise = nil
+func toDomainGlobalTrainingExercises(genExercises interface{}) []domain.GlobalTrainingExercise {
+	var tags []domain.GlobalTrainingExercise = nil
 	var jsonBytes []byte
 
 	switch v := genExercises.(type) {
@@ -78,17 +80,40 @@ func toDomainExercise(genExercises interface{}) []domain.Exercise {
 			VideoUrl    string      `json:"video_url"`
 			ImageUrl    string      `json:"image_url"`
 			Tags        interface{} `json:"tags"`
+			SlotID      int64       `json:"slot_id"`
+			Position    int32       `json:"position"`
+			Approaches  *int32      `json:"approaches"`
+			Reps        *int32      `json:"reps"`
+			Weight      interface{} `json:"weight"`
+			Duration    *int64      `json:"duration"`
+			Rest        *int64      `json:"rest"`
+			GroupID     *int32      `json:"group_id"`
+			GroupType   *string     `json:"group_type"`
 		}
 		if err := json.Unmarshal(jsonBytes, &rawExercises); err == nil {
-			tags = make([]domain.Exercise, len(rawExercises))
+			tags = make([]domain.GlobalTrainingExercise, len(rawExercises))
 			for i, ex := range rawExercises {
-				tags[i] = domain.Exercise{
-					ID:          ex.ID,
-					Title:       ex.Title,
-					Description: ex.Description,
-					VideoUrl:    ex.VideoUrl,
-					ImageUrl:    ex.ImageUrl,
-					Tags:        toDomainTags(ex.Tags),
+				tags[i] = domain.GlobalTrainingExercise{
+					Exercise: domain.Exercise{
+						ID:          ex.ID,
+						Title:       ex.Title,
+						Description: ex.Description,
+						VideoUrl:    ex.VideoUrl,
+						ImageUrl:    ex.ImageUrl,
+						Tags:        toDomainTags(ex.Tags),
+					},
+					SlotID:     ex.SlotID,
+					Position:   ex.Position,
+					Approaches: ex.Approaches,
+					Reps:       ex.Reps,
+					Weight:     weightFromJSON(ex.Weight),
+					Group:      exerciseGroupFromJSON(ex.GroupID, ex.GroupType),
+				}
+				if ex.Duration != nil {
+					tags[i].Duration = toDuration(*ex.Duration)
+				}
+				if ex.Rest != nil {
+					tags[i].Rest = toDuration(*ex.Rest)
 				}
 			}
 		}
@@ -96,6 +121,179 @@ func toDomainExercise(genExercises interface{}) []domain.Exercise {
 	return tags
 }
 
+// cardioJSON - показатели кардио-упражнения в агрегированном JSON; длительности - в секундах
+type cardioJSON struct {
+	Distance        interface{} `json:"distance"`
+	MovingTime      *int64      `json:"moving_time"`
+	ElevationGain   interface{} `json:"elevation_gain"`
+	AvgHeartRate    *int32      `json:"avg_heart_rate"`
+	MaxHeartRate    *int32      `json:"max_heart_rate"`
+	AvgPace         *int64      `json:"avg_pace"`
+	DistanceUnit    *string     `json:"distance_unit"`
+	AvgSpeed        interface{} `json:"avg_speed"`
+	Calories        *int32      `json:"calories"`
+	Incline         interface{} `json:"incline"`
+	ResistanceLevel *int32      `json:"resistance_level"`
+}
+
+func (c *cardioJSON) toDomain() *domain.CardioMetrics {
+	if c == nil {
+		return nil
+	}
+	cardio := &domain.CardioMetrics{
+		Distance:      weightFromJSON(c.Distance),
+		ElevationGain: weightFromJSON(c.ElevationGain),
+		AvgHeartRate:  c.AvgHeartRate,
+		MaxHeartRate:  c.MaxHeartRate,
+		AvgSpeed:      weightFromJSON(c.AvgSpeed),
+		Calories:      c.Calories,
+		Incline:       weightFromJSON(c.Incline),
+		Resistance:    c.ResistanceLevel,
+	}
+	if c.MovingTime != nil {
+		cardio.MovingTime = toDuration(*c.MovingTime)
+	}
+	if c.AvgPace != nil {
+		cardio.AvgPace = toDuration(*c.AvgPace)
+	}
+	if c.DistanceUnit != nil {
+		cardio.DistanceUnit = domain.DistanceUnit(*c.DistanceUnit)
+	}
+	return cardio
+}
+
+// toDomainCardio собирает сохраненные кардио-показатели; nil, если их нет
+func toDomainCardio(row gen.GetTrainedExerciseCardioRow) *domain.CardioMetrics {
+	if !row.HasCardio {
+		return nil
+	}
+	cardio := &domain.CardioMetrics{
+		Distance:      nullDecimalFromSQL(row.Distance),
+		DistanceUnit:  domain.DistanceUnit(row.DistanceUnit.String),
+		ElevationGain: nullDecimalFromSQL(row.ElevationGain),
+		AvgHeartRate:  nullIntFromSQL32(row.AvgHeartRate),
+		MaxHeartRate:  nullIntFromSQL32(row.MaxHeartRate),
+		AvgSpeed:      nullDecimalFromSQL(row.AvgSpeed),
+		Calories:      nullIntFromSQL32(row.Calories),
+		Incline:       nullDecimalFromSQL(row.Incline),
+		Resistance:    nullIntFromSQL32(row.ResistanceLevel),
+	}
+	if row.MovingTime.Valid {
+		cardio.MovingTime = toDuration(row.MovingTime.Int64)
+	}
+	if row.AvgPace.Valid {
+		cardio.AvgPace = toDuration(row.AvgPace.Int64)
+	}
+	return cardio
+}
+
+// cardioToSQL раскладывает показатели кардио-упражнения по колонкам trained_exercise_cardio
+func cardioToSQL(trainedExerciseID int64, cardio *domain.CardioMetrics) gen.UpsertTrainedExerciseCardioParams {
+	return gen.UpsertTrainedExerciseCardioParams{
+		TrainedExerciseID: trainedExerciseID,
+		Distance:          decimalToNullString(cardio.Distance),
+		MovingTime:        durationToNullInt64(cardio.MovingTime),
+		ElevationGain:     decimalToNullString(cardio.ElevationGain),
+		AvgHeartRate:      null.Int32FromPtr(cardio.AvgHeartRate).NullInt32,
+		MaxHeartRate:      null.Int32FromPtr(cardio.MaxHeartRate).NullInt32,
+		AvgPace:           durationToNullInt64(cardio.AvgPace),
+		DistanceUnit:      null.NewString(string(cardio.DistanceUnit), cardio.DistanceUnit != "").NullString,
+		AvgSpeed:          decimalToNullString(cardio.AvgSpeed),
+		Calories:          null.Int32FromPtr(cardio.Calories).NullInt32,
+		Incline:           decimalToNullString(cardio.Incline),
+		ResistanceLevel:   null.Int32FromPtr(cardio.Resistance).NullInt32,
+	}
+}
+
+// exerciseGroupFromJSON собирает группу упражнения из полей group_id и group_type агрегированного JSON
+func exerciseGroupFromJSON(id *int32, groupType *string) *domain.ExerciseGroup {
+	if id == nil || groupType == nil {
+		return nil
+	}
+	return &domain.ExerciseGroup{ID: *id, Type: domain.ExerciseGroupType(*groupType)}
+}
+
+// toDomainExerciseGroup собирает группу упражнения из колонок group_id и group_type
+func toDomainExerciseGroup(id sql.NullInt32, groupType sql.NullString) *domain.ExerciseGroup {
+	if !id.Valid || !groupType.Valid {
+		return nil
+	}
+	return &domain.ExerciseGroup{ID: id.Int32, Type: domain.ExerciseGroupType(groupType.String)}
+}
+
+// exerciseGroupToSQL раскладывает группу упражнения по колонкам group_id и group_type
+func exerciseGroupToSQL(group *domain.ExerciseGroup) (sql.NullInt32, sql.NullString) {
+	if group == nil {
+		return sql.NullInt32{}, sql.NullString{}
+	}
+	return sql.NullInt32{Int32: group.ID, Valid: true}, sql.NullString{String: string(group.Type), Valid: true}
+}
+
+func toDomainTrainedSets(genSets interface{}) []domain.TrainedSet {
+	var sets []domain.TrainedSet = nil
+
+	jsonBytes, err := json.Marshal(genSets)
+	if err != nil || string(jsonBytes) == "[]" || string(jsonBytes) == "null" {
+		return sets
+	}
+
+	var rawSets []struct {
+		ID                int64       `json:"id"`
+		TrainedExerciseID int64       `json:"trained_exercise_id"`
+		Ordinal           int32       `json:"ordinal"`
+		Weight            interface{} `json:"weight"`
+		Reps              *int32      `json:"reps"`
+		Duration          *int64      `json:"duration"`
+		RPE               interface{} `json:"rpe"`
+		IsWarmup          bool        `json:"is_warmup"`
+	}
+	if err := json.Unmarshal(jsonBytes, &rawSets); err == nil {
+		sets = make([]domain.TrainedSet, len(rawSets))
+		for i, set := range rawSets {
+			sets[i] = domain.TrainedSet{
+				ID:                set.ID,
+				TrainedExerciseID: set.TrainedExerciseID,
+				Ordinal:           set.Ordinal,
+				Weight:            weightFromJSON(set.Weight),
+				Reps:              set.Reps,
+				RPE:               weightFromJSON(set.RPE),
+				IsWarmup:          set.IsWarmup,
+			}
+			if set.Duration != nil {
+				sets[i].Duration = toDuration(*set.Duration)
+			}
+		}
+	}
+	return sets
+}
+
+// weightFromJSON разбирает десятичное значение (вес, RPE) из json_agg,
+// где оно может прийти строкой, числом или null
+func weightFromJSON(w interface{}) *decimal.Decimal {
+	switch v := w.(type) {
+	case string:
+		if v != "" {
+			weight, err := decimal.NewFromString(v)
+			if err == nil {
+				return &weight
+			}
+		}
+	case float64:
+		weight := decimal.NewFromFloat(v)
+		return &weight
+	case int64:
+		weight := decimal.NewFromInt(v)
+		return &weight
+	case int:
+		weight := decimal.NewFromInt(int64(v))
+		return &weight
+	case float32:
+		weight := decimal.NewFromFloat32(v)
+		return &weight
+	}
+	return nil
+}
+
 func toDomainTrainedExercise(genExercises interface{}) []domain.TrainedExercise {
 	var tags []domain.TrainedExercise = nil
 	var jsonBytes []byte
@@ -141,48 +339,30 @@ func toDomainTrainedExercise(genExercises interface{}) []domain.TrainedExercise
 			Doing      int64       `json:"doing"`
 			Rest       int64       `json:"rest"`
 			Notes      string      `json:"notes"`
+			Sets       interface{} `json:"sets"`
+			Position   int32       `json:"position"`
+			GroupID    *int32      `json:"group_id"`
+			GroupType  *string     `json:"group_type"`
+			Cardio     *cardioJSON `json:"cardio"`
 		}
 		if err := json.Unmarshal(jsonBytes, &rawExercises); err == nil {
 			tags = make([]domain.TrainedExercise, len(rawExercises))
 			for i, ex := range rawExercises {
-				var weightPtr *decimal.Decimal
-
-				// Handle weight field which can be string, number, or null
-				if ex.Weight != nil {
-					switch w := ex.Weight.(type) {
-					case string:
-						if w != "" {
-							weight, err := decimal.NewFromString(w)
-							if err == nil {
-								weightPtr = &weight
-							}
-						}
-					case float64:
-						weight := decimal.NewFromFloat(w)
-						weightPtr = &weight
-					case int64:
-						weight := decimal.NewFromInt(w)
-						weightPtr = &weight
-					case int:
-						weight := decimal.NewFromInt(int64(w))
-						weightPtr = &weight
-					case float32:
-						weight := decimal.NewFromFloat32(w)
-						weightPtr = &weight
-					}
-				}
-
 				tags[i] = domain.TrainedExercise{
 					ID:         ex.ID,
 					TrainingID: ex.TrainingID,
 					ExerciseID: ex.ExerciseID,
-					Weight:     weightPtr,
+					Weight:     weightFromJSON(ex.Weight),
 					Approaches: &ex.Approaches,
 					Reps:       &ex.Reps,
 					Time:       toDuration(ex.Time),
 					Doing:      toDuration(ex.Doing),
 					Rest:       toDuration(ex.Rest),
 					Notes:      &ex.Notes,
+					Sets:       toDomainTrainedSets(ex.Sets),
+					Position:   ex.Position,
+					Group:      exerciseGroupFromJSON(ex.GroupID, ex.GroupType),
+					Cardio:     ex.Cardio.toDomain(),
 				}
 			}
 		} else {
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+// SavePersonalRecords пересчитывает рекорды выполненного упражнения: удаляет ранее
+// установленные им рекорды и сохраняет кандидатов, которые лучше текущих рекордов.
+// Возвращает только новые рекорды
+func (r *TrainingRepositoryImpl) SavePersonalRecords(ctx context.Context, trainedExerciseID int64, userID uuid.UUID, candidates []domain.PersonalRecord) ([]domain.PersonalRecord, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "SavePersonalRecords", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	err = q.DeletePersonalRecordsByTrainedExercise(ctx, gen.DeletePersonalRecordsByTrainedExerciseParams{
+		TrainedExerciseID: trainedExerciseID,
+		UserID:            userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+		})
+		logging.Error(err, "SavePersonalRecords", jsonData, "failed to delete previous personal records")
+		return nil, err
+	}
+
+	var records []domain.PersonalRecord
+	for _, candidate := range candidates {
+		created, err := q.CreatePersonalRecord(ctx, gen.CreatePersonalRecordParams{
+			ID:     trainedExerciseID,
+			UserID: userID,
+			Type:   string(candidate.Type),
+			Value:  candidate.Value.String(),
+			Weight: decimalToNullString(candidate.Weight),
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// Текущий рекорд не побит
+			continue
+		}
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"trained_exercise_id": trainedExerciseID,
+				"type":                candidate.Type,
+			})
+			logging.Error(err, "SavePersonalRecords", jsonData, "failed to create personal record")
+			return nil, err
+		}
+
+		record := toDomainPersonalRecord(gen.GetPersonalRecordsRow{
+			ID:                created.ID,
+			UserID:            created.UserID,
+			ExerciseID:        created.ExerciseID,
+			TrainedExerciseID: created.TrainedExerciseID,
+			TrainingID:        candidate.TrainingID,
+			Type:              created.Type,
+			Value:             created.Value,
+			Weight:            created.Weight,
+			AchievedAt:        created.AchievedAt,
+		})
+		record.ExerciseTitle = candidate.ExerciseTitle
+		records = append(records, *record)
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "SavePersonalRecords", nil, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": trainedExerciseID,
+		"candidates_count":    len(candidates),
+		"records_count":       len(records),
+	})
+	logging.Debug("SavePersonalRecords", jsonData, "successfully saved personal records")
+
+	return records, nil
+}
+
+func (r *TrainingRepositoryImpl) GetPersonalRecords(ctx context.Context, userID uuid.UUID) ([]*domain.PersonalRecord, error) {
+	rows, err := r.q.GetPersonalRecords(ctx, userID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "GetPersonalRecords", jsonData, "failed to get personal records")
+		return nil, err
+	}
+
+	records := make([]*domain.PersonalRecord, len(rows))
+	for i, row := range rows {
+		records[i] = toDomainPersonalRecord(row)
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":       userID.String(),
+		"records_count": len(records),
+	})
+	logging.Debug("GetPersonalRecords", jsonData, "successfully retrieved personal records")
+
+	return records, nil
+}
+
+func (r *TrainingRepositoryImpl) GetExercisePersonalRecords(ctx context.Context, userID uuid.UUID, exerciseID int64) ([]*domain.PersonalRecord, error) {
+	rows, err := r.q.GetExercisePersonalRecords(ctx, gen.GetExercisePersonalRecordsParams{
+		UserID:     userID,
+		ExerciseID: exerciseID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id":     userID.String(),
+			"exercise_id": exerciseID,
+		})
+		logging.Error(err, "GetExercisePersonalRecords", jsonData, "failed to get exercise personal records")
+		return nil, err
+	}
+
+	records := make([]*domain.PersonalRecord, len(rows))
+	for i, row := range rows {
+		records[i] = toDomainPersonalRecord(gen.GetPersonalRecordsRow(row))
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":       userID.String(),
+		"exercise_id":   exerciseID,
+		"records_count": len(records),
+	})
+	logging.Debug("GetExercisePersonalRecords", jsonData, "successfully retrieved exercise personal records")
+
+	return records, nil
+}
+
+func toDomainPersonalRecord(row gen.GetPersonalRecordsRow) *domain.PersonalRecord {
+	value, _ := decimal.NewFromString(row.Value)
+	record := &domain.PersonalRecord{
+		ID:                row.ID,
+		UserID:            row.UserID,
+		ExerciseID:        row.ExerciseID,
+		ExerciseTitle:     row.ExerciseTitle,
+		TrainedExerciseID: row.TrainedExerciseID,
+		TrainingID:        row.TrainingID,
+		Type:              domain.PersonalRecordType(row.Type),
+		Value:             value,
+		AchievedAt:        row.AchievedAt,
+	}
+	if row.Weight.Valid {
+		if weight, err := decimal.NewFromString(row.Weight.String); err == nil {
+			record.Weight = &weight
+		}
+	}
+	return record
+}
@@ -0,0 +1,468 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetPrograms(ctx context.Context) ([]*domain.Program, error) {
+	rows, err := r.q.GetPrograms(ctx)
+	if err != nil {
+		logging.Error(err, "GetPrograms", nil, "failed to get programs")
+		return nil, err
+	}
+
+	programs := make([]*domain.Program, 0, len(rows))
+	for _, row := range rows {
+		programs = append(programs, toDomainProgram(row))
+	}
+	return programs, nil
+}
+
+func (r *TrainingRepositoryImpl) GetProgram(ctx context.Context, programID int64) (*domain.Program, error) {
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"program_id": programID,
+	})
+
+	row, err := r.q.GetProgramByID(ctx, programID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logging.Error(err, "GetProgram", jsonData, "failed to get program")
+		}
+		return nil, err
+	}
+
+	sessions, err := r.q.GetProgramSessions(ctx, programID)
+	if err != nil {
+		logging.Error(err, "GetProgram", jsonData, "failed to get program sessions")
+		return nil, err
+	}
+
+	program := toDomainProgram(row)
+	program.Sessions = make([]domain.ProgramSession, 0, len(sessions))
+	for _, s := range sessions {
+		session := domain.ProgramSession{
+			ID:                  s.ID,
+			Week:                s.Week,
+			Day:                 s.Day,
+			GlobalTrainingID:    s.GlobalTrainingID,
+			GlobalTrainingTitle: s.GlobalTrainingTitle,
+			RepsIncrement:       nullIntFromSQL32(s.RepsIncrement),
+		}
+		if s.WeightIncrement.Valid {
+			if increment, err := decimal.NewFromString(s.WeightIncrement.String); err == nil {
+				session.WeightIncrement = &increment
+			}
+		}
+		program.Sessions = append(program.Sessions, session)
+	}
+	return program, nil
+}
+
+func (r *TrainingRepositoryImpl) CreateProgram(ctx context.Context, cmd domain.ProgramCmd) (*domain.Program, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "CreateProgram", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	row, err := q.CreateProgram(ctx, gen.CreateProgramParams{
+		Title:       cmd.Title,
+		Description: cmd.Description,
+		Level:       cmd.Level,
+		Weeks:       cmd.Weeks,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"title": cmd.Title,
+		})
+		logging.Error(err, "CreateProgram", jsonData, "failed to create program")
+		return nil, err
+	}
+
+	if err := addProgramSessions(ctx, q, row.ID, cmd.Sessions); err != nil {
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"program_id":     row.ID,
+		"sessions_count": len(cmd.Sessions),
+	})
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "CreateProgram", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+	logging.Info("CreateProgram", jsonData, "program created")
+
+	return r.GetProgram(ctx, row.ID)
+}
+
+func (r *TrainingRepositoryImpl) UpdateProgram(ctx context.Context, programID int64, cmd domain.ProgramCmd) (*domain.Program, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "UpdateProgram", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"program_id": programID,
+	})
+
+	affected, err := q.UpdateProgram(ctx, gen.UpdateProgramParams{
+		Title:       cmd.Title,
+		Description: cmd.Description,
+		Level:       cmd.Level,
+		Weeks:       cmd.Weeks,
+		ID:          programID,
+	})
+	if err != nil {
+		logging.Error(err, "UpdateProgram", jsonData, "failed to update program")
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// Дни обновляются по неделе и номеру, чтобы тренировки записанных пользователей
+	// сохранили ссылку на них. Ссылку теряют только тренировки удаленных дней
+	weeks := make([]int32, len(cmd.Sessions))
+	days := make([]int32, len(cmd.Sessions))
+	for i, session := range cmd.Sessions {
+		weeks[i], days[i] = session.Week, session.Day
+	}
+	err = q.DeleteRemovedProgramSessions(ctx, gen.DeleteRemovedProgramSessionsParams{
+		ProgramID: programID,
+		Weeks:     weeks,
+		Days:      days,
+	})
+	if err != nil {
+		logging.Error(err, "UpdateProgram", jsonData, "failed to delete removed program sessions")
+		return nil, err
+	}
+
+	if err := addProgramSessions(ctx, q, programID, cmd.Sessions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "UpdateProgram", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData = logging.MarshalLogData(map[string]interface{}{
+		"program_id":     programID,
+		"sessions_count": len(cmd.Sessions),
+	})
+	logging.Info("UpdateProgram", jsonData, "program updated")
+
+	return r.GetProgram(ctx, programID)
+}
+
+func (r *TrainingRepositoryImpl) DeleteProgram(ctx context.Context, programID int64) (bool, error) {
+	rows, err := r.q.DeleteProgram(ctx, programID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"program_id": programID,
+		})
+		logging.Error(err, "DeleteProgram", jsonData, "failed to delete program")
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) CountGlobalTrainings(ctx context.Context, ids []int64) (int64, error) {
+	count, err := r.q.CountGlobalTrainingsByIDs(ctx, ids)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"ids_count": len(ids),
+		})
+		logging.Error(err, "CountGlobalTrainings", jsonData, "failed to count global trainings")
+		return 0, err
+	}
+	return count, nil
+}
+
+// addProgramSessions сохраняет тренировочные дни программы, обновляя уже существующие
+func addProgramSessions(ctx context.Context, q *gen.Queries, programID int64, sessions []domain.ProgramSessionCmd) error {
+	for _, s := range sessions {
+		err := q.UpsertProgramSession(ctx, gen.UpsertProgramSessionParams{
+			ProgramID:        programID,
+			Week:             s.Week,
+			Day:              s.Day,
+			GlobalTrainingID: s.GlobalTrainingID,
+			WeightIncrement:  decimalToNullString(s.WeightIncrement),
+			RepsIncrement:    null.Int32FromPtr(s.RepsIncrement).NullInt32,
+		})
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"program_id":         programID,
+				"week":               s.Week,
+				"day":                s.Day,
+				"global_training_id": s.GlobalTrainingID,
+			})
+			logging.Error(err, "addProgramSessions", jsonData, "failed to add program session")
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TrainingRepositoryImpl) EnrollInProgram(ctx context.Context, enrollment *domain.ProgramEnrollment, plan []domain.ProgramSessionPlan) (*domain.ProgramEnrollment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "EnrollInProgram", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"program_id": enrollment.ProgramID,
+		"user_id":    enrollment.UserID.String(),
+	})
+
+	weekdays := make([]int32, len(enrollment.Weekdays))
+	for i, day := range enrollment.Weekdays {
+		weekdays[i] = int32(day)
+	}
+	enrollmentID, err := q.CreateProgramEnrollment(ctx, gen.CreateProgramEnrollmentParams{
+		ProgramID: enrollment.ProgramID,
+		UserID:    enrollment.UserID,
+		StartDate: enrollment.StartDate,
+		Weekdays:  weekdays,
+	})
+	if err != nil {
+		logging.Error(err, "EnrollInProgram", jsonData, "failed to create program enrollment")
+		return nil, err
+	}
+
+	// Одна глобальная тренировка обычно повторяется каждую неделю, читаем ее упражнения один раз
+	prescriptions := make(map[int64][]gen.GetGlobalTrainingExercisesRow)
+	for _, item := range plan {
+		session := item.Session
+		prescription, ok := prescriptions[session.GlobalTrainingID]
+		if !ok {
+			prescription, err = q.GetGlobalTrainingExercises(ctx, session.GlobalTrainingID)
+			if err != nil {
+				logging.Error(err, "EnrollInProgram", jsonData, "failed to get global training exercises")
+				return nil, err
+			}
+			prescriptions[session.GlobalTrainingID] = prescription
+		}
+
+		trainingID, err := q.CreateProgramTraining(ctx, gen.CreateProgramTrainingParams{
+			Title:            session.GlobalTrainingTitle,
+			UserID:           enrollment.UserID,
+			PlannedDate:      item.PlannedDate,
+			EnrollmentID:     sql.NullInt64{Int64: enrollmentID, Valid: true},
+			ProgramSessionID: sql.NullInt64{Int64: session.ID, Valid: true},
+		})
+		if err != nil {
+			logging.Error(err, "EnrollInProgram", jsonData, "failed to create program training")
+			return nil, err
+		}
+
+		for _, ex := range prescription {
+			weight := ex.Weight
+			if weight.Valid {
+				if base, err := decimal.NewFromString(weight.String); err == nil {
+					progressed := session.ProgressWeight(base)
+					weight = decimalToNullString(&progressed)
+				}
+			}
+			reps := ex.Reps
+			if reps.Valid {
+				reps.Int32 = session.ProgressReps(reps.Int32)
+			}
+
+			if _, err := q.AddExerciseToTraining(ctx, gen.AddExerciseToTrainingParams{
+				TrainingID: trainingID,
+				ExerciseID: ex.ExerciseID,
+				Weight:     weight,
+				Approaches: ex.Approaches,
+				Reps:       reps,
+				Time:       sql.NullInt64{Valid: false},
+				Doing:      ex.Duration,
+				Rest:       ex.Rest,
+				Notes:      null.StringFromPtr(nil).NullString,
+				UserID:     enrollment.UserID,
+				GroupID:    ex.GroupID,
+				GroupType:  ex.GroupType,
+			}); err != nil {
+				logging.Error(err, "EnrollInProgram", jsonData, "failed to add exercise to program training")
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "EnrollInProgram", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData = logging.MarshalLogData(map[string]interface{}{
+		"enrollment_id":     enrollmentID,
+		"program_id":        enrollment.ProgramID,
+		"created_trainings": len(plan),
+	})
+	logging.Info("EnrollInProgram", jsonData, "user enrolled in program")
+
+	return r.GetEnrollment(ctx, enrollmentID, enrollment.UserID)
+}
+
+func (r *TrainingRepositoryImpl) GetEnrollment(ctx context.Context, enrollmentID int64, userID uuid.UUID) (*domain.ProgramEnrollment, error) {
+	row, err := r.q.GetProgramEnrollment(ctx, gen.GetProgramEnrollmentParams{
+		ID:     enrollmentID,
+		UserID: userID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"enrollment_id": enrollmentID,
+				"user_id":       userID.String(),
+			})
+			logging.Error(err, "GetEnrollment", jsonData, "failed to get program enrollment")
+		}
+		return nil, err
+	}
+
+	return toDomainProgramEnrollment(gen.GetProgramEnrollmentsRow(row)), nil
+}
+
+func (r *TrainingRepositoryImpl) GetEnrollments(ctx context.Context, userID uuid.UUID) ([]*domain.ProgramEnrollment, error) {
+	rows, err := r.q.GetProgramEnrollments(ctx, userID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "GetEnrollments", jsonData, "failed to get program enrollments")
+		return nil, err
+	}
+
+	enrollments := make([]*domain.ProgramEnrollment, 0, len(rows))
+	for _, row := range rows {
+		enrollments = append(enrollments, toDomainProgramEnrollment(row))
+	}
+	return enrollments, nil
+}
+
+func (r *TrainingRepositoryImpl) GetEnrollmentProgress(ctx context.Context, enrollment *domain.ProgramEnrollment) (*domain.ProgramProgress, error) {
+	row, err := r.q.GetEnrollmentProgress(ctx, enrollment.ID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"enrollment_id": enrollment.ID,
+		})
+		logging.Error(err, "GetEnrollmentProgress", jsonData, "failed to get enrollment progress")
+		return nil, err
+	}
+
+	progress := &domain.ProgramProgress{
+		Enrollment:        *enrollment,
+		TotalSessions:     int32(row.TotalSessions),
+		CompletedSessions: int32(row.CompletedSessions),
+		SkippedSessions:   int32(row.SkippedSessions),
+	}
+	if row.NextTrainingID.Valid {
+		progress.NextTraining = &domain.ProgramNextTraining{
+			TrainingID:  row.NextTrainingID.Int64,
+			Title:       row.NextTitle.String,
+			PlannedDate: row.NextPlannedDate.Time,
+			Week:        row.NextWeek.Int32,
+			Day:         row.NextDay.Int32,
+		}
+	}
+	return progress, nil
+}
+
+func (r *TrainingRepositoryImpl) DeleteEnrollment(ctx context.Context, enrollmentID int64, userID uuid.UUID, from time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "DeleteEnrollment", nil, "failed to begin transaction")
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"enrollment_id": enrollmentID,
+		"user_id":       userID.String(),
+	})
+
+	// Запись проверяется до удаления тренировок, чтобы не трогать чужие
+	if _, err := q.GetProgramEnrollment(ctx, gen.GetProgramEnrollmentParams{ID: enrollmentID, UserID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		logging.Error(err, "DeleteEnrollment", jsonData, "failed to get program enrollment")
+		return false, err
+	}
+
+	if _, err := q.DeletePlannedEnrollmentTrainings(ctx, gen.DeletePlannedEnrollmentTrainingsParams{
+		EnrollmentID: sql.NullInt64{Int64: enrollmentID, Valid: true},
+		PlannedDate:  from,
+	}); err != nil {
+		logging.Error(err, "DeleteEnrollment", jsonData, "failed to delete planned program trainings")
+		return false, err
+	}
+
+	rows, err := q.DeleteProgramEnrollment(ctx, gen.DeleteProgramEnrollmentParams{
+		ID:     enrollmentID,
+		UserID: userID,
+	})
+	if err != nil {
+		logging.Error(err, "DeleteEnrollment", jsonData, "failed to delete program enrollment")
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "DeleteEnrollment", jsonData, "failed to commit transaction")
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func toDomainProgram(row gen.Program) *domain.Program {
+	return &domain.Program{
+		ID:          row.ID,
+		Title:       row.Title,
+		Description: row.Description,
+		Level:       row.Level,
+		Weeks:       row.Weeks,
+	}
+}
+
+func toDomainProgramEnrollment(row gen.GetProgramEnrollmentsRow) *domain.ProgramEnrollment {
+	weekdays := make([]time.Weekday, len(row.Weekdays))
+	for i, day := range row.Weekdays {
+		weekdays[i] = time.Weekday(day)
+	}
+	return &domain.ProgramEnrollment{
+		ID:           row.ID,
+		ProgramID:    row.ProgramID,
+		ProgramTitle: row.ProgramTitle,
+		Weeks:        row.Weeks,
+		UserID:       row.UserID,
+		StartDate:    row.StartDate,
+		Weekdays:     weekdays,
+		CreatedAt:    row.CreatedAt,
+	}
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetRecentExerciseSessions(ctx context.Context, userID uuid.UUID, exerciseID int64, limit int32) ([]domain.ExerciseSession, error) {
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":     userID.String(),
+		"exercise_id": exerciseID,
+	})
+
+	rows, err := r.q.GetRecentExerciseSessions(ctx, gen.GetRecentExerciseSessionsParams{
+		UserID:     userID,
+		ExerciseID: exerciseID,
+		Limit:      limit,
+	})
+	if err != nil {
+		logging.Error(err, "GetRecentExerciseSessions", jsonData, "failed to get recent exercise sessions")
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(rows))
+	sessions := make([]domain.ExerciseSession, len(rows))
+	index := make(map[int64]int, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+		index[row.ID] = i
+		sessions[i] = domain.ExerciseSession{
+			TrainedExerciseID: row.ID,
+			TrainingID:        row.TrainingID,
+			Date:              row.TrainingDate,
+			Approaches:        nullIntFromSQL32(row.Approaches),
+			Reps:              nullIntFromSQL32(row.Reps),
+		}
+		if row.Weight.Valid {
+			if weight, err := decimal.NewFromString(row.Weight.String); err == nil {
+				sessions[i].Weight = &weight
+			}
+		}
+	}
+
+	sets, err := r.q.GetWorkingSetsByTrainedExercises(ctx, ids)
+	if err != nil {
+		logging.Error(err, "GetRecentExerciseSessions", jsonData, "failed to get working sets")
+		return nil, err
+	}
+	for _, set := range sets {
+		session := &sessions[index[set.TrainedExerciseID]]
+		trainedSet := domain.TrainedSet{
+			TrainedExerciseID: set.TrainedExerciseID,
+			Reps:              nullIntFromSQL32(set.Reps),
+		}
+		if set.Weight.Valid {
+			if weight, err := decimal.NewFromString(set.Weight.String); err == nil {
+				trainedSet.Weight = &weight
+			}
+		}
+		session.Sets = append(session.Sets, trainedSet)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,403 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) CreateSchedule(ctx context.Context, schedule *domain.TrainingSchedule) (*domain.TrainingSchedule, error) {
+	row, err := r.q.CreateTrainingSchedule(ctx, gen.CreateTrainingScheduleParams{
+		UserID:           schedule.UserID,
+		Title:            schedule.Title,
+		Rrule:            schedule.Rule.String(),
+		StartDate:        schedule.StartDate,
+		GlobalTrainingID: null.IntFromPtr(schedule.GlobalTrainingID).NullInt64,
+		TemplateID:       null.IntFromPtr(schedule.TemplateID).NullInt64,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": schedule.UserID.String(),
+			"rrule":   schedule.Rule.String(),
+		})
+		logging.Error(err, "CreateSchedule", jsonData, "failed to create training schedule")
+		return nil, err
+	}
+
+	return toDomainTrainingSchedule(row)
+}
+
+func (r *TrainingRepositoryImpl) GetSchedule(ctx context.Context, scheduleID int64, userID uuid.UUID) (*domain.TrainingSchedule, error) {
+	row, err := r.q.GetTrainingSchedule(ctx, gen.GetTrainingScheduleParams{
+		ID:     scheduleID,
+		UserID: userID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"schedule_id": scheduleID,
+				"user_id":     userID.String(),
+			})
+			logging.Error(err, "GetSchedule", jsonData, "failed to get training schedule")
+		}
+		return nil, err
+	}
+
+	return toDomainTrainingSchedule(row)
+}
+
+func (r *TrainingRepositoryImpl) GetSchedules(ctx context.Context, userID uuid.UUID) ([]*domain.TrainingSchedule, error) {
+	rows, err := r.q.GetTrainingSchedules(ctx, userID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "GetSchedules", jsonData, "failed to get training schedules")
+		return nil, err
+	}
+
+	return toDomainTrainingSchedules(rows)
+}
+
+func (r *TrainingRepositoryImpl) GetSchedulesToMaterialize(ctx context.Context, horizon time.Time) ([]*domain.TrainingSchedule, error) {
+	rows, err := r.q.GetSchedulesToMaterialize(ctx, sql.NullTime{Time: horizon, Valid: true})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"horizon": horizon.Format("2006-01-02"),
+		})
+		logging.Error(err, "GetSchedulesToMaterialize", jsonData, "failed to get schedules to materialize")
+		return nil, err
+	}
+
+	return toDomainTrainingSchedules(rows)
+}
+
+func (r *TrainingRepositoryImpl) UpdateSchedule(ctx context.Context, schedule *domain.TrainingSchedule, from time.Time) (*domain.TrainingSchedule, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "UpdateSchedule", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	row, err := q.UpdateTrainingSchedule(ctx, gen.UpdateTrainingScheduleParams{
+		Title:             schedule.Title,
+		Rrule:             schedule.Rule.String(),
+		GlobalTrainingID:  null.IntFromPtr(schedule.GlobalTrainingID).NullInt64,
+		TemplateID:        null.IntFromPtr(schedule.TemplateID).NullInt64,
+		MaterializedUntil: null.TimeFromPtr(schedule.MaterializedUntil).NullTime,
+		ID:                schedule.ID,
+		UserID:            schedule.UserID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"schedule_id": schedule.ID,
+			})
+			logging.Error(err, "UpdateSchedule", jsonData, "failed to update training schedule")
+		}
+		return nil, err
+	}
+
+	// Будущие тренировки созданы по старому правилу, их пересоздаст материализация
+	deleted, err := q.DeletePlannedScheduledTrainings(ctx, gen.DeletePlannedScheduledTrainingsParams{
+		ScheduleID: schedule.ID,
+		FromDate:   from,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"schedule_id": schedule.ID,
+			"from":        from.Format("2006-01-02"),
+		})
+		logging.Error(err, "UpdateSchedule", jsonData, "failed to delete planned scheduled trainings")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "UpdateSchedule", nil, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"schedule_id":       schedule.ID,
+		"deleted_trainings": deleted,
+	})
+	logging.Debug("UpdateSchedule", jsonData, "successfully updated training schedule")
+
+	return toDomainTrainingSchedule(row)
+}
+
+func (r *TrainingRepositoryImpl) DeleteSchedule(ctx context.Context, scheduleID int64, userID uuid.UUID, from time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "DeleteSchedule", nil, "failed to begin transaction")
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"schedule_id": scheduleID,
+		"user_id":     userID.String(),
+	})
+
+	// Расписание проверяется до удаления тренировок, чтобы не трогать чужие
+	if _, err := q.GetTrainingSchedule(ctx, gen.GetTrainingScheduleParams{ID: scheduleID, UserID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		logging.Error(err, "DeleteSchedule", jsonData, "failed to get training schedule")
+		return false, err
+	}
+
+	if _, err := q.DeletePlannedScheduledTrainings(ctx, gen.DeletePlannedScheduledTrainingsParams{
+		ScheduleID: scheduleID,
+		FromDate:   from,
+	}); err != nil {
+		logging.Error(err, "DeleteSchedule", jsonData, "failed to delete planned scheduled trainings")
+		return false, err
+	}
+
+	rows, err := q.DeleteTrainingSchedule(ctx, gen.DeleteTrainingScheduleParams{
+		ID:     scheduleID,
+		UserID: userID,
+	})
+	if err != nil {
+		logging.Error(err, "DeleteSchedule", jsonData, "failed to delete training schedule")
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "DeleteSchedule", jsonData, "failed to commit transaction")
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) GetScheduleSkips(ctx context.Context, scheduleID int64) ([]time.Time, error) {
+	skips, err := r.q.GetTrainingScheduleSkips(ctx, scheduleID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"schedule_id": scheduleID,
+		})
+		logging.Error(err, "GetScheduleSkips", jsonData, "failed to get schedule skips")
+		return nil, err
+	}
+	return skips, nil
+}
+
+func (r *TrainingRepositoryImpl) GetScheduledTrainings(ctx context.Context, scheduleID int64, from, to time.Time) ([]domain.ScheduleOccurrence, error) {
+	rows, err := r.q.GetScheduledTrainings(ctx, gen.GetScheduledTrainingsParams{
+		ScheduleID: scheduleID,
+		FromDate:   from,
+		ToDate:     to,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"schedule_id": scheduleID,
+			"from":        from.Format("2006-01-02"),
+			"to":          to.Format("2006-01-02"),
+		})
+		logging.Error(err, "GetScheduledTrainings", jsonData, "failed to get scheduled trainings")
+		return nil, err
+	}
+
+	occurrences := make([]domain.ScheduleOccurrence, len(rows))
+	for i, row := range rows {
+		id := row.ID
+		plannedDate := row.PlannedDate
+		status := domain.TrainingStatus(row.Status)
+		occurrences[i] = domain.ScheduleOccurrence{
+			Date:        row.OccurrenceDate.Time,
+			TrainingID:  &id,
+			PlannedDate: &plannedDate,
+			Status:      &status,
+		}
+	}
+	return occurrences, nil
+}
+
+func (r *TrainingRepositoryImpl) MaterializeSchedule(ctx context.Context, schedule *domain.TrainingSchedule, dates []time.Time, until *time.Time) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "MaterializeSchedule", nil, "failed to begin transaction")
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"schedule_id": schedule.ID,
+		"user_id":     schedule.UserID.String(),
+	})
+
+	var prescription []gen.GetGlobalTrainingExercisesRow
+	if schedule.GlobalTrainingID != nil && len(dates) > 0 {
+		prescription, err = q.GetGlobalTrainingExercises(ctx, *schedule.GlobalTrainingID)
+		if err != nil {
+			logging.Error(err, "MaterializeSchedule", jsonData, "failed to get global training exercises")
+			return 0, err
+		}
+	}
+
+	created := 0
+	for _, date := range dates {
+		trainingID, err := q.CreateScheduledTraining(ctx, gen.CreateScheduledTrainingParams{
+			Title:       schedule.Title,
+			UserID:      schedule.UserID,
+			PlannedDate: date,
+			ScheduleID:  sql.NullInt64{Int64: schedule.ID, Valid: true},
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// Тренировка на этот день повторения уже создана
+			continue
+		}
+		if err != nil {
+			logging.Error(err, "MaterializeSchedule", jsonData, "failed to create scheduled training")
+			return 0, err
+		}
+
+		// Упражнения шаблона копируются вместе с порядком и группами
+		if schedule.TemplateID != nil {
+			if err := q.AddTemplateExercisesToTraining(ctx, gen.AddTemplateExercisesToTrainingParams{
+				TrainingID: trainingID,
+				TemplateID: *schedule.TemplateID,
+			}); err != nil {
+				logging.Error(err, "MaterializeSchedule", jsonData, "failed to add template exercises to scheduled training")
+				return 0, err
+			}
+		}
+		for _, ex := range prescription {
+			if _, err := q.AddExerciseToTraining(ctx, gen.AddExerciseToTrainingParams{
+				TrainingID: trainingID,
+				ExerciseID: ex.ExerciseID,
+				Weight:     ex.Weight,
+				Approaches: ex.Approaches,
+				Reps:       ex.Reps,
+				Time:       sql.NullInt64{Valid: false},
+				Doing:      ex.Duration,
+				Rest:       ex.Rest,
+				Notes:      null.StringFromPtr(nil).NullString,
+				UserID:     schedule.UserID,
+				GroupID:    ex.GroupID,
+				GroupType:  ex.GroupType,
+			}); err != nil {
+				logging.Error(err, "MaterializeSchedule", jsonData, "failed to add exercise to scheduled training")
+				return 0, err
+			}
+		}
+		created++
+	}
+
+	if until != nil {
+		if err := q.SetScheduleMaterializedUntil(ctx, gen.SetScheduleMaterializedUntilParams{
+			ID:                schedule.ID,
+			MaterializedUntil: sql.NullTime{Time: *until, Valid: true},
+		}); err != nil {
+			logging.Error(err, "MaterializeSchedule", jsonData, "failed to save materialized horizon")
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "MaterializeSchedule", jsonData, "failed to commit transaction")
+		return 0, err
+	}
+
+	jsonData = logging.MarshalLogData(map[string]interface{}{
+		"schedule_id":       schedule.ID,
+		"created_trainings": created,
+	})
+	logging.Debug("MaterializeSchedule", jsonData, "successfully materialized schedule")
+
+	return created, nil
+}
+
+func (r *TrainingRepositoryImpl) SkipScheduleOccurrence(ctx context.Context, scheduleID int64, date time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "SkipScheduleOccurrence", nil, "failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"schedule_id": scheduleID,
+		"date":        date.Format("2006-01-02"),
+	})
+
+	if err := q.CreateTrainingScheduleSkip(ctx, gen.CreateTrainingScheduleSkipParams{
+		ScheduleID:     scheduleID,
+		OccurrenceDate: date,
+	}); err != nil {
+		logging.Error(err, "SkipScheduleOccurrence", jsonData, "failed to save schedule skip")
+		return err
+	}
+
+	if _, err := q.DeletePlannedScheduledTrainings(ctx, gen.DeletePlannedScheduledTrainingsParams{
+		ScheduleID: scheduleID,
+		FromDate:   date,
+		ToDate:     sql.NullTime{Time: date, Valid: true},
+	}); err != nil {
+		logging.Error(err, "SkipScheduleOccurrence", jsonData, "failed to delete skipped training")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "SkipScheduleOccurrence", jsonData, "failed to commit transaction")
+		return err
+	}
+	return nil
+}
+
+func toDomainTrainingSchedules(rows []gen.TrainingSchedule) ([]*domain.TrainingSchedule, error) {
+	schedules := make([]*domain.TrainingSchedule, 0, len(rows))
+	for _, row := range rows {
+		schedule, err := toDomainTrainingSchedule(row)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+func toDomainTrainingSchedule(row gen.TrainingSchedule) (*domain.TrainingSchedule, error) {
+	// Правило сохраняется только после разбора, поэтому ошибка означает порчу данных
+	rule, err := domain.ParseRecurrenceRule(row.Rrule)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"schedule_id": row.ID,
+			"rrule":       row.Rrule,
+		})
+		logging.Error(err, "toDomainTrainingSchedule", jsonData, "failed to parse stored recurrence rule")
+		return nil, err
+	}
+
+	return &domain.TrainingSchedule{
+		ID:                row.ID,
+		UserID:            row.UserID,
+		Title:             row.Title,
+		Rule:              *rule,
+		StartDate:         row.StartDate,
+		GlobalTrainingID:  nullIntFromSQL(row.GlobalTrainingID),
+		TemplateID:        nullIntFromSQL(row.TemplateID),
+		MaterializedUntil: nullTimeFromSQL(row.MaterializedUntil),
+		CreatedAt:         row.CreatedAt,
+	}, nil
+}
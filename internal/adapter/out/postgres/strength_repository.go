@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetExerciseStrengthEfforts(ctx context.Context, userID uuid.UUID, exerciseID int64, from, to time.Time) ([]domain.StrengthEffort, error) {
+	rows, err := r.q.GetExerciseStrengthEfforts(ctx, gen.GetExerciseStrengthEffortsParams{
+		UserID:     userID,
+		ExerciseID: exerciseID,
+		FromDate:   from,
+		ToDate:     to,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id":     userID.String(),
+			"exercise_id": exerciseID,
+			"from":        from,
+			"to":          to,
+		})
+		logging.Error(err, "GetExerciseStrengthEfforts", jsonData, "failed to get exercise strength efforts")
+		return nil, err
+	}
+
+	efforts := make([]domain.StrengthEffort, len(rows))
+	for i, row := range rows {
+		weight, _ := decimal.NewFromString(row.Weight)
+		efforts[i] = domain.StrengthEffort{
+			Date:   row.TrainingDate,
+			Weight: weight,
+			Reps:   row.Reps,
+			Sets:   row.Sets,
+		}
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":       userID.String(),
+		"exercise_id":   exerciseID,
+		"efforts_count": len(efforts),
+	})
+	logging.Debug("GetExerciseStrengthEfforts", jsonData, "successfully retrieved exercise strength efforts")
+
+	return efforts, nil
+}
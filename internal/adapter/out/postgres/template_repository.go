@@ -0,0 +1,368 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetTemplates(ctx context.Context, userID uuid.UUID) ([]*domain.TrainingTemplate, error) {
+	rows, err := r.q.GetTrainingTemplates(ctx, userID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "GetTemplates", jsonData, "failed to get templates")
+		return nil, err
+	}
+
+	templates := make([]*domain.TrainingTemplate, 0, len(rows))
+	for _, row := range rows {
+		templates = append(templates, toDomainTrainingTemplate(row))
+	}
+	return templates, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTemplate(ctx context.Context, templateID int64, userID uuid.UUID) (*domain.TrainingTemplate, error) {
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"template_id": templateID,
+		"user_id":     userID.String(),
+	})
+
+	row, err := r.q.GetTrainingTemplate(ctx, gen.GetTrainingTemplateParams{
+		ID:     templateID,
+		UserID: userID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logging.Error(err, "GetTemplate", jsonData, "failed to get template")
+		}
+		return nil, err
+	}
+
+	exercises, err := r.q.GetTrainingTemplateExercises(ctx, templateID)
+	if err != nil {
+		logging.Error(err, "GetTemplate", jsonData, "failed to get template exercises")
+		return nil, err
+	}
+
+	template := toDomainTrainingTemplate(row)
+	template.Exercises = make([]domain.TemplateExercise, 0, len(exercises))
+	for _, ex := range exercises {
+		exercise := domain.TemplateExercise{
+			ID:            ex.ID,
+			ExerciseID:    ex.ExerciseID,
+			ExerciseTitle: ex.ExerciseTitle,
+			Position:      ex.Position,
+			Approaches:    nullIntFromSQL32(ex.Approaches),
+			Reps:          nullIntFromSQL32(ex.Reps),
+			Group:         toDomainExerciseGroup(ex.GroupID, ex.GroupType),
+		}
+		if ex.Weight.Valid {
+			if weight, err := decimal.NewFromString(ex.Weight.String); err == nil {
+				exercise.Weight = &weight
+			}
+		}
+		if ex.Duration.Valid {
+			exercise.Duration = toDuration(ex.Duration.Int64)
+		}
+		if ex.Rest.Valid {
+			exercise.Rest = toDuration(ex.Rest.Int64)
+		}
+		template.Exercises = append(template.Exercises, exercise)
+	}
+	return template, nil
+}
+
+func (r *TrainingRepositoryImpl) CreateTemplate(ctx context.Context, cmd domain.TemplateCmd) (*domain.TrainingTemplate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "CreateTemplate", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	templateID, err := q.CreateTrainingTemplate(ctx, gen.CreateTrainingTemplateParams{
+		UserID:      cmd.UserID,
+		Title:       cmd.Title,
+		Description: cmd.Description,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": cmd.UserID.String(),
+			"title":   cmd.Title,
+		})
+		logging.Error(err, "CreateTemplate", jsonData, "failed to create template")
+		return nil, err
+	}
+
+	if err := addTemplateExercises(ctx, q, templateID, cmd.Exercises); err != nil {
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"template_id":     templateID,
+		"exercises_count": len(cmd.Exercises),
+	})
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "CreateTemplate", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+	logging.Info("CreateTemplate", jsonData, "template created")
+
+	return r.GetTemplate(ctx, templateID, cmd.UserID)
+}
+
+func (r *TrainingRepositoryImpl) UpdateTemplate(ctx context.Context, templateID int64, cmd domain.TemplateCmd) (*domain.TrainingTemplate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "UpdateTemplate", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"template_id": templateID,
+		"user_id":     cmd.UserID.String(),
+	})
+
+	affected, err := q.UpdateTrainingTemplate(ctx, gen.UpdateTrainingTemplateParams{
+		Title:       cmd.Title,
+		Description: cmd.Description,
+		ID:          templateID,
+		UserID:      cmd.UserID,
+	})
+	if err != nil {
+		logging.Error(err, "UpdateTemplate", jsonData, "failed to update template")
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// Упражнения заменяются целиком, как и слоты глобальной тренировки
+	if err := q.DeleteTrainingTemplateExercises(ctx, templateID); err != nil {
+		logging.Error(err, "UpdateTemplate", jsonData, "failed to delete template exercises")
+		return nil, err
+	}
+
+	if err := addTemplateExercises(ctx, q, templateID, cmd.Exercises); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "UpdateTemplate", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+	logging.Info("UpdateTemplate", jsonData, "template updated")
+
+	return r.GetTemplate(ctx, templateID, cmd.UserID)
+}
+
+func addTemplateExercises(ctx context.Context, q *gen.Queries, templateID int64, exercises []domain.GlobalTrainingExerciseCmd) error {
+	for i, ex := range exercises {
+		groupID, groupType := exerciseGroupToSQL(ex.Group)
+		err := q.AddTrainingTemplateExercise(ctx, gen.AddTrainingTemplateExerciseParams{
+			TemplateID: templateID,
+			ExerciseID: ex.ExerciseID,
+			Position:   int32(i + 1),
+			Approaches: null.Int32FromPtr(ex.Approaches).NullInt32,
+			Reps:       null.Int32FromPtr(ex.Reps).NullInt32,
+			Weight:     decimalToNullString(ex.Weight),
+			Duration:   durationToNullInt64(ex.Duration),
+			Rest:       durationToNullInt64(ex.Rest),
+			GroupID:    groupID,
+			GroupType:  groupType,
+		})
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"template_id": templateID,
+				"exercise_id": ex.ExerciseID,
+				"position":    i + 1,
+			})
+			logging.Error(err, "addTemplateExercises", jsonData, "failed to add template exercise")
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TrainingRepositoryImpl) DeleteTemplate(ctx context.Context, templateID int64, userID uuid.UUID) (bool, error) {
+	rows, err := r.q.DeleteTrainingTemplate(ctx, gen.DeleteTrainingTemplateParams{
+		ID:     templateID,
+		UserID: userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"template_id": templateID,
+			"user_id":     userID.String(),
+		})
+		logging.Error(err, "DeleteTemplate", jsonData, "failed to delete template")
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) CreateTemplateFromTraining(ctx context.Context, cmd domain.TemplateFromTrainingCmd) (*domain.TrainingTemplate, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "CreateTemplateFromTraining", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"training_id": cmd.TrainingID,
+		"user_id":     cmd.UserID.String(),
+	})
+
+	templateID, err := q.CreateTrainingTemplate(ctx, gen.CreateTrainingTemplateParams{
+		UserID:      cmd.UserID,
+		Title:       cmd.Title,
+		Description: cmd.Description,
+	})
+	if err != nil {
+		logging.Error(err, "CreateTemplateFromTraining", jsonData, "failed to create template")
+		return nil, err
+	}
+
+	err = q.CopyTrainedExercisesToTemplate(ctx, gen.CopyTrainedExercisesToTemplateParams{
+		TemplateID: templateID,
+		TrainingID: cmd.TrainingID,
+	})
+	if err != nil {
+		logging.Error(err, "CreateTemplateFromTraining", jsonData, "failed to copy training exercises")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "CreateTemplateFromTraining", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData = logging.MarshalLogData(map[string]interface{}{
+		"training_id": cmd.TrainingID,
+		"template_id": templateID,
+	})
+	logging.Info("CreateTemplateFromTraining", jsonData, "template created from training")
+
+	return r.GetTemplate(ctx, templateID, cmd.UserID)
+}
+
+func (r *TrainingRepositoryImpl) CreateTrainingFromTemplate(ctx context.Context, template *domain.TrainingTemplate, plannedDate time.Time) (*domain.Training, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "CreateTrainingFromTemplate", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"template_id":  template.ID,
+		"user_id":      template.UserID.String(),
+		"planned_date": plannedDate.Format("2006-01-02"),
+	})
+
+	trainingID, err := q.CreatePlannedTraining(ctx, gen.CreatePlannedTrainingParams{
+		Title:       template.Title,
+		UserID:      template.UserID,
+		PlannedDate: plannedDate,
+	})
+	if err != nil {
+		logging.Error(err, "CreateTrainingFromTemplate", jsonData, "failed to create training")
+		return nil, err
+	}
+
+	err = q.AddTemplateExercisesToTraining(ctx, gen.AddTemplateExercisesToTrainingParams{
+		TrainingID: trainingID,
+		TemplateID: template.ID,
+	})
+	if err != nil {
+		logging.Error(err, "CreateTrainingFromTemplate", jsonData, "failed to add template exercises")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "CreateTrainingFromTemplate", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+	logging.Info("CreateTrainingFromTemplate", jsonData, "training created from template")
+
+	return r.GetTrainingWithExercises(ctx, trainingID, template.UserID)
+}
+
+func (r *TrainingRepositoryImpl) CloneTraining(ctx context.Context, training *domain.Training, plannedDate time.Time) (*domain.Training, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "CloneTraining", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"training_id":  training.ID,
+		"user_id":      training.UserID.String(),
+		"planned_date": plannedDate.Format("2006-01-02"),
+	})
+
+	cloneID, err := q.CreatePlannedTraining(ctx, gen.CreatePlannedTrainingParams{
+		Title:       training.Title,
+		UserID:      training.UserID,
+		PlannedDate: plannedDate,
+	})
+	if err != nil {
+		logging.Error(err, "CloneTraining", jsonData, "failed to create training")
+		return nil, err
+	}
+
+	err = q.CopyTrainedExercises(ctx, gen.CopyTrainedExercisesParams{
+		TargetTrainingID: cloneID,
+		SourceTrainingID: training.ID,
+	})
+	if err != nil {
+		logging.Error(err, "CloneTraining", jsonData, "failed to copy training exercises")
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "CloneTraining", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData = logging.MarshalLogData(map[string]interface{}{
+		"training_id": training.ID,
+		"clone_id":    cloneID,
+	})
+	logging.Info("CloneTraining", jsonData, "training cloned")
+
+	return r.GetTrainingWithExercises(ctx, cloneID, training.UserID)
+}
+
+func toDomainTrainingTemplate(row gen.TrainingTemplate) *domain.TrainingTemplate {
+	return &domain.TrainingTemplate{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Title:       row.Title,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+	}
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/guregu/null/v6"
+	"github.com/shopspring/decimal"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetTrainedSets(ctx context.Context, trainedExerciseID int64, userID uuid.UUID) ([]*domain.TrainedSet, error) {
+	rows, err := r.q.ListTrainedSets(ctx, gen.ListTrainedSetsParams{
+		TrainedExerciseID: trainedExerciseID,
+		UserID:            userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+		})
+		logging.Error(err, "GetTrainedSets", jsonData, "failed to get trained sets")
+		return nil, err
+	}
+
+	sets := make([]*domain.TrainedSet, len(rows))
+	for i, row := range rows {
+		sets[i] = toDomainTrainedSet(gen.TrainedSet(row))
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": trainedExerciseID,
+		"sets_count":          len(sets),
+	})
+	logging.Debug("GetTrainedSets", jsonData, "successfully retrieved trained sets")
+
+	return sets, nil
+}
+
+func (r *TrainingRepositoryImpl) AddTrainedSet(ctx context.Context, set *domain.TrainedSet, userID uuid.UUID) (*domain.TrainedSet, error) {
+	created, err := r.q.AddTrainedSet(ctx, gen.AddTrainedSetParams{
+		ID:       set.TrainedExerciseID,
+		Weight:   decimalToNullString(set.Weight),
+		Reps:     null.Int32FromPtr(set.Reps).NullInt32,
+		Duration: durationToNullInt64(set.Duration),
+		Rpe:      decimalToNullString(set.RPE),
+		IsWarmup: set.IsWarmup,
+		UserID:   userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": set.TrainedExerciseID,
+		})
+		logging.Error(err, "AddTrainedSet", jsonData, "failed to add trained set")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": created.TrainedExerciseID,
+		"set_id":              created.ID,
+		"ordinal":             created.Ordinal,
+	})
+	logging.Debug("AddTrainedSet", jsonData, "successfully added trained set")
+
+	return toDomainTrainedSet(gen.TrainedSet(created)), nil
+}
+
+func (r *TrainingRepositoryImpl) UpdateTrainedSet(ctx context.Context, cmd domain.UpdateTrainedSetCmd, userID uuid.UUID) (*domain.TrainedSet, error) {
+	updated, err := r.q.UpdateTrainedSet(ctx, gen.UpdateTrainedSetParams{
+		Weight:            decimalToNullString(cmd.Weight),
+		Reps:              null.Int32FromPtr(cmd.Reps).NullInt32,
+		Duration:          durationToNullInt64(cmd.Duration),
+		Rpe:               decimalToNullString(cmd.RPE),
+		IsWarmup:          null.BoolFromPtr(cmd.IsWarmup).NullBool,
+		ID:                cmd.ID,
+		TrainedExerciseID: cmd.TrainedExerciseID,
+		UserID:            userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": cmd.TrainedExerciseID,
+			"set_id":              cmd.ID,
+		})
+		logging.Error(err, "UpdateTrainedSet", jsonData, "failed to update trained set")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": updated.TrainedExerciseID,
+		"set_id":              updated.ID,
+	})
+	logging.Debug("UpdateTrainedSet", jsonData, "successfully updated trained set")
+
+	return toDomainTrainedSet(gen.TrainedSet(updated)), nil
+}
+
+func (r *TrainingRepositoryImpl) DeleteTrainedSet(ctx context.Context, trainedExerciseID, setID int64, userID uuid.UUID) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "DeleteTrainedSet", nil, "failed to begin transaction")
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	affected, err := q.DeleteTrainedSet(ctx, gen.DeleteTrainedSetParams{
+		ID:                setID,
+		TrainedExerciseID: trainedExerciseID,
+		UserID:            userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+			"set_id":              setID,
+		})
+		logging.Error(err, "DeleteTrainedSet", jsonData, "failed to delete trained set")
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	// Оставшиеся подходы нумеруются заново без пропусков
+	if err := q.RenumberTrainedSets(ctx, trainedExerciseID); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+		})
+		logging.Error(err, "DeleteTrainedSet", jsonData, "failed to renumber trained sets")
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "DeleteTrainedSet", nil, "failed to commit transaction")
+		return false, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": trainedExerciseID,
+		"set_id":              setID,
+	})
+	logging.Debug("DeleteTrainedSet", jsonData, "successfully deleted trained set")
+
+	return true, nil
+}
+
+// ReorderTrainedSets присваивает подходам порядковые номера по их позиции в setIDs.
+// Ожидается, что setIDs содержит все подходы упражнения
+func (r *TrainingRepositoryImpl) ReorderTrainedSets(ctx context.Context, trainedExerciseID int64, setIDs []int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "ReorderTrainedSets", nil, "failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	for i, setID := range setIDs {
+		affected, err := q.SetTrainedSetOrdinal(ctx, gen.SetTrainedSetOrdinalParams{
+			Ordinal:           int32(i + 1),
+			ID:                setID,
+			TrainedExerciseID: trainedExerciseID,
+		})
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"trained_exercise_id": trainedExerciseID,
+				"set_id":              setID,
+			})
+			logging.Error(err, "ReorderTrainedSets", jsonData, "failed to set trained set ordinal")
+			return err
+		}
+		if affected == 0 {
+			return sql.ErrNoRows
+		}
+	}
+
+	// Уникальность (trained_exercise_id, ordinal) проверяется при коммите
+	if err := tx.Commit(); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": trainedExerciseID,
+		})
+		logging.Error(err, "ReorderTrainedSets", jsonData, "failed to commit transaction")
+		return err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"trained_exercise_id": trainedExerciseID,
+		"sets_count":          len(setIDs),
+	})
+	logging.Debug("ReorderTrainedSets", jsonData, "successfully reordered trained sets")
+
+	return nil
+}
+
+func toDomainTrainedSet(set gen.TrainedSet) *domain.TrainedSet {
+	result := &domain.TrainedSet{
+		ID:                set.ID,
+		TrainedExerciseID: set.TrainedExerciseID,
+		Ordinal:           set.Ordinal,
+		Reps:              nullIntFromSQL32(set.Reps),
+		IsWarmup:          set.IsWarmup,
+	}
+	if set.Weight.Valid {
+		if weight, err := decimal.NewFromString(set.Weight.String); err == nil {
+			result.Weight = &weight
+		}
+	}
+	if set.Rpe.Valid {
+		if rpe, err := decimal.NewFromString(set.Rpe.String); err == nil {
+			result.RPE = &rpe
+		}
+	}
+	if set.Duration.Valid {
+		result.Duration = toDuration(set.Duration.Int64)
+	}
+	return result
+}
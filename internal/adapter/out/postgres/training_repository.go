@@ -50,11 +50,89 @@ func (r *TrainingRepositoryImpl) GetTrainingsByUser(ctx context.Context, userID
 	return result, nil
 }
 
-func (r *TrainingRepositoryImpl) GetTrainingWithExercises(ctx context.Context, trainingID int64) (*domain.Training, error) {
-	training, err := r.q.GetTrainingWithExercises(ctx, trainingID)
+func (r *TrainingRepositoryImpl) GetTrainingsFiltered(ctx context.Context, userID uuid.UUID, filter domain.TrainingFilter) (*domain.TrainingPage, error) {
+	params := gen.GetTrainingsFilteredParams{
+		UserID:      userID,
+		PlannedFrom: null.TimeFromPtr(filter.PlannedFrom).NullTime,
+		PlannedTo:   null.TimeFromPtr(filter.PlannedTo).NullTime,
+		IsDone:      null.BoolFromPtr(filter.IsDone).NullBool,
+		MinRating:   null.Int32FromPtr(filter.MinRating).NullInt32,
+		MaxRating:   null.Int32FromPtr(filter.MaxRating).NullInt32,
+		SortBy:      string(filter.SortBy),
+		SortDesc:    filter.SortDesc,
+		// Лишняя запись показывает, что есть следующая страница
+		PageLimit: filter.Limit + 1,
+	}
+	if filter.Search != nil {
+		params.Search = sql.NullString{String: *filter.Search, Valid: true}
+	}
+	if filter.Cursor != nil {
+		params.CursorKey = sql.NullInt64{Int64: filter.Cursor.Key, Valid: true}
+		params.CursorID = sql.NullInt64{Int64: filter.Cursor.ID, Valid: true}
+	}
+
+	rows, err := r.q.GetTrainingsFiltered(ctx, params)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+			"sort_by": filter.SortBy,
+			"limit":   filter.Limit,
+		})
+		logging.Error(err, "GetTrainingsFiltered", jsonData, "failed to get filtered trainings")
+		return nil, err
+	}
+
+	page := &domain.TrainingPage{}
+	if int32(len(rows)) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = &domain.TrainingCursor{
+			SortBy:   filter.SortBy,
+			SortDesc: filter.SortDesc,
+			Key:      last.SortKey,
+			ID:       last.ID,
+		}
+	}
+
+	page.Trainings = make([]*domain.Training, len(rows))
+	for i, t := range rows {
+		page.Trainings[i] = r.toDomainTraining(gen.GetTrainingsByUserRow{
+			ID:                t.ID,
+			Title:             t.Title,
+			UserID:            t.UserID,
+			IsDone:            t.IsDone,
+			PlannedDate:       t.PlannedDate,
+			ActualDate:        t.ActualDate,
+			StartedAt:         t.StartedAt,
+			FinishedAt:        t.FinishedAt,
+			TotalDuration:     t.TotalDuration,
+			TotalRestTime:     t.TotalRestTime,
+			TotalExerciseTime: t.TotalExerciseTime,
+			Rating:            t.Rating,
+			Status:            t.Status,
+			IsPaused:          t.IsPaused,
+		})
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":         userID.String(),
+		"trainings_count": len(page.Trainings),
+		"has_next":        page.NextCursor != nil,
+	})
+	logging.Debug("GetTrainingsFiltered", jsonData, "successfully retrieved filtered trainings")
+
+	return page, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainingWithExercises(ctx context.Context, trainingID int64, userID uuid.UUID) (*domain.Training, error) {
+	training, err := r.q.GetTrainingWithExercises(ctx, gen.GetTrainingWithExercisesParams{
+		ID:     trainingID,
+		UserID: userID,
+	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": trainingID,
+			"user_id":     userID.String(),
 		})
 		logging.Error(err, "GetTrainingWithExercises", jsonData, "failed to get training with exercises")
 		return nil, err
@@ -90,6 +168,7 @@ func (r *TrainingRepositoryImpl) CreateTraining(ctx context.Context, training *d
 		TotalRestTime:     durationToNullInt64(training.TotalRestTime),
 		TotalExerciseTime: durationToNullInt64(training.TotalExerciseTime),
 		Rating:            null.Int32FromPtr(training.Rating).NullInt32,
+		Status:            string(training.Status),
 	}
 
 	created, err := r.q.CreateTraining(ctx, params)
@@ -115,6 +194,7 @@ func (r *TrainingRepositoryImpl) CreateTraining(ctx context.Context, training *d
 		TotalRestTime:     created.TotalRestTime,
 		TotalExerciseTime: created.TotalExerciseTime,
 		Rating:            created.Rating,
+		Status:            created.Status,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -128,24 +208,20 @@ func (r *TrainingRepositoryImpl) CreateTraining(ctx context.Context, training *d
 
 func (r *TrainingRepositoryImpl) UpdateTraining(ctx context.Context, training *domain.Training) (*domain.Training, error) {
 	params := gen.UpdateTrainingParams{
-		IsDone:            training.IsDone,
 		PlannedDate:       training.PlannedDate,
-		ActualDate:        null.TimeFromPtr(training.ActualDate).NullTime,
-		StartedAt:         null.TimeFromPtr(training.StartedAt).NullTime,
-		FinishedAt:        null.TimeFromPtr(training.FinishedAt).NullTime,
 		TotalDuration:     durationToNullInt64(training.TotalDuration),
 		TotalRestTime:     durationToNullInt64(training.TotalRestTime),
 		TotalExerciseTime: durationToNullInt64(training.TotalExerciseTime),
 		Rating:            null.Int32FromPtr(training.Rating).NullInt32,
 		ID:                training.ID,
 		Title:             training.Title,
+		UserID:            training.UserID,
 	}
 
 	updated, err := r.q.UpdateTraining(ctx, params)
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": training.ID,
-			"is_done":     training.IsDone,
 			"rating":      training.Rating,
 		})
 		logging.Error(err, "UpdateTraining", jsonData, "failed to update training")
@@ -154,6 +230,7 @@ func (r *TrainingRepositoryImpl) UpdateTraining(ctx context.Context, training *d
 
 	domainTraining := r.toDomainTraining(gen.GetTrainingsByUserRow{
 		ID:                updated.ID,
+		Title:             updated.Title,
 		UserID:            updated.UserID,
 		IsDone:            updated.IsDone,
 		PlannedDate:       updated.PlannedDate,
@@ -164,6 +241,7 @@ func (r *TrainingRepositoryImpl) UpdateTraining(ctx context.Context, training *d
 		TotalRestTime:     updated.TotalRestTime,
 		TotalExerciseTime: updated.TotalExerciseTime,
 		Rating:            updated.Rating,
+		Status:            updated.Status,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -174,11 +252,15 @@ func (r *TrainingRepositoryImpl) UpdateTraining(ctx context.Context, training *d
 	return domainTraining, nil
 }
 
-func (r *TrainingRepositoryImpl) DeleteTrainingAndExercises(ctx context.Context, trainingID int64) error {
-	err := r.q.DeleteTrainingAndExercises(ctx, trainingID)
+func (r *TrainingRepositoryImpl) DeleteTrainingAndExercises(ctx context.Context, trainingID int64, userID uuid.UUID) error {
+	err := r.q.DeleteTrainingAndExercises(ctx, gen.DeleteTrainingAndExercisesParams{
+		ID:     trainingID,
+		UserID: userID,
+	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": trainingID,
+			"user_id":     userID.String(),
 		})
 		logging.Error(err, "DeleteTrainingAndExercises", jsonData, "failed to delete training and exercises")
 		return err
@@ -192,20 +274,63 @@ func (r *TrainingRepositoryImpl) DeleteTrainingAndExercises(ctx context.Context,
 	return nil
 }
 
-func (r *TrainingRepositoryImpl) AddExerciseToTraining(ctx context.Context, exercise *domain.TrainedExercise) (*domain.TrainedExercise, error) {
-	weight := exercise.Weight.String()
-	params := gen.AddExerciseToTrainingParams{
+// addExerciseToTrainingParams - параметры добавления упражнения. Вес не задан у кардио-упражнений
+// и у подсказки без истории, поэтому nil сохраняется как NULL
+func addExerciseToTrainingParams(exercise *domain.TrainedExercise, userID uuid.UUID) gen.AddExerciseToTrainingParams {
+	groupID, groupType := exerciseGroupToSQL(exercise.Group)
+	return gen.AddExerciseToTrainingParams{
 		TrainingID: exercise.TrainingID,
 		ExerciseID: exercise.ExerciseID,
-		Weight:     null.StringFromPtr(&weight).NullString,
+		Weight:     decimalToNullString(exercise.Weight),
+		Approaches: null.Int32FromPtr(exercise.Approaches).NullInt32,
+		Reps:       null.Int32FromPtr(exercise.Reps).NullInt32,
+		Time:       durationToNullInt64(exercise.Time),
+		Doing:      durationToNullInt64(exercise.Doing),
+		Rest:       durationToNullInt64(exercise.Rest),
+		Notes:      null.StringFromPtr(exercise.Notes).NullString,
+		UserID:     userID,
+		GroupID:    groupID,
+		GroupType:  groupType,
+	}
+}
+
+func updateTrainedExerciseParams(exercise *domain.TrainedExercise, userID uuid.UUID) gen.UpdateTrainedExerciseParams {
+	return gen.UpdateTrainedExerciseParams{
+		Weight:     decimalToNullString(exercise.Weight),
 		Approaches: null.Int32FromPtr(exercise.Approaches).NullInt32,
 		Reps:       null.Int32FromPtr(exercise.Reps).NullInt32,
 		Time:       durationToNullInt64(exercise.Time),
 		Doing:      durationToNullInt64(exercise.Doing),
 		Rest:       durationToNullInt64(exercise.Rest),
 		Notes:      null.StringFromPtr(exercise.Notes).NullString,
+		ID:         exercise.ID,
+		UserID:     userID,
+	}
+}
+
+func (r *TrainingRepositoryImpl) AddExerciseToTraining(ctx context.Context, exercise *domain.TrainedExercise, userID uuid.UUID) (*domain.TrainedExercise, error) {
+	params := addExerciseToTrainingParams(exercise, userID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "AddExerciseToTraining", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	// Позиция считается как MAX(position) + 1, поэтому параллельные добавления в одну
+	// тренировку ждут друг друга, а не падают на уникальности (training_id, position)
+	if _, err := q.LockTraining(ctx, gen.LockTrainingParams{ID: exercise.TrainingID, UserID: userID}); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": exercise.TrainingID,
+		})
+		logging.Error(err, "AddExerciseToTraining", jsonData, "failed to lock training")
+		return nil, err
 	}
-	created, err := r.q.AddExerciseToTraining(ctx, params)
+
+	created, err := q.AddExerciseToTraining(ctx, params)
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": exercise.TrainingID,
@@ -214,8 +339,17 @@ func (r *TrainingRepositoryImpl) AddExerciseToTraining(ctx context.Context, exer
 		logging.Error(err, "AddExerciseToTraining", jsonData, "failed to add exercise to training")
 		return nil, err
 	}
+	if err := saveCardio(ctx, q, created.ID, exercise.Cardio); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "AddExerciseToTraining", nil, "failed to commit transaction")
+		return nil, err
+	}
 
 	domainExercise := r.toDomainTrainedExercise(created)
+	domainExercise.Cardio = exercise.Cardio
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
 		"trained_exercise_id": domainExercise.ID,
@@ -227,20 +361,19 @@ func (r *TrainingRepositoryImpl) AddExerciseToTraining(ctx context.Context, exer
 	return domainExercise, nil
 }
 
-func (r *TrainingRepositoryImpl) UpdateTrainedExercise(ctx context.Context, exercise *domain.TrainedExercise) (*domain.TrainedExercise, error) {
-	weight := exercise.Weight.String()
-	params := gen.UpdateTrainedExerciseParams{
-		Weight:     null.StringFromPtr(&weight).NullString,
-		Approaches: null.Int32FromPtr(exercise.Approaches).NullInt32,
-		Reps:       null.Int32FromPtr(exercise.Reps).NullInt32,
-		Time:       durationToNullInt64(exercise.Time),
-		Doing:      durationToNullInt64(exercise.Doing),
-		Rest:       durationToNullInt64(exercise.Rest),
-		Notes:      null.StringFromPtr(exercise.Notes).NullString,
-		ID:         exercise.ID,
+func (r *TrainingRepositoryImpl) UpdateTrainedExercise(ctx context.Context, exercise *domain.TrainedExercise, userID uuid.UUID) (*domain.TrainedExercise, error) {
+	params := updateTrainedExerciseParams(exercise, userID)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "UpdateTrainedExercise", nil, "failed to begin transaction")
+		return nil, err
 	}
+	defer tx.Rollback()
 
-	updated, err := r.q.UpdateTrainedExercise(ctx, params)
+	q := r.q.WithTx(tx)
+
+	updated, err := q.UpdateTrainedExercise(ctx, params)
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"trained_exercise_id": exercise.ID,
@@ -249,6 +382,14 @@ func (r *TrainingRepositoryImpl) UpdateTrainedExercise(ctx context.Context, exer
 		logging.Error(err, "UpdateTrainedExercise", jsonData, "failed to update trained exercise")
 		return nil, err
 	}
+	if err := saveCardio(ctx, q, updated.ID, exercise.Cardio); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "UpdateTrainedExercise", nil, "failed to commit transaction")
+		return nil, err
+	}
 
 	domainExercise := r.toDomainTrainedExercise(gen.AddExerciseToTrainingRow{
 		ID:         updated.ID,
@@ -261,7 +402,11 @@ func (r *TrainingRepositoryImpl) UpdateTrainedExercise(ctx context.Context, exer
 		Doing:      updated.Doing,
 		Rest:       updated.Rest,
 		Notes:      updated.Notes,
+		Position:   updated.Position,
+		GroupID:    updated.GroupID,
+		GroupType:  updated.GroupType,
 	})
+	domainExercise.Cardio = exercise.Cardio
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
 		"trained_exercise_id": domainExercise.ID,
@@ -271,10 +416,20 @@ func (r *TrainingRepositoryImpl) UpdateTrainedExercise(ctx context.Context, exer
 	return domainExercise, nil
 }
 
-func (r *TrainingRepositoryImpl) DeleteExerciseFromTraining(ctx context.Context, exerciseID, trainingID int64) error {
-	err := r.q.DeleteExerciseFromTraining(ctx, gen.DeleteExerciseFromTrainingParams{
+func (r *TrainingRepositoryImpl) DeleteExerciseFromTraining(ctx context.Context, exerciseID, trainingID int64, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "DeleteExerciseFromTraining", nil, "failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	err = q.DeleteExerciseFromTraining(ctx, gen.DeleteExerciseFromTrainingParams{
 		ID:         exerciseID,
 		TrainingID: trainingID,
+		UserID:     userID,
 	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -285,6 +440,20 @@ func (r *TrainingRepositoryImpl) DeleteExerciseFromTraining(ctx context.Context,
 		return err
 	}
 
+	// Оставшиеся упражнения нумеруются заново без пропусков
+	if err := q.RenumberTrainedExercises(ctx, trainingID); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": trainingID,
+		})
+		logging.Error(err, "DeleteExerciseFromTraining", jsonData, "failed to renumber trained exercises")
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.Error(err, "DeleteExerciseFromTraining", nil, "failed to commit transaction")
+		return err
+	}
+
 	jsonData := logging.MarshalLogData(map[string]interface{}{
 		"trained_exercise_id": exerciseID,
 		"training_id":         trainingID,
@@ -294,46 +463,94 @@ func (r *TrainingRepositoryImpl) DeleteExerciseFromTraining(ctx context.Context,
 	return nil
 }
 
-func (r *TrainingRepositoryImpl) GetUserTrainingStats(ctx context.Context, userID uuid.UUID) (*domain.TrainingStats, error) {
-	trainings, err := r.GetTrainingsByUser(ctx, userID)
+func (r *TrainingRepositoryImpl) GetUserTrainingStats(ctx context.Context, userID uuid.UUID, query domain.UserStatsQuery) (*domain.UserTrainingStats, error) {
+	totalsRow, err := r.q.GetUserTrainingStatsTotals(ctx, gen.GetUserTrainingStatsTotalsParams{
+		UserID:   userID,
+		FromDate: query.From,
+		ToDate:   query.To,
+	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"user_id": userID.String(),
+			"from":    query.From,
+			"to":      query.To,
 		})
 		logging.Error(err, "GetUserTrainingStats", jsonData, "failed to get user training stats")
 		return nil, err
 	}
 
-	stats := &domain.TrainingStats{}
-	var totalRating int64
-	var completedCount int
-
-	for _, t := range trainings {
-		stats.TotalTrainings++
-		if t.IsDone {
-			completedCount++
-			if t.Rating != nil {
-				totalRating += int64(*t.Rating)
-			}
-		}
+	periodRows, err := r.q.GetUserTrainingStatsByPeriod(ctx, gen.GetUserTrainingStatsByPeriodParams{
+		UserID:   userID,
+		FromDate: query.From,
+		ToDate:   query.To,
+		GroupBy:  string(query.GroupBy),
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id":  userID.String(),
+			"from":     query.From,
+			"to":       query.To,
+			"group_by": query.GroupBy,
+		})
+		logging.Error(err, "GetUserTrainingStats", jsonData, "failed to get user training stats by period")
+		return nil, err
 	}
 
-	stats.CompletedTrainings = int64(completedCount)
-	if completedCount > 0 {
-		stats.AverageRating = float64(totalRating) / float64(completedCount)
+	stats := &domain.UserTrainingStats{
+		From:    query.From,
+		To:      query.To,
+		GroupBy: query.GroupBy,
+		Totals: toDomainTrainingStats(gen.GetUserTrainingStatsByPeriodRow{
+			TotalTrainings:     totalsRow.TotalTrainings,
+			CompletedTrainings: totalsRow.CompletedTrainings,
+			SkippedTrainings:   totalsRow.SkippedTrainings,
+			AverageRating:      totalsRow.AverageRating,
+			TotalDuration:      totalsRow.TotalDuration,
+			TotalRestTime:      totalsRow.TotalRestTime,
+			TotalExerciseTime:  totalsRow.TotalExerciseTime,
+			TotalVolume:        totalsRow.TotalVolume,
+			TotalDistance:      totalsRow.TotalDistance,
+			PacedDistance:      totalsRow.PacedDistance,
+			PacedTime:          totalsRow.PacedTime,
+			TotalCalories:      totalsRow.TotalCalories,
+		}),
+		Buckets: make([]domain.TrainingStatsBucket, len(periodRows)),
+	}
+	for i, row := range periodRows {
+		stats.Buckets[i] = domain.TrainingStatsBucket{
+			PeriodStart:   row.PeriodStart,
+			TrainingStats: toDomainTrainingStats(row),
+		}
 	}
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
 		"user_id":             userID.String(),
-		"total_trainings":     stats.TotalTrainings,
-		"completed_trainings": stats.CompletedTrainings,
-		"average_rating":      stats.AverageRating,
+		"total_trainings":     stats.Totals.TotalTrainings,
+		"completed_trainings": stats.Totals.CompletedTrainings,
+		"buckets_count":       len(stats.Buckets),
 	})
 	logging.Debug("GetUserTrainingStats", jsonData, "successfully calculated user training stats")
 
 	return stats, nil
 }
 
+func toDomainTrainingStats(row gen.GetUserTrainingStatsByPeriodRow) domain.TrainingStats {
+	totalVolume, _ := decimal.NewFromString(row.TotalVolume)
+	stats := domain.TrainingStats{
+		TotalTrainings:     row.TotalTrainings,
+		CompletedTrainings: row.CompletedTrainings,
+		SkippedTrainings:   row.SkippedTrainings,
+		CompletionRate:     domain.CompletionRate(row.CompletedTrainings, row.TotalTrainings),
+		AverageRating:      row.AverageRating,
+		TotalDuration:      time.Duration(row.TotalDuration) * time.Second,
+		TotalRestTime:      time.Duration(row.TotalRestTime) * time.Second,
+		TotalExerciseTime:  time.Duration(row.TotalExerciseTime) * time.Second,
+		TotalVolume:        totalVolume,
+	}
+	setCardioStats(&stats, row.TotalDistance, row.PacedDistance, row.PacedTime, row.TotalCalories)
+	return stats
+}
+
 func (r *TrainingRepositoryImpl) toDomainTraining(t gen.GetTrainingsByUserRow) *domain.Training {
 	return &domain.Training{
 		ID:                t.ID,
@@ -348,6 +565,8 @@ func (r *TrainingRepositoryImpl) toDomainTraining(t gen.GetTrainingsByUserRow) *
 		TotalRestTime:     toDuration(t.TotalRestTime),
 		TotalExerciseTime: toDuration(t.TotalExerciseTime),
 		Rating:            nullIntFromSQL32(t.Rating),
+		Status:            domain.TrainingStatus(t.Status),
+		IsPaused:          t.IsPaused,
 	}
 }
 
@@ -365,17 +584,20 @@ func (r *TrainingRepositoryImpl) toDomainTrainingFromJoined(t gen.GetTrainingWit
 		TotalRestTime:     toDuration(t.TotalRestTime),
 		TotalExerciseTime: toDuration(t.TotalExerciseTime),
 		Rating:            nullIntFromSQL32(t.Rating),
+		Status:            domain.TrainingStatus(t.Status),
+		IsPaused:          t.IsPaused,
 		Exercises:         toDomainTrainedExercise(t.Exercises),
 	}
 	return training
 }
 
-func (r *TrainingRepositoryImpl) UpdateExerciseTime(ctx context.Context, exercise *domain.TrainedExercise) (*domain.TrainedExercise, error) {
+func (r *TrainingRepositoryImpl) UpdateExerciseTime(ctx context.Context, exercise *domain.TrainedExercise, userID uuid.UUID) (*domain.TrainedExercise, error) {
 	params := gen.UpdateExerciseTimeParams{
 		Doing:      durationToNullInt64(exercise.Doing),
 		Rest:       durationToNullInt64(exercise.Rest),
 		Time:       durationToNullInt64(exercise.Time),
 		ID:         exercise.ID,
+		UserID:     userID,
 	}
 
 	updated, err := r.q.UpdateExerciseTime(ctx, params)
@@ -398,6 +620,9 @@ func (r *TrainingRepositoryImpl) UpdateExerciseTime(ctx context.Context, exercis
 		Doing:      updated.Doing,
 		Rest:       updated.Rest,
 		Notes:      updated.Notes,
+		Position:   updated.Position,
+		GroupID:    updated.GroupID,
+		GroupType:  updated.GroupType,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -417,6 +642,7 @@ func (r *TrainingRepositoryImpl) UpdateTrainingTimers(ctx context.Context, train
 		TotalRestTime:     durationToNullInt64(training.TotalRestTime),
 		TotalExerciseTime: durationToNullInt64(training.TotalExerciseTime),
 		ID:                training.ID,
+		UserID:            training.UserID,
 	}
 
 	updated, err := r.q.UpdateTrainingTimers(ctx, params)
@@ -440,6 +666,7 @@ func (r *TrainingRepositoryImpl) UpdateTrainingTimers(ctx context.Context, train
 		TotalRestTime:     updated.TotalRestTime,
 		TotalExerciseTime: updated.TotalExerciseTime,
 		Rating:            updated.Rating,
+		Status:            updated.Status,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -450,8 +677,11 @@ func (r *TrainingRepositoryImpl) UpdateTrainingTimers(ctx context.Context, train
 	return domainTraining, nil
 }
 
-func (r *TrainingRepositoryImpl) GetCurrentTraining(ctx context.Context, userID uuid.UUID) (*domain.Training, error) {
-	t, err := r.q.GetCurrentTraining(ctx, userID)
+func (r *TrainingRepositoryImpl) GetCurrentTraining(ctx context.Context, userID uuid.UUID, today time.Time) (*domain.Training, error) {
+	t, err := r.q.GetCurrentTraining(ctx, gen.GetCurrentTrainingParams{
+		UserID:      userID,
+		PlannedDate: today,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -459,6 +689,7 @@ func (r *TrainingRepositoryImpl) GetCurrentTraining(ctx context.Context, userID
 
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"user_id": userID.String(),
+			"today":   today,
 		})
 		logging.Error(err, "GetCurrentTraining", jsonData, "failed to get current training")
 		return nil, err
@@ -477,6 +708,8 @@ func (r *TrainingRepositoryImpl) GetCurrentTraining(ctx context.Context, userID
 		TotalRestTime:     t.TotalRestTime,
 		TotalExerciseTime: t.TotalExerciseTime,
 		Rating:            t.Rating,
+		Status:            t.Status,
+		IsPaused:          t.IsPaused,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -489,11 +722,15 @@ func (r *TrainingRepositoryImpl) GetCurrentTraining(ctx context.Context, userID
 	return domainTraining, nil
 }
 
-func (r *TrainingRepositoryImpl) GetTodaysTraining(ctx context.Context, userID uuid.UUID) ([]*domain.Training, error) {
-	trainingRows, err := r.q.GetTodaysTraining(ctx, userID)
+func (r *TrainingRepositoryImpl) GetTodaysTraining(ctx context.Context, userID uuid.UUID, today time.Time) ([]*domain.Training, error) {
+	trainingRows, err := r.q.GetTodaysTraining(ctx, gen.GetTodaysTrainingParams{
+		UserID:      userID,
+		PlannedDate: today,
+	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"user_id": userID.String(),
+			"today":   today,
 		})
 		logging.Error(err, "GetTodaysTraining", jsonData, "failed to get today's training")
 		return nil, err
@@ -514,6 +751,8 @@ func (r *TrainingRepositoryImpl) GetTodaysTraining(ctx context.Context, userID u
 			TotalRestTime:     t.TotalRestTime,
 			TotalExerciseTime: t.TotalExerciseTime,
 			Rating:            t.Rating,
+			Status:            t.Status,
+			IsPaused:          t.IsPaused,
 		})
 	}
 
@@ -605,9 +844,10 @@ func (r *TrainingRepositoryImpl) GetGlobalTrainingByLevel(ctx context.Context, l
 	return globalTrainings, nil
 }
 
-func (r *TrainingRepositoryImpl) MarkTrainingAsDone(ctx context.Context, trainingID int64, userID uuid.UUID) (*domain.Training, error) {
+func (r *TrainingRepositoryImpl) MarkTrainingAsDone(ctx context.Context, trainingID int64, userID uuid.UUID, actualDate time.Time) (*domain.Training, error) {
 	finishedAt := null.TimeFromPtr(nil)
 	params := gen.MarkTrainingAsDoneParams{
+		ActualDate: null.TimeFrom(actualDate).NullTime,
 		FinishedAt: finishedAt.NullTime,
 		ID:         trainingID,
 		UserID:     userID,
@@ -636,6 +876,7 @@ func (r *TrainingRepositoryImpl) MarkTrainingAsDone(ctx context.Context, trainin
 		TotalRestTime:     updated.TotalRestTime,
 		TotalExerciseTime: updated.TotalExerciseTime,
 		Rating:            updated.Rating,
+		Status:            updated.Status,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -646,8 +887,55 @@ func (r *TrainingRepositoryImpl) MarkTrainingAsDone(ctx context.Context, trainin
 	return domainTraining, nil
 }
 
-func (r *TrainingRepositoryImpl) GetTrainingStats(ctx context.Context, trainingID int64) (*domain.TrainingStats, error) {
-	statsRow, err := r.q.GetTrainingStats(ctx, trainingID)
+// CompleteTraining завершает тренировку, если она еще не завершена, не пропущена и не отменена.
+// Если статус успел измениться, возвращается sql.ErrNoRows
+func (r *TrainingRepositoryImpl) CompleteTraining(ctx context.Context, trainingID int64, userID uuid.UUID, actualDate, finishedAt time.Time, rating *int32, totalDuration *time.Duration) (*domain.Training, error) {
+	updated, err := r.q.CompleteTraining(ctx, gen.CompleteTrainingParams{
+		ActualDate:    null.TimeFrom(actualDate).NullTime,
+		FinishedAt:    null.TimeFrom(finishedAt).NullTime,
+		Rating:        null.Int32FromPtr(rating).NullInt32,
+		TotalDuration: durationToNullInt64(totalDuration),
+		ID:            trainingID,
+		UserID:        userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": trainingID,
+			"user_id":     userID.String(),
+		})
+		logging.Error(err, "CompleteTraining", jsonData, "failed to complete training")
+		return nil, err
+	}
+
+	domainTraining := r.toDomainTraining(gen.GetTrainingsByUserRow{
+		ID:                updated.ID,
+		Title:             updated.Title,
+		UserID:            updated.UserID,
+		IsDone:            updated.IsDone,
+		PlannedDate:       updated.PlannedDate,
+		ActualDate:        updated.ActualDate,
+		StartedAt:         updated.StartedAt,
+		FinishedAt:        updated.FinishedAt,
+		TotalDuration:     updated.TotalDuration,
+		TotalRestTime:     updated.TotalRestTime,
+		TotalExerciseTime: updated.TotalExerciseTime,
+		Rating:            updated.Rating,
+		Status:            updated.Status,
+	})
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"training_id": domainTraining.ID,
+	})
+	logging.Debug("CompleteTraining", jsonData, "successfully completed training")
+
+	return domainTraining, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainingStats(ctx context.Context, trainingID int64, userID uuid.UUID) (*domain.TrainingStats, error) {
+	statsRow, err := r.q.GetTrainingStats(ctx, gen.GetTrainingStatsParams{
+		ID:     trainingID,
+		UserID: userID,
+	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": trainingID,
@@ -656,7 +944,7 @@ func (r *TrainingRepositoryImpl) GetTrainingStats(ctx context.Context, trainingI
 		return nil, err
 	}
 
-	training, err := r.GetTrainingWithExercises(ctx, trainingID)
+	training, err := r.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -666,22 +954,41 @@ func (r *TrainingRepositoryImpl) GetTrainingStats(ctx context.Context, trainingI
 		totalDuration = *training.TotalDuration
 	}
 
+	var averageRating float64
+	if training.Rating != nil {
+		averageRating = float64(*training.Rating)
+	}
+
+	totalVolume, _ := decimal.NewFromString(statsRow.TotalVolume)
+
 	stats := &domain.TrainingStats{
 		TotalTrainings:     1,
 		CompletedTrainings: 0,
-		AverageRating:      float64(*training.Rating),
+		AverageRating:      averageRating,
 		TotalDuration:      totalDuration,
+		TotalApproaches:    statsRow.TotalApproaches,
+		TotalReps:          statsRow.TotalReps,
+		TotalVolume:        totalVolume,
 	}
 
+	if training.TotalRestTime != nil {
+		stats.TotalRestTime = *training.TotalRestTime
+	}
+	if training.TotalExerciseTime != nil {
+		stats.TotalExerciseTime = *training.TotalExerciseTime
+	}
 	if training.IsDone {
 		stats.CompletedTrainings = 1
+		stats.CompletionRate = 1
 	}
+	setCardioStats(stats, statsRow.TotalDistance, statsRow.PacedDistance, statsRow.PacedTime, statsRow.TotalCalories)
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
 		"training_id":        trainingID,
 		"exercise_count":     statsRow.ExerciseCount,
 		"total_approaches":   statsRow.TotalApproaches,
 		"total_reps":         statsRow.TotalReps,
+		"total_volume":       statsRow.TotalVolume,
 		"total_duration_sec": totalDuration.Seconds(),
 	})
 	logging.Debug("GetTrainingStats", jsonData, "successfully retrieved training stats")
@@ -720,6 +1027,7 @@ func (r *TrainingRepositoryImpl) StartTraining(ctx context.Context, trainingID i
 		TotalRestTime:     updated.TotalRestTime,
 		TotalExerciseTime: updated.TotalExerciseTime,
 		Rating:            updated.Rating,
+		Status:            updated.Status,
 	})
 
 	jsonData := logging.MarshalLogData(map[string]interface{}{
@@ -731,6 +1039,67 @@ func (r *TrainingRepositoryImpl) StartTraining(ctx context.Context, trainingID i
 	return domainTraining, nil
 }
 
+func (r *TrainingRepositoryImpl) PauseTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (bool, error) {
+	affected, err := r.q.StartTrainingPause(ctx, gen.StartTrainingPauseParams{
+		ID:     trainingID,
+		UserID: userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": trainingID,
+			"user_id":     userID.String(),
+		})
+		logging.Error(err, "PauseTraining", jsonData, "failed to pause training")
+		return false, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"training_id": trainingID,
+		"paused":      affected > 0,
+	})
+	logging.Debug("PauseTraining", jsonData, "pause training finished")
+
+	return affected > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) ResumeTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (bool, error) {
+	affected, err := r.q.EndTrainingPause(ctx, gen.EndTrainingPauseParams{
+		ID:     trainingID,
+		UserID: userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": trainingID,
+			"user_id":     userID.String(),
+		})
+		logging.Error(err, "ResumeTraining", jsonData, "failed to resume training")
+		return false, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"training_id": trainingID,
+		"resumed":     affected > 0,
+	})
+	logging.Debug("ResumeTraining", jsonData, "resume training finished")
+
+	return affected > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainingPausedTime(ctx context.Context, trainingID int64, userID uuid.UUID) (time.Duration, error) {
+	micros, err := r.q.GetTrainingPausedTime(ctx, gen.GetTrainingPausedTimeParams{
+		TrainingID: trainingID,
+		UserID:     userID,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": trainingID,
+		})
+		logging.Error(err, "GetTrainingPausedTime", jsonData, "failed to get training paused time")
+		return 0, err
+	}
+	return time.Duration(micros) * time.Microsecond, nil
+}
+
 func (r *TrainingRepositoryImpl) toDomainTrainedExercise(ex gen.AddExerciseToTrainingRow) *domain.TrainedExercise {
 	weight, _ := decimal.NewFromString(ex.Weight.String)
 	return &domain.TrainedExercise{
@@ -744,11 +1113,16 @@ func (r *TrainingRepositoryImpl) toDomainTrainedExercise(ex gen.AddExerciseToTra
 		Doing:      toDuration(ex.Doing),
 		Rest:       toDuration(ex.Rest),
 		Notes:      nullStringFromSQL(ex.Notes),
+		Position:   ex.Position,
+		Group:      toDomainExerciseGroup(ex.GroupID, ex.GroupType),
 	}
 }
 
-func (r *TrainingRepositoryImpl) CalculateTrainingTotalTime(ctx context.Context, trainingID int64) (*domain.TrainingTime, error) {
-	timeStats, err := r.q.CalculateTrainingTotalTime(ctx, trainingID)
+func (r *TrainingRepositoryImpl) CalculateTrainingTotalTime(ctx context.Context, trainingID int64, userID uuid.UUID) (*domain.TrainingTime, error) {
+	timeStats, err := r.q.CalculateTrainingTotalTime(ctx, gen.CalculateTrainingTotalTimeParams{
+		TrainingID: trainingID,
+		UserID:     userID,
+	})
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": trainingID,
@@ -807,7 +1181,7 @@ func (r *TrainingRepositoryImpl) toDomainGlobalTraining(gt GlobalTrainingRow) *d
 		Title:       gt.Title,
 		Description: gt.Description,
 		Level:       gt.Level,
-		Exercises:   toDomainExercise(gt.Exercises),
+		Exercises:   toDomainGlobalTrainingExercises(gt.Exercises),
 	}
 }
 
@@ -852,6 +1226,7 @@ func (r *TrainingRepositoryImpl) AssignGlobalTrainingToUser(ctx context.Context,
 		StartedAt:  null.TimeFromPtr(nil).NullTime,
 		FinishedAt: null.TimeFromPtr(nil).NullTime,
 		Rating:     null.Int32FromPtr(nil).NullInt32,
+		Status:     string(domain.TrainingStatusPlanned),
 	}
 
 	createdTraining, err := q.CreateTraining(ctx, trainingParams)
@@ -875,18 +1250,22 @@ func (r *TrainingRepositoryImpl) AssignGlobalTrainingToUser(ctx context.Context,
 		return nil, err
 	}
 
-	// 4. Добавляем упражнения в пользовательскую тренировку
+	// 4. Добавляем упражнения в пользовательскую тренировку в порядке слотов,
+	// перенося предписанную нагрузку как план
 	for _, globalExercise := range globalExercises {
 		exerciseParams := gen.AddExerciseToTrainingParams{
 			TrainingID: createdTraining.ID,
 			ExerciseID: globalExercise.ExerciseID,
-			Weight:     null.StringFromPtr(nil).NullString,
-			Approaches: null.Int32FromPtr(nil).NullInt32,
-			Reps:       null.Int32FromPtr(nil).NullInt32,
+			Weight:     globalExercise.Weight,
+			Approaches: globalExercise.Approaches,
+			Reps:       globalExercise.Reps,
 			Time:       sql.NullInt64{Valid: false},
-			Doing:      sql.NullInt64{Valid: false},
-			Rest:       sql.NullInt64{Valid: false},
+			Doing:      globalExercise.Duration,
+			Rest:       globalExercise.Rest,
 			Notes:      null.StringFromPtr(nil).NullString,
+			UserID:     cmd.UserID,
+			GroupID:    globalExercise.GroupID,
+			GroupType:  globalExercise.GroupType,
 		}
 
 		_, err := q.AddExerciseToTraining(ctx, exerciseParams)
@@ -912,7 +1291,7 @@ func (r *TrainingRepositoryImpl) AssignGlobalTrainingToUser(ctx context.Context,
 	}
 
 	// 6. Получаем полную информацию о созданной тренировке
-	fullTraining, err := r.GetTrainingWithExercises(ctx, createdTraining.ID)
+	fullTraining, err := r.GetTrainingWithExercises(ctx, createdTraining.ID, cmd.UserID)
 	if err != nil {
 		jsonData := logging.MarshalLogData(map[string]interface{}{
 			"training_id": createdTraining.ID,
@@ -934,3 +1313,205 @@ func (r *TrainingRepositoryImpl) AssignGlobalTrainingToUser(ctx context.Context,
 
 	return fullTraining, nil
 }
+
+func (r *TrainingRepositoryImpl) CreateGlobalTraining(ctx context.Context, cmd domain.GlobalTrainingCmd) (*domain.GlobalTraining, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "CreateGlobalTraining", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	created, err := q.CreateGlobalTraining(ctx, gen.CreateGlobalTrainingParams{
+		Title:       cmd.Title,
+		Description: cmd.Description,
+		Level:       cmd.Level,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"title": cmd.Title,
+			"level": cmd.Level,
+		})
+		logging.Error(err, "CreateGlobalTraining", jsonData, "failed to create global training")
+		return nil, err
+	}
+
+	if err := addGlobalTrainingExercises(ctx, q, created.ID, cmd.Exercises); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"global_training_id": created.ID,
+		})
+		logging.Error(err, "CreateGlobalTraining", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"global_training_id": created.ID,
+		"exercises_count":    len(cmd.Exercises),
+	})
+	logging.Info("CreateGlobalTraining", jsonData, "global training created")
+
+	return r.GetGlobalTrainingById(ctx, created.ID)
+}
+
+func (r *TrainingRepositoryImpl) UpdateGlobalTraining(ctx context.Context, id int64, cmd domain.GlobalTrainingCmd) (*domain.GlobalTraining, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logging.Error(err, "UpdateGlobalTraining", nil, "failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := r.q.WithTx(tx)
+
+	affected, err := q.UpdateGlobalTraining(ctx, gen.UpdateGlobalTrainingParams{
+		Title:       cmd.Title,
+		Description: cmd.Description,
+		Level:       cmd.Level,
+		ID:          id,
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"global_training_id": id,
+		})
+		logging.Error(err, "UpdateGlobalTraining", jsonData, "failed to update global training")
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	// Слоты заменяются целиком: так порядок и нагрузка всегда совпадают с запросом
+	if err := q.DeleteGlobalTrainingExercises(ctx, id); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"global_training_id": id,
+		})
+		logging.Error(err, "UpdateGlobalTraining", jsonData, "failed to delete global training exercises")
+		return nil, err
+	}
+
+	if err := addGlobalTrainingExercises(ctx, q, id, cmd.Exercises); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"global_training_id": id,
+		})
+		logging.Error(err, "UpdateGlobalTraining", jsonData, "failed to commit transaction")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"global_training_id": id,
+		"exercises_count":    len(cmd.Exercises),
+	})
+	logging.Info("UpdateGlobalTraining", jsonData, "global training updated")
+
+	return r.GetGlobalTrainingById(ctx, id)
+}
+
+// addGlobalTrainingExercises сохраняет слоты глобальной тренировки, нумеруя их по порядку в cmd
+func addGlobalTrainingExercises(ctx context.Context, q *gen.Queries, globalTrainingID int64, exercises []domain.GlobalTrainingExerciseCmd) error {
+	for i, ex := range exercises {
+		groupID, groupType := exerciseGroupToSQL(ex.Group)
+		err := q.AddGlobalTrainingExercise(ctx, gen.AddGlobalTrainingExerciseParams{
+			GlobalTrainingID: globalTrainingID,
+			ExerciseID:       ex.ExerciseID,
+			Position:         int32(i + 1),
+			Approaches:       null.Int32FromPtr(ex.Approaches).NullInt32,
+			Reps:             null.Int32FromPtr(ex.Reps).NullInt32,
+			Weight:           decimalToNullString(ex.Weight),
+			Duration:         durationToNullInt64(ex.Duration),
+			Rest:             durationToNullInt64(ex.Rest),
+			GroupID:          groupID,
+			GroupType:        groupType,
+		})
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"global_training_id": globalTrainingID,
+				"exercise_id":        ex.ExerciseID,
+				"position":           i + 1,
+			})
+			logging.Error(err, "addGlobalTrainingExercises", jsonData, "failed to add global training exercise")
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TrainingRepositoryImpl) DeleteGlobalTraining(ctx context.Context, id int64) (bool, error) {
+	affected, err := r.q.DeleteGlobalTraining(ctx, id)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"global_training_id": id,
+		})
+		logging.Error(err, "DeleteGlobalTraining", jsonData, "failed to delete global training")
+		return false, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"global_training_id": id,
+		"deleted":            affected > 0,
+	})
+	logging.Info("DeleteGlobalTraining", jsonData, "global training delete finished")
+
+	return affected > 0, nil
+}
+
+func (r *TrainingRepositoryImpl) IsGlobalTrainingUsedByProgram(ctx context.Context, id int64) (bool, error) {
+	used, err := r.q.IsGlobalTrainingUsedByProgram(ctx, id)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"global_training_id": id,
+		})
+		logging.Error(err, "IsGlobalTrainingUsedByProgram", jsonData, "failed to check global training usage")
+		return false, err
+	}
+	return used, nil
+}
+
+func (r *TrainingRepositoryImpl) CountExercises(ctx context.Context, ids []int64) (int64, error) {
+	count, err := r.q.CountExercisesByIDs(ctx, ids)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"exercise_ids": ids,
+		})
+		logging.Error(err, "CountExercises", jsonData, "failed to count exercises")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainingOwner(ctx context.Context, trainingID int64) (uuid.UUID, error) {
+	userID, err := r.q.GetTrainingOwner(ctx, trainingID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"training_id": trainingID,
+			})
+			logging.Error(err, "GetTrainingOwner", jsonData, "failed to get training owner")
+		}
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+func (r *TrainingRepositoryImpl) GetTrainedExerciseOwner(ctx context.Context, trainedExerciseID int64) (uuid.UUID, int64, error) {
+	owner, err := r.q.GetTrainedExerciseOwner(ctx, trainedExerciseID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"trained_exercise_id": trainedExerciseID,
+			})
+			logging.Error(err, "GetTrainedExerciseOwner", jsonData, "failed to get trained exercise owner")
+		}
+		return uuid.Nil, 0, err
+	}
+	return owner.UserID, owner.TrainingID, nil
+}
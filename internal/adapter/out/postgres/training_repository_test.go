@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestAddExerciseToTrainingParamsCardioWithoutWeight(t *testing.T) {
+	distance := decimal.NewFromInt(5000)
+	moving := 25 * time.Minute
+	userID := uuid.New()
+	exercise := &domain.TrainedExercise{
+		TrainingID: 10,
+		ExerciseID: 20,
+		Cardio:     &domain.CardioMetrics{Distance: &distance, MovingTime: &moving, DistanceUnit: domain.DistanceKilometers},
+	}
+
+	params := addExerciseToTrainingParams(exercise, userID)
+	if params.Weight.Valid || params.Approaches.Valid || params.Reps.Valid {
+		t.Errorf("strength load of a cardio exercise must be NULL: %+v", params)
+	}
+	if params.TrainingID != 10 || params.ExerciseID != 20 || params.UserID != userID {
+		t.Errorf("params = %+v", params)
+	}
+	if params.GroupID.Valid || params.GroupType.Valid {
+		t.Errorf("exercise without group has group %v %v", params.GroupID, params.GroupType)
+	}
+
+	cardio := cardioToSQL(1, exercise.Cardio)
+	if !cardio.Distance.Valid || cardio.Distance.String != "5000" || cardio.MovingTime.Int64 != moving.Microseconds() {
+		t.Errorf("cardio params = %+v", cardio)
+	}
+}
+
+func TestAddExerciseToTrainingParamsWeight(t *testing.T) {
+	weight := decimal.RequireFromString("62.5")
+	params := addExerciseToTrainingParams(&domain.TrainedExercise{Weight: &weight}, uuid.New())
+	if !params.Weight.Valid || params.Weight.String != "62.5" {
+		t.Errorf("Weight = %+v, want 62.5", params.Weight)
+	}
+}
+
+func TestUpdateTrainedExerciseParamsWithoutWeight(t *testing.T) {
+	moving := 30 * time.Minute
+	params := updateTrainedExerciseParams(&domain.TrainedExercise{
+		ID:     7,
+		Cardio: &domain.CardioMetrics{MovingTime: &moving},
+	}, uuid.New())
+	if params.Weight.Valid {
+		t.Errorf("Weight = %+v, want NULL", params.Weight)
+	}
+	if params.ID != 7 {
+		t.Errorf("ID = %d, want 7", params.ID)
+	}
+}
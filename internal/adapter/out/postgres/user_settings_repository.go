@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/EnduranNSU/trainings/internal/adapter/out/postgres/gen"
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+func (r *TrainingRepositoryImpl) GetUserTrainingDates(ctx context.Context, userID uuid.UUID) ([]domain.TrainingDate, error) {
+	rows, err := r.q.GetUserTrainingDates(ctx, userID)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "GetUserTrainingDates", jsonData, "failed to get user training dates")
+		return nil, err
+	}
+
+	dates := make([]domain.TrainingDate, len(rows))
+	for i, row := range rows {
+		dates[i] = domain.TrainingDate{
+			PlannedDate: row.PlannedDate,
+			ActualDate:  nullTimeFromSQL(row.ActualDate),
+			IsDone:      row.IsDone,
+			Status:      domain.TrainingStatus(row.Status),
+		}
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":     userID.String(),
+		"dates_count": len(dates),
+	})
+	logging.Debug("GetUserTrainingDates", jsonData, "successfully retrieved user training dates")
+
+	return dates, nil
+}
+
+func (r *TrainingRepositoryImpl) GetUserSettings(ctx context.Context, userID uuid.UUID) (*domain.UserSettings, error) {
+	row, err := r.q.GetUserSettings(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		settings := domain.DefaultUserSettings(userID)
+		return &settings, nil
+	}
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id": userID.String(),
+		})
+		logging.Error(err, "GetUserSettings", jsonData, "failed to get user settings")
+		return nil, err
+	}
+
+	return toDomainUserSettings(row), nil
+}
+
+func (r *TrainingRepositoryImpl) SaveUserSettings(ctx context.Context, settings *domain.UserSettings) (*domain.UserSettings, error) {
+	row, err := r.q.UpsertUserSettings(ctx, gen.UpsertUserSettingsParams{
+		UserID:    settings.UserID,
+		WeekStart: int32(settings.WeekStart),
+		RestDays:  settings.RestDays,
+		Timezone:  sql.NullString{String: settings.Timezone, Valid: settings.Timezone != ""},
+	})
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"user_id":    settings.UserID.String(),
+			"week_start": settings.WeekStart,
+			"rest_days":  settings.RestDays,
+			"timezone":   settings.Timezone,
+		})
+		logging.Error(err, "SaveUserSettings", jsonData, "failed to save user settings")
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id": settings.UserID.String(),
+	})
+	logging.Info("SaveUserSettings", jsonData, "user settings saved")
+
+	return toDomainUserSettings(row), nil
+}
+
+func toDomainUserSettings(row gen.UserSetting) *domain.UserSettings {
+	return &domain.UserSettings{
+		UserID:    row.UserID,
+		WeekStart: time.Weekday(row.WeekStart),
+		RestDays:  row.RestDays,
+		Timezone:  row.Timezone.String,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
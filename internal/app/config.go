@@ -2,6 +2,7 @@ package app
 
 import (
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -19,15 +20,38 @@ func GetConfigName() string {
 }
 
 type Config struct {
-	Db     DbConfig
-	Logger LoggerConfig
-	Http   HttpConfig
+	Db          DbConfig
+	Logger      LoggerConfig
+	Http        HttpConfig
+	Auth        AuthConfig
+	Schedule    ScheduleConfig
+	Progression ProgressionConfig
 }
 
 type HttpConfig struct {
 	Addr string `default:":8080"`
 }
 
+// AuthConfig ключи для проверки JWT: HS256 по секрету и/или RS256 по JWKS файлу
+type AuthConfig struct {
+	HMACSecret string
+	JWKSFile   string
+	Issuer     string
+	Audience   string
+}
+
+// ScheduleConfig - фоновое создание тренировок по расписаниям
+type ScheduleConfig struct {
+	MaterializeInterval time.Duration `default:"1h" validate:"min=1m"`
+}
+
+// ProgressionConfig - правила подбора нагрузки на следующую тренировку
+type ProgressionConfig struct {
+	WeightStep    float64 `default:"2.5" validate:"min=0"`
+	DeloadAfter   int32   `default:"3" validate:"min=1"`
+	DeloadPercent float64 `default:"10" validate:"min=0,max=100"`
+}
+
 type DbConfig struct {
 	User     string
 	Password string
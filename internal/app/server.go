@@ -17,27 +17,43 @@ type Server struct {
 	TrainingSvc svc.TrainingService
 	ExerciseSvc svc.ExerciseService
 	Addr string
+	Auth AuthConfig
 }
 
 func SetupServer(trainingSvc svc.TrainingService,
-	exerciseSvc svc.ExerciseService, addr string) *Server {
+	exerciseSvc svc.ExerciseService, addr string, auth AuthConfig) *Server {
 	return &Server{
 		TrainingSvc: trainingSvc,
 		ExerciseSvc: exerciseSvc,
 		Addr: addr,
+		Auth: auth,
 	}
 }
 
 func (s *Server) StartServer() error {
+	auth, err := httpin.NewAuthMiddleware(httpin.AuthConfig{
+		HMACSecret: s.Auth.HMACSecret,
+		JWKSFile:   s.Auth.JWKSFile,
+		Issuer:     s.Auth.Issuer,
+		Audience:   s.Auth.Audience,
+	})
+	if err != nil {
+		log.Error().Err(err).
+		Str("service", "trainings").Msg("failed to setup auth middleware")
+		return err
+	}
+
 	eh := httpin.NewExerciseHandler(s.ExerciseSvc)
 	th := httpin.NewTrainingHandler(s.TrainingSvc)
-	engine := httpin.NewGinRouter(th, eh)
+	engine := httpin.NewGinRouter(th, eh, auth)
 
 	srv := &http.Server{
 		Addr:              s.Addr,
 		Handler:           engine,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
+	// Shutdown ждет активные соединения, а потоки живых сессий сами не заканчиваются
+	srv.RegisterOnShutdown(th.CloseStreams)
 	// Запуск сервера в отдельной горутине
 	go func() {
 		log.Info().Msgf("HTTP server starting on %s", s.Addr)
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// CalendarTraining - краткая сводка тренировки для календаря
+type CalendarTraining struct {
+	ID            int64
+	Title         string
+	Status        TrainingStatus
+	Rating        *int32
+	TotalDuration time.Duration
+	ExerciseCount int32
+}
+
+// CalendarDay - день календаря и запланированные на него тренировки.
+// Дни без тренировок тоже входят в календарь, с пустым списком
+type CalendarDay struct {
+	Date      time.Time
+	Trainings []CalendarTraining
+}
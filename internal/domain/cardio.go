@@ -0,0 +1,315 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CardioMetrics - показатели кардио-упражнения (пробежки, заезда, заплыва)
+type CardioMetrics struct {
+	Distance      *decimal.Decimal // Метры
+	DistanceUnit  DistanceUnit     // Единица, в которой дистанция показывается пользователю
+	MovingTime    *time.Duration   // Время в движении, без остановок
+	ElevationGain *decimal.Decimal // Набор высоты в метрах
+	AvgHeartRate  *int32           // Удары в минуту
+	MaxHeartRate  *int32
+	AvgPace       *time.Duration   // Время на километр в движении
+	AvgSpeed      *decimal.Decimal // Км/ч
+	Calories      *int32
+	Incline       *decimal.Decimal // Наклон беговой дорожки в процентах
+	Resistance    *int32           // Уровень сопротивления тренажера
+}
+
+// DistanceUnit - единица дистанции кардио-упражнения
+type DistanceUnit string
+
+const (
+	DistanceMeters     DistanceUnit = "m"
+	DistanceKilometers DistanceUnit = "km"
+	DistanceMiles      DistanceUnit = "mi"
+)
+
+// distanceUnitMeters - число метров в единице дистанции
+var distanceUnitMeters = map[DistanceUnit]decimal.Decimal{
+	DistanceMeters:     decimal.NewFromInt(1),
+	DistanceKilometers: decimal.NewFromInt(1000),
+	DistanceMiles:      decimal.RequireFromString("1609.344"),
+}
+
+func (u DistanceUnit) IsValid() bool {
+	_, ok := distanceUnitMeters[u]
+	return ok
+}
+
+// ToMeters переводит дистанцию в единице u в метры с точностью до десятых
+func (u DistanceUnit) ToMeters(value decimal.Decimal) decimal.Decimal {
+	return value.Mul(distanceUnitMeters[u]).Round(1)
+}
+
+// FromMeters переводит метры в единицу u. Километры и мили округляются до метров
+func (u DistanceUnit) FromMeters(meters decimal.Decimal) decimal.Decimal {
+	if u == DistanceMeters {
+		return meters.Round(1)
+	}
+	return meters.DivRound(distanceUnitMeters[u], 3)
+}
+
+// CardioSport - вид кардио-активности из файла часов или велокомпьютера
+type CardioSport string
+
+const (
+	CardioSportRunning  CardioSport = "running"
+	CardioSportCycling  CardioSport = "cycling"
+	CardioSportWalking  CardioSport = "walking"
+	CardioSportHiking   CardioSport = "hiking"
+	CardioSportSwimming CardioSport = "swimming"
+	CardioSportOther    CardioSport = "other"
+)
+
+// cardioSportNames - названия упражнения каталога, с которыми сопоставляется вид активности,
+// в порядке предпочтения. Первое название становится названием тренировки, если в файле его нет
+var cardioSportNames = map[CardioSport][]string{
+	CardioSportRunning:  {"Бег", "Running", "Run"},
+	CardioSportCycling:  {"Велосипед", "Cycling", "Ride"},
+	CardioSportWalking:  {"Ходьба", "Walking", "Walk"},
+	CardioSportHiking:   {"Поход", "Hiking", "Hike"},
+	CardioSportSwimming: {"Плавание", "Swimming", "Swim"},
+	CardioSportOther:    {"Кардио", "Cardio"},
+}
+
+// parseCardioSport переводит вид активности из файла ("running", "Biking", "run") в CardioSport.
+// Слова нормализованного названия отсортированы, поэтому "trail running" сравнивается как "running trail"
+func parseCardioSport(value string) CardioSport {
+	switch normalizeExerciseName(value) {
+	case "running", "run", "running trail", "running treadmill":
+		return CardioSportRunning
+	case "cycling", "biking", "bike", "ride", "cycling road", "biking mountain":
+		return CardioSportCycling
+	case "walking", "walk":
+		return CardioSportWalking
+	case "hiking", "hike":
+		return CardioSportHiking
+	case "swimming", "swim", "lap swimming", "open swimming water":
+		return CardioSportSwimming
+	}
+	return CardioSportOther
+}
+
+const (
+	// movingSpeedThreshold - скорость в м/с, ниже которой участок трека считается остановкой
+	movingSpeedThreshold = 0.5
+	// maxTrackGap - промежуток между точками трека, после которого участок считается паузой записи
+	maxTrackGap = 5 * time.Minute
+	// elevationThreshold - подъем в метрах, который засчитывается в набор высоты; меньшие колебания
+	// высоты считаются шумом GPS
+	elevationThreshold = 3.0
+	// earthRadius - средний радиус Земли в метрах
+	earthRadius = 6371000.0
+	// minHeartRate и maxHeartRate ограничивают правдоподобный пульс; значения вне них отбрасываются
+	minHeartRate = 20
+	maxHeartRate = 250
+)
+
+// trackPoint - точка трека. Незаданные поля равны nil
+type trackPoint struct {
+	Time      time.Time
+	Lat, Lon  *float64
+	Elevation *float64
+	Distance  *float64 // Пройденное от начала расстояние в метрах, если его пишет устройство
+	HeartRate *int32
+
+	// Первая точка сегмента трека: расстояние и время от предыдущей точки не учитываются
+	NewSegment bool
+}
+
+// cardioTrack - трек активности и итоги, записанные устройством. Итоги устройства точнее
+// посчитанных по точкам и используются, если они есть
+type cardioTrack struct {
+	Sport  CardioSport
+	Title  string
+	Start  time.Time
+	End    time.Time
+	Points []trackPoint
+
+	Distance      *float64
+	MovingTime    *time.Duration
+	ElevationGain *float64
+	AvgHeartRate  *int32
+	MaxHeartRate  *int32
+}
+
+// workout переводит трек в импортируемую тренировку с одним кардио-упражнением.
+// Время начала и конца берется из трека, а если точек нет - из итогов устройства
+func (t *cardioTrack) workout(loc *time.Location) (ImportedWorkout, error) {
+	if len(t.Points) > 0 {
+		if t.Start.IsZero() || t.Points[0].Time.Before(t.Start) {
+			t.Start = t.Points[0].Time
+		}
+		if last := t.Points[len(t.Points)-1].Time; last.After(t.End) {
+			t.End = last
+		}
+	}
+	if t.Start.IsZero() {
+		return ImportedWorkout{}, errMalformedImportFile
+	}
+
+	metrics := t.metrics()
+	names := cardioSportNames[t.Sport]
+	if names == nil {
+		names = cardioSportNames[CardioSportOther]
+	}
+
+	workout := ImportedWorkout{
+		Title:        t.Title,
+		StartedAt:    t.Start.In(loc),
+		ExerciseTime: metrics.MovingTime,
+		Exercises: []ImportedExercise{{
+			Name:     names[0],
+			AltNames: names[1:],
+			Cardio:   &metrics,
+		}},
+	}
+	if workout.Title == "" {
+		workout.Title = names[0]
+	}
+	if d := t.End.Sub(t.Start); d > 0 {
+		workout.Duration = &d
+	}
+	return workout, nil
+}
+
+// metrics считает показатели по точкам трека и подставляет итоги устройства, если они записаны
+func (t *cardioTrack) metrics() CardioMetrics {
+	var distance, gain float64
+	var moving time.Duration
+	var hrSum, hrCount int64
+	var hrMax int32
+	hasDistance, hasElevation := false, false
+
+	var climbFrom *float64
+	for i, p := range t.Points {
+		if p.HeartRate != nil && *p.HeartRate >= minHeartRate && *p.HeartRate <= maxHeartRate {
+			hrSum += int64(*p.HeartRate)
+			hrCount++
+			hrMax = max(hrMax, *p.HeartRate)
+		}
+		if p.Elevation != nil {
+			hasElevation = true
+			switch {
+			case climbFrom == nil || *p.Elevation < *climbFrom:
+				climbFrom = p.Elevation
+			case *p.Elevation-*climbFrom >= elevationThreshold:
+				gain += *p.Elevation - *climbFrom
+				climbFrom = p.Elevation
+			}
+		}
+		if i == 0 || p.NewSegment {
+			continue
+		}
+
+		prev := t.Points[i-1]
+		step, ok := trackStep(prev, p)
+		if !ok {
+			continue
+		}
+		hasDistance = true
+		distance += step
+
+		gap := p.Time.Sub(prev.Time)
+		if gap > 0 && gap <= maxTrackGap && step/gap.Seconds() >= movingSpeedThreshold {
+			moving += gap
+		}
+	}
+
+	var m CardioMetrics
+	switch {
+	case t.Distance != nil && *t.Distance > 0:
+		m.Distance = cardioDecimal(*t.Distance)
+	case hasDistance && distance > 0:
+		m.Distance = cardioDecimal(distance)
+	}
+	switch {
+	case t.MovingTime != nil && *t.MovingTime > 0:
+		m.MovingTime = t.MovingTime
+	case moving > 0:
+		moving = moving.Round(time.Second)
+		m.MovingTime = &moving
+	}
+	switch {
+	case t.ElevationGain != nil:
+		m.ElevationGain = cardioDecimal(*t.ElevationGain)
+	case hasElevation:
+		m.ElevationGain = cardioDecimal(gain)
+	}
+	switch {
+	case t.AvgHeartRate != nil:
+		m.AvgHeartRate = t.AvgHeartRate
+	case hrCount > 0:
+		avg := int32(math.Round(float64(hrSum) / float64(hrCount)))
+		m.AvgHeartRate = &avg
+	}
+	switch {
+	case t.MaxHeartRate != nil:
+		m.MaxHeartRate = t.MaxHeartRate
+	case hrCount > 0:
+		m.MaxHeartRate = &hrMax
+	}
+	m.AvgPace = CardioPace(m.Distance, m.MovingTime)
+	m.AvgSpeed = CardioSpeed(m.Distance, m.MovingTime)
+	return m
+}
+
+// trackStep - расстояние между соседними точками: по расстоянию от начала, которое пишет устройство,
+// а если его нет - по координатам
+func trackStep(prev, p trackPoint) (float64, bool) {
+	if prev.Distance != nil && p.Distance != nil {
+		return math.Max(*p.Distance-*prev.Distance, 0), true
+	}
+	if prev.Lat != nil && prev.Lon != nil && p.Lat != nil && p.Lon != nil {
+		return haversine(*prev.Lat, *prev.Lon, *p.Lat, *p.Lon), true
+	}
+	return 0, false
+}
+
+// haversine - расстояние в метрах между точками с координатами в градусах
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// CardioPace - средний темп (время на километр) по дистанции в метрах и времени в движении.
+// nil, если дистанция или время не заданы
+func CardioPace(distance *decimal.Decimal, moving *time.Duration) *time.Duration {
+	if distance == nil || moving == nil || !distance.IsPositive() || *moving <= 0 {
+		return nil
+	}
+	km, _ := distance.Div(decimal.NewFromInt(1000)).Float64()
+	pace := time.Duration(float64(*moving) / km).Round(time.Second)
+	return &pace
+}
+
+// CardioSpeed - средняя скорость в км/ч по дистанции в метрах и времени в движении.
+// nil, если дистанция или время не заданы
+func CardioSpeed(distance *decimal.Decimal, moving *time.Duration) *decimal.Decimal {
+	if distance == nil || moving == nil || !distance.IsPositive() || *moving <= 0 {
+		return nil
+	}
+	meters, _ := distance.Float64()
+	return cardioDecimal(meters / 1000 / moving.Hours())
+}
+
+// cardioDecimal округляет метры или км/ч до десятых. Бесконечность и NaN (например, после переполнения
+// суммы дистанций из файла) дают nil
+func cardioDecimal(meters float64) *decimal.Decimal {
+	if math.IsNaN(meters) || math.IsInf(meters, 0) {
+		return nil
+	}
+	d := decimal.NewFromFloat(meters).Round(1)
+	return &d
+}
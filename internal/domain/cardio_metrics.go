@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var errInvalidCardioMetrics = errors.New("invalid cardio metrics")
+
+// Допустимые значения кардио-показателей, введенных пользователем
+var (
+	maxCardioDistance = decimal.NewFromInt(1000000) // 1000 км
+	maxCardioSpeed    = decimal.NewFromInt(150)
+	maxElevationGain  = decimal.NewFromInt(100000)
+	minIncline        = decimal.NewFromInt(-10)
+	maxIncline        = decimal.NewFromInt(40)
+)
+
+const (
+	maxCardioDuration = 24 * time.Hour
+	maxCardioCalories = 10000
+	maxResistance     = 100
+)
+
+// Normalize проверяет кардио-показатели для типа упражнения каталога, подставляет единицу
+// дистанции по умолчанию и вычисляет темп и скорость, если они не заданы
+func (m *CardioMetrics) Normalize(t ExerciseType) error {
+	support, ok := exerciseTypeCardio[t]
+	if !ok {
+		return fmt.Errorf("%w: %s exercises have no cardio metrics", errInvalidCardioMetrics, t)
+	}
+
+	if m.DistanceUnit == "" {
+		m.DistanceUnit = t.DefaultDistanceUnit()
+	}
+	switch {
+	case !m.DistanceUnit.IsValid():
+		return fmt.Errorf("%w: distance_unit must be one of m, km, mi", errInvalidCardioMetrics)
+	case m.Distance != nil && (!m.Distance.IsPositive() || m.Distance.GreaterThan(maxCardioDistance)):
+		return fmt.Errorf("%w: distance must be positive and at most 1000 km", errInvalidCardioMetrics)
+	case !validCardioDuration(m.MovingTime):
+		return fmt.Errorf("%w: moving_time must be positive and at most 24h", errInvalidCardioMetrics)
+	case m.ElevationGain != nil && (m.ElevationGain.IsNegative() || m.ElevationGain.GreaterThan(maxElevationGain)):
+		return fmt.Errorf("%w: elevation_gain must be between 0 and %s m", errInvalidCardioMetrics, maxElevationGain)
+	case m.Calories != nil && (*m.Calories <= 0 || *m.Calories > maxCardioCalories):
+		return fmt.Errorf("%w: calories must be between 1 and %d", errInvalidCardioMetrics, maxCardioCalories)
+	case !validHeartRate(m.AvgHeartRate) || !validHeartRate(m.MaxHeartRate):
+		return fmt.Errorf("%w: heart rate must be between %d and %d", errInvalidCardioMetrics, minHeartRate, maxHeartRate)
+	case m.AvgHeartRate != nil && m.MaxHeartRate != nil && *m.AvgHeartRate > *m.MaxHeartRate:
+		return fmt.Errorf("%w: avg_heart_rate cannot exceed max_heart_rate", errInvalidCardioMetrics)
+	case m.Incline != nil && (m.Incline.LessThan(minIncline) || m.Incline.GreaterThan(maxIncline)):
+		return fmt.Errorf("%w: incline must be between %s and %s percent", errInvalidCardioMetrics, minIncline, maxIncline)
+	case m.Resistance != nil && (*m.Resistance < 0 || *m.Resistance > maxResistance):
+		return fmt.Errorf("%w: resistance_level must be between 0 and %d", errInvalidCardioMetrics, maxResistance)
+	}
+
+	switch {
+	case m.ElevationGain != nil && !support.ElevationGain:
+		return fmt.Errorf("%w: elevation_gain is not tracked for %s exercises", errInvalidCardioMetrics, t)
+	case m.Incline != nil && !support.Incline:
+		return fmt.Errorf("%w: incline is not tracked for %s exercises", errInvalidCardioMetrics, t)
+	case m.Resistance != nil && !support.Resistance:
+		return fmt.Errorf("%w: resistance_level is not tracked for %s exercises", errInvalidCardioMetrics, t)
+	}
+
+	if err := validCardioPaceSpeed(m, ""); err != nil {
+		return err
+	}
+
+	// Темп и скорость считаются по дистанции и времени, а без них - друг из друга
+	if m.AvgPace == nil {
+		m.AvgPace = CardioPace(m.Distance, m.MovingTime)
+	}
+	if m.AvgPace == nil && m.AvgSpeed != nil && m.AvgSpeed.IsPositive() {
+		kmh, _ := m.AvgSpeed.Float64()
+		pace := time.Duration(float64(time.Hour) / kmh).Round(time.Second)
+		m.AvgPace = &pace
+	}
+	if m.AvgSpeed == nil {
+		m.AvgSpeed = CardioSpeed(m.Distance, m.MovingTime)
+	}
+	if m.AvgSpeed == nil && m.AvgPace != nil && *m.AvgPace > 0 {
+		m.AvgSpeed = cardioDecimal(time.Hour.Seconds() / m.AvgPace.Seconds())
+	}
+
+	// Вычисленные значения проверяются теми же границами: иначе неправдоподобная пара
+	// дистанции и времени дошла бы до ограничений таблицы
+	return validCardioPaceSpeed(m, " (derived from distance, moving_time, avg_pace or avg_speed)")
+}
+
+// validCardioPaceSpeed проверяет темп и скорость; hint поясняет, откуда взялось значение
+func validCardioPaceSpeed(m *CardioMetrics, hint string) error {
+	switch {
+	case !validCardioDuration(m.AvgPace):
+		return fmt.Errorf("%w: avg_pace must be positive and at most 24h per km%s", errInvalidCardioMetrics, hint)
+	case m.AvgSpeed != nil && (!m.AvgSpeed.IsPositive() || m.AvgSpeed.GreaterThan(maxCardioSpeed)):
+		return fmt.Errorf("%w: avg_speed must be positive and at most %s km/h%s", errInvalidCardioMetrics, maxCardioSpeed, hint)
+	}
+	return nil
+}
+
+// MergeCardioMetrics накладывает переданные показатели на сохраненные. Если изменились дистанция
+// или время, а темп и скорость не переданы, они сбрасываются, чтобы Normalize посчитал их заново
+func MergeCardioMetrics(current, patch *CardioMetrics) *CardioMetrics {
+	var merged CardioMetrics
+	if current != nil {
+		merged = *current
+	}
+	if patch == nil {
+		return &merged
+	}
+
+	if patch.Distance != nil || patch.MovingTime != nil {
+		merged.AvgPace, merged.AvgSpeed = nil, nil
+	}
+	if patch.Distance != nil {
+		merged.Distance = patch.Distance
+	}
+	if patch.DistanceUnit != "" {
+		merged.DistanceUnit = patch.DistanceUnit
+	}
+	if patch.MovingTime != nil {
+		merged.MovingTime = patch.MovingTime
+	}
+	if patch.ElevationGain != nil {
+		merged.ElevationGain = patch.ElevationGain
+	}
+	if patch.AvgHeartRate != nil {
+		merged.AvgHeartRate = patch.AvgHeartRate
+	}
+	if patch.MaxHeartRate != nil {
+		merged.MaxHeartRate = patch.MaxHeartRate
+	}
+	if patch.AvgPace != nil {
+		merged.AvgPace = patch.AvgPace
+	}
+	if patch.AvgSpeed != nil {
+		merged.AvgSpeed = patch.AvgSpeed
+	}
+	if patch.Calories != nil {
+		merged.Calories = patch.Calories
+	}
+	if patch.Incline != nil {
+		merged.Incline = patch.Incline
+	}
+	if patch.Resistance != nil {
+		merged.Resistance = patch.Resistance
+	}
+	return &merged
+}
+
+func validCardioDuration(d *time.Duration) bool {
+	return d == nil || (*d > 0 && *d <= maxCardioDuration)
+}
+
+func validHeartRate(hr *int32) bool {
+	return hr == nil || (*hr >= minHeartRate && *hr <= maxHeartRate)
+}
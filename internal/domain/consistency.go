@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// TrainingDate - дата и результат тренировки, из которых считаются серии и регулярность
+type TrainingDate struct {
+	PlannedDate time.Time
+	ActualDate  *time.Time
+	IsDone      bool
+	Status      TrainingStatus
+}
+
+// WeeklyAdherence - сколько тренировок недели было запланировано и сколько из них выполнено
+type WeeklyAdherence struct {
+	WeekStart time.Time `json:"week_start"`
+	Planned   int32     `json:"planned"`
+	Completed int32     `json:"completed"`
+}
+
+// Consistency - регулярность тренировок пользователя.
+// Серия - число дней с выполненными тренировками, между которыми не больше RestDays дней отдыха подряд.
+// Текущая серия обнуляется, если с последней тренировки прошло больше RestDays дней
+type Consistency struct {
+	CurrentStreak   int32      `json:"current_streak"`
+	LongestStreak   int32      `json:"longest_streak"`
+	LastTrainingDay *time.Time `json:"last_training_day"`
+	// Доля выполненных тренировок из запланированных на недели в Weeks, в процентах
+	AdherencePercent float64 `json:"adherence_percent"`
+	// Тренировки за недели в Weeks, запланированные на прошедшие дни и так и не начатые или пропущенные
+	MissedSessions int32             `json:"missed_sessions"`
+	WeekStart      time.Weekday      `json:"week_start"`
+	RestDays       int32             `json:"rest_days"`
+	Weeks          []WeeklyAdherence `json:"weeks"`
+}
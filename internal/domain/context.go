@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type contextKey int
+
+const (
+	userIDContextKey contextKey = iota
+	rolesContextKey
+	locationContextKey
+)
+
+// RoleAdmin - роль администратора каталога упражнений и глобальных тренировок
+const RoleAdmin = "admin"
+
+// ContextWithUserID кладет идентификатор аутентифицированного пользователя в контекст
+func ContextWithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserIDFromContext достает идентификатор аутентифицированного пользователя из контекста
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userIDContextKey).(uuid.UUID)
+	if !ok || userID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// ContextWithRoles кладет роли аутентифицированного пользователя в контекст
+func ContextWithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, rolesContextKey, roles)
+}
+
+// HasRole проверяет, есть ли у аутентифицированного пользователя роль
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(rolesContextKey).([]string)
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextWithLocation кладет в контекст часовой пояс, переданный клиентом в запросе.
+// Он имеет приоритет над поясом из настроек пользователя
+func ContextWithLocation(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, locationContextKey, loc)
+}
+
+// LocationFromContext достает часовой пояс, переданный клиентом в запросе
+func LocationFromContext(ctx context.Context) (*time.Location, bool) {
+	loc, ok := ctx.Value(locationContextKey).(*time.Location)
+	return loc, ok && loc != nil
+}
@@ -20,14 +20,35 @@ type Training struct {
 	TotalRestTime     *time.Duration    `db:"total_rest_time" json:"total_rest_time"`
 	TotalExerciseTime *time.Duration    `db:"total_exercise_time" json:"total_exercise_time"`
 	Rating            *int32            `db:"rating" json:"rating"`
+	Status            TrainingStatus    `db:"status" json:"status"`
+	IsPaused          bool              `db:"is_paused" json:"is_paused"`
 	Exercises         []TrainedExercise `db:"exercises" json:"exercises"`
+
+	// Рекорды, установленные при завершении тренировки (заполняется только CompleteTraining)
+	PersonalRecords []PersonalRecord `db:"-" json:"personal_records,omitempty"`
 }
 
 type TrainingStats struct {
 	TotalTrainings     int64         `json:"total_trainings"`
 	CompletedTrainings int64         `json:"completed_trainings"`
+	SkippedTrainings   int64         `json:"skipped_trainings"`
+	CompletionRate     float64       `json:"completion_rate"`
 	AverageRating      float64       `json:"average_rating"`
 	TotalDuration      time.Duration `json:"total_time"`
+	TotalRestTime      time.Duration `json:"total_rest_time"`
+	TotalExerciseTime  time.Duration `json:"total_exercise_time"`
+
+	// Объем по рабочим подходам (подходы и повторения заполняются только для статистики одной тренировки)
+	TotalApproaches int64           `json:"total_approaches"`
+	TotalReps       int64           `json:"total_reps"`
+	TotalVolume     decimal.Decimal `json:"total_volume"`
+
+	// Кардио: дистанция в метрах и калории по всем упражнениям, время в движении и темп -
+	// по упражнениям, у которых есть и дистанция, и время
+	TotalDistance decimal.Decimal `json:"total_distance"`
+	CardioTime    time.Duration   `json:"cardio_time"`
+	AvgPace       *time.Duration  `json:"avg_pace"`
+	TotalCalories int64           `json:"total_calories"`
 }
 
 type TrainedExercise struct {
@@ -41,15 +62,33 @@ type TrainedExercise struct {
 	Doing      *time.Duration   `db:"doing" json:"doing"`
 	Rest       *time.Duration   `db:"rest" json:"rest"`
 	Notes      *string          `db:"notes" json:"notes"`
+	Sets       []TrainedSet     `db:"sets" json:"sets"`
+	Position   int32            `db:"position" json:"position"`
+	Group      *ExerciseGroup   `db:"-" json:"group"`
+	Cardio     *CardioMetrics   `db:"-" json:"cardio"`
+}
+
+// TrainedSet — отдельный подход выполненного упражнения.
+// Разминочные подходы не входят в агрегаты упражнения и статистику
+type TrainedSet struct {
+	ID                int64            `db:"id" json:"id"`
+	TrainedExerciseID int64            `db:"trained_exercise_id" json:"trained_exercise_id"`
+	Ordinal           int32            `db:"ordinal" json:"ordinal"`
+	Weight            *decimal.Decimal `db:"weight" json:"weight"`
+	Reps              *int32           `db:"reps" json:"reps"`
+	Duration          *time.Duration   `db:"duration" json:"duration"`
+	RPE               *decimal.Decimal `db:"rpe" json:"rpe"`
+	IsWarmup          bool             `db:"is_warmup" json:"is_warmup"`
 }
 
 type Exercise struct {
-	ID          int64  `db:"id" json:"id"`
-	Title       string `db:"title" json:"title"`
-	Description string `db:"description" json:"description"`
-	VideoUrl    string `db:"video_url" json:"video_url"`
-	ImageUrl    string `db:"image_url" json:"image_url"`
-	Tags        []Tag  `db:"tags" json:"tags"`
+	ID          int64        `db:"id" json:"id"`
+	Title       string       `db:"title" json:"title"`
+	Description string       `db:"description" json:"description"`
+	VideoUrl    string       `db:"video_url" json:"video_url"`
+	ImageUrl    string       `db:"image_url" json:"image_url"`
+	Type        ExerciseType `db:"type" json:"type"`
+	Tags        []Tag        `db:"tags" json:"tags"`
 }
 
 type Tag struct {
@@ -69,9 +108,23 @@ type TrainingTime struct {
 }
 
 type GlobalTraining struct {
-	ID          int64      `json:"id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Level       string     `json:"level"`
-	Exercises   []Exercise `json:"exercises"`
+	ID          int64                    `json:"id"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Level       string                   `json:"level"`
+	Exercises   []GlobalTrainingExercise `json:"exercises"`
+}
+
+// GlobalTrainingExercise — слот глобальной тренировки: упражнение с предписанной
+// нагрузкой. Position задает порядок выполнения
+type GlobalTrainingExercise struct {
+	Exercise
+	SlotID     int64            `json:"slot_id"`
+	Position   int32            `json:"position"`
+	Approaches *int32           `json:"approaches"`
+	Reps       *int32           `json:"reps"`
+	Weight     *decimal.Decimal `json:"weight"`
+	Duration   *time.Duration   `json:"duration"`
+	Rest       *time.Duration   `json:"rest"`
+	Group      *ExerciseGroup   `json:"group"`
 }
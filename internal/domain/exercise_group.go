@@ -0,0 +1,26 @@
+package domain
+
+// ExerciseGroupType - вид группы упражнений, которые выполняются подряд без отдыха между ними
+type ExerciseGroupType string
+
+const (
+	ExerciseGroupSuperset ExerciseGroupType = "superset"  // Два упражнения подряд
+	ExerciseGroupCircuit  ExerciseGroupType = "circuit"   // Круг из нескольких упражнений, повторяемый несколько раз
+	ExerciseGroupGiantSet ExerciseGroupType = "giant_set" // Три и более упражнений на одну группу мышц
+)
+
+// Valid сообщает, что вид группы поддерживается
+func (t ExerciseGroupType) Valid() bool {
+	switch t {
+	case ExerciseGroupSuperset, ExerciseGroupCircuit, ExerciseGroupGiantSet:
+		return true
+	}
+	return false
+}
+
+// ExerciseGroup - принадлежность упражнения группе. Упражнения одной тренировки (шаблона)
+// с одинаковым ID составляют группу: отдых берется после всей группы, а не после каждого упражнения
+type ExerciseGroup struct {
+	ID   int32
+	Type ExerciseGroupType
+}
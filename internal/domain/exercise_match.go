@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatchThreshold - наименьшая похожесть названий (от 0 до 1), при которой упражнение
+// сопоставляется приблизительно
+const FuzzyMatchThreshold = 0.8
+
+// ExerciseMatchKind - как название из выгрузки сопоставлено с каталогом
+type ExerciseMatchKind string
+
+const (
+	ExerciseMatchExact ExerciseMatchKind = "exact" // Совпадает с названием упражнения
+	ExerciseMatchAlias ExerciseMatchKind = "alias" // Совпадает с другим названием упражнения
+	ExerciseMatchFuzzy ExerciseMatchKind = "fuzzy" // Похоже на название или другое название
+	ExerciseMatchID    ExerciseMatchKind = "id"    // ID упражнения из выгрузки json есть в каталоге
+)
+
+// CatalogExercise - упражнение каталога с другими названиями
+type CatalogExercise struct {
+	ID      int64
+	Title   string
+	Type    ExerciseType
+	Aliases []string
+}
+
+// ExerciseMatch - упражнение каталога, сопоставленное названию из выгрузки
+type ExerciseMatch struct {
+	Name          string
+	ExerciseID    int64
+	ExerciseTitle string
+	ExerciseType  ExerciseType // По типу проверяются кардио-показатели из файла
+	Kind          ExerciseMatchKind
+	Similarity    float64 // 1 для точного совпадения
+}
+
+// ExerciseMatcher сопоставляет названия упражнений с каталогом: сначала точно по названию,
+// затем по другим названиям и, наконец, приблизительно. Регистр, знаки препинания и порядок слов не учитываются
+type ExerciseMatcher struct {
+	catalog []CatalogExercise
+	ids     map[int64]int  // ID упражнения -> индекс в catalog
+	titles  map[string]int // Нормализованное название -> индекс в catalog
+	aliases map[string]int
+}
+
+// NewExerciseMatcher строит сопоставление по каталогу. При совпадении нормализованных названий
+// выигрывает упражнение, идущее в каталоге раньше
+func NewExerciseMatcher(catalog []CatalogExercise) *ExerciseMatcher {
+	m := &ExerciseMatcher{
+		catalog: catalog,
+		ids:     make(map[int64]int, len(catalog)),
+		titles:  make(map[string]int, len(catalog)),
+		aliases: make(map[string]int),
+	}
+	for i, ex := range catalog {
+		m.ids[ex.ID] = i
+		if key := normalizeExerciseName(ex.Title); key != "" {
+			if _, ok := m.titles[key]; !ok {
+				m.titles[key] = i
+			}
+		}
+		for _, alias := range ex.Aliases {
+			if key := normalizeExerciseName(alias); key != "" {
+				if _, ok := m.aliases[key]; !ok {
+					m.aliases[key] = i
+				}
+			}
+		}
+	}
+	return m
+}
+
+// Match находит упражнение каталога для названия из выгрузки
+func (m *ExerciseMatcher) Match(name string) (ExerciseMatch, bool) {
+	key := normalizeExerciseName(name)
+	if key == "" {
+		return ExerciseMatch{}, false
+	}
+	if i, ok := m.titles[key]; ok {
+		return m.match(name, i, ExerciseMatchExact, 1), true
+	}
+	if i, ok := m.aliases[key]; ok {
+		return m.match(name, i, ExerciseMatchAlias, 1), true
+	}
+
+	best, bestScore := -1, 0.0
+	for i, ex := range m.catalog {
+		candidates := append([]string{ex.Title}, ex.Aliases...)
+		for _, candidate := range candidates {
+			score := nameSimilarity(key, normalizeExerciseName(candidate))
+			if score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+	}
+	if best < 0 || bestScore < FuzzyMatchThreshold {
+		return ExerciseMatch{}, false
+	}
+	return m.match(name, best, ExerciseMatchFuzzy, bestScore), true
+}
+
+// MatchID находит упражнение каталога по ID из выгрузки json. name - название упражнения в выгрузке
+func (m *ExerciseMatcher) MatchID(id int64, name string) (ExerciseMatch, bool) {
+	i, ok := m.ids[id]
+	if !ok {
+		return ExerciseMatch{}, false
+	}
+	return m.match(name, i, ExerciseMatchID, 1), true
+}
+
+func (m *ExerciseMatcher) match(name string, i int, kind ExerciseMatchKind, similarity float64) ExerciseMatch {
+	return ExerciseMatch{
+		Name:          name,
+		ExerciseID:    m.catalog[i].ID,
+		ExerciseTitle: m.catalog[i].Title,
+		ExerciseType:  m.catalog[i].Type,
+		Kind:          kind,
+		Similarity:    similarity,
+	}
+}
+
+// normalizeExerciseName приводит название к нижнему регистру, заменяет знаки препинания пробелами
+// и сортирует слова: "Bench Press (Barbell)" и "barbell bench press" дают одно и то же
+func normalizeExerciseName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		words[i] = strings.ReplaceAll(w, "ё", "е")
+	}
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// nameSimilarity - похожесть нормализованных названий по расстоянию Левенштейна, от 0 до 1
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func testMatcher() *ExerciseMatcher {
+	return NewExerciseMatcher([]CatalogExercise{
+		{ID: 1, Title: "Bench Press", Type: ExerciseTypeStrength, Aliases: []string{"Жим лежа"}},
+		{ID: 2, Title: "Squat", Type: ExerciseTypeStrength, Aliases: []string{"Back Squat (Barbell)"}},
+		{ID: 3, Title: "Deadlift", Type: ExerciseTypeStrength},
+		{ID: 4, Title: "bench-press", Type: ExerciseTypeStrength},
+		{ID: 5, Title: "Бег", Type: ExerciseTypeRunning, Aliases: []string{"Running"}},
+	})
+}
+
+func TestExerciseMatcherMatch(t *testing.T) {
+	m := testMatcher()
+	tests := []struct {
+		name       string
+		in         string
+		wantID     int64
+		wantKind   ExerciseMatchKind
+		similarity float64
+	}{
+		{"exact", "Bench Press", 1, ExerciseMatchExact, 1},
+		{"case and punctuation", "  BENCH, press! ", 1, ExerciseMatchExact, 1},
+		{"word order", "Press Bench", 1, ExerciseMatchExact, 1},
+		{"earlier duplicate wins", "bench-press", 1, ExerciseMatchExact, 1},
+		{"alias", "barbell back squat", 2, ExerciseMatchAlias, 1},
+		{"alias with ё", "Жим лёжа", 1, ExerciseMatchAlias, 1},
+		{"fuzzy title", "Deadlit", 3, ExerciseMatchFuzzy, 0.875},
+		{"fuzzy alias", "Runing", 5, ExerciseMatchFuzzy, 6.0 / 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := m.Match(tt.in)
+			if !ok {
+				t.Fatalf("Match(%q) found nothing", tt.in)
+			}
+			if got.ExerciseID != tt.wantID || got.Kind != tt.wantKind || got.Name != tt.in {
+				t.Errorf("Match(%q) = %+v, want id %d kind %s", tt.in, got, tt.wantID, tt.wantKind)
+			}
+			if math.Abs(got.Similarity-tt.similarity) > 1e-9 {
+				t.Errorf("Match(%q) similarity = %v, want %v", tt.in, got.Similarity, tt.similarity)
+			}
+		})
+	}
+}
+
+func TestExerciseMatcherMatchType(t *testing.T) {
+	got, ok := testMatcher().Match("running")
+	if !ok || got.ExerciseType != ExerciseTypeRunning || got.ExerciseTitle != "Бег" {
+		t.Fatalf("Match(running) = %+v, %v", got, ok)
+	}
+}
+
+func TestExerciseMatcherNoMatch(t *testing.T) {
+	m := testMatcher()
+	for _, in := range []string{"", "   ", "!!! ---", "Dead", "Pull Up", "Bench Press Incline Dumbbell"} {
+		if got, ok := m.Match(in); ok {
+			t.Errorf("Match(%q) = %+v, want no match", in, got)
+		}
+	}
+	if got, ok := NewExerciseMatcher(nil).Match("Squat"); ok {
+		t.Errorf("empty catalog Match() = %+v, want no match", got)
+	}
+}
+
+func TestExerciseMatcherMatchID(t *testing.T) {
+	m := testMatcher()
+	got, ok := m.MatchID(3, "Становая")
+	if !ok || got.ExerciseID != 3 || got.Kind != ExerciseMatchID || got.Name != "Становая" || got.ExerciseTitle != "Deadlift" {
+		t.Errorf("MatchID(3) = %+v, %v", got, ok)
+	}
+	if got, ok := m.MatchID(99, "Deadlift"); ok {
+		t.Errorf("MatchID(99) = %+v, want no match", got)
+	}
+}
+
+func TestNormalizeExerciseName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Bench Press (Barbell)", "barbell bench press"},
+		{"barbell bench press", "barbell bench press"},
+		{"Жим ЛЁЖА", "жим лежа"},
+		{"21s curl", "21s curl"},
+		{"  --  ", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeExerciseName(tt.in); got != tt.want {
+			t.Errorf("normalizeExerciseName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"жим", "жим", 0},
+		{"жим", "жами", 2},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := nameSimilarity("", ""); got != 0 {
+		t.Errorf("nameSimilarity of empty names = %v, want 0", got)
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+// ExerciseType - тип упражнения каталога. От типа зависит, какие кардио-показатели
+// можно сохранить при выполнении упражнения
+type ExerciseType string
+
+const (
+	ExerciseTypeStrength ExerciseType = "strength"
+	ExerciseTypeRunning  ExerciseType = "running"
+	ExerciseTypeWalking  ExerciseType = "walking"
+	ExerciseTypeCycling  ExerciseType = "cycling"
+	ExerciseTypeRowing   ExerciseType = "rowing"
+	ExerciseTypeSwimming ExerciseType = "swimming"
+	// ExerciseTypeCardio - прочие кардиотренажеры (эллипс, степпер), для них допустимы все показатели
+	ExerciseTypeCardio ExerciseType = "cardio"
+)
+
+// cardioMetricSupport - показатели, которые есть не у всех кардио-упражнений. Дистанция, время,
+// темп, скорость, калории и пульс допустимы для любого кардио-упражнения
+type cardioMetricSupport struct {
+	ElevationGain bool
+	Incline       bool // Наклон беговой дорожки
+	Resistance    bool // Уровень сопротивления велотренажера или гребного тренажера
+}
+
+var exerciseTypeCardio = map[ExerciseType]cardioMetricSupport{
+	ExerciseTypeRunning:  {ElevationGain: true, Incline: true},
+	ExerciseTypeWalking:  {ElevationGain: true, Incline: true},
+	ExerciseTypeCycling:  {ElevationGain: true, Resistance: true},
+	ExerciseTypeRowing:   {Resistance: true},
+	ExerciseTypeSwimming: {},
+	ExerciseTypeCardio:   {ElevationGain: true, Incline: true, Resistance: true},
+}
+
+func (t ExerciseType) IsValid() bool {
+	if t == ExerciseTypeStrength {
+		return true
+	}
+	_, ok := exerciseTypeCardio[t]
+	return ok
+}
+
+// IsCardio сообщает, можно ли сохранять кардио-показатели для упражнения этого типа
+func (t ExerciseType) IsCardio() bool {
+	_, ok := exerciseTypeCardio[t]
+	return ok
+}
+
+// DefaultDistanceUnit - единица дистанции, если пользователь ее не указал: гребля и плавание
+// меряются в метрах, остальное - в километрах
+func (t ExerciseType) DefaultDistanceUnit() DistanceUnit {
+	switch t {
+	case ExerciseTypeRowing, ExerciseTypeSwimming:
+		return DistanceMeters
+	}
+	return DistanceKilometers
+}
@@ -0,0 +1,387 @@
+package domain
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExportFormat - формат выгрузки тренировок пользователя
+type ExportFormat string
+
+const (
+	ExportFormatJSON   ExportFormat = "json"   // Один документ ExportDocument; его принимает импорт в формате json
+	ExportFormatCSV    ExportFormat = "csv"    // Строка на каждый подход, а для упражнений без подходов - на упражнение
+	ExportFormatNDJSON ExportFormat = "ndjson" // ExportedTraining на каждой строке
+)
+
+// Valid сообщает, что формат выгрузки поддерживается
+func (f ExportFormat) Valid() bool {
+	switch f {
+	case ExportFormatJSON, ExportFormatCSV, ExportFormatNDJSON:
+		return true
+	}
+	return false
+}
+
+// ExportVersion - версия формата ExportDocument
+const ExportVersion = 1
+
+// ExportCursor - последняя выгруженная тренировка: выгрузка читается пачками по planned_date и id
+type ExportCursor struct {
+	PlannedDate time.Time
+	ID          int64
+}
+
+// ExportDocument - выгрузка в формате json. Тренировки пишутся в trainings по мере чтения
+type ExportDocument struct {
+	Version    int                `json:"version"`
+	ExportedAt time.Time          `json:"exported_at"`
+	Trainings  []ExportedTraining `json:"trainings"`
+}
+
+// ExportedTraining - тренировка в выгрузке. Даты - в формате YYYY-MM-DD, длительности - в секундах
+type ExportedTraining struct {
+	ID                int64              `json:"id"`
+	Title             string             `json:"title"`
+	Status            TrainingStatus     `json:"status"`
+	PlannedDate       string             `json:"planned_date"`
+	ActualDate        *string            `json:"actual_date,omitempty"`
+	StartedAt         *time.Time         `json:"started_at,omitempty"`
+	FinishedAt        *time.Time         `json:"finished_at,omitempty"`
+	Rating            *int32             `json:"rating,omitempty"`
+	TotalDuration     *float64           `json:"total_duration,omitempty"`
+	TotalRestTime     *float64           `json:"total_rest_time,omitempty"`
+	TotalExerciseTime *float64           `json:"total_exercise_time,omitempty"`
+	Exercises         []ExportedExercise `json:"exercises"`
+}
+
+// ExportedExercise - упражнение тренировки в выгрузке. Для упражнений с подходами вес, подходы
+// и повторения посчитаны по рабочим подходам
+type ExportedExercise struct {
+	ExerciseID    int64              `json:"exercise_id"`
+	ExerciseTitle string             `json:"exercise_title"`
+	Position      int32              `json:"position"`
+	GroupID       *int32             `json:"group_id,omitempty"`
+	GroupType     *ExerciseGroupType `json:"group_type,omitempty"`
+	Weight        *decimal.Decimal   `json:"weight,omitempty"`
+	Approaches    *int32             `json:"approaches,omitempty"`
+	Reps          *int32             `json:"reps,omitempty"`
+	Time          *float64           `json:"time,omitempty"`
+	Doing         *float64           `json:"doing,omitempty"`
+	Rest          *float64           `json:"rest,omitempty"`
+	Notes         *string            `json:"notes,omitempty"`
+	Sets          []ExportedSet      `json:"sets"`
+}
+
+// ExportedSet - подход в выгрузке
+type ExportedSet struct {
+	Ordinal  int32            `json:"ordinal"`
+	Weight   *decimal.Decimal `json:"weight,omitempty"`
+	Reps     *int32           `json:"reps,omitempty"`
+	Duration *float64         `json:"duration,omitempty"`
+	RPE      *decimal.Decimal `json:"rpe,omitempty"`
+	IsWarmup bool             `json:"is_warmup"`
+}
+
+// ExportTraining переводит тренировку в формат выгрузки. titles - названия упражнений каталога по ID.
+// Нулевые значения считаются незаданными и не выгружаются
+func ExportTraining(training *Training, titles map[int64]string) ExportedTraining {
+	exported := ExportedTraining{
+		ID:                training.ID,
+		Title:             training.Title,
+		Status:            training.Status,
+		PlannedDate:       training.PlannedDate.Format("2006-01-02"),
+		StartedAt:         exportTime(training.StartedAt),
+		FinishedAt:        exportTime(training.FinishedAt),
+		Rating:            training.Rating,
+		TotalDuration:     exportSeconds(training.TotalDuration),
+		TotalRestTime:     exportSeconds(training.TotalRestTime),
+		TotalExerciseTime: exportSeconds(training.TotalExerciseTime),
+		Exercises:         make([]ExportedExercise, 0, len(training.Exercises)),
+	}
+	if training.ActualDate != nil {
+		date := training.ActualDate.Format("2006-01-02")
+		exported.ActualDate = &date
+	}
+
+	for _, ex := range training.Exercises {
+		exercise := ExportedExercise{
+			ExerciseID:    ex.ExerciseID,
+			ExerciseTitle: titles[ex.ExerciseID],
+			Position:      ex.Position,
+			Weight:        exportDecimal(ex.Weight),
+			Approaches:    exportInt(ex.Approaches),
+			Reps:          exportInt(ex.Reps),
+			Time:          exportSeconds(ex.Time),
+			Doing:         exportSeconds(ex.Doing),
+			Rest:          exportSeconds(ex.Rest),
+			Sets:          make([]ExportedSet, 0, len(ex.Sets)),
+		}
+		if ex.Group != nil {
+			id, groupType := ex.Group.ID, ex.Group.Type
+			exercise.GroupID, exercise.GroupType = &id, &groupType
+		}
+		if ex.Notes != nil && *ex.Notes != "" {
+			exercise.Notes = ex.Notes
+		}
+		for _, set := range ex.Sets {
+			exercise.Sets = append(exercise.Sets, ExportedSet{
+				Ordinal:  set.Ordinal,
+				Weight:   exportDecimal(set.Weight),
+				Reps:     exportInt(set.Reps),
+				Duration: exportSeconds(set.Duration),
+				RPE:      exportDecimal(set.RPE),
+				IsWarmup: set.IsWarmup,
+			})
+		}
+		exported.Exercises = append(exported.Exercises, exercise)
+	}
+	return exported
+}
+
+func exportTime(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func exportSeconds(d *time.Duration) *float64 {
+	if d == nil || *d <= 0 {
+		return nil
+	}
+	seconds := d.Seconds()
+	return &seconds
+}
+
+func exportInt(n *int32) *int32 {
+	if n == nil || *n == 0 {
+		return nil
+	}
+	return n
+}
+
+func exportDecimal(d *decimal.Decimal) *decimal.Decimal {
+	if d == nil || d.IsZero() {
+		return nil
+	}
+	return d
+}
+
+// ExportWriter пишет тренировки в выгрузку по одной, не накапливая их в памяти.
+// Close дописывает конец выгрузки и должен быть вызван после последней тренировки
+type ExportWriter interface {
+	Write(training ExportedTraining) error
+	Close() error
+}
+
+// NewExportWriter возвращает запись выгрузки в формате format
+func NewExportWriter(format ExportFormat, w io.Writer) (ExportWriter, error) {
+	switch format {
+	case ExportFormatJSON:
+		return &jsonExportWriter{w: w}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}, nil
+	case ExportFormatCSV:
+		return &csvExportWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, errors.New("unsupported export format")
+}
+
+// jsonExportWriter пишет ExportDocument: заголовок документа - перед первой тренировкой, конец - в Close
+type jsonExportWriter struct {
+	w       io.Writer
+	started bool
+	count   int
+}
+
+func (j *jsonExportWriter) start() error {
+	if j.started {
+		return nil
+	}
+	j.started = true
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(j.w, `{"version":`+strconv.Itoa(ExportVersion)+`,"exported_at":`+string(exportedAt)+`,"trainings":[`)
+	return err
+}
+
+func (j *jsonExportWriter) Write(training ExportedTraining) error {
+	if err := j.start(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(training)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) Close() error {
+	if err := j.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(j.w, "]}\n")
+	return err
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonExportWriter) Write(training ExportedTraining) error {
+	return n.enc.Encode(training)
+}
+
+func (n *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// exportCSVHeader - колонки выгрузки csv. Колонки тренировки повторяются в каждой строке
+var exportCSVHeader = []string{
+	"training_id", "title", "status", "planned_date", "actual_date", "started_at", "finished_at", "rating",
+	"total_duration", "total_rest_time", "total_exercise_time",
+	"position", "exercise_id", "exercise_title", "group_id", "group_type",
+	"exercise_time", "exercise_doing", "exercise_rest", "exercise_notes", "approaches",
+	"set_ordinal", "is_warmup", "weight", "reps", "duration", "rpe",
+}
+
+type csvExportWriter struct {
+	w       *csv.Writer
+	started bool
+}
+
+func (c *csvExportWriter) start() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	return c.w.Write(exportCSVHeader)
+}
+
+// Write пишет строку на каждый подход тренировки. Упражнение без подходов занимает одну строку
+// с весом и повторениями упражнения, тренировка без упражнений - одну строку с пустыми колонками упражнения
+func (c *csvExportWriter) Write(training ExportedTraining) error {
+	if err := c.start(); err != nil {
+		return err
+	}
+
+	base := []string{
+		strconv.FormatInt(training.ID, 10),
+		training.Title,
+		string(training.Status),
+		training.PlannedDate,
+		csvString(training.ActualDate),
+		csvTime(training.StartedAt),
+		csvTime(training.FinishedAt),
+		csvInt(training.Rating),
+		csvSeconds(training.TotalDuration),
+		csvSeconds(training.TotalRestTime),
+		csvSeconds(training.TotalExerciseTime),
+	}
+	if len(training.Exercises) == 0 {
+		return c.w.Write(append(base, make([]string, len(exportCSVHeader)-len(base))...))
+	}
+
+	for _, ex := range training.Exercises {
+		var groupType *string
+		if ex.GroupType != nil {
+			s := string(*ex.GroupType)
+			groupType = &s
+		}
+		exercise := append(append([]string(nil), base...),
+			strconv.FormatInt(int64(ex.Position), 10),
+			strconv.FormatInt(ex.ExerciseID, 10),
+			ex.ExerciseTitle,
+			csvInt(ex.GroupID),
+			csvString(groupType),
+			csvSeconds(ex.Time),
+			csvSeconds(ex.Doing),
+			csvSeconds(ex.Rest),
+			csvString(ex.Notes),
+			csvInt(ex.Approaches),
+		)
+
+		if len(ex.Sets) == 0 {
+			row := append(exercise, "", "", csvDecimal(ex.Weight), csvInt(ex.Reps), "", "")
+			if err := c.w.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, set := range ex.Sets {
+			row := append(append([]string(nil), exercise...),
+				strconv.FormatInt(int64(set.Ordinal), 10),
+				strconv.FormatBool(set.IsWarmup),
+				csvDecimal(set.Weight),
+				csvInt(set.Reps),
+				csvSeconds(set.Duration),
+				csvDecimal(set.RPE),
+			)
+			if err := c.w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func csvString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func csvInt(n *int32) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*n), 10)
+}
+
+func csvSeconds(s *float64) string {
+	if s == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*s, 'f', -1, 64)
+}
+
+func csvDecimal(d *decimal.Decimal) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
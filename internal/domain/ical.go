@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icalLineLimit - наибольшая длина строки iCalendar в байтах без CRLF (RFC 5545, 3.1)
+const icalLineLimit = 75
+
+// calendarStatusLabels - статус тренировки в описании события
+var calendarStatusLabels = map[TrainingStatus]string{
+	TrainingStatusPlanned:    "запланирована",
+	TrainingStatusInProgress: "идет",
+	TrainingStatusPaused:     "на паузе",
+	TrainingStatusCompleted:  "выполнена",
+	TrainingStatusSkipped:    "пропущена",
+	TrainingStatusCancelled:  "отменена",
+}
+
+// WriteICalendar пишет тренировки в w в формате iCalendar (RFC 5545): событие VEVENT на каждую тренировку.
+// Завершенная тренировка с известным временем начала занимает время от начала до конца, остальные -
+// весь запланированный день. titles - названия упражнений каталога по ID, now - время формирования календаря
+func WriteICalendar(w io.Writer, trainings []*Training, titles map[int64]string, now time.Time) error {
+	iw := &icalWriter{w: bufio.NewWriter(w)}
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:-//Enduran//Trainings//RU")
+	iw.line("CALSCALE:GREGORIAN")
+	iw.line("METHOD:PUBLISH")
+	iw.line("X-WR-CALNAME:" + icalText("Тренировки"))
+	// Подсказка приложениям календаря, как часто обновлять подписку
+	iw.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	iw.line("X-PUBLISHED-TTL:PT1H")
+	for _, training := range trainings {
+		writeTrainingEvent(iw, training, titles, now)
+	}
+	iw.line("END:VCALENDAR")
+	return iw.w.Flush()
+}
+
+func writeTrainingEvent(iw *icalWriter, training *Training, titles map[int64]string, now time.Time) {
+	iw.line("BEGIN:VEVENT")
+	iw.line(fmt.Sprintf("UID:training-%d@trainings.enduran", training.ID))
+	iw.line("DTSTAMP:" + icalDateTime(now))
+
+	if start, end, ok := trainingEventTime(training); ok {
+		iw.line("DTSTART:" + icalDateTime(start))
+		iw.line("DTEND:" + icalDateTime(end))
+	} else {
+		day := training.PlannedDate
+		// Тренировка, отмеченная выполненной без таймера, показывается в день выполнения
+		if training.Status == TrainingStatusCompleted && training.ActualDate != nil {
+			day = *training.ActualDate
+		}
+		iw.line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		iw.line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+	}
+
+	summary := training.Title
+	if training.Status == TrainingStatusCompleted {
+		summary = "✓ " + summary
+	}
+	iw.line("SUMMARY:" + icalText(summary))
+	iw.line("DESCRIPTION:" + icalText(trainingEventDescription(training, titles)))
+
+	switch training.Status {
+	case TrainingStatusSkipped, TrainingStatusCancelled:
+		iw.line("STATUS:CANCELLED")
+	default:
+		iw.line("STATUS:CONFIRMED")
+	}
+	iw.line("TRANSP:TRANSPARENT")
+	iw.line("END:VEVENT")
+}
+
+// trainingEventTime - фактические начало и конец завершенной тренировки. Если время окончания
+// не сохранено, конец считается по общей длительности тренировки
+func trainingEventTime(training *Training) (time.Time, time.Time, bool) {
+	if training.Status != TrainingStatusCompleted || training.StartedAt == nil || training.StartedAt.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+	start := *training.StartedAt
+	switch {
+	case training.FinishedAt != nil && training.FinishedAt.After(start):
+		return start, *training.FinishedAt, true
+	case training.TotalDuration != nil && *training.TotalDuration > 0:
+		return start, start.Add(*training.TotalDuration), true
+	}
+	return start, start, true
+}
+
+// trainingEventDescription - статус тренировки и упражнения в порядке выполнения с нагрузкой
+func trainingEventDescription(training *Training, titles map[int64]string) string {
+	var b strings.Builder
+	b.WriteString("Статус: ")
+	if label, ok := calendarStatusLabels[training.Status]; ok {
+		b.WriteString(label)
+	} else {
+		b.WriteString(string(training.Status))
+	}
+	if training.Rating != nil {
+		fmt.Fprintf(&b, "\nОценка: %d/5", *training.Rating)
+	}
+
+	if len(training.Exercises) > 0 {
+		b.WriteString("\n\nУпражнения:")
+	}
+	for i, ex := range training.Exercises {
+		title := titles[ex.ExerciseID]
+		if title == "" {
+			title = fmt.Sprintf("Упражнение %d", ex.ExerciseID)
+		}
+		fmt.Fprintf(&b, "\n%d. %s", i+1, title)
+		if load := exerciseLoad(ex); load != "" {
+			b.WriteString(" - ")
+			b.WriteString(load)
+		}
+	}
+	return b.String()
+}
+
+// exerciseLoad - нагрузка упражнения вида "3×10, 60 кг"; пустая строка, если нагрузка не задана
+func exerciseLoad(ex TrainedExercise) string {
+	var parts []string
+	switch {
+	case ex.Approaches != nil && *ex.Approaches > 0 && ex.Reps != nil && *ex.Reps > 0:
+		parts = append(parts, fmt.Sprintf("%d×%d", *ex.Approaches, *ex.Reps))
+	case ex.Approaches != nil && *ex.Approaches > 0:
+		parts = append(parts, fmt.Sprintf("%d подх.", *ex.Approaches))
+	case ex.Reps != nil && *ex.Reps > 0:
+		parts = append(parts, fmt.Sprintf("%d повт.", *ex.Reps))
+	}
+	if ex.Weight != nil && ex.Weight.IsPositive() {
+		parts = append(parts, ex.Weight.String()+" кг")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func icalDateTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icalText экранирует значение типа TEXT: обратную косую черту, точку с запятой, запятую и перевод строки
+func icalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// icalWriter пишет строки iCalendar с CRLF, перенося длинные строки. Ошибка записи
+// запоминается bufio.Writer и возвращается из Flush
+type icalWriter struct {
+	w *bufio.Writer
+}
+
+// line пишет строку, перенося ее на строки продолжения, которые начинаются с пробела.
+// Перенос не разрывает символы UTF-8
+func (iw *icalWriter) line(s string) {
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		iw.w.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Пробел в начале строки продолжения входит в ее длину
+		limit = icalLineLimit - 1
+	}
+	iw.w.WriteString(s + "\r\n")
+}
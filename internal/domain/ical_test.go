@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"bufio"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestICalText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Ноги", "Ноги"},
+		{`a\b`, `a\\b`},
+		{"a;b,c", `a\;b\,c`},
+		{"line1\nline2", `line1\nline2`},
+		{"line1\r\nline2", `line1\nline2`},
+		{"line1\rline2", "line1line2"},
+		{`\n`, `\\n`},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := icalText(tt.in); got != tt.want {
+			t.Errorf("icalText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestICalWriterLineFolding(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        string
+		wantLines int
+	}{
+		{"empty", "", 1},
+		{"short", "SUMMARY:Ноги", 1},
+		{"exactly limit", strings.Repeat("a", 75), 1},
+		{"one byte over", strings.Repeat("a", 76), 2},
+		// 75 + 74 байта
+		{"two full lines", strings.Repeat("a", 149), 2},
+		{"three lines", strings.Repeat("a", 150), 3},
+		// Кириллица - по 2 байта на символ, граница 75 приходится на середину символа
+		{"cyrillic", "DESCRIPTION:" + strings.Repeat("ж", 100), 3},
+		// Символы по 4 байта
+		{"emoji", "SUMMARY:" + strings.Repeat("💪", 40), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			iw := &icalWriter{w: bufio.NewWriter(&b)}
+			iw.line(tt.in)
+			if err := iw.w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			out := b.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end with CRLF", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d lines, want %d: %q", len(lines), tt.wantLines, lines)
+			}
+			for i, line := range lines {
+				if len(line) > icalLineLimit {
+					t.Errorf("line %d is %d bytes, limit %d", i, len(line), icalLineLimit)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, line)
+				}
+			}
+			// Разворачивание строк по RFC 5545 возвращает исходное значение
+			if unfolded := strings.ReplaceAll(strings.TrimSuffix(out, "\r\n"), "\r\n ", ""); unfolded != tt.in {
+				t.Errorf("unfolded = %q, want %q", unfolded, tt.in)
+			}
+		})
+	}
+}
+
+func TestWriteICalendar(t *testing.T) {
+	moscow := time.FixedZone("UTC+3", 3*60*60)
+	started := time.Date(2026, time.January, 5, 18, 0, 0, 0, moscow)
+	finished := started.Add(75 * time.Minute)
+	rating := int32(4)
+	approaches, reps := int32(3), int32(10)
+	weight := decimal.RequireFromString("62.5")
+	now := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	trainings := []*Training{
+		{
+			ID:          1,
+			Title:       "Грудь; спина, плечи",
+			PlannedDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+			StartedAt:   &started,
+			FinishedAt:  &finished,
+			Rating:      &rating,
+			Status:      TrainingStatusCompleted,
+			Exercises: []TrainedExercise{
+				{ExerciseID: 7, Approaches: &approaches, Reps: &reps, Weight: &weight},
+				{ExerciseID: 8},
+			},
+		},
+		{
+			ID:          2,
+			Title:       "Ноги",
+			PlannedDate: time.Date(2026, time.January, 12, 0, 0, 0, 0, time.UTC),
+			Status:      TrainingStatusSkipped,
+		},
+	}
+
+	var b strings.Builder
+	if err := WriteICalendar(&b, trainings, map[int64]string{7: "Жим лежа"}, now); err != nil {
+		t.Fatalf("WriteICalendar() error: %v", err)
+	}
+	out := b.String()
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:training-1@trainings.enduran\r\n",
+		"DTSTAMP:20260110T120000Z\r\n",
+		"DTSTART:20260105T150000Z\r\n",
+		"DTEND:20260105T161500Z\r\n",
+		`SUMMARY:✓ Грудь\; спина\, плечи` + "\r\n",
+		`DESCRIPTION:Статус: выполнена\nОценка: 4/5\n\nУпражнения:\n1. Жим лежа - 3×10\, 62.5 кг\n2. Упражнение 8` + "\r\n",
+		"UID:training-2@trainings.enduran\r\n",
+		"DTSTART;VALUE=DATE:20260112\r\n",
+		"DTEND;VALUE=DATE:20260113\r\n",
+		"STATUS:CANCELLED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("calendar does not contain %q:\n%s", want, unfolded)
+		}
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 2 || strings.Count(out, "END:VEVENT") != 2 {
+		t.Errorf("calendar must contain two events:\n%s", out)
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > icalLineLimit {
+			t.Errorf("line %d is %d bytes: %q", i, len(line), line)
+		}
+	}
+}
+
+func TestWriteICalendarAllDayCompleted(t *testing.T) {
+	actual := time.Date(2026, time.January, 6, 0, 0, 0, 0, time.UTC)
+	training := &Training{
+		ID:          3,
+		Title:       "Бег",
+		PlannedDate: time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC),
+		ActualDate:  &actual,
+		Status:      TrainingStatusCompleted,
+	}
+	var b strings.Builder
+	if err := WriteICalendar(&b, []*Training{training}, nil, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "DTSTART;VALUE=DATE:20260106\r\n") {
+		t.Errorf("completed training without timer must be shown on the actual date:\n%s", b.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestWriteICalendarWriteError(t *testing.T) {
+	trainings := make([]*Training, 100)
+	for i := range trainings {
+		trainings[i] = &Training{ID: int64(i), Title: "Тренировка", Status: TrainingStatusPlanned}
+	}
+	if err := WriteICalendar(failingWriter{}, trainings, nil, time.Now()); err == nil {
+		t.Fatal("WriteICalendar() must return the write error")
+	}
+}
@@ -0,0 +1,230 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ImportFormat - приложение, из выгрузки которого импортируется история тренировок
+type ImportFormat string
+
+const (
+	ImportFormatStrong ImportFormat = "strong"
+	ImportFormatHevy   ImportFormat = "hevy"
+	ImportFormatJSON   ImportFormat = "json" // Выгрузка этого сервиса в формате json
+
+	// Кардио-активность из файла часов или велокомпьютера: тренировка с одним кардио-упражнением
+	ImportFormatGPX ImportFormat = "gpx"
+	ImportFormatTCX ImportFormat = "tcx"
+	ImportFormatFIT ImportFormat = "fit"
+)
+
+// Valid сообщает, что формат выгрузки поддерживается
+func (f ImportFormat) Valid() bool {
+	switch f {
+	case ImportFormatStrong, ImportFormatHevy, ImportFormatJSON,
+		ImportFormatGPX, ImportFormatTCX, ImportFormatFIT:
+		return true
+	}
+	return false
+}
+
+// ImportedWorkout - тренировка из выгрузки с упражнениями в порядке выполнения
+type ImportedWorkout struct {
+	Title     string
+	StartedAt time.Time      // Время начала в часовом поясе пользователя
+	Untimed   bool           // Время начала неизвестно, StartedAt - полночь дня тренировки
+	Duration  *time.Duration // Длительность, если она есть в выгрузке
+	Exercises []ImportedExercise
+
+	// Оценка и таймеры есть только в выгрузке json
+	Rating       *int32
+	RestTime     *time.Duration
+	ExerciseTime *time.Duration
+}
+
+// Date возвращает день тренировки в часовом поясе пользователя
+func (w ImportedWorkout) Date() time.Time {
+	return LocalDate(w.StartedAt, w.StartedAt.Location())
+}
+
+// key - тренировка считается уже импортированной, если у пользователя есть тренировка
+// с тем же названием в тот же день
+func (w ImportedWorkout) key() string {
+	return importKey(w.Date(), w.Title)
+}
+
+func importKey(date time.Time, title string) string {
+	return date.Format("2006-01-02") + "|" + strings.ToLower(strings.TrimSpace(title))
+}
+
+// ImportedExercise - упражнение из выгрузки. ExerciseID заполняется после сопоставления с каталогом;
+// в выгрузке json он задан заранее
+type ImportedExercise struct {
+	Name       string
+	ExerciseID int64
+	Notes      *string
+	Group      *ExerciseGroup // Суперсет из выгрузки Hevy или группа из выгрузки json
+	Sets       []TrainedSet   // В порядке выполнения
+
+	// Нагрузка упражнения без подходов и таймеры есть только в выгрузке json
+	Weight     *decimal.Decimal
+	Approaches *int32
+	Reps       *int32
+	Time       *time.Duration
+	Doing      *time.Duration
+	Rest       *time.Duration
+
+	// Кардио-активность сопоставляется по названию вида активности, а если его нет в каталоге -
+	// по другим его названиям. Показатели есть только в файлах GPX, TCX и FIT
+	AltNames []string
+	Cardio   *CardioMetrics
+}
+
+// TrainingTitle - день и название существующей тренировки пользователя
+type TrainingTitle struct {
+	Date  time.Time
+	Title string
+}
+
+// ImportSkipReason - почему тренировка из выгрузки не импортируется
+type ImportSkipReason string
+
+const (
+	ImportSkipDuplicate          ImportSkipReason = "duplicate"            // Такая тренировка уже есть у пользователя или раньше в файле
+	ImportSkipNoMatchedExercises ImportSkipReason = "no_matched_exercises" // Ни одно упражнение не найдено в каталоге
+)
+
+// SkippedWorkout - тренировка из выгрузки, которая не импортируется
+type SkippedWorkout struct {
+	Title  string
+	Date   time.Time
+	Reason ImportSkipReason
+}
+
+// UnmatchedExercise - название из выгрузки без пары в каталоге. Его подходы пропускаются
+type UnmatchedExercise struct {
+	Name string
+	Sets int
+}
+
+// RejectedCardio - кардио-показатели из файла, которые не подходят сопоставленному упражнению
+// каталога (например, силовому) или выходят за допустимые границы. Упражнение импортируется без них
+type RejectedCardio struct {
+	Name          string
+	ExerciseTitle string
+	Date          time.Time
+	Reason        string
+}
+
+// ImportReport - итог импорта или пробного запуска
+type ImportReport struct {
+	Format      ImportFormat
+	DryRun      bool
+	Workouts    int // Тренировок в файле
+	Imported    int // Импортировано тренировок; при пробном запуске - сколько было бы импортировано
+	Sets        int // Подходов в импортированных тренировках
+	Matches     []ExerciseMatch
+	Unmatched   []UnmatchedExercise
+	Skipped     []SkippedWorkout
+	Cardio      []RejectedCardio
+	TrainingIDs []int64 // Созданные тренировки; при пробном запуске пусто
+}
+
+// PlanImport сопоставляет упражнения тренировок с каталогом и отбирает тренировки для импорта:
+// упражнения без пары в каталоге и кардио-показатели, не подходящие типу упражнения, отбрасываются,
+// а тренировки без упражнений и уже импортированные (с тем же названием в тот же день, что у существующей
+// или предыдущей тренировки) пропускаются. Возвращает тренировки для импорта и отчет без созданных тренировок
+func PlanImport(format ImportFormat, workouts []ImportedWorkout, matcher *ExerciseMatcher, existing []TrainingTitle) ([]ImportedWorkout, *ImportReport) {
+	report := &ImportReport{Format: format, Workouts: len(workouts)}
+
+	seen := make(map[string]bool, len(existing))
+	for _, t := range existing {
+		seen[importKey(t.Date, t.Title)] = true
+	}
+
+	matches := make(map[string]*ExerciseMatch)
+	unmatched := make(map[string]int)
+	var unmatchedOrder []string
+
+	planned := make([]ImportedWorkout, 0, len(workouts))
+	for _, workout := range workouts {
+		exercises := make([]ImportedExercise, 0, len(workout.Exercises))
+		for _, ex := range workout.Exercises {
+			key := ex.Name
+			if ex.ExerciseID != 0 {
+				key = fmt.Sprintf("%d|%s", ex.ExerciseID, ex.Name)
+			}
+			match, ok := matches[key]
+			if !ok {
+				m, found := matcher.MatchID(ex.ExerciseID, ex.Name)
+				if !found {
+					m, found = matcher.Match(ex.Name)
+				}
+				for _, name := range ex.AltNames {
+					if found {
+						break
+					}
+					if m, found = matcher.Match(name); found {
+						m.Name = ex.Name
+					}
+				}
+				if found {
+					match = &m
+					report.Matches = append(report.Matches, m)
+				}
+				matches[key] = match
+			}
+			if match == nil {
+				if _, counted := unmatched[ex.Name]; !counted {
+					unmatchedOrder = append(unmatchedOrder, ex.Name)
+				}
+				unmatched[ex.Name] += len(ex.Sets)
+				continue
+			}
+			ex.ExerciseID = match.ExerciseID
+			if ex.Cardio != nil {
+				// Показатели проверяются так же, как при вводе вручную, чтобы их потом можно было изменить
+				cardio := *ex.Cardio
+				if err := cardio.Normalize(match.ExerciseType); err != nil {
+					report.Cardio = append(report.Cardio, RejectedCardio{
+						Name:          ex.Name,
+						ExerciseTitle: match.ExerciseTitle,
+						Date:          workout.Date(),
+						Reason:        err.Error(),
+					})
+					ex.Cardio = nil
+				} else {
+					ex.Cardio = &cardio
+				}
+			}
+			exercises = append(exercises, ex)
+		}
+
+		skipped := SkippedWorkout{Title: workout.Title, Date: workout.Date()}
+		switch key := workout.key(); {
+		case seen[key]:
+			skipped.Reason = ImportSkipDuplicate
+		case len(exercises) == 0:
+			skipped.Reason = ImportSkipNoMatchedExercises
+		default:
+			seen[key] = true
+			workout.Exercises = exercises
+			planned = append(planned, workout)
+			report.Imported++
+			for _, ex := range exercises {
+				report.Sets += len(ex.Sets)
+			}
+			continue
+		}
+		report.Skipped = append(report.Skipped, skipped)
+	}
+
+	for _, name := range unmatchedOrder {
+		report.Unmatched = append(report.Unmatched, UnmatchedExercise{Name: name, Sets: unmatched[name]})
+	}
+	return planned, report
+}
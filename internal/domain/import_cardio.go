@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParseImportCardio разбирает файл кардио-активности в формате GPX, TCX или FIT: каждая активность
+// становится тренировкой с одним кардио-упражнением, время начала и конца берется из файла.
+// Тренировки возвращаются по возрастанию времени начала
+func ParseImportCardio(format ImportFormat, r io.Reader, loc *time.Location) ([]ImportedWorkout, error) {
+	var tracks []*cardioTrack
+	var err error
+	switch format {
+	case ImportFormatGPX:
+		tracks, err = parseGPX(r)
+	case ImportFormatTCX:
+		tracks, err = parseTCX(r)
+	case ImportFormatFIT:
+		var data []byte
+		if data, err = io.ReadAll(r); err == nil {
+			tracks, err = parseFIT(data)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", errMalformedImportFile, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	workouts := make([]ImportedWorkout, 0, len(tracks))
+	for i, track := range tracks {
+		workout, err := track.workout(loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: activity %d has no start time", errMalformedImportFile, i+1)
+		}
+		workouts = append(workouts, workout)
+	}
+	sort.SliceStable(workouts, func(i, j int) bool {
+		return workouts[i].StartedAt.Before(workouts[j].StartedAt)
+	})
+	return workouts, nil
+}
+
+// gpxFile - трек GPX 1.1. Пульс читается из расширения Garmin TrackPointExtension
+type gpxFile struct {
+	Metadata struct {
+		Name string `xml:"name"`
+	} `xml:"metadata"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat       float64   `xml:"lat,attr"`
+	Lon       float64   `xml:"lon,attr"`
+	Elevation *float64  `xml:"ele"`
+	Time      time.Time `xml:"time"`
+	HeartRate *int32    `xml:"extensions>TrackPointExtension>hr"`
+}
+
+// parseGPX собирает все треки файла в одну активность. Точки без времени пропускаются,
+// расстояние между сегментами трека не учитывается
+func parseGPX(r io.Reader) ([]*cardioTrack, error) {
+	var file gpxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformedImportFile, err)
+	}
+
+	track := &cardioTrack{Sport: CardioSportOther, Title: strings.TrimSpace(file.Metadata.Name)}
+	for i, trk := range file.Tracks {
+		if i == 0 {
+			track.Sport = parseCardioSport(trk.Type)
+			if name := strings.TrimSpace(trk.Name); name != "" {
+				track.Title = name
+			}
+		}
+		for _, segment := range trk.Segments {
+			newSegment := true
+			for _, p := range segment.Points {
+				if p.Time.IsZero() {
+					continue
+				}
+				lat, lon := p.Lat, p.Lon
+				track.Points = append(track.Points, trackPoint{
+					Time:       p.Time,
+					Lat:        &lat,
+					Lon:        &lon,
+					Elevation:  p.Elevation,
+					HeartRate:  p.HeartRate,
+					NewSegment: newSegment,
+				})
+				newSegment = false
+			}
+		}
+	}
+	if len(track.Points) == 0 {
+		return nil, fmt.Errorf("%w: track has no timestamped points", errMalformedImportFile)
+	}
+	return []*cardioTrack{track}, nil
+}
+
+// tcxFile - выгрузка Garmin Training Center: активности из кругов с точками трека и итогами круга
+type tcxFile struct {
+	Activities []struct {
+		Sport string `xml:"Sport,attr"`
+		Notes string `xml:"Notes"`
+		Laps  []struct {
+			StartTime    time.Time `xml:"StartTime,attr"`
+			TotalSeconds float64   `xml:"TotalTimeSeconds"`
+			Distance     *float64  `xml:"DistanceMeters"`
+			AvgHeartRate *int32    `xml:"AverageHeartRateBpm>Value"`
+			MaxHeartRate *int32    `xml:"MaximumHeartRateBpm>Value"`
+			Tracks       []struct {
+				Points []tcxPoint `xml:"Trackpoint"`
+			} `xml:"Track"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+type tcxPoint struct {
+	Time      time.Time `xml:"Time"`
+	Lat       *float64  `xml:"Position>LatitudeDegrees"`
+	Lon       *float64  `xml:"Position>LongitudeDegrees"`
+	Altitude  *float64  `xml:"AltitudeMeters"`
+	Distance  *float64  `xml:"DistanceMeters"`
+	HeartRate *int32    `xml:"HeartRateBpm>Value"`
+}
+
+// parseTCX возвращает активность на каждый элемент Activity. Дистанция, время и пульс берутся
+// из итогов кругов, набор высоты считается по точкам
+func parseTCX(r io.Reader) ([]*cardioTrack, error) {
+	var file tcxFile
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformedImportFile, err)
+	}
+	if len(file.Activities) == 0 {
+		return nil, fmt.Errorf("%w: file has no activities", errMalformedImportFile)
+	}
+
+	tracks := make([]*cardioTrack, 0, len(file.Activities))
+	for _, activity := range file.Activities {
+		track := &cardioTrack{
+			Sport: parseCardioSport(activity.Sport),
+			Title: strings.TrimSpace(activity.Notes),
+		}
+
+		var distance, seconds, hrWeighted, hrSeconds float64
+		for i, lap := range activity.Laps {
+			if math.IsNaN(lap.TotalSeconds) || math.IsInf(lap.TotalSeconds, 0) || lap.TotalSeconds < 0 {
+				return nil, fmt.Errorf("%w: invalid lap time %v", errMalformedImportFile, lap.TotalSeconds)
+			}
+			if i == 0 {
+				track.Start = lap.StartTime
+			}
+			if end := lap.StartTime.Add(time.Duration(lap.TotalSeconds * float64(time.Second))); end.After(track.End) {
+				track.End = end
+			}
+			seconds += lap.TotalSeconds
+			if lap.Distance != nil {
+				distance += *lap.Distance
+			}
+			if lap.AvgHeartRate != nil && lap.TotalSeconds > 0 {
+				hrWeighted += float64(*lap.AvgHeartRate) * lap.TotalSeconds
+				hrSeconds += lap.TotalSeconds
+			}
+			if lap.MaxHeartRate != nil && (track.MaxHeartRate == nil || *lap.MaxHeartRate > *track.MaxHeartRate) {
+				hr := *lap.MaxHeartRate
+				track.MaxHeartRate = &hr
+			}
+
+			for _, trk := range lap.Tracks {
+				newSegment := true
+				for _, p := range trk.Points {
+					if p.Time.IsZero() {
+						continue
+					}
+					track.Points = append(track.Points, trackPoint{
+						Time:       p.Time,
+						Lat:        p.Lat,
+						Lon:        p.Lon,
+						Elevation:  p.Altitude,
+						Distance:   p.Distance,
+						HeartRate:  p.HeartRate,
+						NewSegment: newSegment,
+					})
+					newSegment = false
+				}
+			}
+		}
+
+		if distance > 0 {
+			track.Distance = &distance
+		}
+		// Время круга в TCX - время таймера, без пауз
+		if seconds > 0 {
+			moving := time.Duration(seconds * float64(time.Second)).Round(time.Second)
+			track.MovingTime = &moving
+		}
+		if hrSeconds > 0 {
+			avg := int32(hrWeighted/hrSeconds + 0.5)
+			track.AvgHeartRate = &avg
+		}
+		tracks = append(tracks, track)
+	}
+	return tracks, nil
+}
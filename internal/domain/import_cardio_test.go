@@ -0,0 +1,263 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1"
+     xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1">
+  <metadata><name>Morning</name></metadata>
+  <trk>
+    <name>Утренняя пробежка</name>
+    <type>running</type>
+    <trkseg>
+      <trkpt lat="55.0" lon="37.0">
+        <ele>100</ele>
+        <time>2026-01-05T10:00:00Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>140</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+      <trkpt lat="55.0" lon="37.0005"><ele>101</ele></trkpt>
+      <trkpt lat="55.0" lon="37.001">
+        <ele>105</ele>
+        <time>2026-01-05T10:00:30Z</time>
+        <extensions><gpxtpx:TrackPointExtension><gpxtpx:hr>160</gpxtpx:hr></gpxtpx:TrackPointExtension></extensions>
+      </trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="56.0" lon="38.0"><time>2026-01-05T10:10:00Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`
+
+const testTCX = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+  <Activities>
+    <Activity Sport="Biking">
+      <Lap StartTime="2026-01-06T08:00:00Z">
+        <TotalTimeSeconds>1800</TotalTimeSeconds>
+        <DistanceMeters>10000</DistanceMeters>
+      </Lap>
+    </Activity>
+    <Activity Sport="Running">
+      <Notes>Интервалы</Notes>
+      <Lap StartTime="2026-01-05T07:00:00Z">
+        <TotalTimeSeconds>600</TotalTimeSeconds>
+        <DistanceMeters>2000</DistanceMeters>
+        <AverageHeartRateBpm><Value>140</Value></AverageHeartRateBpm>
+        <MaximumHeartRateBpm><Value>160</Value></MaximumHeartRateBpm>
+      </Lap>
+      <Lap StartTime="2026-01-05T07:10:00Z">
+        <TotalTimeSeconds>300</TotalTimeSeconds>
+        <DistanceMeters>1000</DistanceMeters>
+        <AverageHeartRateBpm><Value>170</Value></AverageHeartRateBpm>
+        <MaximumHeartRateBpm><Value>180</Value></MaximumHeartRateBpm>
+        <Track>
+          <Trackpoint><Time>2026-01-05T07:10:00Z</Time><AltitudeMeters>100</AltitudeMeters></Trackpoint>
+          <Trackpoint><Time>2026-01-05T07:15:00Z</Time><AltitudeMeters>110</AltitudeMeters></Trackpoint>
+        </Track>
+      </Lap>
+    </Activity>
+  </Activities>
+</TrainingCenterDatabase>`
+
+func TestParseImportCardioGPX(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	workouts, err := ParseImportCardio(ImportFormatGPX, strings.NewReader(testGPX), loc)
+	if err != nil {
+		t.Fatalf("ParseImportCardio() error: %v", err)
+	}
+	if len(workouts) != 1 {
+		t.Fatalf("ParseImportCardio() returned %d workouts, want 1", len(workouts))
+	}
+
+	w := workouts[0]
+	if w.Title != "Утренняя пробежка" {
+		t.Errorf("Title = %q, want track name", w.Title)
+	}
+	if want := time.Date(2026, time.January, 5, 13, 0, 0, 0, loc); !w.StartedAt.Equal(want) || w.StartedAt.Location() != loc {
+		t.Errorf("StartedAt = %v, want %v", w.StartedAt, want)
+	}
+	if w.Duration == nil || *w.Duration != 10*time.Minute {
+		t.Errorf("Duration = %v, want 10m", w.Duration)
+	}
+	if len(w.Exercises) != 1 {
+		t.Fatalf("exercises = %d, want 1", len(w.Exercises))
+	}
+	ex := w.Exercises[0]
+	if ex.Name != "Бег" || strings.Join(ex.AltNames, ",") != "Running,Run" {
+		t.Errorf("exercise = %q, alt names %v", ex.Name, ex.AltNames)
+	}
+
+	c := ex.Cardio
+	// 0.001 градуса долготы на широте 55 - около 63.8 м; второй сегмент трека не учитывается
+	if c.Distance == nil || c.Distance.InexactFloat64() < 63 || c.Distance.InexactFloat64() > 64.5 {
+		t.Errorf("Distance = %v, want about 63.8", c.Distance)
+	}
+	if c.MovingTime == nil || *c.MovingTime != 30*time.Second {
+		t.Errorf("MovingTime = %v, want 30s", c.MovingTime)
+	}
+	if c.ElevationGain == nil || c.ElevationGain.String() != "5" {
+		t.Errorf("ElevationGain = %v, want 5", c.ElevationGain)
+	}
+	if c.AvgHeartRate == nil || *c.AvgHeartRate != 150 || c.MaxHeartRate == nil || *c.MaxHeartRate != 160 {
+		t.Errorf("heart rate = %v/%v, want 150/160", c.AvgHeartRate, c.MaxHeartRate)
+	}
+	if c.AvgPace == nil || c.AvgSpeed == nil {
+		t.Errorf("pace and speed must be derived: %v, %v", c.AvgPace, c.AvgSpeed)
+	}
+}
+
+func TestParseImportCardioTCX(t *testing.T) {
+	workouts, err := ParseImportCardio(ImportFormatTCX, strings.NewReader(testTCX), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseImportCardio() error: %v", err)
+	}
+	if len(workouts) != 2 {
+		t.Fatalf("ParseImportCardio() returned %d workouts, want 2", len(workouts))
+	}
+
+	run, ride := workouts[0], workouts[1]
+	if run.Title != "Интервалы" || run.Exercises[0].Name != "Бег" {
+		t.Errorf("first workout = %q with %q, want the earlier run", run.Title, run.Exercises[0].Name)
+	}
+	if run.Duration == nil || *run.Duration != 15*time.Minute {
+		t.Errorf("run Duration = %v, want 15m", run.Duration)
+	}
+	c := run.Exercises[0].Cardio
+	if c.Distance == nil || c.Distance.String() != "3000" {
+		t.Errorf("run Distance = %v, want 3000 from laps", c.Distance)
+	}
+	if c.MovingTime == nil || *c.MovingTime != 15*time.Minute {
+		t.Errorf("run MovingTime = %v, want 15m", c.MovingTime)
+	}
+	// Средний пульс взвешивается по времени кругов: (140*600 + 170*300) / 900
+	if c.AvgHeartRate == nil || *c.AvgHeartRate != 150 || c.MaxHeartRate == nil || *c.MaxHeartRate != 180 {
+		t.Errorf("run heart rate = %v/%v, want 150/180", c.AvgHeartRate, c.MaxHeartRate)
+	}
+	if c.ElevationGain == nil || c.ElevationGain.String() != "10" {
+		t.Errorf("run ElevationGain = %v, want 10", c.ElevationGain)
+	}
+	if c.AvgPace == nil || *c.AvgPace != 5*time.Minute {
+		t.Errorf("run AvgPace = %v, want 5m", c.AvgPace)
+	}
+
+	if ride.Title != "Велосипед" || ride.Exercises[0].Name != "Велосипед" {
+		t.Errorf("second workout = %q with %q, want an untitled ride", ride.Title, ride.Exercises[0].Name)
+	}
+	if speed := ride.Exercises[0].Cardio.AvgSpeed; speed == nil || speed.String() != "20" {
+		t.Errorf("ride AvgSpeed = %v, want 20", speed)
+	}
+}
+
+func TestParseImportCardioMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ImportFormat
+		data   string
+	}{
+		{"unsupported format", ImportFormatStrong, testGPX},
+		{"empty gpx", ImportFormatGPX, ""},
+		{"not xml", ImportFormatGPX, "lat,lon\n55,37\n"},
+		{"unclosed gpx", ImportFormatGPX, `<gpx><trk><trkseg><trkpt lat="55" lon="37">`},
+		{"gpx without points", ImportFormatGPX, `<gpx><trk><trkseg></trkseg></trk></gpx>`},
+		{"gpx points without time", ImportFormatGPX, `<gpx><trk><trkseg><trkpt lat="55" lon="37"/></trkseg></trk></gpx>`},
+		{"gpx invalid time", ImportFormatGPX, `<gpx><trk><trkseg><trkpt lat="55" lon="37"><time>yesterday</time></trkpt></trkseg></trk></gpx>`},
+		{"gpx invalid latitude", ImportFormatGPX, `<gpx><trk><trkseg><trkpt lat="north" lon="37"><time>2026-01-05T10:00:00Z</time></trkpt></trkseg></trk></gpx>`},
+		{"empty tcx", ImportFormatTCX, ""},
+		{"tcx without activities", ImportFormatTCX, `<TrainingCenterDatabase><Activities/></TrainingCenterDatabase>`},
+		{"tcx activity without laps", ImportFormatTCX, `<TrainingCenterDatabase><Activities><Activity Sport="Running"/></Activities></TrainingCenterDatabase>`},
+		{"tcx NaN lap time", ImportFormatTCX, tcxLap("NaN", "1000")},
+		{"tcx infinite lap time", ImportFormatTCX, tcxLap("Inf", "1000")},
+		{"tcx negative lap time", ImportFormatTCX, tcxLap("-60", "1000")},
+		{"empty fit", ImportFormatFIT, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workouts, err := ParseImportCardio(tt.format, strings.NewReader(tt.data), time.UTC)
+			if !errors.Is(err, errMalformedImportFile) {
+				t.Fatalf("ParseImportCardio() = %+v, %v; want malformed file error", workouts, err)
+			}
+		})
+	}
+}
+
+func tcxLap(seconds, distance string) string {
+	return `<TrainingCenterDatabase><Activities><Activity Sport="Running"><Lap StartTime="2026-01-05T07:00:00Z">` +
+		`<TotalTimeSeconds>` + seconds + `</TotalTimeSeconds><DistanceMeters>` + distance + `</DistanceMeters>` +
+		`</Lap></Activity></Activities></TrainingCenterDatabase>`
+}
+
+func TestParseImportCardioNonFiniteValues(t *testing.T) {
+	// Значения, которые дают бесконечность или NaN, отбрасываются, а не роняют разбор
+	tests := []struct {
+		name       string
+		format     ImportFormat
+		data       string
+		noDistance bool
+	}{
+		{"infinite lap distance", ImportFormatTCX, tcxLap("60", "Inf"), true},
+		{"overflowing lap distance", ImportFormatTCX, strings.Replace(tcxLap("60", "1e308"), "</Lap>",
+			`</Lap><Lap StartTime="2026-01-05T07:01:00Z"><TotalTimeSeconds>60</TotalTimeSeconds><DistanceMeters>1e308</DistanceMeters></Lap>`, 1), true},
+		{"infinite elevation", ImportFormatGPX, `<gpx><trk><trkseg>` +
+			`<trkpt lat="55" lon="37"><ele>1</ele><time>2026-01-05T10:00:00Z</time></trkpt>` +
+			`<trkpt lat="55" lon="37"><ele>Inf</ele><time>2026-01-05T10:01:00Z</time></trkpt>` +
+			`</trkseg></trk></gpx>`, false},
+		{"NaN coordinates", ImportFormatGPX, `<gpx><trk><trkseg>` +
+			`<trkpt lat="NaN" lon="37"><time>2026-01-05T10:00:00Z</time></trkpt>` +
+			`<trkpt lat="55" lon="NaN"><time>2026-01-05T10:01:00Z</time></trkpt>` +
+			`</trkseg></trk></gpx>`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workouts, err := ParseImportCardio(tt.format, strings.NewReader(tt.data), time.UTC)
+			if err != nil {
+				t.Fatalf("ParseImportCardio() error: %v", err)
+			}
+			if len(workouts) != 1 || workouts[0].Exercises[0].Cardio == nil {
+				t.Fatalf("ParseImportCardio() = %+v", workouts)
+			}
+			if c := workouts[0].Exercises[0].Cardio; tt.noDistance && c.Distance != nil {
+				t.Errorf("Distance = %v, want none", c.Distance)
+			}
+		})
+	}
+}
+
+func TestParseCardioSport(t *testing.T) {
+	tests := []struct {
+		in   string
+		want CardioSport
+	}{
+		{"running", CardioSportRunning},
+		{"Running", CardioSportRunning},
+		{"treadmill_running", CardioSportRunning},
+		{"Biking", CardioSportCycling},
+		{"mountain_biking", CardioSportCycling},
+		{"walk", CardioSportWalking},
+		{"Hiking", CardioSportHiking},
+		{"open_water_swimming", CardioSportSwimming},
+		{"Other", CardioSportOther},
+		{"", CardioSportOther},
+	}
+	for _, tt := range tests {
+		if got := parseCardioSport(tt.in); got != tt.want {
+			t.Errorf("parseCardioSport(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func FuzzParseImportCardio(f *testing.F) {
+	f.Add("gpx", []byte(testGPX))
+	f.Add("tcx", []byte(testTCX))
+	f.Add("tcx", []byte(tcxLap("1e400", "-1e400")))
+	f.Add("fit", testFITFile())
+	f.Fuzz(func(t *testing.T, format string, data []byte) {
+		// Проверяется только отсутствие паники на произвольном файле
+		_, _ = ParseImportCardio(ImportFormat(format), bytes.NewReader(data), time.UTC)
+	})
+}
@@ -0,0 +1,338 @@
+package domain
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var errMalformedImportFile = errors.New("malformed import file")
+
+// poundsToKilograms - множитель для выгрузок с весом в фунтах
+var poundsToKilograms = decimal.RequireFromString("0.45359237")
+
+// maxImportedWeight - наибольший вес подхода, который помещается в DECIMAL(5,2)
+var maxImportedWeight = decimal.RequireFromString("999.99")
+
+// importColumns - названия колонок выгрузки (в нижнем регистре) для каждого поля подхода.
+// Первое найденное название используется
+type importColumns struct {
+	title, start, end, duration     []string
+	exercise, notes, order, setType []string
+	weightKg, weightLbs, weightUnit []string
+	reps, seconds, rpe, superset    []string
+	startLayouts                    []string
+	warmupOrders, warmupTypes       map[string]bool
+	requiredColumns                 []string
+}
+
+var importFormats = map[ImportFormat]importColumns{
+	// Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+	ImportFormatStrong: {
+		title:           []string{"workout name"},
+		start:           []string{"date"},
+		duration:        []string{"duration"},
+		exercise:        []string{"exercise name"},
+		notes:           []string{"notes"},
+		order:           []string{"set order"},
+		weightKg:        []string{"weight", "weight (kg)"},
+		weightLbs:       []string{"weight (lbs)"},
+		weightUnit:      []string{"weight unit"},
+		reps:            []string{"reps"},
+		seconds:         []string{"seconds"},
+		rpe:             []string{"rpe"},
+		startLayouts:    []string{"2006-01-02 15:04:05", "2006-01-02 15:04"},
+		warmupOrders:    map[string]bool{"w": true},
+		requiredColumns: []string{"date", "workout name", "exercise name"},
+	},
+	// "title","start_time","end_time","description","exercise_title","superset_id","exercise_notes",
+	// "set_index","set_type","weight_kg","reps","distance_km","duration_seconds","rpe"
+	ImportFormatHevy: {
+		title:           []string{"title"},
+		start:           []string{"start_time"},
+		end:             []string{"end_time"},
+		exercise:        []string{"exercise_title"},
+		notes:           []string{"exercise_notes"},
+		setType:         []string{"set_type"},
+		weightKg:        []string{"weight_kg"},
+		weightLbs:       []string{"weight_lbs"},
+		reps:            []string{"reps"},
+		seconds:         []string{"duration_seconds"},
+		rpe:             []string{"rpe"},
+		superset:        []string{"superset_id"},
+		startLayouts:    []string{"2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", "2006-01-02T15:04:05"},
+		warmupTypes:     map[string]bool{"warmup": true},
+		requiredColumns: []string{"title", "start_time", "exercise_title"},
+	},
+}
+
+// ParseImportCSV разбирает выгрузку Strong или Hevy: строка на каждый подход. Строки одной тренировки
+// (одинаковые время начала и название) объединяются, подряд идущие строки одного упражнения -
+// в упражнение с подходами. Время без часового пояса считается временем в loc.
+// Тренировки возвращаются по возрастанию времени начала
+func ParseImportCSV(format ImportFormat, r io.Reader, loc *time.Location) ([]ImportedWorkout, error) {
+	columns, ok := importFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported format %q", errMalformedImportFile, format)
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read header: %v", errMalformedImportFile, err)
+	}
+	// Strong в некоторых локалях разделяет колонки точкой с запятой
+	if len(header) == 1 && strings.Contains(header[0], ";") {
+		header = strings.Split(header[0], ";")
+		reader.Comma = ';'
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := index[name]; !ok {
+			index[name] = i
+		}
+	}
+	for _, name := range columns.requiredColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", errMalformedImportFile, name)
+		}
+	}
+
+	var workouts []*ImportedWorkout
+	byKey := make(map[string]*ImportedWorkout)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", errMalformedImportFile, line, err)
+		}
+		row := importRow{record: record, index: index}
+		if row.empty() {
+			continue
+		}
+
+		title := row.get(columns.title)
+		start := row.get(columns.start)
+		exerciseName := row.get(columns.exercise)
+		if start == "" || exerciseName == "" {
+			return nil, fmt.Errorf("%w: line %d: workout start and exercise name are required", errMalformedImportFile, line)
+		}
+
+		key := start + "|" + title
+		workout, ok := byKey[key]
+		if !ok {
+			startedAt, err := parseImportTime(start, columns.startLayouts, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: invalid workout start %q", errMalformedImportFile, line, start)
+			}
+			duration, err := row.workoutDuration(columns, startedAt, loc)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %v", errMalformedImportFile, line, err)
+			}
+			workout = &ImportedWorkout{Title: title, StartedAt: startedAt, Duration: duration}
+			if workout.Title == "" {
+				workout.Title = "Импортированная тренировка"
+			}
+			byKey[key] = workout
+			workouts = append(workouts, workout)
+		}
+
+		set, ok, err := row.set(columns)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", errMalformedImportFile, line, err)
+		}
+		if !ok {
+			continue
+		}
+
+		last := len(workout.Exercises) - 1
+		if last < 0 || workout.Exercises[last].Name != exerciseName {
+			exercise := ImportedExercise{Name: exerciseName}
+			if id, err := strconv.Atoi(row.get(columns.superset)); err == nil && id >= 0 {
+				exercise.Group = &ExerciseGroup{ID: int32(id) + 1, Type: ExerciseGroupSuperset}
+			}
+			workout.Exercises = append(workout.Exercises, exercise)
+			last++
+		}
+		exercise := &workout.Exercises[last]
+		if notes := row.get(columns.notes); notes != "" && exercise.Notes == nil {
+			exercise.Notes = &notes
+		}
+		set.Ordinal = int32(len(exercise.Sets) + 1)
+		exercise.Sets = append(exercise.Sets, set)
+	}
+
+	result := make([]ImportedWorkout, 0, len(workouts))
+	for _, workout := range workouts {
+		if len(workout.Exercises) > 0 {
+			result = append(result, *workout)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartedAt.Before(result[j].StartedAt)
+	})
+	return result, nil
+}
+
+func parseImportTime(value string, layouts []string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errMalformedImportFile
+}
+
+// importRow - строка выгрузки с доступом к колонкам по названию
+type importRow struct {
+	record []string
+	index  map[string]int
+}
+
+func (r importRow) get(names []string) string {
+	for _, name := range names {
+		if i, ok := r.index[name]; ok && i < len(r.record) {
+			return strings.TrimSpace(r.record[i])
+		}
+	}
+	return ""
+}
+
+func (r importRow) empty() bool {
+	for _, value := range r.record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// workoutDuration - длительность тренировки: колонка длительности Strong ("1h 5m")
+// или разница между концом и началом в Hevy
+func (r importRow) workoutDuration(columns importColumns, startedAt time.Time, loc *time.Location) (*time.Duration, error) {
+	if value := r.get(columns.duration); value != "" {
+		d, err := time.ParseDuration(strings.ReplaceAll(value, " ", ""))
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid workout duration %q", value)
+		}
+		return &d, nil
+	}
+	if value := r.get(columns.end); value != "" {
+		end, err := parseImportTime(value, columns.startLayouts, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workout end %q", value)
+		}
+		if d := end.Sub(startedAt); d > 0 {
+			return &d, nil
+		}
+	}
+	return nil, nil
+}
+
+// set разбирает подход. Строки без подхода (например, таймер отдыха в Strong) пропускаются.
+// Нулевые вес, повторения и время считаются незаданными
+func (r importRow) set(columns importColumns) (TrainedSet, bool, error) {
+	var set TrainedSet
+
+	if order := strings.ToLower(r.get(columns.order)); order != "" {
+		if _, err := strconv.Atoi(order); err != nil {
+			switch {
+			case columns.warmupOrders[order]:
+				set.IsWarmup = true
+			case order == "d" || order == "f":
+			default:
+				return set, false, nil
+			}
+		}
+	}
+	if columns.warmupTypes[strings.ToLower(r.get(columns.setType))] {
+		set.IsWarmup = true
+	}
+
+	weight, err := r.weight(columns)
+	if err != nil {
+		return set, false, err
+	}
+	set.Weight = weight
+
+	if value := r.get(columns.reps); value != "" {
+		reps, err := strconv.ParseFloat(value, 64)
+		if err != nil || reps < 0 {
+			return set, false, fmt.Errorf("invalid reps %q", value)
+		}
+		if n := int32(reps); n > 0 {
+			set.Reps = &n
+		}
+	}
+
+	if value := r.get(columns.seconds); value != "" {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			return set, false, fmt.Errorf("invalid duration %q", value)
+		}
+		if seconds > 0 {
+			d := time.Duration(seconds * float64(time.Second))
+			set.Duration = &d
+		}
+	}
+
+	if value := r.get(columns.rpe); value != "" {
+		rpe, err := decimal.NewFromString(value)
+		if err != nil {
+			return set, false, fmt.Errorf("invalid rpe %q", value)
+		}
+		// RPE вне шкалы от 1 до 10 не сохраняется
+		if rpe.GreaterThanOrEqual(decimal.NewFromInt(1)) && rpe.LessThanOrEqual(decimal.NewFromInt(10)) {
+			rpe = rpe.Round(1)
+			set.RPE = &rpe
+		}
+	}
+
+	return set, true, nil
+}
+
+// weight - вес подхода в килограммах. Вес в фунтах (колонка в фунтах или единица lbs) переводится в килограммы
+func (r importRow) weight(columns importColumns) (*decimal.Decimal, error) {
+	value, pounds := r.get(columns.weightKg), false
+	if value == "" {
+		value, pounds = r.get(columns.weightLbs), true
+	}
+	if unit := strings.ToLower(r.get(columns.weightUnit)); unit == "lbs" || unit == "lb" {
+		pounds = true
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	weight, err := decimal.NewFromString(strings.ReplaceAll(value, ",", "."))
+	if err != nil || weight.IsNegative() {
+		return nil, fmt.Errorf("invalid weight %q", value)
+	}
+	if pounds {
+		weight = weight.Mul(poundsToKilograms)
+	}
+	weight = weight.Round(2)
+	if weight.GreaterThan(maxImportedWeight) {
+		return nil, fmt.Errorf("weight %q is out of range", value)
+	}
+	if weight.IsZero() {
+		return nil, nil
+	}
+	return &weight, nil
+}
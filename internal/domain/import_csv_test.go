@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const strongHeader = "Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE\n"
+
+const hevyHeader = `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes",` +
+	`"set_index","set_type","weight_kg","reps","distance_km","duration_seconds","rpe"` + "\n"
+
+func TestParseImportCSVStrong(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	data := "\ufeff" + strongHeader +
+		"2026-01-05 18:00:00,Push,1h 5m,Bench Press (Barbell),W,40,10,0,0,,,\n" +
+		"2026-01-05 18:00:00,Push,1h 5m,Bench Press (Barbell),1,\"82,5\",5,0,0,Felt good,,8.5\n" +
+		"2026-01-05 18:00:00,Push,1h 5m,Bench Press (Barbell),Rest Timer,0,0,0,90,,,\n" +
+		"2026-01-05 18:00:00,Push,1h 5m,Overhead Press,1,50,8,0,0,,,12\n" +
+		",,,,,,,,,,,\n" +
+		"2026-01-03 10:00,,45m,Squat,1,100,5,0,0,,,\n"
+
+	workouts, err := ParseImportCSV(ImportFormatStrong, strings.NewReader(data), loc)
+	if err != nil {
+		t.Fatalf("ParseImportCSV() error: %v", err)
+	}
+	if len(workouts) != 2 {
+		t.Fatalf("ParseImportCSV() returned %d workouts, want 2", len(workouts))
+	}
+
+	legs := workouts[0]
+	if legs.Title != "Импортированная тренировка" {
+		t.Errorf("untitled workout title = %q", legs.Title)
+	}
+	if want := time.Date(2026, time.January, 3, 10, 0, 0, 0, loc); !legs.StartedAt.Equal(want) {
+		t.Errorf("StartedAt = %v, want %v", legs.StartedAt, want)
+	}
+	if legs.Duration == nil || *legs.Duration != 45*time.Minute {
+		t.Errorf("Duration = %v, want 45m", legs.Duration)
+	}
+
+	push := workouts[1]
+	if push.Title != "Push" || push.Duration == nil || *push.Duration != 65*time.Minute {
+		t.Fatalf("push workout = %q, %v", push.Title, push.Duration)
+	}
+	if len(push.Exercises) != 2 {
+		t.Fatalf("push exercises = %d, want 2", len(push.Exercises))
+	}
+	bench := push.Exercises[0]
+	if bench.Name != "Bench Press (Barbell)" || bench.Notes == nil || *bench.Notes != "Felt good" {
+		t.Errorf("bench = %q, notes %v", bench.Name, bench.Notes)
+	}
+	if len(bench.Sets) != 2 {
+		t.Fatalf("bench sets = %d, want 2 (rest timer skipped)", len(bench.Sets))
+	}
+	warmup, work := bench.Sets[0], bench.Sets[1]
+	if !warmup.IsWarmup || warmup.Ordinal != 1 || warmup.Weight.String() != "40" || *warmup.Reps != 10 {
+		t.Errorf("warmup set = %+v", warmup)
+	}
+	if work.IsWarmup || work.Ordinal != 2 || work.Weight.String() != "82.5" || *work.Reps != 5 {
+		t.Errorf("work set = %+v", work)
+	}
+	if work.RPE == nil || work.RPE.String() != "8.5" {
+		t.Errorf("work set RPE = %v, want 8.5", work.RPE)
+	}
+	if warmup.Duration != nil {
+		t.Errorf("zero seconds parsed as duration %v", *warmup.Duration)
+	}
+	if rpe := push.Exercises[1].Sets[0].RPE; rpe != nil {
+		t.Errorf("RPE out of scale kept: %v", rpe)
+	}
+}
+
+func TestParseImportCSVStrongPounds(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "pounds column",
+			data: "Date,Workout Name,Exercise Name,Set Order,Weight (lbs),Reps\n" +
+				"2026-01-05 18:00:00,Push,Bench Press,1,100,5\n",
+			want: "45.36",
+		},
+		{
+			name: "weight unit column",
+			data: "Date;Workout Name;Exercise Name;Set Order;Weight;Weight Unit;Reps\n" +
+				"2026-01-05 18:00:00;Push;Bench Press;1;225;lbs;5\n",
+			want: "102.06",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workouts, err := ParseImportCSV(ImportFormatStrong, strings.NewReader(tt.data), time.UTC)
+			if err != nil {
+				t.Fatalf("ParseImportCSV() error: %v", err)
+			}
+			if len(workouts) != 1 || len(workouts[0].Exercises) != 1 || len(workouts[0].Exercises[0].Sets) != 1 {
+				t.Fatalf("ParseImportCSV() = %+v", workouts)
+			}
+			if got := workouts[0].Exercises[0].Sets[0].Weight; got == nil || got.String() != tt.want {
+				t.Errorf("weight = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseImportCSVHevy(t *testing.T) {
+	data := hevyHeader +
+		`"Upper","5 Jan 2026, 18:00","5 Jan 2026, 19:10","","Pull Up","0","","0","warmup","","5","","",""` + "\n" +
+		`"Upper","5 Jan 2026, 18:00","5 Jan 2026, 19:10","","Pull Up","0","","1","normal","10","8","","","9"` + "\n" +
+		`"Upper","5 Jan 2026, 18:00","5 Jan 2026, 19:10","","Plank","","","0","normal","","","","60.5",""` + "\n"
+
+	workouts, err := ParseImportCSV(ImportFormatHevy, strings.NewReader(data), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseImportCSV() error: %v", err)
+	}
+	if len(workouts) != 1 {
+		t.Fatalf("ParseImportCSV() returned %d workouts, want 1", len(workouts))
+	}
+	upper := workouts[0]
+	if upper.Duration == nil || *upper.Duration != 70*time.Minute {
+		t.Errorf("Duration = %v, want 1h10m from end_time", upper.Duration)
+	}
+	if len(upper.Exercises) != 2 {
+		t.Fatalf("exercises = %d, want 2", len(upper.Exercises))
+	}
+	pullUp, plank := upper.Exercises[0], upper.Exercises[1]
+	if pullUp.Group == nil || pullUp.Group.ID != 1 || pullUp.Group.Type != ExerciseGroupSuperset {
+		t.Errorf("pull up group = %+v, want superset 1", pullUp.Group)
+	}
+	if len(pullUp.Sets) != 2 || !pullUp.Sets[0].IsWarmup || pullUp.Sets[1].IsWarmup {
+		t.Errorf("pull up sets = %+v", pullUp.Sets)
+	}
+	if pullUp.Sets[0].Weight != nil {
+		t.Errorf("empty weight parsed as %v", pullUp.Sets[0].Weight)
+	}
+	if plank.Group != nil {
+		t.Errorf("plank group = %+v, want none", plank.Group)
+	}
+	if d := plank.Sets[0].Duration; d == nil || *d != 60500*time.Millisecond {
+		t.Errorf("plank duration = %v, want 60.5s", d)
+	}
+}
+
+func TestParseImportCSVMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ImportFormat
+		data   string
+	}{
+		{"unsupported format", ImportFormat("fitbod"), strongHeader},
+		{"empty file", ImportFormatStrong, ""},
+		{"missing column", ImportFormatStrong, "Date,Workout Name\n2026-01-05 18:00:00,Push\n"},
+		{"hevy header for strong", ImportFormatStrong, hevyHeader},
+		{"missing start", ImportFormatStrong, strongHeader + ",Push,1h,Bench Press,1,80,5,0,0,,,\n"},
+		{"missing exercise", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,,1,80,5,0,0,,,\n"},
+		{"invalid start", ImportFormatStrong, strongHeader + "05.01.2026,Push,1h,Bench Press,1,80,5,0,0,,,\n"},
+		{"invalid duration", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,an hour,Bench Press,1,80,5,0,0,,,\n"},
+		{"negative duration", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,-1h,Bench Press,1,80,5,0,0,,,\n"},
+		{"invalid weight", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Bench Press,1,heavy,5,0,0,,,\n"},
+		{"negative weight", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Bench Press,1,-5,5,0,0,,,\n"},
+		{"weight out of range", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Bench Press,1,1000,5,0,0,,,\n"},
+		{"invalid reps", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Bench Press,1,80,five,0,0,,,\n"},
+		{"negative reps", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Bench Press,1,80,-5,0,0,,,\n"},
+		{"invalid seconds", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Plank,1,0,0,0,1m,,,\n"},
+		{"invalid rpe", ImportFormatStrong, strongHeader + "2026-01-05 18:00:00,Push,1h,Bench Press,1,80,5,0,0,,,hard\n"},
+		{"invalid end", ImportFormatHevy, hevyHeader + `"Upper","5 Jan 2026, 18:00","tomorrow","","Plank","","","0","normal","","","","60",""` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workouts, err := ParseImportCSV(tt.format, strings.NewReader(tt.data), time.UTC)
+			if !errors.Is(err, errMalformedImportFile) {
+				t.Fatalf("ParseImportCSV() = %+v, %v; want malformed file error", workouts, err)
+			}
+		})
+	}
+}
+
+func TestParseImportCSVOnlyHeader(t *testing.T) {
+	workouts, err := ParseImportCSV(ImportFormatHevy, strings.NewReader(hevyHeader), time.UTC)
+	if err != nil || len(workouts) != 0 {
+		t.Fatalf("ParseImportCSV() = %+v, %v; want no workouts", workouts, err)
+	}
+}
+
+func FuzzParseImportCSV(f *testing.F) {
+	f.Add("strong", strongHeader+"2026-01-05 18:00:00,Push,1h 5m,Bench Press,W,40,10,0,0,,,\n")
+	f.Add("strong", "Date;Workout Name;Exercise Name\n2026-01-05;Push;\"Bench\n")
+	f.Add("hevy", hevyHeader+`"Upper","5 Jan 2026, 18:00","","","Pull Up","-1","","0","warmup","1e400","NaN","","Inf","1e-400"`+"\n")
+	f.Add("hevy", "\"title\n")
+	f.Fuzz(func(t *testing.T, format, data string) {
+		// Проверяется только отсутствие паники на произвольном файле
+		_, _ = ParseImportCSV(ImportFormat(format), strings.NewReader(data), time.UTC)
+	})
+}
@@ -0,0 +1,320 @@
+package domain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+)
+
+// fitEpoch - начало отсчета времени в файлах FIT
+var fitEpoch = time.Date(1989, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// Глобальные номера сообщений FIT, из которых читается активность
+const (
+	fitMesgSession = 18
+	fitMesgRecord  = 20
+)
+
+// Номера полей сообщений record и session
+const (
+	fitFieldTimestamp = 253
+
+	fitRecordLat              = 0
+	fitRecordLon              = 1
+	fitRecordAltitude         = 2
+	fitRecordHeartRate        = 3
+	fitRecordDistance         = 5
+	fitRecordEnhancedAltitude = 78
+
+	fitSessionStartTime    = 2
+	fitSessionSport        = 5
+	fitSessionTimerTime    = 8
+	fitSessionDistance     = 9
+	fitSessionAvgHeartRate = 16
+	fitSessionMaxHeartRate = 17
+	fitSessionTotalAscent  = 22
+)
+
+// fitSports - значения перечисления sport в FIT
+var fitSports = map[uint64]CardioSport{
+	1:  CardioSportRunning,
+	2:  CardioSportCycling,
+	5:  CardioSportSwimming,
+	11: CardioSportWalking,
+	17: CardioSportHiking,
+}
+
+// fitDefinition - описание полей сообщения для локального номера
+type fitDefinition struct {
+	global    uint16
+	bigEndian bool
+	fields    []fitField
+	size      int // Размер сообщения с данными, включая поля разработчика
+}
+
+type fitField struct {
+	num    byte
+	offset int
+	size   int
+}
+
+// fitMessage - сообщение с данными и его описание
+type fitMessage struct {
+	def  *fitDefinition
+	data []byte
+}
+
+// uint читает беззнаковое поле размером 1, 2 или 4 байта. Поле со всеми установленными битами не задано
+func (m fitMessage) uint(num byte) (uint64, bool) {
+	for _, f := range m.def.fields {
+		if f.num != num {
+			continue
+		}
+		b := m.data[f.offset : f.offset+f.size]
+		var order binary.ByteOrder = binary.LittleEndian
+		if m.def.bigEndian {
+			order = binary.BigEndian
+		}
+		switch f.size {
+		case 1:
+			return uint64(b[0]), b[0] != math.MaxUint8
+		case 2:
+			v := order.Uint16(b)
+			return uint64(v), v != math.MaxUint16
+		case 4:
+			v := order.Uint32(b)
+			return uint64(v), v != math.MaxUint32
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// semicircles читает координату в полукругах и переводит ее в градусы
+func (m fitMessage) semicircles(num byte) (*float64, bool) {
+	v, ok := m.uint(num)
+	if !ok || v == math.MaxInt32 {
+		return nil, false
+	}
+	degrees := float64(int32(uint32(v))) * 180 / math.Pow(2, 31)
+	return &degrees, true
+}
+
+// parseFIT разбирает файл активности FIT: точки трека из сообщений record и итоги из сообщений session.
+// Несколько сессий (например, мультиспорт) объединяются в одну активность
+func parseFIT(data []byte) ([]*cardioTrack, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("%w: file is too short", errMalformedImportFile)
+	}
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize || string(data[8:12]) != ".FIT" {
+		return nil, fmt.Errorf("%w: not a FIT file", errMalformedImportFile)
+	}
+	end := headerSize + int(binary.LittleEndian.Uint32(data[4:8]))
+	if end > len(data) {
+		return nil, fmt.Errorf("%w: file is truncated", errMalformedImportFile)
+	}
+	if len(data) >= end+2 {
+		if crc := binary.LittleEndian.Uint16(data[end : end+2]); crc != 0 && crc != fitCRC(data[:end]) {
+			return nil, fmt.Errorf("%w: checksum mismatch", errMalformedImportFile)
+		}
+	}
+
+	track := &cardioTrack{Sport: CardioSportOther}
+	defs := make(map[byte]*fitDefinition)
+	var lastTimestamp uint32
+	var sessions int
+	var distance, ascent, hrWeighted, hrSeconds float64
+	var timer time.Duration
+
+	for pos := headerSize; pos < end; {
+		header := data[pos]
+		pos++
+
+		var local byte
+		compressed := header&0x80 != 0
+		if compressed {
+			// Сжатый заголовок хранит младшие 5 бит времени относительно предыдущего сообщения
+			local = (header >> 5) & 0x03
+			offset := uint32(header & 0x1F)
+			timestamp := lastTimestamp&^0x1F + offset
+			if offset < lastTimestamp&0x1F {
+				timestamp += 0x20
+			}
+			lastTimestamp = timestamp
+		} else {
+			local = header & 0x0F
+			if header&0x40 != 0 {
+				def, n, err := parseFITDefinition(data[pos:end], header&0x20 != 0)
+				if err != nil {
+					return nil, err
+				}
+				defs[local] = def
+				pos += n
+				continue
+			}
+		}
+
+		def, ok := defs[local]
+		if !ok {
+			return nil, fmt.Errorf("%w: message without definition", errMalformedImportFile)
+		}
+		if pos+def.size > end {
+			return nil, fmt.Errorf("%w: file is truncated", errMalformedImportFile)
+		}
+		msg := fitMessage{def: def, data: data[pos : pos+def.size]}
+		pos += def.size
+
+		if ts, ok := msg.uint(fitFieldTimestamp); ok {
+			lastTimestamp = uint32(ts)
+		}
+		timestamp := fitEpoch.Add(time.Duration(lastTimestamp) * time.Second)
+
+		switch def.global {
+		case fitMesgRecord:
+			if lastTimestamp == 0 {
+				continue
+			}
+			point := trackPoint{Time: timestamp}
+			if lat, ok := msg.semicircles(fitRecordLat); ok {
+				if lon, ok := msg.semicircles(fitRecordLon); ok {
+					point.Lat, point.Lon = lat, lon
+				}
+			}
+			if v, ok := msg.uint(fitRecordEnhancedAltitude); ok {
+				alt := float64(v)/5 - 500
+				point.Elevation = &alt
+			} else if v, ok := msg.uint(fitRecordAltitude); ok {
+				alt := float64(v)/5 - 500
+				point.Elevation = &alt
+			}
+			if v, ok := msg.uint(fitRecordDistance); ok {
+				d := float64(v) / 100
+				point.Distance = &d
+			}
+			if v, ok := msg.uint(fitRecordHeartRate); ok {
+				hr := int32(v)
+				point.HeartRate = &hr
+			}
+			track.Points = append(track.Points, point)
+
+		case fitMesgSession:
+			sessions++
+			if v, ok := msg.uint(fitSessionStartTime); ok {
+				start := fitEpoch.Add(time.Duration(v) * time.Second)
+				if track.Start.IsZero() || start.Before(track.Start) {
+					track.Start = start
+				}
+			}
+			if timestamp.After(track.End) {
+				track.End = timestamp
+			}
+			if v, ok := msg.uint(fitSessionSport); ok && sessions == 1 {
+				if sport, known := fitSports[v]; known {
+					track.Sport = sport
+				}
+			}
+			var seconds float64
+			if v, ok := msg.uint(fitSessionTimerTime); ok {
+				seconds = float64(v) / 1000
+				timer += time.Duration(seconds * float64(time.Second))
+			}
+			if v, ok := msg.uint(fitSessionDistance); ok {
+				distance += float64(v) / 100
+			}
+			if v, ok := msg.uint(fitSessionTotalAscent); ok {
+				ascent += float64(v)
+				track.ElevationGain = &ascent
+			}
+			if v, ok := msg.uint(fitSessionAvgHeartRate); ok && seconds > 0 {
+				hrWeighted += float64(v) * seconds
+				hrSeconds += seconds
+			}
+			if v, ok := msg.uint(fitSessionMaxHeartRate); ok && (track.MaxHeartRate == nil || int32(v) > *track.MaxHeartRate) {
+				hr := int32(v)
+				track.MaxHeartRate = &hr
+			}
+		}
+	}
+
+	if sessions == 0 && len(track.Points) == 0 {
+		return nil, fmt.Errorf("%w: file has no activity", errMalformedImportFile)
+	}
+	if distance > 0 {
+		track.Distance = &distance
+	}
+	// Время таймера сессии не включает паузы записи
+	if timer > 0 {
+		timer = timer.Round(time.Second)
+		track.MovingTime = &timer
+	}
+	if hrSeconds > 0 {
+		avg := int32(math.Round(hrWeighted / hrSeconds))
+		track.AvgHeartRate = &avg
+	}
+	return []*cardioTrack{track}, nil
+}
+
+// parseFITDefinition разбирает сообщение-описание и возвращает его длину в байтах
+func parseFITDefinition(data []byte, developer bool) (*fitDefinition, int, error) {
+	truncated := fmt.Errorf("%w: definition is truncated", errMalformedImportFile)
+	if len(data) < 5 {
+		return nil, 0, truncated
+	}
+	def := &fitDefinition{bigEndian: data[1] == 1}
+	if def.bigEndian {
+		def.global = binary.BigEndian.Uint16(data[2:4])
+	} else {
+		def.global = binary.LittleEndian.Uint16(data[2:4])
+	}
+
+	n := 5
+	fields := int(data[4])
+	if len(data) < n+fields*3 {
+		return nil, 0, truncated
+	}
+	for i := 0; i < fields; i++ {
+		f := fitField{num: data[n], offset: def.size, size: int(data[n+1])}
+		def.fields = append(def.fields, f)
+		def.size += f.size
+		n += 3
+	}
+
+	// Поля разработчика не разбираются, но их размер нужен, чтобы пропустить данные
+	if developer {
+		if len(data) < n+1 {
+			return nil, 0, truncated
+		}
+		devFields := int(data[n])
+		n++
+		if len(data) < n+devFields*3 {
+			return nil, 0, truncated
+		}
+		for i := 0; i < devFields; i++ {
+			def.size += int(data[n+1])
+			n += 3
+		}
+	}
+	return def, n, nil
+}
+
+// fitCRCTable - таблица CRC-16 файлов FIT по полубайтам
+var fitCRCTable = [16]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
+}
+
+// fitCRC - контрольная сумма заголовка и данных файла FIT
+func fitCRC(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		tmp := fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[b&0xF]
+		tmp = fitCRCTable[crc&0xF]
+		crc = (crc >> 4) & 0x0FFF
+		crc = crc ^ tmp ^ fitCRCTable[(b>>4)&0xF]
+	}
+	return crc
+}
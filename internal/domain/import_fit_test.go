@@ -0,0 +1,290 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fitBuilder собирает файл FIT из сообщений-описаний и сообщений с данными
+type fitBuilder struct {
+	data []byte
+}
+
+// definition добавляет описание: fields - пары из номера поля и его размера в байтах
+func (b *fitBuilder) definition(local byte, global uint16, bigEndian bool, fields ...[2]byte) {
+	arch := byte(0)
+	order := binary.AppendByteOrder(binary.LittleEndian)
+	if bigEndian {
+		arch, order = 1, binary.BigEndian
+	}
+	b.data = append(b.data, 0x40|local, 0, arch)
+	b.data = order.AppendUint16(b.data, global)
+	b.data = append(b.data, byte(len(fields)))
+	for _, f := range fields {
+		b.data = append(b.data, f[0], f[1], 0)
+	}
+}
+
+func (b *fitBuilder) message(header byte, values ...[]byte) {
+	b.data = append(b.data, header)
+	for _, v := range values {
+		b.data = append(b.data, v...)
+	}
+}
+
+// file возвращает файл с 14-байтовым заголовком и, если withCRC, контрольной суммой в конце
+func (b *fitBuilder) file(withCRC bool) []byte {
+	file := []byte{14, 0x10, 0, 0, 0, 0, 0, 0, '.', 'F', 'I', 'T', 0, 0}
+	binary.LittleEndian.PutUint32(file[4:8], uint32(len(b.data)))
+	file = append(file, b.data...)
+	if withCRC {
+		file = binary.LittleEndian.AppendUint16(file, fitCRC(file))
+	}
+	return file
+}
+
+func u8(v uint8) []byte   { return []byte{v} }
+func u16(v uint16) []byte { return binary.LittleEndian.AppendUint16(nil, v) }
+func u32(v uint32) []byte { return binary.LittleEndian.AppendUint32(nil, v) }
+
+// testFITStart - время первой точки; младшие 5 бит равны 30, чтобы сжатые заголовки переходили через 32
+const testFITStart = uint32(1136000000)&^0x1F | 30
+
+// testFITFile - пробежка из трех точек (вторая и третья - со сжатым временем) и одной сессии
+func testFITFile() []byte {
+	var b fitBuilder
+	b.definition(0, fitMesgSession, false,
+		[2]byte{fitFieldTimestamp, 4}, [2]byte{fitSessionStartTime, 4}, [2]byte{fitSessionSport, 1},
+		[2]byte{fitSessionTimerTime, 4}, [2]byte{fitSessionDistance, 4}, [2]byte{fitSessionAvgHeartRate, 1},
+		[2]byte{fitSessionMaxHeartRate, 1}, [2]byte{fitSessionTotalAscent, 2})
+	b.definition(1, fitMesgRecord, false,
+		[2]byte{fitFieldTimestamp, 4}, [2]byte{fitRecordLat, 4}, [2]byte{fitRecordLon, 4},
+		[2]byte{fitRecordHeartRate, 1}, [2]byte{fitRecordDistance, 4}, [2]byte{fitRecordAltitude, 2})
+	b.definition(2, fitMesgRecord, false,
+		[2]byte{fitRecordLat, 4}, [2]byte{fitRecordLon, 4}, [2]byte{fitRecordHeartRate, 1}, [2]byte{fitRecordDistance, 4})
+
+	// 2^29 полукругов - 45 градусов; высота хранится как (метры + 500) * 5
+	b.message(0x01, u32(testFITStart), u32(1<<29), u32(1<<29), u8(140), u32(0), u16((100+500)*5))
+	// Сжатый заголовок: локальный номер 2, смещение 31 - следующая секунда
+	b.message(0x80|2<<5|31, u32(1<<29), u32(1<<29+1000), u8(150), u32(1000))
+	// Смещение 2 меньше предыдущих младших бит 31 - время переходит через 32 секунды
+	b.message(0x80|2<<5|2, u32(1<<29), u32(1<<29+2000), u8(0xFF), u32(2000))
+	b.message(0x00, u32(testFITStart+4), u32(testFITStart), u8(1), u32(4000), u32(2000), u8(150), u8(170), u16(5))
+	return b.file(true)
+}
+
+func fitTime(t uint32) time.Time {
+	return fitEpoch.Add(time.Duration(t) * time.Second)
+}
+
+func TestParseFIT(t *testing.T) {
+	tracks, err := parseFIT(testFITFile())
+	if err != nil {
+		t.Fatalf("parseFIT() error: %v", err)
+	}
+	if len(tracks) != 1 {
+		t.Fatalf("parseFIT() returned %d tracks, want 1", len(tracks))
+	}
+	track := tracks[0]
+	if track.Sport != CardioSportRunning {
+		t.Errorf("Sport = %q, want running", track.Sport)
+	}
+	if !track.Start.Equal(fitTime(testFITStart)) || !track.End.Equal(fitTime(testFITStart+4)) {
+		t.Errorf("Start, End = %v, %v", track.Start, track.End)
+	}
+
+	wantTimes := []uint32{testFITStart, testFITStart + 1, testFITStart + 4}
+	if len(track.Points) != len(wantTimes) {
+		t.Fatalf("points = %d, want %d", len(track.Points), len(wantTimes))
+	}
+	for i, want := range wantTimes {
+		if got := track.Points[i].Time; !got.Equal(fitTime(want)) {
+			t.Errorf("point %d time = %v, want %v", i, got, fitTime(want))
+		}
+	}
+	first := track.Points[0]
+	if first.Lat == nil || *first.Lat != 45 || first.Elevation == nil || *first.Elevation != 100 {
+		t.Errorf("first point = lat %v, elevation %v; want 45, 100", first.Lat, first.Elevation)
+	}
+	if hr := track.Points[2].HeartRate; hr != nil {
+		t.Errorf("invalid heart rate 0xFF parsed as %d", *hr)
+	}
+	if d := track.Points[2].Distance; d == nil || *d != 20 {
+		t.Errorf("last point distance = %v, want 20", d)
+	}
+
+	if track.Distance == nil || *track.Distance != 20 {
+		t.Errorf("Distance = %v, want 20", track.Distance)
+	}
+	if track.MovingTime == nil || *track.MovingTime != 4*time.Second {
+		t.Errorf("MovingTime = %v, want 4s", track.MovingTime)
+	}
+	if track.AvgHeartRate == nil || *track.AvgHeartRate != 150 || track.MaxHeartRate == nil || *track.MaxHeartRate != 170 {
+		t.Errorf("heart rate = %v/%v, want 150/170", track.AvgHeartRate, track.MaxHeartRate)
+	}
+	if track.ElevationGain == nil || *track.ElevationGain != 5 {
+		t.Errorf("ElevationGain = %v, want 5", track.ElevationGain)
+	}
+}
+
+func TestParseImportCardioFIT(t *testing.T) {
+	workouts, err := ParseImportCardio(ImportFormatFIT, bytes.NewReader(testFITFile()), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseImportCardio() error: %v", err)
+	}
+	if len(workouts) != 1 || workouts[0].Exercises[0].Name != "Бег" || !workouts[0].StartedAt.Equal(fitTime(testFITStart)) {
+		t.Fatalf("ParseImportCardio() = %+v", workouts)
+	}
+	if d := workouts[0].Duration; d == nil || *d != 4*time.Second {
+		t.Errorf("Duration = %v, want 4s", d)
+	}
+}
+
+func TestParseFITBigEndian(t *testing.T) {
+	var b fitBuilder
+	b.definition(0, fitMesgSession, true,
+		[2]byte{fitFieldTimestamp, 4}, [2]byte{fitSessionStartTime, 4}, [2]byte{fitSessionSport, 1},
+		[2]byte{fitSessionDistance, 4})
+	be32 := func(v uint32) []byte { return binary.BigEndian.AppendUint32(nil, v) }
+	b.message(0x00, be32(testFITStart+600), be32(testFITStart), u8(2), be32(500000))
+
+	tracks, err := parseFIT(b.file(true))
+	if err != nil {
+		t.Fatalf("parseFIT() error: %v", err)
+	}
+	track := tracks[0]
+	if track.Sport != CardioSportCycling || track.Distance == nil || *track.Distance != 5000 {
+		t.Errorf("track = %s, distance %v; want cycling 5000", track.Sport, track.Distance)
+	}
+	if !track.Start.Equal(fitTime(testFITStart)) || !track.End.Equal(fitTime(testFITStart+600)) {
+		t.Errorf("Start, End = %v, %v", track.Start, track.End)
+	}
+}
+
+func TestParseFITDeveloperFields(t *testing.T) {
+	var b fitBuilder
+	// Описание с одним полем разработчика размером 3 байта
+	b.data = append(b.data, 0x40|0x20, 0, 0)
+	b.data = binary.LittleEndian.AppendUint16(b.data, fitMesgRecord)
+	b.data = append(b.data, 1, fitFieldTimestamp, 4, 0, 1, 0, 3, 0)
+	b.message(0x00, u32(testFITStart), []byte{1, 2, 3})
+	b.message(0x00, u32(testFITStart+1), []byte{4, 5, 6})
+
+	tracks, err := parseFIT(b.file(true))
+	if err != nil {
+		t.Fatalf("parseFIT() error: %v", err)
+	}
+	if n := len(tracks[0].Points); n != 2 {
+		t.Errorf("points = %d, want 2 (developer data skipped)", n)
+	}
+}
+
+func TestParseFITChecksum(t *testing.T) {
+	valid := testFITFile()
+	end := len(valid) - 2
+
+	noCRC := valid[:end]
+	if _, err := parseFIT(noCRC); err != nil {
+		t.Errorf("file without checksum: %v", err)
+	}
+	zeroCRC := append(append([]byte(nil), noCRC...), 0, 0)
+	if _, err := parseFIT(zeroCRC); err != nil {
+		t.Errorf("file with zero checksum: %v", err)
+	}
+
+	corrupted := append([]byte(nil), valid...)
+	corrupted[end-1] ^= 0x01
+	if _, err := parseFIT(corrupted); !errors.Is(err, errMalformedImportFile) {
+		t.Errorf("corrupted data: %v, want checksum mismatch", err)
+	}
+	wrongCRC := append([]byte(nil), valid...)
+	wrongCRC[end] ^= 0x01
+	if _, err := parseFIT(wrongCRC); !errors.Is(err, errMalformedImportFile) {
+		t.Errorf("wrong checksum: %v, want checksum mismatch", err)
+	}
+}
+
+func TestFITCRC(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint16
+	}{
+		{"", 0},
+		// Контрольное значение CRC-16/ARC
+		{"123456789", 0xBB3D},
+	}
+	for _, tt := range tests {
+		if got := fitCRC([]byte(tt.in)); got != tt.want {
+			t.Errorf("fitCRC(%q) = %#04x, want %#04x", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFITMalformed(t *testing.T) {
+	header := func(size byte, data ...byte) []byte {
+		var b fitBuilder
+		b.data = data
+		file := b.file(false)
+		file[0] = size
+		return file
+	}
+	var b fitBuilder
+	b.definition(0, fitMesgRecord, false, [2]byte{fitFieldTimestamp, 4})
+	definitionOnly := b.data
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"too short", []byte{14, 0x10, 0, 0}},
+		{"header size below 12", header(11)},
+		{"header size beyond file", header(200)},
+		{"wrong magic", append([]byte{12, 0x10, 0, 0, 0, 0, 0, 0}, ".GPX"...)},
+		{"truncated data", func() []byte { f := testFITFile(); return f[:len(f)-10] }()},
+		{"message without definition", header(14, 0x00, 1, 2, 3, 4)},
+		{"compressed message without definition", header(14, 0x85)},
+		{"definition without header fields", header(14, 0x40, 0, 0)},
+		{"definition with missing fields", header(14, 0x40, 0, 0, 20, 0, 3, 253, 4)},
+		{"developer definition without count", header(14, 0x60, 0, 0, 20, 0, 0)},
+		{"developer definition with missing fields", header(14, 0x60, 0, 0, 20, 0, 0, 2, 0, 1)},
+		{"truncated data message", header(14, append(definitionOnly, 0x00, 1, 2)...)},
+		{"no activity", header(14, definitionOnly...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracks, err := parseFIT(tt.data)
+			if !errors.Is(err, errMalformedImportFile) {
+				t.Fatalf("parseFIT() = %+v, %v; want malformed file error", tracks, err)
+			}
+		})
+	}
+}
+
+func TestParseFITTruncatedNeverPanics(t *testing.T) {
+	valid := testFITFile()
+	end := len(valid) - 2
+
+	for n := 0; n < end; n++ {
+		// Файл обрезан, а размер в заголовке прежний
+		if _, err := parseFIT(valid[:n]); err == nil {
+			t.Errorf("file truncated to %d bytes parsed without error", n)
+		}
+	}
+	for n := 14; n < end; n++ {
+		// Размер в заголовке совпадает с обрезанными данными: сообщения обрываются на середине
+		file := append([]byte(nil), valid[:n]...)
+		binary.LittleEndian.PutUint32(file[4:8], uint32(n-14))
+		_, _ = parseFIT(file)
+	}
+	for i := 0; i < end; i++ {
+		// Испорченный байт при нулевой контрольной сумме, которая не проверяется
+		file := append([]byte(nil), valid...)
+		file[i] ^= 0xFF
+		file[end], file[end+1] = 0, 0
+		_, _ = parseFIT(file)
+	}
+}
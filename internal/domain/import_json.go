@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ParseImportJSON разбирает выгрузку этого сервиса в формате json. Импортируются только
+// завершенные тренировки; тренировки без времени начала привязываются к фактической
+// или запланированной дате в loc. Тренировки возвращаются по возрастанию времени начала
+func ParseImportJSON(r io.Reader, loc *time.Location) ([]ImportedWorkout, error) {
+	var doc ExportDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", errMalformedImportFile, err)
+	}
+	if doc.Version != ExportVersion {
+		return nil, fmt.Errorf("%w: unsupported export version %d", errMalformedImportFile, doc.Version)
+	}
+
+	workouts := make([]ImportedWorkout, 0, len(doc.Trainings))
+	for i, training := range doc.Trainings {
+		if training.Status != TrainingStatusCompleted {
+			continue
+		}
+		workout, err := importExportedTraining(training, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: training %d: %v", errMalformedImportFile, i+1, err)
+		}
+		workouts = append(workouts, workout)
+	}
+
+	sort.SliceStable(workouts, func(i, j int) bool {
+		return workouts[i].StartedAt.Before(workouts[j].StartedAt)
+	})
+	return workouts, nil
+}
+
+func importExportedTraining(training ExportedTraining, loc *time.Location) (ImportedWorkout, error) {
+	workout := ImportedWorkout{
+		Title:        strings.TrimSpace(training.Title),
+		Rating:       training.Rating,
+		Duration:     importSeconds(training.TotalDuration),
+		RestTime:     importSeconds(training.TotalRestTime),
+		ExerciseTime: importSeconds(training.TotalExerciseTime),
+	}
+	if workout.Title == "" {
+		workout.Title = "Импортированная тренировка"
+	}
+	if workout.Rating != nil && (*workout.Rating < 1 || *workout.Rating > 5) {
+		return workout, fmt.Errorf("invalid rating %d", *workout.Rating)
+	}
+
+	if training.StartedAt != nil {
+		workout.StartedAt = training.StartedAt.In(loc)
+	} else {
+		day := training.PlannedDate
+		if training.ActualDate != nil {
+			day = *training.ActualDate
+		}
+		date, err := time.ParseInLocation("2006-01-02", day, loc)
+		if err != nil {
+			return workout, fmt.Errorf("invalid date %q", day)
+		}
+		workout.StartedAt = date
+		workout.Untimed = true
+	}
+
+	for _, ex := range training.Exercises {
+		if ex.ExerciseID <= 0 && strings.TrimSpace(ex.ExerciseTitle) == "" {
+			return workout, fmt.Errorf("exercise id or title is required")
+		}
+		exercise := ImportedExercise{
+			Name:       strings.TrimSpace(ex.ExerciseTitle),
+			ExerciseID: ex.ExerciseID,
+			Notes:      ex.Notes,
+			Time:       importSeconds(ex.Time),
+			Doing:      importSeconds(ex.Doing),
+			Rest:       importSeconds(ex.Rest),
+		}
+		if ex.GroupID != nil && ex.GroupType != nil {
+			exercise.Group = &ExerciseGroup{ID: *ex.GroupID, Type: *ex.GroupType}
+			if exercise.Group.ID <= 0 || !exercise.Group.Type.Valid() {
+				return workout, fmt.Errorf("invalid exercise group")
+			}
+		}
+		// Вес, подходы и повторения упражнения с подходами считаются по подходам
+		if len(ex.Sets) == 0 {
+			exercise.Weight, exercise.Approaches, exercise.Reps = ex.Weight, ex.Approaches, ex.Reps
+		}
+		if err := checkImportedWeight(exercise.Weight); err != nil {
+			return workout, err
+		}
+
+		for _, s := range ex.Sets {
+			set := TrainedSet{
+				Ordinal:  int32(len(exercise.Sets) + 1),
+				Weight:   s.Weight,
+				Reps:     s.Reps,
+				Duration: importSeconds(s.Duration),
+				RPE:      s.RPE,
+				IsWarmup: s.IsWarmup,
+			}
+			if err := checkImportedWeight(set.Weight); err != nil {
+				return workout, err
+			}
+			if set.RPE != nil && (set.RPE.LessThan(decimal.NewFromInt(1)) || set.RPE.GreaterThan(decimal.NewFromInt(10))) {
+				return workout, fmt.Errorf("invalid rpe %s", set.RPE)
+			}
+			exercise.Sets = append(exercise.Sets, set)
+		}
+		workout.Exercises = append(workout.Exercises, exercise)
+	}
+	return workout, nil
+}
+
+func importSeconds(seconds *float64) *time.Duration {
+	if seconds == nil || *seconds <= 0 {
+		return nil
+	}
+	d := time.Duration(*seconds * float64(time.Second))
+	return &d
+}
+
+func checkImportedWeight(weight *decimal.Decimal) error {
+	if weight != nil && (weight.IsNegative() || weight.GreaterThan(maxImportedWeight)) {
+		return fmt.Errorf("weight %s is out of range", weight)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LiveEventType - тип изменения тренировки в живой сессии
+type LiveEventType string
+
+const (
+	LiveEventTrainingStarted   LiveEventType = "training.started"
+	LiveEventTrainingPaused    LiveEventType = "training.paused"
+	LiveEventTrainingResumed   LiveEventType = "training.resumed"
+	LiveEventTrainingCompleted LiveEventType = "training.completed"
+
+	LiveEventExerciseAdded   LiveEventType = "exercise.added"
+	LiveEventExerciseUpdated LiveEventType = "exercise.updated"
+	LiveEventExerciseRemoved LiveEventType = "exercise.removed"
+
+	LiveEventExercisesReordered LiveEventType = "exercises.reordered"
+
+	LiveEventSetAdded      LiveEventType = "set.added"
+	LiveEventSetUpdated    LiveEventType = "set.updated"
+	LiveEventSetRemoved    LiveEventType = "set.removed"
+	LiveEventSetsReordered LiveEventType = "sets.reordered"
+)
+
+// LiveEvent - изменение тренировки, рассылаемое всем клиентам, которые следят за сессией.
+// Заполняется только часть полей, относящаяся к типу события
+type LiveEvent struct {
+	Type       LiveEventType
+	TrainingID int64
+	UserID     uuid.UUID
+	OccurredAt time.Time
+
+	// События тренировки
+	Training *Training
+
+	// События упражнений и подходов
+	TrainedExerciseID int64
+	Exercise          *TrainedExercise
+	SetID             int64
+	Set               *TrainedSet
+	Sets              []*TrainedSet
+}
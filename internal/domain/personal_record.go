@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PersonalRecordType - вид личного рекорда
+type PersonalRecordType string
+
+const (
+	PersonalRecordMaxWeight    PersonalRecordType = "max_weight"
+	PersonalRecordMaxReps      PersonalRecordType = "max_reps"
+	PersonalRecordEstimated1RM PersonalRecordType = "estimated_1rm"
+	PersonalRecordMaxDuration  PersonalRecordType = "max_duration"
+	PersonalRecordMaxVolume    PersonalRecordType = "max_volume"
+)
+
+// PersonalRecord - лучший результат пользователя в упражнении.
+// Value - килограммы, повторения, секунды или килограммы x повторения в зависимости от Type.
+// Weight задан только для max_reps: рекорд повторений считается отдельно для каждого веса
+type PersonalRecord struct {
+	ID                int64              `db:"id" json:"id"`
+	UserID            uuid.UUID          `db:"user_id" json:"user_id"`
+	ExerciseID        int64              `db:"exercise_id" json:"exercise_id"`
+	ExerciseTitle     string             `db:"exercise_title" json:"exercise_title"`
+	TrainedExerciseID int64              `db:"trained_exercise_id" json:"trained_exercise_id"`
+	TrainingID        int64              `db:"training_id" json:"training_id"`
+	Type              PersonalRecordType `db:"type" json:"type"`
+	Value             decimal.Decimal    `db:"value" json:"value"`
+	Weight            *decimal.Decimal   `db:"weight" json:"weight"`
+	AchievedAt        time.Time          `db:"achieved_at" json:"achieved_at"`
+}
+
+// PersonalRecordCandidates собирает результаты выполненного упражнения, которые могут
+// оказаться рекордами. Если у упражнения есть подходы, учитываются рабочие подходы,
+// иначе - вес, повторения и подходы самого упражнения
+func PersonalRecordCandidates(exercise *TrainedExercise) []PersonalRecord {
+	type effort struct {
+		weight *decimal.Decimal
+		reps   *int32
+		times  int64
+	}
+
+	var efforts []effort
+	var maxDuration time.Duration
+	if len(exercise.Sets) > 0 {
+		for _, set := range exercise.Sets {
+			if set.IsWarmup {
+				continue
+			}
+			efforts = append(efforts, effort{weight: set.Weight, reps: set.Reps, times: 1})
+			if set.Duration != nil && *set.Duration > maxDuration {
+				maxDuration = *set.Duration
+			}
+		}
+	} else {
+		times := int64(1)
+		if exercise.Approaches != nil && *exercise.Approaches > 0 {
+			times = int64(*exercise.Approaches)
+		}
+		efforts = append(efforts, effort{weight: exercise.Weight, reps: exercise.Reps, times: times})
+		if exercise.Doing != nil {
+			maxDuration = *exercise.Doing
+		}
+	}
+
+	var maxWeight, best1RM, volume decimal.Decimal
+	repsAtWeight := make(map[string]PersonalRecord)
+	var repsOrder []string
+	for _, e := range efforts {
+		hasWeight := e.weight != nil && e.weight.IsPositive()
+		hasReps := e.reps != nil && *e.reps > 0
+
+		if hasWeight && (e.reps == nil || hasReps) && e.weight.GreaterThan(maxWeight) {
+			maxWeight = *e.weight
+		}
+		if !hasReps {
+			continue
+		}
+
+		reps := decimal.NewFromInt32(*e.reps)
+		key := "bodyweight"
+		var weight *decimal.Decimal
+		if hasWeight {
+			w := e.weight.Round(2)
+			weight = &w
+			key = w.String()
+			volume = volume.Add(w.Mul(reps).Mul(decimal.NewFromInt(e.times)))
+			if oneRM := EstimateOneRepMax(OneRepMaxEpley, w, *e.reps); oneRM.GreaterThan(best1RM) {
+				best1RM = oneRM
+			}
+		}
+		if current, ok := repsAtWeight[key]; !ok || reps.GreaterThan(current.Value) {
+			if !ok {
+				repsOrder = append(repsOrder, key)
+			}
+			repsAtWeight[key] = PersonalRecord{Type: PersonalRecordMaxReps, Value: reps, Weight: weight}
+		}
+	}
+
+	var candidates []PersonalRecord
+	add := func(recordType PersonalRecordType, value decimal.Decimal) {
+		if value.IsPositive() {
+			candidates = append(candidates, PersonalRecord{Type: recordType, Value: value.Round(2)})
+		}
+	}
+	add(PersonalRecordMaxWeight, maxWeight)
+	add(PersonalRecordEstimated1RM, best1RM)
+	add(PersonalRecordMaxVolume, volume)
+	add(PersonalRecordMaxDuration, decimal.NewFromFloat(maxDuration.Seconds()))
+	for _, key := range repsOrder {
+		candidates = append(candidates, repsAtWeight[key])
+	}
+
+	for i := range candidates {
+		candidates[i].ExerciseID = exercise.ExerciseID
+		candidates[i].TrainedExerciseID = exercise.ID
+		candidates[i].TrainingID = exercise.TrainingID
+	}
+	return candidates
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// MaxProgramWeeks - наибольшая длительность программы в неделях
+const MaxProgramWeeks = 52
+
+// Program - многонедельная программа: тренировочные дни по неделям, каждый из которых
+// ссылается на глобальную тренировку
+type Program struct {
+	ID          int64
+	Title       string
+	Description string
+	Level       string
+	Weeks       int32
+	Sessions    []ProgramSession // По порядку недель и дней
+}
+
+// ProgramSession - тренировочный день программы. Day - порядковый номер тренировки внутри
+// недели, а не день недели: конкретную дату определяют дни, выбранные пользователем при записи.
+// Прибавки начисляются к предписанной нагрузке глобальной тренировки за каждую неделю после первой
+type ProgramSession struct {
+	ID                  int64
+	Week                int32
+	Day                 int32
+	GlobalTrainingID    int64
+	GlobalTrainingTitle string
+	WeightIncrement     *decimal.Decimal // Прибавка веса за неделю, кг
+	RepsIncrement       *int32           // Прибавка повторений за неделю
+}
+
+// ProgressWeight возвращает вес недели сессии для предписанного веса первой недели
+func (s ProgramSession) ProgressWeight(base decimal.Decimal) decimal.Decimal {
+	if s.WeightIncrement == nil || s.Week <= 1 {
+		return base
+	}
+	return base.Add(s.WeightIncrement.Mul(decimal.NewFromInt32(s.Week - 1)))
+}
+
+// ProgressReps возвращает число повторений недели сессии для предписанного числа первой недели
+func (s ProgramSession) ProgressReps(base int32) int32 {
+	if s.RepsIncrement == nil || s.Week <= 1 {
+		return base
+	}
+	return base + *s.RepsIncrement*(s.Week-1)
+}
+
+// ProgramEnrollment - запись пользователя на программу. Неделя w программы занимает
+// семь дней начиная с StartDate + 7*(w-1); тренировки недели ставятся на Weekdays по порядку
+type ProgramEnrollment struct {
+	ID           int64
+	ProgramID    int64
+	ProgramTitle string
+	Weeks        int32
+	UserID       uuid.UUID
+	StartDate    time.Time
+	Weekdays     []time.Weekday
+	CreatedAt    time.Time
+}
+
+// ProgramSessionPlan - тренировочный день программы и дата, на которую он назначен
+type ProgramSessionPlan struct {
+	Session     ProgramSession
+	PlannedDate time.Time
+}
+
+// ProgramProgress - прохождение программы. Пропущенными считаются тренировки
+// со статусом skipped или cancelled
+type ProgramProgress struct {
+	Enrollment        ProgramEnrollment
+	TotalSessions     int32
+	CompletedSessions int32
+	SkippedSessions   int32
+	CompletionPercent float64
+	CurrentWeek       int32 // Неделя программы на сегодня: 0 до начала, Weeks после окончания
+	NextTraining      *ProgramNextTraining
+}
+
+// ProgramNextTraining - ближайшая невыполненная тренировка программы
+type ProgramNextTraining struct {
+	TrainingID  int64
+	Title       string
+	PlannedDate time.Time
+	Week        int32
+	Day         int32
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ProgressionRules - правила подбора нагрузки на следующую тренировку по прошлым выполнениям упражнения
+type ProgressionRules struct {
+	WeightStep    decimal.Decimal // Прибавка веса после полностью выполненной тренировки, кг
+	DeloadAfter   int32           // После стольких невыполненных тренировок подряд вес снижается
+	DeloadPercent decimal.Decimal // На сколько процентов снижается вес
+}
+
+// LoadSuggestionReason - почему предложена такая нагрузка
+type LoadSuggestionReason string
+
+const (
+	SuggestionNoHistory LoadSuggestionReason = "no_history" // Упражнение еще не выполнялось
+	SuggestionIncrease  LoadSuggestionReason = "increase"   // Все повторения выполнены, нагрузка растет
+	SuggestionRepeat    LoadSuggestionReason = "repeat"     // Часть повторений не выполнена, нагрузка прежняя
+	SuggestionDeload    LoadSuggestionReason = "deload"     // Несколько неудач подряд, вес снижается
+)
+
+// ExerciseSession - выполнение упражнения в завершенной тренировке: предписанная
+// нагрузка и рабочие подходы (без разминочных)
+type ExerciseSession struct {
+	TrainedExerciseID int64
+	TrainingID        int64
+	Date              time.Time
+	Weight            *decimal.Decimal
+	Approaches        *int32
+	Reps              *int32
+	Sets              []TrainedSet
+}
+
+// Completed сообщает, что в тренировке выполнены все целевые повторения: рабочих подходов
+// не меньше предписанного и в каждом не меньше целевого числа повторений.
+// Без записанных подходов считается, что упражнение выполнено как предписано
+func (s ExerciseSession) Completed() bool {
+	if len(s.Sets) == 0 {
+		return true
+	}
+	if s.Approaches != nil && int32(len(s.Sets)) < *s.Approaches {
+		return false
+	}
+	if s.Reps == nil {
+		return true
+	}
+	for _, set := range s.Sets {
+		if set.Reps == nil || *set.Reps < *s.Reps {
+			return false
+		}
+	}
+	return true
+}
+
+// WorkingWeight возвращает вес тренировки: предписанный, а если его нет - наибольший в рабочих подходах
+func (s ExerciseSession) WorkingWeight() *decimal.Decimal {
+	if s.Weight != nil {
+		return s.Weight
+	}
+	var top *decimal.Decimal
+	for _, set := range s.Sets {
+		if set.Weight != nil && (top == nil || set.Weight.GreaterThan(*top)) {
+			top = set.Weight
+		}
+	}
+	return top
+}
+
+// LoadSuggestion - предлагаемая нагрузка на следующую тренировку
+type LoadSuggestion struct {
+	ExerciseID     int64
+	Weight         *decimal.Decimal
+	Approaches     *int32
+	Reps           *int32
+	Reason         LoadSuggestionReason
+	FailedSessions int32      // Невыполненных тренировок подряд с последним весом
+	LastSession    *time.Time // Дата последнего выполнения
+}
+
+// Suggest подбирает нагрузку по истории упражнения, упорядоченной от последнего выполнения.
+// Если все целевые повторения выполнены, вес растет на WeightStep (без веса - растут повторения);
+// если нет - нагрузка повторяется, а после DeloadAfter неудач подряд с тем же весом он снижается на DeloadPercent
+// с округлением вниз до кратного WeightStep
+func (r ProgressionRules) Suggest(exerciseID int64, history []ExerciseSession) LoadSuggestion {
+	suggestion := LoadSuggestion{ExerciseID: exerciseID, Reason: SuggestionNoHistory}
+	if len(history) == 0 {
+		return suggestion
+	}
+
+	last := history[0]
+	lastDate := last.Date
+	suggestion.LastSession = &lastDate
+	suggestion.Approaches = last.Approaches
+	suggestion.Reps = last.Reps
+	weight := last.WorkingWeight()
+
+	// Неудачи считаются только с тем же весом: после снижения веса счет начинается заново
+	for _, session := range history {
+		if session.Completed() || !sameWeight(session.WorkingWeight(), weight) {
+			break
+		}
+		suggestion.FailedSessions++
+	}
+
+	switch {
+	case suggestion.FailedSessions == 0:
+		suggestion.Reason = SuggestionIncrease
+		if weight != nil {
+			next := weight.Add(r.WeightStep)
+			weight = &next
+		} else if last.Reps != nil {
+			reps := *last.Reps + 1
+			suggestion.Reps = &reps
+		}
+	case r.DeloadAfter > 0 && suggestion.FailedSessions >= r.DeloadAfter:
+		suggestion.Reason = SuggestionDeload
+		if weight != nil {
+			next := r.deload(*weight)
+			weight = &next
+		}
+	default:
+		suggestion.Reason = SuggestionRepeat
+	}
+
+	suggestion.Weight = weight
+	return suggestion
+}
+
+func sameWeight(a, b *decimal.Decimal) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+func (r ProgressionRules) deload(weight decimal.Decimal) decimal.Decimal {
+	hundred := decimal.NewFromInt(100)
+	next := weight.Mul(hundred.Sub(r.DeloadPercent)).Div(hundred)
+	if r.WeightStep.IsPositive() {
+		next = next.Div(r.WeightStep).Floor().Mul(r.WeightStep)
+	}
+	if next.IsNegative() {
+		return decimal.Zero
+	}
+	return next
+}
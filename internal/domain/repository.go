@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,39 +10,152 @@ import (
 type TrainingRepository interface {
 	// Тренировки
 	GetTrainingsByUser(ctx context.Context, userID uuid.UUID) ([]*Training, error)
-	GetTrainingWithExercises(ctx context.Context, trainingID int64) (*Training, error)
+	GetTrainingsFiltered(ctx context.Context, userID uuid.UUID, filter TrainingFilter) (*TrainingPage, error)
+	GetTrainingWithExercises(ctx context.Context, trainingID int64, userID uuid.UUID) (*Training, error)
 	CreateTraining(ctx context.Context, training *Training) (*Training, error)
 	UpdateTraining(ctx context.Context, training *Training) (*Training, error)
-	DeleteTrainingAndExercises(ctx context.Context, trainingID int64) error
+	DeleteTrainingAndExercises(ctx context.Context, trainingID int64, userID uuid.UUID) error
 	
 	// Упражнения в тренировках
-	AddExerciseToTraining(ctx context.Context, exercise *TrainedExercise) (*TrainedExercise, error)
-	UpdateTrainedExercise(ctx context.Context, exercise *TrainedExercise) (*TrainedExercise, error)
-	DeleteExerciseFromTraining(ctx context.Context, exerciseID, trainingID int64) error
+	AddExerciseToTraining(ctx context.Context, exercise *TrainedExercise, userID uuid.UUID) (*TrainedExercise, error)
+	UpdateTrainedExercise(ctx context.Context, exercise *TrainedExercise, userID uuid.UUID) (*TrainedExercise, error)
+	DeleteExerciseFromTraining(ctx context.Context, exerciseID, trainingID int64, userID uuid.UUID) error
+	ReorderTrainedExercises(ctx context.Context, trainingID int64, trainedExerciseIDs []int64) error
+	SetTrainedExerciseGroup(ctx context.Context, trainedExerciseID int64, userID uuid.UUID, group *ExerciseGroup) (*TrainedExercise, error)
+	// Тип упражнения каталога и сохраненные кардио-показатели для их проверки
+	GetExerciseType(ctx context.Context, exerciseID int64) (ExerciseType, error)
+	GetTrainedExerciseCardio(ctx context.Context, trainedExerciseID int64) (ExerciseType, *CardioMetrics, error)
 	
 	// Статистика
-	GetUserTrainingStats(ctx context.Context, userID uuid.UUID) (*TrainingStats, error)
+	GetUserTrainingStats(ctx context.Context, userID uuid.UUID, query UserStatsQuery) (*UserTrainingStats, error)
 
 	// Таймер
-	UpdateExerciseTime(ctx context.Context, exercise *TrainedExercise) (*TrainedExercise, error)
+	UpdateExerciseTime(ctx context.Context, exercise *TrainedExercise, userID uuid.UUID) (*TrainedExercise, error)
 	UpdateTrainingTimers(ctx context.Context, training *Training) (*Training, error)
-	CalculateTrainingTotalTime(ctx context.Context, trainingID int64) (*TrainingTime, error)
+	CalculateTrainingTotalTime(ctx context.Context, trainingID int64, userID uuid.UUID) (*TrainingTime, error)
 	
 	// Актуальные тренировки
-	GetCurrentTraining(ctx context.Context, userID uuid.UUID) (*Training, error)
-	GetTodaysTraining(ctx context.Context, userID uuid.UUID) ([]*Training, error)
+	GetCurrentTraining(ctx context.Context, userID uuid.UUID, today time.Time) (*Training, error)
+	GetTodaysTraining(ctx context.Context, userID uuid.UUID, today time.Time) ([]*Training, error)
 	
 	// Популярные/известные
 	GetGlobalTrainings(ctx context.Context) ([]*GlobalTraining, error)
 	GetGlobalTrainingByLevel(ctx context.Context, level string) ([]*GlobalTraining, error)
 	GetGlobalTrainingById(ctx context.Context, trainingID int64) (*GlobalTraining, error)
+	CreateGlobalTraining(ctx context.Context, cmd GlobalTrainingCmd) (*GlobalTraining, error)
+	UpdateGlobalTraining(ctx context.Context, id int64, cmd GlobalTrainingCmd) (*GlobalTraining, error)
+	DeleteGlobalTraining(ctx context.Context, id int64) (bool, error)
+	IsGlobalTrainingUsedByProgram(ctx context.Context, id int64) (bool, error)
+	CountExercises(ctx context.Context, ids []int64) (int64, error)
 	
 	//Прогресс тренировки
-	MarkTrainingAsDone(ctx context.Context, trainingID int64, userID uuid.UUID) (*Training, error)
-	GetTrainingStats(ctx context.Context, trainingID int64) (*TrainingStats, error)
+	MarkTrainingAsDone(ctx context.Context, trainingID int64, userID uuid.UUID, actualDate time.Time) (*Training, error)
+	CompleteTraining(ctx context.Context, trainingID int64, userID uuid.UUID, actualDate, finishedAt time.Time, rating *int32, totalDuration *time.Duration) (*Training, error)
+	GetTrainingStats(ctx context.Context, trainingID int64, userID uuid.UUID) (*TrainingStats, error)
 	StartTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (*Training, error)
 
+	// Паузы
+	PauseTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (bool, error)
+	ResumeTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (bool, error)
+	GetTrainingPausedTime(ctx context.Context, trainingID int64, userID uuid.UUID) (time.Duration, error)
+
 	AssignGlobalTrainingToUser(ctx context.Context, cmd AssignGlobalTrainingCmd) (*Training, error)
+
+	// Подходы
+	GetTrainedSets(ctx context.Context, trainedExerciseID int64, userID uuid.UUID) ([]*TrainedSet, error)
+	AddTrainedSet(ctx context.Context, set *TrainedSet, userID uuid.UUID) (*TrainedSet, error)
+	UpdateTrainedSet(ctx context.Context, cmd UpdateTrainedSetCmd, userID uuid.UUID) (*TrainedSet, error)
+	DeleteTrainedSet(ctx context.Context, trainedExerciseID, setID int64, userID uuid.UUID) (bool, error)
+	ReorderTrainedSets(ctx context.Context, trainedExerciseID int64, setIDs []int64) error
+
+	// Личные рекорды
+	SavePersonalRecords(ctx context.Context, trainedExerciseID int64, userID uuid.UUID, candidates []PersonalRecord) ([]PersonalRecord, error)
+	GetPersonalRecords(ctx context.Context, userID uuid.UUID) ([]*PersonalRecord, error)
+	GetExercisePersonalRecords(ctx context.Context, userID uuid.UUID, exerciseID int64) ([]*PersonalRecord, error)
+
+	// Силовая аналитика: рабочие подходы упражнения за период, по возрастанию даты
+	GetExerciseStrengthEfforts(ctx context.Context, userID uuid.UUID, exerciseID int64, from, to time.Time) ([]StrengthEffort, error)
+
+	// Прогрессия нагрузки: последние limit выполнений упражнения в завершенных тренировках, начиная с последнего
+	GetRecentExerciseSessions(ctx context.Context, userID uuid.UUID, exerciseID int64, limit int32) ([]ExerciseSession, error)
+
+	// Регулярность тренировок: даты всех тренировок пользователя по возрастанию
+	GetUserTrainingDates(ctx context.Context, userID uuid.UUID) ([]TrainingDate, error)
+
+	// Календарь: все дни с from по to включительно, тренировки - по запланированной дате
+	GetTrainingCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]CalendarDay, error)
+
+	// Импорт истории тренировок
+	GetExerciseCatalog(ctx context.Context) ([]CatalogExercise, error)
+	// Дни и названия тренировок пользователя с from по to включительно
+	GetTrainingTitles(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]TrainingTitle, error)
+	// Создает завершенные тренировки с упражнениями и подходами одной транзакцией
+	ImportTrainings(ctx context.Context, userID uuid.UUID, workouts []ImportedWorkout) ([]*Training, error)
+
+	// Пачка тренировок с упражнениями и подходами после after (для первой пачки after == nil) по planned_date и id
+	GetTrainingsForExport(ctx context.Context, userID uuid.UUID, from, to *time.Time, after *ExportCursor, limit int32) ([]*Training, error)
+
+	// Ключ подписки на календарь; хранится только его хэш
+	SetCalendarToken(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	DeleteCalendarToken(ctx context.Context, userID uuid.UUID) (bool, error)
+	// Владелец ключа с хэшем tokenHash; sql.ErrNoRows, если ключа нет
+	GetCalendarTokenUser(ctx context.Context, tokenHash string) (uuid.UUID, error)
+
+	// Расписания тренировок
+	CreateSchedule(ctx context.Context, schedule *TrainingSchedule) (*TrainingSchedule, error)
+	GetSchedule(ctx context.Context, scheduleID int64, userID uuid.UUID) (*TrainingSchedule, error)
+	GetSchedules(ctx context.Context, userID uuid.UUID) ([]*TrainingSchedule, error)
+	// Расписания, тренировки по которым созданы не до дня horizon
+	GetSchedulesToMaterialize(ctx context.Context, horizon time.Time) ([]*TrainingSchedule, error)
+	// Обновляет расписание и удаляет не начатые тренировки его повторений начиная с from
+	UpdateSchedule(ctx context.Context, schedule *TrainingSchedule, from time.Time) (*TrainingSchedule, error)
+	// Удаляет расписание и не начатые тренировки его повторений начиная с from; прошлые тренировки остаются
+	DeleteSchedule(ctx context.Context, scheduleID int64, userID uuid.UUID, from time.Time) (bool, error)
+	GetScheduleSkips(ctx context.Context, scheduleID int64) ([]time.Time, error)
+	// Созданные тренировки повторений с from по to (по дню повторения)
+	GetScheduledTrainings(ctx context.Context, scheduleID int64, from, to time.Time) ([]ScheduleOccurrence, error)
+	// Создает тренировки на дни повторений dates, пропуская уже созданные, и, если until задан,
+	// запоминает, что расписание развернуто до него. Возвращает число созданных тренировок
+	MaterializeSchedule(ctx context.Context, schedule *TrainingSchedule, dates []time.Time, until *time.Time) (int, error)
+	// Запоминает пропуск повторения и удаляет его тренировку, если она еще не начата
+	SkipScheduleOccurrence(ctx context.Context, scheduleID int64, date time.Time) error
+
+	// Многонедельные программы
+	GetPrograms(ctx context.Context) ([]*Program, error)
+	GetProgram(ctx context.Context, programID int64) (*Program, error)
+	CreateProgram(ctx context.Context, cmd ProgramCmd) (*Program, error)
+	UpdateProgram(ctx context.Context, programID int64, cmd ProgramCmd) (*Program, error)
+	DeleteProgram(ctx context.Context, programID int64) (bool, error)
+	CountGlobalTrainings(ctx context.Context, ids []int64) (int64, error)
+	// Создает запись на программу и тренировки по плану; упражнения копируются
+	// из глобальных тренировок с прибавками недели
+	EnrollInProgram(ctx context.Context, enrollment *ProgramEnrollment, plan []ProgramSessionPlan) (*ProgramEnrollment, error)
+	GetEnrollment(ctx context.Context, enrollmentID int64, userID uuid.UUID) (*ProgramEnrollment, error)
+	GetEnrollments(ctx context.Context, userID uuid.UUID) ([]*ProgramEnrollment, error)
+	// Число тренировок записи по статусам и ближайшая невыполненная тренировка
+	GetEnrollmentProgress(ctx context.Context, enrollment *ProgramEnrollment) (*ProgramProgress, error)
+	// Удаляет запись и не начатые тренировки программы начиная с from; прошлые тренировки остаются
+	DeleteEnrollment(ctx context.Context, enrollmentID int64, userID uuid.UUID, from time.Time) (bool, error)
+
+	// Пользовательские шаблоны тренировок; список возвращается без упражнений
+	GetTemplates(ctx context.Context, userID uuid.UUID) ([]*TrainingTemplate, error)
+	GetTemplate(ctx context.Context, templateID int64, userID uuid.UUID) (*TrainingTemplate, error)
+	CreateTemplate(ctx context.Context, cmd TemplateCmd) (*TrainingTemplate, error)
+	UpdateTemplate(ctx context.Context, templateID int64, cmd TemplateCmd) (*TrainingTemplate, error)
+	DeleteTemplate(ctx context.Context, templateID int64, userID uuid.UUID) (bool, error)
+	CreateTemplateFromTraining(ctx context.Context, cmd TemplateFromTrainingCmd) (*TrainingTemplate, error)
+	// Создают запланированную тренировку пользователя с упражнениями шаблона или копиями упражнений тренировки
+	CreateTrainingFromTemplate(ctx context.Context, template *TrainingTemplate, plannedDate time.Time) (*Training, error)
+	CloneTraining(ctx context.Context, training *Training, plannedDate time.Time) (*Training, error)
+
+	// Настройки пользователя; если пользователь их не сохранял, возвращаются значения по умолчанию
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*UserSettings, error)
+	SaveUserSettings(ctx context.Context, settings *UserSettings) (*UserSettings, error)
+
+	// Владельцы (для проверки доступа)
+	GetTrainingOwner(ctx context.Context, trainingID int64) (uuid.UUID, error)
+	// Возвращает владельца и идентификатор тренировки, в которую входит упражнение
+	GetTrainedExerciseOwner(ctx context.Context, trainedExerciseID int64) (uuid.UUID, int64, error)
 }
 
 
@@ -60,4 +174,18 @@ type ExerciseRepository interface {
 	
 	// Связи упражнений с тегами
 	GetExerciseTags(ctx context.Context, exerciseID int64) ([]*Tag, error)
+
+	// Администрирование каталога
+	CreateExercise(ctx context.Context, exercise *Exercise) (*Exercise, error)
+	UpdateExercise(ctx context.Context, exercise *Exercise) (*Exercise, error)
+	DeleteExercise(ctx context.Context, id int64) (bool, error)
+	IsExerciseUsed(ctx context.Context, id int64) (bool, error)
+	CreateTag(ctx context.Context, tag *Tag) (*Tag, error)
+	UpdateTag(ctx context.Context, tag *Tag) (*Tag, error)
+	DeleteTag(ctx context.Context, id int64) (bool, error)
+	AttachTagToExercise(ctx context.Context, exerciseID, tagID int64) error
+	DetachTagFromExercise(ctx context.Context, exerciseID, tagID int64) (bool, error)
+	GetExerciseAliases(ctx context.Context, exerciseID int64) ([]string, error)
+	// Заменяет другие названия упражнения; false, если какое-то из них уже занято другим упражнением
+	SetExerciseAliases(ctx context.Context, exerciseID int64, aliases []string) (bool, error)
 }
\ No newline at end of file
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MaxRecurrenceInterval - наибольший шаг правила в неделях
+	MaxRecurrenceInterval = 52
+	// MaxRecurrenceCount - наибольшее число повторений в правиле с COUNT
+	MaxRecurrenceCount = 1000
+)
+
+var errMalformedRecurrenceRule = errors.New("malformed recurrence rule")
+
+// rruleWeekdays - коды дней недели из RFC 5545
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// rruleWeekOrder - порядок дней в неделе правила, с понедельника
+var rruleWeekOrder = []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}
+
+// RecurrenceRule - поддерживаемое подмножество RRULE из RFC 5545:
+// FREQ=WEEKLY с необязательными INTERVAL, BYDAY и одним из UNTIL или COUNT.
+// Недели считаются с понедельника (WKST=MO)
+type RecurrenceRule struct {
+	Interval int32          // Шаг в неделях, не меньше 1
+	ByDay    []time.Weekday // Дни недели; пусто - день недели даты начала
+	Until    *time.Time     // Последний допустимый день включительно
+	Count    *int32         // Сколько всего повторений, начиная с даты начала
+}
+
+// ParseRecurrenceRule разбирает строку вида "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// Префикс "RRULE:" допускается
+func ParseRecurrenceRule(s string) (*RecurrenceRule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errMalformedRecurrenceRule
+	}
+
+	rule := &RecurrenceRule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" || seen[name] {
+			return nil, errMalformedRecurrenceRule
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			if value != "WEEKLY" {
+				return nil, fmt.Errorf("%w: only FREQ=WEEKLY is supported", errMalformedRecurrenceRule)
+			}
+		case "INTERVAL":
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || n < 1 || n > MaxRecurrenceInterval {
+				return nil, fmt.Errorf("%w: INTERVAL must be between 1 and %d", errMalformedRecurrenceRule, MaxRecurrenceInterval)
+			}
+			rule.Interval = int32(n)
+		case "BYDAY":
+			days := make(map[time.Weekday]bool)
+			for _, code := range strings.Split(value, ",") {
+				day, ok := rruleWeekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %q", errMalformedRecurrenceRule, code)
+				}
+				if !days[day] {
+					days[day] = true
+					rule.ByDay = append(rule.ByDay, day)
+				}
+			}
+		case "UNTIL":
+			// Время, если оно указано, не учитывается: повторения идут по дням
+			date := value
+			if i := strings.IndexByte(value, 'T'); i >= 0 {
+				date = value[:i]
+			}
+			until, err := time.Parse("20060102", date)
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL must be a date in YYYYMMDD format", errMalformedRecurrenceRule)
+			}
+			rule.Until = &until
+		case "COUNT":
+			n, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || n < 1 || n > MaxRecurrenceCount {
+				return nil, fmt.Errorf("%w: COUNT must be between 1 and %d", errMalformedRecurrenceRule, MaxRecurrenceCount)
+			}
+			count := int32(n)
+			rule.Count = &count
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", errMalformedRecurrenceRule, name)
+		}
+	}
+
+	if !seen["FREQ"] {
+		return nil, fmt.Errorf("%w: FREQ is required", errMalformedRecurrenceRule)
+	}
+	if rule.Until != nil && rule.Count != nil {
+		return nil, fmt.Errorf("%w: UNTIL and COUNT are mutually exclusive", errMalformedRecurrenceRule)
+	}
+	return rule, nil
+}
+
+// String возвращает правило в нормализованном виде RRULE
+func (r RecurrenceRule) String() string {
+	parts := []string{"FREQ=WEEKLY"}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(int(r.Interval)))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, 0, len(r.ByDay))
+		for _, code := range rruleWeekOrder {
+			if containsWeekday(r.ByDay, rruleWeekdays[code]) {
+				codes = append(codes, code)
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	if r.Count != nil {
+		parts = append(parts, "COUNT="+strconv.Itoa(int(*r.Count)))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences возвращает дни повторений правила с началом start, попадающие в отрезок [from, to].
+// COUNT отсчитывается от start, поэтому повторения до from тоже учитываются в лимите
+func (r RecurrenceRule) Occurrences(start, from, to time.Time) []time.Time {
+	start = dateOnly(start)
+	from = dateOnly(from)
+	to = dateOnly(to)
+	if r.Until != nil && r.Until.Before(to) {
+		to = dateOnly(*r.Until)
+	}
+
+	byDay := r.ByDay
+	if len(byDay) == 0 {
+		byDay = []time.Weekday{start.Weekday()}
+	}
+	interval := int(r.Interval)
+	if interval < 1 {
+		interval = 1
+	}
+
+	// Номер недели считается от понедельника недели, в которую попадает start
+	firstMonday := start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+
+	var dates []time.Time
+	var n int32
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		week := int(day.Sub(firstMonday).Hours()/24) / 7
+		if week%interval != 0 || !containsWeekday(byDay, day.Weekday()) {
+			continue
+		}
+		n++
+		if r.Count != nil && n > *r.Count {
+			break
+		}
+		if !day.Before(from) {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+// IsOccurrence сообщает, что day - один из дней повторения правила с началом start
+func (r RecurrenceRule) IsOccurrence(start, day time.Time) bool {
+	return len(r.Occurrences(start, day, day)) == 1
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TrainingSchedule - расписание повторяющейся тренировки. Тренировки по расписанию
+// создаются заранее на горизонт вперед; MaterializedUntil - день, до которого они уже созданы
+type TrainingSchedule struct {
+	ID                int64
+	UserID            uuid.UUID
+	Title             string
+	Rule              RecurrenceRule
+	StartDate         time.Time
+	GlobalTrainingID  *int64 // Глобальная тренировка, упражнения которой копируются в каждое повторение
+	TemplateID        *int64 // Шаблон пользователя - другой источник упражнений; задается не вместе с GlobalTrainingID
+	MaterializedUntil *time.Time
+	CreatedAt         time.Time
+}
+
+// ScheduleOccurrence - повторение расписания. TrainingID задан, если тренировка уже создана;
+// ее PlannedDate может отличаться от Date, если повторение перенесли
+type ScheduleOccurrence struct {
+	Date        time.Time
+	Skipped     bool
+	TrainingID  *int64
+	PlannedDate *time.Time
+	Status      *TrainingStatus
+}
@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"only freq", "FREQ=WEEKLY", "FREQ=WEEKLY"},
+		{"rrule prefix", "RRULE:FREQ=WEEKLY;INTERVAL=2", "FREQ=WEEKLY;INTERVAL=2"},
+		{"lower case and spaces", " freq=weekly; byday=th,mo ", "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{"duplicate days", "FREQ=WEEKLY;BYDAY=MO,MO,SU", "FREQ=WEEKLY;BYDAY=MO,SU"},
+		{"interval one is omitted", "FREQ=WEEKLY;INTERVAL=1", "FREQ=WEEKLY"},
+		{"until date", "FREQ=WEEKLY;UNTIL=20260131", "FREQ=WEEKLY;UNTIL=20260131"},
+		{"until with time", "FREQ=WEEKLY;UNTIL=20260131T235959Z", "FREQ=WEEKLY;UNTIL=20260131"},
+		{"count", "FREQ=WEEKLY;COUNT=10", "FREQ=WEEKLY;COUNT=10"},
+		{"max interval", "FREQ=WEEKLY;INTERVAL=52", "FREQ=WEEKLY;INTERVAL=52"},
+		{"max count", "FREQ=WEEKLY;COUNT=1000", "FREQ=WEEKLY;COUNT=1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.in)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) error: %v", tt.in, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("ParseRecurrenceRule(%q).String() = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceRuleMalformed(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"RRULE:",
+		"FREQ",
+		"FREQ=",
+		"=WEEKLY",
+		";",
+		"FREQ=WEEKLY;",
+		"FREQ=DAILY",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"INTERVAL=2",
+		"FREQ=WEEKLY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=WEEKLY;INTERVAL=-1",
+		"FREQ=WEEKLY;INTERVAL=53",
+		"FREQ=WEEKLY;INTERVAL=two",
+		"FREQ=WEEKLY;INTERVAL=99999999999",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYDAY=MO,",
+		"FREQ=WEEKLY;UNTIL=2026-01-31",
+		"FREQ=WEEKLY;UNTIL=20261332",
+		"FREQ=WEEKLY;COUNT=0",
+		"FREQ=WEEKLY;COUNT=1001",
+		"FREQ=WEEKLY;UNTIL=20260131;COUNT=3",
+		"FREQ=WEEKLY;WKST=SU",
+		"FREQ=WEEKLY;BYDAY=MO;BYDAY=TU",
+	}
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(in)
+			if !errors.Is(err, errMalformedRecurrenceRule) {
+				t.Fatalf("ParseRecurrenceRule(%q) = %v, %v; want malformed rule error", in, rule, err)
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	// 5 января 2026 - понедельник
+	monday := date(2026, time.January, 5)
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		from  time.Time
+		to    time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "weekday of start",
+			rule:  "FREQ=WEEKLY",
+			start: date(2026, time.January, 7),
+			from:  date(2026, time.January, 1),
+			to:    date(2026, time.January, 21),
+			want:  []time.Time{date(2026, time.January, 7), date(2026, time.January, 14), date(2026, time.January, 21)},
+		},
+		{
+			name:  "every other week on two days",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: monday,
+			from:  monday,
+			to:    date(2026, time.January, 31),
+			want:  []time.Time{monday, date(2026, time.January, 8), date(2026, time.January, 19), date(2026, time.January, 22)},
+		},
+		{
+			name:  "weeks counted from the monday of start",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			start: date(2026, time.January, 7),
+			from:  date(2026, time.January, 1),
+			to:    date(2026, time.January, 31),
+			want:  []time.Time{date(2026, time.January, 19)},
+		},
+		{
+			name:  "count limits occurrences",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3",
+			start: monday,
+			from:  monday,
+			to:    date(2026, time.February, 28),
+			want:  []time.Time{monday, date(2026, time.January, 8), date(2026, time.January, 12)},
+		},
+		{
+			name:  "count includes occurrences before from",
+			rule:  "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3",
+			start: monday,
+			from:  date(2026, time.January, 10),
+			to:    date(2026, time.February, 28),
+			want:  []time.Time{date(2026, time.January, 12)},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=WEEKLY;UNTIL=20260119",
+			start: monday,
+			from:  monday,
+			to:    date(2026, time.February, 28),
+			want:  []time.Time{monday, date(2026, time.January, 12), date(2026, time.January, 19)},
+		},
+		{
+			name:  "time of day is ignored",
+			rule:  "FREQ=WEEKLY",
+			start: time.Date(2026, time.January, 5, 23, 30, 0, 0, time.UTC),
+			from:  time.Date(2026, time.January, 12, 18, 0, 0, 0, time.UTC),
+			to:    time.Date(2026, time.January, 12, 6, 0, 0, 0, time.UTC),
+			want:  []time.Time{date(2026, time.January, 12)},
+		},
+		{
+			name:  "to before start",
+			rule:  "FREQ=WEEKLY",
+			start: monday,
+			from:  date(2025, time.December, 1),
+			to:    date(2025, time.December, 31),
+			want:  nil,
+		},
+		{
+			name:  "from after to",
+			rule:  "FREQ=WEEKLY",
+			start: monday,
+			from:  date(2026, time.February, 1),
+			to:    date(2026, time.January, 20),
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) error: %v", tt.rule, err)
+			}
+			got := rule.Occurrences(tt.start, tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceRuleOccurrencesZeroInterval(t *testing.T) {
+	// Правило, собранное вручную, с нулевым шагом считается еженедельным
+	rule := RecurrenceRule{}
+	got := rule.Occurrences(date(2026, time.January, 5), date(2026, time.January, 5), date(2026, time.January, 19))
+	if len(got) != 3 {
+		t.Fatalf("Occurrences() = %v, want 3 weekly dates", got)
+	}
+}
+
+func TestRecurrenceRuleIsOccurrence(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=TU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := date(2026, time.January, 6)
+	tests := []struct {
+		day  time.Time
+		want bool
+	}{
+		{date(2026, time.January, 6), true},
+		{date(2026, time.January, 13), false},
+		{date(2026, time.January, 20), true},
+		{date(2026, time.January, 21), false},
+		{date(2025, time.December, 23), false},
+	}
+	for _, tt := range tests {
+		if got := rule.IsOccurrence(start, tt.day); got != tt.want {
+			t.Errorf("IsOccurrence(%s) = %v, want %v", tt.day.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +11,7 @@ import (
 
 type TrainingService interface {
 	GetTrainingsByUser(ctx context.Context, userID uuid.UUID) ([]*Training, error)
+	ListTrainings(ctx context.Context, userID uuid.UUID, filter TrainingFilter) (*TrainingPage, error)
 	GetTrainingWithExercises(ctx context.Context, trainingID int64) (*Training, error)
 	CreateTraining(ctx context.Context, cmd CreateTrainingCmd) (*Training, error)
 	UpdateTraining(ctx context.Context, cmd UpdateTrainingCmd) (*Training, error)
@@ -17,7 +19,11 @@ type TrainingService interface {
 	AddExerciseToTraining(ctx context.Context, cmd AddExerciseToTrainingCmd) (*TrainedExercise, error)
 	UpdateTrainedExercise(ctx context.Context, cmd UpdateTrainedExerciseCmd) (*TrainedExercise, error)
 	RemoveExerciseFromTraining(ctx context.Context, trainingID, exerciseID int64) error
-	GetUserTrainingStats(ctx context.Context, userID uuid.UUID) (*TrainingStats, error)
+	// Задает порядок упражнений тренировки: список должен содержать каждое ее упражнение ровно один раз
+	ReorderTrainedExercises(ctx context.Context, trainingID int64, trainedExerciseIDs []int64) (*Training, error)
+	// Включает упражнение в суперсет, круг или гигантский сет; nil исключает его из группы
+	SetTrainedExerciseGroup(ctx context.Context, trainedExerciseID int64, group *ExerciseGroup) (*TrainedExercise, error)
+	GetUserTrainingStats(ctx context.Context, userID uuid.UUID, query UserStatsQuery) (*UserTrainingStats, error)
 	CompleteTraining(ctx context.Context, trainingID int64, rating *int32) (*Training, error)
 
 	UpdateExerciseTime(ctx context.Context, exerciseID int64, weight *decimal.Decimal, approaches *int32, reps *int32, time *time.Duration, doing *time.Duration, rest *time.Duration) (*TrainedExercise, error)
@@ -31,6 +37,11 @@ type TrainingService interface {
 	GetGlobalTrainingById(ctx context.Context, trainingID int64) (*GlobalTraining, error)
 	AssignGlobalTraining(ctx context.Context, cmd AssignGlobalTrainingCmd) (*Training, error)
 
+	// Администрирование глобальных тренировок (только для роли admin)
+	CreateGlobalTraining(ctx context.Context, cmd GlobalTrainingCmd) (*GlobalTraining, error)
+	UpdateGlobalTraining(ctx context.Context, id int64, cmd GlobalTrainingCmd) (*GlobalTraining, error)
+	DeleteGlobalTraining(ctx context.Context, id int64) error
+
 	MarkTrainingAsDone(ctx context.Context, trainingID int64, userID uuid.UUID) (*Training, error)
 	GetTrainingStats(ctx context.Context, trainingID int64) (*TrainingStats, error)
 	StartTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (*Training, error)
@@ -38,6 +49,83 @@ type TrainingService interface {
 	UpdateExerciseDoingTime(ctx context.Context, exerciseID int64, doingTime time.Duration) (*TrainedExercise, error)
 	PauseTraining(ctx context.Context, trainingID int64) (*Training, error)
 	ResumeTraining(ctx context.Context, trainingID int64) (*Training, error)
+
+	// Подходы выполненного упражнения
+	GetTrainedSets(ctx context.Context, trainedExerciseID int64) ([]*TrainedSet, error)
+	AddTrainedSet(ctx context.Context, cmd AddTrainedSetCmd) (*TrainedSet, error)
+	UpdateTrainedSet(ctx context.Context, cmd UpdateTrainedSetCmd) (*TrainedSet, error)
+	DeleteTrainedSet(ctx context.Context, trainedExerciseID, setID int64) error
+	ReorderTrainedSets(ctx context.Context, trainedExerciseID int64, setIDs []int64) ([]*TrainedSet, error)
+
+	// Личные рекорды
+	GetPersonalRecords(ctx context.Context, userID uuid.UUID) ([]*PersonalRecord, error)
+	GetExercisePersonalRecords(ctx context.Context, userID uuid.UUID, exerciseID int64) ([]*PersonalRecord, error)
+
+	// Силовая аналитика
+	GetExerciseProgress(ctx context.Context, userID uuid.UUID, exerciseID int64, query StrengthProgressQuery) (*StrengthProgress, error)
+
+	// Нагрузка на следующую тренировку по прошлым выполнениям упражнения
+	SuggestExerciseLoad(ctx context.Context, userID uuid.UUID, exerciseID int64) (*LoadSuggestion, error)
+
+	// Календарь тренировок за период
+	GetTrainingCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]CalendarDay, error)
+
+	// Расписания повторяющихся тренировок
+	CreateSchedule(ctx context.Context, cmd CreateScheduleCmd) (*TrainingSchedule, error)
+	GetSchedules(ctx context.Context, userID uuid.UUID) ([]*TrainingSchedule, error)
+	GetSchedule(ctx context.Context, userID uuid.UUID, scheduleID int64) (*TrainingSchedule, error)
+	// Изменяет расписание для всех будущих повторений
+	UpdateSchedule(ctx context.Context, cmd UpdateScheduleCmd) (*TrainingSchedule, error)
+	DeleteSchedule(ctx context.Context, userID uuid.UUID, scheduleID int64) error
+	GetScheduleOccurrences(ctx context.Context, userID uuid.UUID, scheduleID int64, from, to time.Time) ([]ScheduleOccurrence, error)
+	SkipScheduleOccurrence(ctx context.Context, userID uuid.UUID, scheduleID int64, date time.Time) error
+	// Изменяет одно повторение; если его тренировка еще не создана, она создается
+	UpdateScheduleOccurrence(ctx context.Context, cmd UpdateScheduleOccurrenceCmd) (*Training, error)
+
+	// Многонедельные программы и запись на них
+	GetPrograms(ctx context.Context) ([]*Program, error)
+	GetProgram(ctx context.Context, programID int64) (*Program, error)
+	EnrollInProgram(ctx context.Context, cmd EnrollProgramCmd) (*ProgramProgress, error)
+	GetEnrollments(ctx context.Context, userID uuid.UUID) ([]*ProgramProgress, error)
+	GetEnrollmentProgress(ctx context.Context, userID uuid.UUID, enrollmentID int64) (*ProgramProgress, error)
+	Unenroll(ctx context.Context, userID uuid.UUID, enrollmentID int64) error
+
+	// Администрирование программ (только для роли admin)
+	CreateProgram(ctx context.Context, cmd ProgramCmd) (*Program, error)
+	UpdateProgram(ctx context.Context, programID int64, cmd ProgramCmd) (*Program, error)
+	DeleteProgram(ctx context.Context, programID int64) error
+
+	// Пользовательские шаблоны тренировок
+	GetTemplates(ctx context.Context, userID uuid.UUID) ([]*TrainingTemplate, error)
+	GetTemplate(ctx context.Context, userID uuid.UUID, templateID int64) (*TrainingTemplate, error)
+	CreateTemplate(ctx context.Context, cmd TemplateCmd) (*TrainingTemplate, error)
+	UpdateTemplate(ctx context.Context, templateID int64, cmd TemplateCmd) (*TrainingTemplate, error)
+	DeleteTemplate(ctx context.Context, userID uuid.UUID, templateID int64) error
+	// Сохраняет упражнения завершенной тренировки как шаблон
+	CreateTemplateFromTraining(ctx context.Context, cmd TemplateFromTrainingCmd) (*TrainingTemplate, error)
+	// Создает запланированную тренировку с упражнениями шаблона
+	InstantiateTemplate(ctx context.Context, cmd InstantiateTemplateCmd) (*Training, error)
+	// Копирует тренировку со всеми упражнениями как новую запланированную
+	CloneTraining(ctx context.Context, trainingID int64, plannedDate *time.Time) (*Training, error)
+
+	// Импорт истории тренировок из выгрузки Strong, Hevy, json или файла GPX, TCX, FIT; при DryRun ничего не сохраняется
+	ImportHistory(ctx context.Context, cmd ImportHistoryCmd) (*ImportReport, error)
+	// Выгрузка тренировок пользователя в w; тренировки пишутся по мере чтения
+	ExportTrainings(ctx context.Context, cmd ExportCmd, w io.Writer) error
+
+	// Подписка на календарь тренировок по секретному ключу: новый ключ заменяет прежний
+	RotateCalendarToken(ctx context.Context, userID uuid.UUID) (string, error)
+	RevokeCalendarToken(ctx context.Context, userID uuid.UUID) error
+	// Календарь iCalendar владельца ключа; ключ заменяет аутентификацию
+	GetCalendarFeed(ctx context.Context, token string) ([]byte, error)
+
+	// Регулярность тренировок и настройки пользователя
+	GetTrainingConsistency(ctx context.Context, userID uuid.UUID, weeks int32) (*Consistency, error)
+	GetUserSettings(ctx context.Context, userID uuid.UUID) (*UserSettings, error)
+	UpdateUserSettings(ctx context.Context, cmd UpdateUserSettingsCmd) (*UserSettings, error)
+
+	// Живая сессия: события тренировки приходят до вызова функции отмены
+	SubscribeTraining(ctx context.Context, trainingID int64) (<-chan LiveEvent, func(), error)
 }
 
 type CreateTrainingCmd struct {
@@ -54,14 +142,11 @@ type CreateTrainingCmd struct {
 	Rating            *int32
 }
 
+// UpdateTrainingCmd меняет поля тренировки, не связанные с ее статусом
 type UpdateTrainingCmd struct {
 	ID                int64
 	Title             string
-	IsDone            *bool
 	PlannedDate       time.Time
-	ActualDate        *time.Time
-	StartedAt         *time.Time
-	FinishedAt        *time.Time
 	TotalDuration     *time.Duration
 	TotalRestTime     *time.Duration
 	TotalExerciseTime *time.Duration
@@ -78,8 +163,13 @@ type AddExerciseToTrainingCmd struct {
 	Doing      *time.Duration
 	Rest       *time.Duration
 	Notes      *string
+	Suggest    bool           // Незаданные вес, подходы и повторения заполняются предлагаемой нагрузкой
+	Group      *ExerciseGroup // Суперсет или круг, в который сразу входит упражнение
+	Cardio     *CardioMetrics // Только для кардио-упражнений каталога; дистанция - в Cardio.DistanceUnit
 }
 
+// UpdateTrainedExerciseCmd - изменение выполненного упражнения. Незаданные поля, в том числе
+// кардио-показатели, не меняются. Дистанция задается так же, как в AddExerciseToTrainingCmd
 type UpdateTrainedExerciseCmd struct {
 	ID         int64
 	Weight     *decimal.Decimal
@@ -89,6 +179,130 @@ type UpdateTrainedExerciseCmd struct {
 	Doing      *time.Duration
 	Rest       *time.Duration
 	Notes      *string
+	Cardio     *CardioMetrics
+}
+
+type AddTrainedSetCmd struct {
+	TrainedExerciseID int64
+	Weight            *decimal.Decimal
+	Reps              *int32
+	Duration          *time.Duration
+	RPE               *decimal.Decimal
+	IsWarmup          bool
+}
+
+type UpdateTrainedSetCmd struct {
+	ID                int64
+	TrainedExerciseID int64
+	Weight            *decimal.Decimal
+	Reps              *int32
+	Duration          *time.Duration
+	RPE               *decimal.Decimal
+	IsWarmup          *bool
+}
+
+// UpdateUserSettingsCmd - изменение настроек; незаданные поля остаются прежними
+type UpdateUserSettingsCmd struct {
+	UserID    uuid.UUID
+	WeekStart *time.Weekday
+	RestDays  *int32
+	Timezone  *string // пустая строка сбрасывает пояс на UTC
+}
+
+// CreateScheduleCmd - новое расписание; RRule - правило повторения в формате RRULE
+type CreateScheduleCmd struct {
+	UserID           uuid.UUID
+	Title            string
+	RRule            string
+	StartDate        time.Time
+	GlobalTrainingID *int64
+	TemplateID       *int64
+}
+
+// UpdateScheduleCmd заменяет название, правило и источник упражнений расписания.
+// Изменения применяются к повторениям начиная с сегодняшнего дня
+type UpdateScheduleCmd struct {
+	ID               int64
+	UserID           uuid.UUID
+	Title            string
+	RRule            string
+	GlobalTrainingID *int64
+	TemplateID       *int64
+}
+
+// UpdateScheduleOccurrenceCmd - изменение одного повторения; незаданные поля остаются прежними
+type UpdateScheduleOccurrenceCmd struct {
+	ScheduleID  int64
+	UserID      uuid.UUID
+	Date        time.Time // День повторения по правилу
+	Title       *string
+	PlannedDate *time.Time // Перенос тренировки на другой день
+}
+
+// ProgramCmd описывает программу целиком; тренировочные дни заменяются при обновлении
+type ProgramCmd struct {
+	Title       string
+	Description string
+	Level       string
+	Weeks       int32
+	Sessions    []ProgramSessionCmd
+}
+
+type ProgramSessionCmd struct {
+	Week             int32
+	Day              int32
+	GlobalTrainingID int64
+	WeightIncrement  *decimal.Decimal
+	RepsIncrement    *int32
+}
+
+// EnrollProgramCmd - запись на программу. Тренировки недели ставятся на Weekdays
+// по порядку; StartDate по умолчанию - сегодня
+type EnrollProgramCmd struct {
+	UserID    uuid.UUID
+	ProgramID int64
+	StartDate *time.Time
+	Weekdays  []time.Weekday
+}
+
+// TemplateCmd описывает шаблон целиком; упражнения заменяются при обновлении,
+// их порядок определяет порядок выполнения
+type TemplateCmd struct {
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	Exercises   []GlobalTrainingExerciseCmd
+}
+
+// TemplateFromTrainingCmd - шаблон из завершенной тренировки. Пустое Title заменяется названием тренировки
+type TemplateFromTrainingCmd struct {
+	UserID      uuid.UUID
+	TrainingID  int64
+	Title       string
+	Description string
+}
+
+// InstantiateTemplateCmd - тренировка по шаблону; PlannedDate по умолчанию - сегодня
+type InstantiateTemplateCmd struct {
+	UserID      uuid.UUID
+	TemplateID  int64
+	PlannedDate *time.Time
+}
+
+// ImportHistoryCmd - выгрузка истории тренировок. Время без часового пояса считается временем пользователя
+type ImportHistoryCmd struct {
+	UserID uuid.UUID
+	Format ImportFormat
+	File   io.Reader
+	DryRun bool
+}
+
+// ExportCmd - выгрузка тренировок с запланированной датой с From по To; незаданные границы не ограничивают период
+type ExportCmd struct {
+	UserID uuid.UUID
+	Format ExportFormat
+	From   *time.Time
+	To     *time.Time
 }
 
 type AssignGlobalTrainingCmd struct {
@@ -97,6 +311,25 @@ type AssignGlobalTrainingCmd struct {
 	PlannedDate      time.Time // Дата, на которую назначается тренировка
 }
 
+// GlobalTrainingCmd описывает глобальную тренировку целиком.
+// Порядок Exercises определяет порядок выполнения упражнений
+type GlobalTrainingCmd struct {
+	Title       string
+	Description string
+	Level       string
+	Exercises   []GlobalTrainingExerciseCmd
+}
+
+type GlobalTrainingExerciseCmd struct {
+	ExerciseID int64
+	Approaches *int32
+	Reps       *int32
+	Weight     *decimal.Decimal
+	Duration   *time.Duration
+	Rest       *time.Duration
+	Group      *ExerciseGroup
+}
+
 type ExerciseService interface {
 	GetAllExercises(ctx context.Context) ([]*Exercise, error)
 	GetExerciseByID(ctx context.Context, id int64) (*Exercise, error)
@@ -107,4 +340,34 @@ type ExerciseService interface {
 	GetExerciseTags(ctx context.Context, exerciseID int64) ([]*Tag, error)
 	GetExercisesByMultipleTags(ctx context.Context, tagIDs []int64) ([]*Exercise, error)
 	GetPopularTags(ctx context.Context, limit int) ([]*Tag, error)
+
+	// Администрирование каталога (только для роли admin)
+	CreateExercise(ctx context.Context, cmd CreateExerciseCmd) (*Exercise, error)
+	UpdateExercise(ctx context.Context, cmd UpdateExerciseCmd) (*Exercise, error)
+	DeleteExercise(ctx context.Context, id int64) error
+	CreateTag(ctx context.Context, tagType string) (*Tag, error)
+	UpdateTag(ctx context.Context, id int64, tagType string) (*Tag, error)
+	DeleteTag(ctx context.Context, id int64) error
+	AttachTagToExercise(ctx context.Context, exerciseID, tagID int64) error
+	DetachTagFromExercise(ctx context.Context, exerciseID, tagID int64) error
+	// Другие названия упражнения, по которым сопоставляются упражнения импортируемых выгрузок
+	GetExerciseAliases(ctx context.Context, exerciseID int64) ([]string, error)
+	SetExerciseAliases(ctx context.Context, exerciseID int64, aliases []string) ([]string, error)
+}
+
+type CreateExerciseCmd struct {
+	Title       string
+	Description string
+	VideoUrl    string
+	ImageUrl    string
+	Type        ExerciseType // Пустой тип - силовое упражнение
+}
+
+type UpdateExerciseCmd struct {
+	ID          int64
+	Title       *string
+	Description *string
+	VideoUrl    *string
+	ImageUrl    *string
+	Type        *ExerciseType
 }
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OneRepMaxFormula - формула оценки максимума на одно повторение (1ПМ)
+type OneRepMaxFormula string
+
+const (
+	OneRepMaxEpley    OneRepMaxFormula = "epley"
+	OneRepMaxBrzycki  OneRepMaxFormula = "brzycki"
+	OneRepMaxLombardi OneRepMaxFormula = "lombardi"
+)
+
+// brzyckiMaxReps - при 37 повторениях и больше формула Бжицки теряет смысл
+const brzyckiMaxReps = 37
+
+// IsValid сообщает, что формула поддерживается
+func (f OneRepMaxFormula) IsValid() bool {
+	switch f {
+	case OneRepMaxEpley, OneRepMaxBrzycki, OneRepMaxLombardi:
+		return true
+	}
+	return false
+}
+
+// EstimateOneRepMax оценивает 1ПМ по весу и числу повторений подхода.
+// Возвращает ноль, если подход не позволяет оценить 1ПМ
+func EstimateOneRepMax(formula OneRepMaxFormula, weight decimal.Decimal, reps int32) decimal.Decimal {
+	if !weight.IsPositive() || reps <= 0 {
+		return decimal.Zero
+	}
+	if reps == 1 {
+		return weight
+	}
+
+	r := decimal.NewFromInt32(reps)
+	switch formula {
+	case OneRepMaxBrzycki:
+		if reps >= brzyckiMaxReps {
+			return decimal.Zero
+		}
+		return weight.Mul(decimal.NewFromInt(36)).Div(decimal.NewFromInt(brzyckiMaxReps).Sub(r))
+	case OneRepMaxLombardi:
+		return weight.Mul(decimal.NewFromFloat(math.Pow(float64(reps), 0.1)))
+	default:
+		return weight.Mul(decimal.NewFromInt(1).Add(r.Div(decimal.NewFromInt(30))))
+	}
+}
+
+// ProgressGranularity - шаг временного ряда прогресса
+type ProgressGranularity string
+
+const (
+	ProgressDay   ProgressGranularity = "day"
+	ProgressWeek  ProgressGranularity = "week"
+	ProgressMonth ProgressGranularity = "month"
+)
+
+// IsValid сообщает, что шаг поддерживается
+func (g ProgressGranularity) IsValid() bool {
+	switch g {
+	case ProgressDay, ProgressWeek, ProgressMonth:
+		return true
+	}
+	return false
+}
+
+// PeriodStart возвращает начало периода, в который попадает дата. Недели начинаются с понедельника
+func (g ProgressGranularity) PeriodStart(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch g {
+	case ProgressMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case ProgressWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	default:
+		return day
+	}
+}
+
+// StrengthEffort - рабочий подход упражнения в завершенной тренировке.
+// Sets > 1, если у упражнения нет отдельных подходов и вес с повторениями заданы на все подходы сразу
+type StrengthEffort struct {
+	Date   time.Time
+	Weight decimal.Decimal
+	Reps   int32
+	Sets   int32
+}
+
+// StrengthProgressQuery - параметры запроса прогресса в упражнении
+type StrengthProgressQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity ProgressGranularity
+	Formula     OneRepMaxFormula
+}
+
+// StrengthProgressPoint - показатели упражнения за один период
+type StrengthProgressPoint struct {
+	PeriodStart  time.Time       `json:"period_start"`
+	BestE1RM     decimal.Decimal `json:"best_e1rm"`
+	TopSetWeight decimal.Decimal `json:"top_set_weight"`
+	TotalVolume  decimal.Decimal `json:"total_volume"`
+	Sets         int32           `json:"sets"`
+}
+
+// StrengthProgress - временной ряд силовых показателей пользователя в упражнении
+type StrengthProgress struct {
+	ExerciseID  int64                   `json:"exercise_id"`
+	From        time.Time               `json:"from"`
+	To          time.Time               `json:"to"`
+	Granularity ProgressGranularity     `json:"granularity"`
+	Formula     OneRepMaxFormula        `json:"formula"`
+	Points      []StrengthProgressPoint `json:"points"`
+}
+
+// BuildStrengthProgress группирует подходы по периодам. Подходы должны быть отсортированы
+// по дате; периоды без подходов в ряд не попадают
+func BuildStrengthProgress(efforts []StrengthEffort, granularity ProgressGranularity, formula OneRepMaxFormula) []StrengthProgressPoint {
+	points := make([]StrengthProgressPoint, 0)
+	for _, effort := range efforts {
+		start := granularity.PeriodStart(effort.Date)
+		if len(points) == 0 || !points[len(points)-1].PeriodStart.Equal(start) {
+			points = append(points, StrengthProgressPoint{PeriodStart: start})
+		}
+		point := &points[len(points)-1]
+
+		sets := effort.Sets
+		if sets <= 0 {
+			sets = 1
+		}
+		point.Sets += sets
+
+		if effort.Reps <= 0 || !effort.Weight.IsPositive() {
+			continue
+		}
+		if effort.Weight.GreaterThan(point.TopSetWeight) {
+			point.TopSetWeight = effort.Weight
+		}
+		if e1rm := EstimateOneRepMax(formula, effort.Weight, effort.Reps); e1rm.GreaterThan(point.BestE1RM) {
+			point.BestE1RM = e1rm
+		}
+		volume := effort.Weight.Mul(decimal.NewFromInt32(effort.Reps)).Mul(decimal.NewFromInt32(sets))
+		point.TotalVolume = point.TotalVolume.Add(volume)
+	}
+
+	for i := range points {
+		points[i].BestE1RM = points[i].BestE1RM.Round(2)
+	}
+	return points
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// TrainingTemplate - пользовательский шаблон тренировки: упражнения с целевой нагрузкой,
+// по которому создаются запланированные тренировки
+type TrainingTemplate struct {
+	ID          int64
+	UserID      uuid.UUID
+	Title       string
+	Description string
+	CreatedAt   time.Time
+	Exercises   []TemplateExercise // В порядке выполнения
+}
+
+// TemplateExercise - упражнение шаблона с целевой нагрузкой
+type TemplateExercise struct {
+	ID            int64
+	ExerciseID    int64
+	ExerciseTitle string
+	Position      int32
+	Approaches    *int32
+	Reps          *int32
+	Weight        *decimal.Decimal
+	Duration      *time.Duration
+	Rest          *time.Duration
+	Group         *ExerciseGroup
+}
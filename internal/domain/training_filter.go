@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TrainingSort - поле сортировки списка тренировок
+type TrainingSort string
+
+const (
+	TrainingSortPlannedDate TrainingSort = "planned_date"
+	TrainingSortActualDate  TrainingSort = "actual_date"
+	TrainingSortRating      TrainingSort = "rating"
+)
+
+// IsValid сообщает, что сортировка поддерживается
+func (s TrainingSort) IsValid() bool {
+	switch s {
+	case TrainingSortPlannedDate, TrainingSortActualDate, TrainingSortRating:
+		return true
+	}
+	return false
+}
+
+// TrainingFilter - фильтры, сортировка и позиция страницы списка тренировок.
+// Незаданные фильтры не применяются
+type TrainingFilter struct {
+	PlannedFrom *time.Time
+	PlannedTo   *time.Time
+	IsDone      *bool
+	MinRating   *int32
+	MaxRating   *int32
+	Search      *string // Подстрока названия без учета регистра
+
+	SortBy   TrainingSort
+	SortDesc bool
+
+	Limit  int32
+	Cursor *TrainingCursor // Последняя запись предыдущей страницы
+}
+
+// TrainingCursor - позиция в списке тренировок: ключ сортировки и ID последней записи страницы.
+// Сортировка хранится в курсоре, чтобы курсор нельзя было применить к списку с другим порядком
+type TrainingCursor struct {
+	SortBy   TrainingSort `json:"s"`
+	SortDesc bool         `json:"d"`
+	Key      int64        `json:"k"`
+	ID       int64        `json:"i"`
+}
+
+var errMalformedCursor = errors.New("malformed cursor")
+
+// Encode возвращает непрозрачное строковое представление курсора
+func (c TrainingCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeTrainingCursor разбирает курсор, полученный от Encode
+func DecodeTrainingCursor(s string) (*TrainingCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errMalformedCursor
+	}
+	var c TrainingCursor
+	if err := json.Unmarshal(data, &c); err != nil || !c.SortBy.IsValid() || c.ID <= 0 {
+		return nil, errMalformedCursor
+	}
+	return &c, nil
+}
+
+// TrainingPage - страница списка тренировок. NextCursor не задан на последней странице
+type TrainingPage struct {
+	Trainings  []*Training
+	NextCursor *TrainingCursor
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// UserStatsQuery - параметры запроса статистики тренировок пользователя.
+// Нулевой From означает всю историю пользователя
+type UserStatsQuery struct {
+	From    time.Time
+	To      time.Time
+	GroupBy ProgressGranularity
+}
+
+// TrainingStatsBucket - статистика тренировок за один день, неделю или месяц
+type TrainingStatsBucket struct {
+	PeriodStart time.Time `json:"period_start"`
+	TrainingStats
+}
+
+// UserTrainingStats - статистика тренировок пользователя за период: итог и разбивка по периодам.
+// В Buckets попадают только периоды, в которых были тренировки
+type UserTrainingStats struct {
+	From    time.Time             `json:"from"`
+	To      time.Time             `json:"to"`
+	GroupBy ProgressGranularity   `json:"group_by"`
+	Totals  TrainingStats         `json:"totals"`
+	Buckets []TrainingStatsBucket `json:"buckets"`
+}
+
+// CompletionRate возвращает долю завершенных тренировок от 0 до 1
+func CompletionRate(completed, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(completed) / float64(total)
+}
@@ -0,0 +1,99 @@
+package domain
+
+import "fmt"
+
+// TrainingStatus - этап жизненного цикла тренировки
+type TrainingStatus string
+
+const (
+	TrainingStatusPlanned    TrainingStatus = "planned"
+	TrainingStatusInProgress TrainingStatus = "in_progress"
+	TrainingStatusPaused     TrainingStatus = "paused"
+	TrainingStatusCompleted  TrainingStatus = "completed"
+	TrainingStatusSkipped    TrainingStatus = "skipped"
+	TrainingStatusCancelled  TrainingStatus = "cancelled"
+)
+
+// TrainingEvent - действие, переводящее тренировку из одного статуса в другой
+type TrainingEvent string
+
+const (
+	TrainingEventStart    TrainingEvent = "start"
+	TrainingEventPause    TrainingEvent = "pause"
+	TrainingEventResume   TrainingEvent = "resume"
+	TrainingEventComplete TrainingEvent = "complete"
+	TrainingEventSkip     TrainingEvent = "skip"
+	TrainingEventCancel   TrainingEvent = "cancel"
+)
+
+// Машиночитаемые причины отказа в переходе
+const (
+	TransitionReasonNotStarted      = "training_not_started"
+	TransitionReasonAlreadyStarted  = "training_already_started"
+	TransitionReasonAlreadyPaused   = "training_already_paused"
+	TransitionReasonNotPaused       = "training_not_paused"
+	TransitionReasonAlreadyFinished = "training_already_finished"
+	TransitionReasonStatusChanged   = "training_status_changed"
+)
+
+// trainingTransitions - допустимые переходы: статус -> событие -> новый статус.
+// Завершить можно и не начатую тренировку: так отмечаются тренировки,
+// выполненные без запуска таймера
+var trainingTransitions = map[TrainingStatus]map[TrainingEvent]TrainingStatus{
+	TrainingStatusPlanned: {
+		TrainingEventStart:    TrainingStatusInProgress,
+		TrainingEventComplete: TrainingStatusCompleted,
+		TrainingEventSkip:     TrainingStatusSkipped,
+		TrainingEventCancel:   TrainingStatusCancelled,
+	},
+	TrainingStatusInProgress: {
+		TrainingEventPause:    TrainingStatusPaused,
+		TrainingEventComplete: TrainingStatusCompleted,
+		TrainingEventCancel:   TrainingStatusCancelled,
+	},
+	TrainingStatusPaused: {
+		TrainingEventResume:   TrainingStatusInProgress,
+		TrainingEventComplete: TrainingStatusCompleted,
+		TrainingEventCancel:   TrainingStatusCancelled,
+	},
+}
+
+// TransitionError - событие недопустимо в текущем статусе тренировки
+type TransitionError struct {
+	From   TrainingStatus
+	Event  TrainingEvent
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot %s training in status %s: %s", e.Event, e.From, e.Reason)
+}
+
+// IsFinal сообщает, что из статуса нет переходов
+func (s TrainingStatus) IsFinal() bool {
+	return len(trainingTransitions[s]) == 0
+}
+
+// Transition возвращает статус после события или *TransitionError,
+// если событие в текущем статусе недопустимо
+func (s TrainingStatus) Transition(event TrainingEvent) (TrainingStatus, error) {
+	if next, ok := trainingTransitions[s][event]; ok {
+		return next, nil
+	}
+	return s, &TransitionError{From: s, Event: event, Reason: transitionReason(s, event)}
+}
+
+func transitionReason(from TrainingStatus, event TrainingEvent) string {
+	switch {
+	case from.IsFinal():
+		return TransitionReasonAlreadyFinished
+	case event == TrainingEventStart, event == TrainingEventSkip:
+		return TransitionReasonAlreadyStarted
+	case from == TrainingStatusPlanned:
+		return TransitionReasonNotStarted
+	case event == TrainingEventPause:
+		return TransitionReasonAlreadyPaused
+	default:
+		return TransitionReasonNotPaused
+	}
+}
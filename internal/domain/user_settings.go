@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultWeekStart = time.Monday
+	DefaultRestDays  = 1
+	// MaxRestDays - при недельном перерыве серия в любом случае считается прерванной
+	MaxRestDays = 6
+)
+
+// UserSettings - персональные настройки пользователя
+type UserSettings struct {
+	UserID    uuid.UUID    `json:"user_id"`
+	WeekStart time.Weekday `json:"week_start"` // Первый день недели для недельной статистики
+	RestDays  int32        `json:"rest_days"`  // Сколько дней отдыха подряд не прерывают серию тренировок
+	Timezone  string       `json:"timezone"`   // Часовой пояс IANA, по которому определяется "сегодня"; пустой - UTC
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// Location возвращает часовой пояс пользователя. Пояс проверяется при сохранении настроек,
+// поэтому неизвестный пояс (например, после обновления tzdata) считается UTC
+func (s UserSettings) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LocalDate возвращает календарную дату момента t в часовом поясе loc.
+// Дата представлена полночью UTC, как и даты тренировок из базы
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DefaultUserSettings возвращает настройки пользователя, который их еще не сохранял
+func DefaultUserSettings(userID uuid.UUID) UserSettings {
+	return UserSettings{
+		UserID:    userID,
+		WeekStart: DefaultWeekStart,
+		RestDays:  DefaultRestDays,
+	}
+}
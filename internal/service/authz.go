@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnauthenticated         = errors.New("unauthenticated")
+	ErrForbidden               = errors.New("access to resource is forbidden")
+	ErrTrainedExerciseNotFound = errors.New("trained exercise not found")
+)
+
+// callerID возвращает идентификатор пользователя, от имени которого выполняется запрос
+func callerID(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := domain.UserIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, ErrUnauthenticated
+	}
+	return userID, nil
+}
+
+// authorizeUser проверяет, что пользователь работает со своими данными
+func authorizeUser(ctx context.Context, userID uuid.UUID) error {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return err
+	}
+	if caller != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeAdmin проверяет, что у вызывающего пользователя есть роль администратора
+func authorizeAdmin(ctx context.Context) error {
+	if _, err := callerID(ctx); err != nil {
+		return err
+	}
+	if !domain.HasRole(ctx, domain.RoleAdmin) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// authorizeTraining проверяет, что тренировка принадлежит вызывающему пользователю,
+// и возвращает его идентификатор
+func (s *trainingService) authorizeTraining(ctx context.Context, trainingID int64) (uuid.UUID, error) {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	owner, err := s.repo.GetTrainingOwner(ctx, trainingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, ErrTrainingNotFound
+		}
+		return uuid.Nil, err
+	}
+	if owner != caller {
+		return uuid.Nil, ErrForbidden
+	}
+
+	return caller, nil
+}
+
+// authorizeTrainedExercise проверяет, что выполненное упражнение входит
+// в тренировку вызывающего пользователя, и возвращает идентификаторы пользователя и тренировки
+func (s *trainingService) authorizeTrainedExercise(ctx context.Context, trainedExerciseID int64) (uuid.UUID, int64, error) {
+	caller, err := callerID(ctx)
+	if err != nil {
+		return uuid.Nil, 0, err
+	}
+
+	owner, trainingID, err := s.repo.GetTrainedExerciseOwner(ctx, trainedExerciseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, 0, ErrTrainedExerciseNotFound
+		}
+		return uuid.Nil, 0, err
+	}
+	if owner != caller {
+		return uuid.Nil, 0, ErrForbidden
+	}
+
+	return caller, trainingID, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCalendarFeedNotFound  = errors.New("calendar feed not found")
+	ErrCalendarTokenNotFound = errors.New("calendar token not found")
+)
+
+const (
+	// calendarTokenBytes - длина случайного ключа подписки до кодирования base64url
+	calendarTokenBytes = 32
+	// calendarFeedHistoryDays - за сколько прошедших дней тренировки попадают в календарь.
+	// Запланированные тренировки попадают все
+	calendarFeedHistoryDays = 180
+)
+
+// RotateCalendarToken выдает пользователю новый ключ подписки на календарь. Прежний ключ
+// перестает работать. Сохраняется только хэш ключа, поэтому сам ключ возвращается один раз
+func (s *trainingService) RotateCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	if userID == uuid.Nil {
+		return "", ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return "", err
+	}
+
+	raw := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		logging.Error(err, "RotateCalendarToken", nil, "failed to generate calendar token")
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.repo.SetCalendarToken(ctx, userID, calendarTokenHash(token)); err != nil {
+		return "", err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id": userID.String(),
+	})
+	logging.Info("RotateCalendarToken", jsonData, "calendar token rotated")
+
+	return token, nil
+}
+
+// RevokeCalendarToken отзывает ключ подписки: ссылка на календарь перестает работать
+func (s *trainingService) RevokeCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteCalendarToken(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarTokenNotFound
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id": userID.String(),
+	})
+	logging.Info("RevokeCalendarToken", jsonData, "calendar token revoked")
+
+	return nil
+}
+
+// GetCalendarFeed строит календарь iCalendar с тренировками владельца ключа: прошедшими за последние
+// calendarFeedHistoryDays дней и всеми запланированными. Ключ заменяет аутентификацию, поэтому
+// неизвестный ключ не отличается от отозванного
+func (s *trainingService) GetCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	userID, err := s.repo.GetCalendarTokenUser(ctx, calendarTokenHash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCalendarFeedNotFound
+		}
+		return nil, err
+	}
+
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	from := domain.LocalDate(time.Now(), loc).AddDate(0, 0, -calendarFeedHistoryDays)
+
+	titles, err := s.exerciseTitles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var trainings []*domain.Training
+	var after *domain.ExportCursor
+	for {
+		batch, err := s.repo.GetTrainingsForExport(ctx, userID, &from, nil, after, exportBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		trainings = append(trainings, batch...)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		after = &domain.ExportCursor{PlannedDate: last.PlannedDate, ID: last.ID}
+	}
+
+	var buf bytes.Buffer
+	if err := domain.WriteICalendar(&buf, trainings, titles, time.Now()); err != nil {
+		return nil, err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":   userID.String(),
+		"trainings": len(trainings),
+	})
+	logging.Debug("GetCalendarFeed", jsonData, "calendar feed built")
+
+	return buf.Bytes(), nil
+}
+
+// calendarTokenHash - хэш ключа подписки, по которому ключ ищется в базе
+func calendarTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
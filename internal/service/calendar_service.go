@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCalendarRange = errors.New("from and to are required, from must not be after to, range must not exceed 366 days")
+
+// maxCalendarDays ограничивает период календаря, чтобы запрос не разворачивал произвольно длинный ряд дней
+const maxCalendarDays = 366
+
+func (s *trainingService) GetTrainingCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.CalendarDay, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if from.IsZero() || to.IsZero() || from.After(to) || daysBetween(from, to) >= maxCalendarDays {
+		return nil, ErrInvalidCalendarRange
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTrainingCalendar(ctx, userID, from, to)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+)
+
+var ErrInvalidCardioMetrics = errors.New("cardio metrics do not match the exercise type or are out of range")
+
+// exerciseType - тип упражнения каталога, по которому проверяются кардио-показатели
+func (s *trainingService) exerciseType(ctx context.Context, exerciseID int64) (domain.ExerciseType, error) {
+	exerciseType, err := s.repo.GetExerciseType(ctx, exerciseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrExerciseNotFound
+		}
+		return "", err
+	}
+	return exerciseType, nil
+}
+
+// normalizeCardio накладывает переданные кардио-показатели на сохраненные и проверяет результат
+// по типу упражнения каталога. Дистанция в patch задана в его единице, а без нее - в сохраненной
+// единице или единице по умолчанию для типа упражнения. Если время в движении не задано,
+// берется время выполнения упражнения, а без него - общее время упражнения
+func normalizeCardio(exerciseType domain.ExerciseType, current, patch *domain.CardioMetrics, doing, total *time.Duration) (*domain.CardioMetrics, error) {
+	p := *patch
+	if p.DistanceUnit == "" && current != nil {
+		p.DistanceUnit = current.DistanceUnit
+	}
+	if p.DistanceUnit == "" {
+		p.DistanceUnit = exerciseType.DefaultDistanceUnit()
+	}
+	if p.Distance != nil && p.DistanceUnit.IsValid() {
+		meters := p.DistanceUnit.ToMeters(*p.Distance)
+		p.Distance = &meters
+	}
+
+	if p.MovingTime == nil && (current == nil || current.MovingTime == nil) {
+		switch {
+		case doing != nil && *doing > 0:
+			p.MovingTime = doing
+		case total != nil && *total > 0:
+			p.MovingTime = total
+		}
+	}
+
+	cardio := domain.MergeCardioMetrics(current, &p)
+	if err := cardio.Normalize(exerciseType); err != nil {
+		return nil, fmt.Errorf("%w (%v)", ErrInvalidCardioMetrics, err)
+	}
+	return cardio, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidConsistencyWeeks = errors.New("weeks must be between 1 and 52")
+	ErrInvalidUserSettings     = errors.New("week_start must be between 0 (sunday) and 6, rest_days must be between 0 and 6")
+)
+
+const (
+	// defaultConsistencyWeeks - за сколько последних недель считается регулярность, если не задано
+	defaultConsistencyWeeks = 12
+	maxConsistencyWeeks     = 52
+)
+
+func (s *trainingService) GetTrainingConsistency(ctx context.Context, userID uuid.UUID, weeks int32) (*domain.Consistency, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if weeks == 0 {
+		weeks = defaultConsistencyWeeks
+	}
+	if weeks < 0 || weeks > maxConsistencyWeeks {
+		return nil, ErrInvalidConsistencyWeeks
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	dates, err := s.repo.GetUserTrainingDates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := domain.LocalDate(time.Now(), settingsLocation(ctx, settings))
+	return calculateConsistency(dates, *settings, today, weeks), nil
+}
+
+func (s *trainingService) GetUserSettings(ctx context.Context, userID uuid.UUID) (*domain.UserSettings, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetUserSettings(ctx, userID)
+}
+
+func (s *trainingService) UpdateUserSettings(ctx context.Context, cmd domain.UpdateUserSettingsCmd) (*domain.UserSettings, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.GetUserSettings(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.WeekStart != nil {
+		settings.WeekStart = *cmd.WeekStart
+	}
+	if cmd.RestDays != nil {
+		settings.RestDays = *cmd.RestDays
+	}
+	if cmd.Timezone != nil {
+		if *cmd.Timezone != "" {
+			if _, err := parseTimezone(*cmd.Timezone); err != nil {
+				return nil, err
+			}
+		}
+		settings.Timezone = *cmd.Timezone
+	}
+	if settings.WeekStart < time.Sunday || settings.WeekStart > time.Saturday ||
+		settings.RestDays < 0 || settings.RestDays > domain.MaxRestDays {
+		return nil, ErrInvalidUserSettings
+	}
+
+	return s.repo.SaveUserSettings(ctx, settings)
+}
+
+// calculateConsistency считает серии по дням выполненных тренировок (фактическая дата,
+// а если ее нет - запланированная) и недельную регулярность по запланированным датам.
+// Регулярность считается за weeks последних недель, включая текущую; тренировки,
+// запланированные на будущие дни текущей недели, еще не учитываются
+func calculateConsistency(dates []domain.TrainingDate, settings domain.UserSettings, today time.Time, weeks int32) *domain.Consistency {
+	consistency := &domain.Consistency{
+		WeekStart: settings.WeekStart,
+		RestDays:  settings.RestDays,
+	}
+
+	// Между соседними днями серии может быть не больше RestDays дней отдыха
+	maxGap := int(settings.RestDays) + 1
+
+	var lastDay time.Time
+	var streak int32
+	for _, d := range dates {
+		if !d.IsDone {
+			continue
+		}
+		day := d.PlannedDate
+		if d.ActualDate != nil {
+			day = *d.ActualDate
+		}
+		day = truncateToDay(day)
+		if day.After(today) {
+			continue
+		}
+
+		switch {
+		case lastDay.IsZero() || daysBetween(lastDay, day) > maxGap:
+			streak = 1
+		case day.After(lastDay):
+			streak++
+		default:
+			// Вторая тренировка в тот же день серию не продлевает
+			continue
+		}
+		lastDay = day
+		if streak > consistency.LongestStreak {
+			consistency.LongestStreak = streak
+		}
+	}
+	if !lastDay.IsZero() {
+		consistency.LastTrainingDay = &lastDay
+		if daysBetween(lastDay, today) <= maxGap {
+			consistency.CurrentStreak = streak
+		}
+	}
+
+	currentWeek := weekStart(today, settings.WeekStart)
+	firstWeek := currentWeek.AddDate(0, 0, -7*int(weeks-1))
+	consistency.Weeks = make([]domain.WeeklyAdherence, weeks)
+	for i := range consistency.Weeks {
+		consistency.Weeks[i].WeekStart = firstWeek.AddDate(0, 0, 7*i)
+	}
+
+	var planned, completed int32
+	for _, d := range dates {
+		if d.Status == domain.TrainingStatusCancelled {
+			continue
+		}
+		day := truncateToDay(d.PlannedDate)
+		if day.Before(firstWeek) || day.After(today) {
+			continue
+		}
+		// Начатые и поставленные на паузу тренировки еще могут быть завершены
+		if day.Before(today) && (d.Status == domain.TrainingStatusPlanned || d.Status == domain.TrainingStatusSkipped) {
+			consistency.MissedSessions++
+		}
+
+		week := &consistency.Weeks[daysBetween(firstWeek, day)/7]
+		week.Planned++
+		planned++
+		if d.IsDone {
+			week.Completed++
+			completed++
+		}
+	}
+	if planned > 0 {
+		consistency.AdherencePercent = math.Round(float64(completed)/float64(planned)*1000) / 10
+	}
+
+	return consistency
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween возвращает число календарных дней от from до to
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+// weekStart возвращает первый день недели, в которую попадает day
+func weekStart(day time.Time, first time.Weekday) time.Time {
+	offset := (int(day.Weekday()) - int(first) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+// subscriberBuffer - сколько событий может накопиться у медленного подписчика,
+// прежде чем его подписка будет закрыта
+const subscriberBuffer = 32
+
+// EventBus - внутрипроцессная шина событий живых сессий тренировок.
+// Подписки группируются по тренировке, публикация не блокирует сервис
+type EventBus struct {
+	mu   sync.RWMutex
+	subs map[int64]map[*subscription]struct{}
+}
+
+type subscription struct {
+	ch   chan domain.LiveEvent
+	once sync.Once
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[int64]map[*subscription]struct{})}
+}
+
+// Subscribe подписывает на события тренировки. Возвращаемая функция отменяет
+// подписку и закрывает канал; вызывать ее повторно безопасно
+func (b *EventBus) Subscribe(trainingID int64) (<-chan domain.LiveEvent, func()) {
+	sub := &subscription{ch: make(chan domain.LiveEvent, subscriberBuffer)}
+
+	b.mu.Lock()
+	if b.subs[trainingID] == nil {
+		b.subs[trainingID] = make(map[*subscription]struct{})
+	}
+	b.subs[trainingID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.ch, func() { b.unsubscribe(trainingID, sub) }
+}
+
+// Publish рассылает событие подписчикам его тренировки. Подписка, в буфер которой событие
+// не помещается, закрывается: пропущенное событие рассинхронизировало бы клиента,
+// а после переподключения он получит актуальный снимок тренировки
+func (b *EventBus) Publish(event domain.LiveEvent) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	var slow []*subscription
+	b.mu.RLock()
+	for sub := range b.subs[event.TrainingID] {
+		select {
+		case sub.ch <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"training_id": event.TrainingID,
+			"event":       event.Type,
+		})
+		logging.Warn("EventBus.Publish", jsonData, "subscriber is too slow, subscription closed")
+		b.unsubscribe(event.TrainingID, sub)
+	}
+}
+
+// unsubscribe удаляет подписку и закрывает ее канал. Канал закрывается после удаления
+// из шины, поэтому Publish в него уже не пишет
+func (b *EventBus) unsubscribe(trainingID int64, sub *subscription) {
+	b.mu.Lock()
+	delete(b.subs[trainingID], sub)
+	if len(b.subs[trainingID]) == 0 {
+		delete(b.subs, trainingID)
+	}
+	b.mu.Unlock()
+
+	sub.once.Do(func() { close(sub.ch) })
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidExerciseOrder = errors.New("order must list every exercise of the training exactly once")
+	ErrInvalidExerciseGroup = errors.New("invalid exercise group: group_id must be positive, group_type one of superset, circuit, giant_set and the same for the whole group")
+)
+
+func (s *trainingService) ReorderTrainedExercises(ctx context.Context, trainingID int64, trainedExerciseIDs []int64) (*domain.Training, error) {
+	if trainingID <= 0 {
+		return nil, ErrInvalidTrainingID
+	}
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrainingNotFound
+		}
+		return nil, err
+	}
+
+	// Новый порядок должен быть перестановкой текущих упражнений
+	if len(trainedExerciseIDs) != len(current.Exercises) {
+		return nil, ErrInvalidExerciseOrder
+	}
+	known := make(map[int64]bool, len(current.Exercises))
+	for _, ex := range current.Exercises {
+		known[ex.ID] = true
+	}
+	for _, id := range trainedExerciseIDs {
+		if !known[id] {
+			return nil, ErrInvalidExerciseOrder
+		}
+		delete(known, id)
+	}
+
+	if err := s.repo.ReorderTrainedExercises(ctx, trainingID, trainedExerciseIDs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidExerciseOrder
+		}
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:       domain.LiveEventExercisesReordered,
+		TrainingID: trainingID,
+		UserID:     userID,
+		Training:   training,
+	})
+	return training, nil
+}
+
+func (s *trainingService) SetTrainedExerciseGroup(ctx context.Context, trainedExerciseID int64, group *domain.ExerciseGroup) (*domain.TrainedExercise, error) {
+	if trainedExerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, trainedExerciseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if group != nil {
+		if err := s.checkTrainingGroup(ctx, trainingID, userID, trainedExerciseID, *group); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.SetTrainedExerciseGroup(ctx, trainedExerciseID, userID, group)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishExerciseUpdated(trainingID, userID, updated)
+	return updated, nil
+}
+
+// checkTrainingGroup проверяет группу, в которую входит упражнение тренировки: остальные упражнения
+// с тем же номером группы должны быть объединены в группу того же вида
+func (s *trainingService) checkTrainingGroup(ctx context.Context, trainingID int64, userID uuid.UUID, trainedExerciseID int64, group domain.ExerciseGroup) error {
+	if group.ID <= 0 || !group.Type.Valid() {
+		return ErrInvalidExerciseGroup
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTrainingNotFound
+		}
+		return err
+	}
+	for _, ex := range training.Exercises {
+		if ex.ID == trainedExerciseID || ex.Group == nil {
+			continue
+		}
+		if ex.Group.ID == group.ID && ex.Group.Type != group.Type {
+			return ErrInvalidExerciseGroup
+		}
+	}
+	return nil
+}
+
+// validateSlotGroups проверяет группы слотов глобальной тренировки или шаблона:
+// у всех слотов с одним номером группы должен быть один вид
+func validateSlotGroups(exercises []domain.GlobalTrainingExerciseCmd) error {
+	types := make(map[int32]domain.ExerciseGroupType)
+	for _, ex := range exercises {
+		if ex.Group == nil {
+			continue
+		}
+		if ex.Group.ID <= 0 || !ex.Group.Type.Valid() {
+			return ErrInvalidExerciseGroup
+		}
+		if groupType, ok := types[ex.Group.ID]; ok && groupType != ex.Group.Type {
+			return ErrInvalidExerciseGroup
+		}
+		types[ex.Group.ID] = ex.Group.Type
+	}
+	return nil
+}
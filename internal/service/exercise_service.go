@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"net/url"
 	"strings"
 
 	"github.com/EnduranNSU/trainings/internal/domain"
@@ -14,6 +15,16 @@ var (
 	ErrExerciseNotFound  = errors.New("exercise not found")
 	ErrTagNotFound       = errors.New("tag not found")
 	ErrEmptySearchQuery  = errors.New("search query cannot be empty")
+
+	// Ошибки администрирования каталога
+	ErrEmptyExerciseTitle  = errors.New("exercise title is required")
+	ErrEmptyTagType        = errors.New("tag type is required")
+	ErrInvalidVideoURL     = errors.New("invalid video_url")
+	ErrInvalidImageURL     = errors.New("invalid image_url")
+	ErrExerciseInUse       = errors.New("exercise is used in trainings or templates")
+	ErrEmptyExerciseAlias  = errors.New("exercise alias cannot be empty")
+	ErrExerciseAliasTaken  = errors.New("exercise alias is already used by another exercise")
+	ErrInvalidExerciseType = errors.New("exercise type must be one of strength, running, walking, cycling, rowing, swimming, cardio")
 )
 
 func NewExerciseService(repo domain.ExerciseRepository) domain.ExerciseService {
@@ -214,4 +225,268 @@ func (s *exerciseService) exerciseHasAllTags(exercise *domain.Exercise, tagIDs [
 	}
 
 	return true
-}
\ No newline at end of file
+}
+
+func (s *exerciseService) CreateExercise(ctx context.Context, cmd domain.CreateExerciseCmd) (*domain.Exercise, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	exercise := &domain.Exercise{
+		Title:       strings.TrimSpace(cmd.Title),
+		Description: strings.TrimSpace(cmd.Description),
+		VideoUrl:    strings.TrimSpace(cmd.VideoUrl),
+		ImageUrl:    strings.TrimSpace(cmd.ImageUrl),
+		Type:        cmd.Type,
+	}
+	if exercise.Type == "" {
+		exercise.Type = domain.ExerciseTypeStrength
+	}
+	if err := validateExercise(exercise); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateExercise(ctx, exercise)
+}
+
+func (s *exerciseService) UpdateExercise(ctx context.Context, cmd domain.UpdateExerciseCmd) (*domain.Exercise, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if cmd.ID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+
+	existing, err := s.repo.GetExerciseByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, ErrExerciseNotFound
+	}
+
+	// Обновляем только переданные поля
+	if cmd.Title != nil {
+		existing.Title = strings.TrimSpace(*cmd.Title)
+	}
+	if cmd.Description != nil {
+		existing.Description = strings.TrimSpace(*cmd.Description)
+	}
+	if cmd.VideoUrl != nil {
+		existing.VideoUrl = strings.TrimSpace(*cmd.VideoUrl)
+	}
+	if cmd.ImageUrl != nil {
+		existing.ImageUrl = strings.TrimSpace(*cmd.ImageUrl)
+	}
+	if cmd.Type != nil {
+		existing.Type = *cmd.Type
+	}
+	if err := validateExercise(existing); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.UpdateExercise(ctx, existing)
+	if err != nil {
+		return nil, err
+	}
+	updated.Tags = existing.Tags
+
+	return updated, nil
+}
+
+func (s *exerciseService) DeleteExercise(ctx context.Context, id int64) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return ErrInvalidExerciseID
+	}
+
+	// Удаление каскадно стерло бы историю и шаблоны пользователей, поэтому
+	// используемые упражнения удалять нельзя
+	used, err := s.repo.IsExerciseUsed(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrExerciseInUse
+	}
+
+	deleted, err := s.repo.DeleteExercise(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrExerciseNotFound
+	}
+
+	return nil
+}
+
+func (s *exerciseService) CreateTag(ctx context.Context, tagType string) (*domain.Tag, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	tagType = strings.TrimSpace(tagType)
+	if tagType == "" {
+		return nil, ErrEmptyTagType
+	}
+
+	return s.repo.CreateTag(ctx, &domain.Tag{Type: tagType})
+}
+
+func (s *exerciseService) UpdateTag(ctx context.Context, id int64, tagType string) (*domain.Tag, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, ErrInvalidTagID
+	}
+
+	tagType = strings.TrimSpace(tagType)
+	if tagType == "" {
+		return nil, ErrEmptyTagType
+	}
+
+	if _, err := s.repo.GetTagByID(ctx, id); err != nil {
+		return nil, ErrTagNotFound
+	}
+
+	return s.repo.UpdateTag(ctx, &domain.Tag{ID: id, Type: tagType})
+}
+
+func (s *exerciseService) DeleteTag(ctx context.Context, id int64) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return ErrInvalidTagID
+	}
+
+	deleted, err := s.repo.DeleteTag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (s *exerciseService) AttachTagToExercise(ctx context.Context, exerciseID, tagID int64) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if exerciseID <= 0 {
+		return ErrInvalidExerciseID
+	}
+	if tagID <= 0 {
+		return ErrInvalidTagID
+	}
+
+	if _, err := s.repo.GetExerciseByID(ctx, exerciseID); err != nil {
+		return ErrExerciseNotFound
+	}
+	if _, err := s.repo.GetTagByID(ctx, tagID); err != nil {
+		return ErrTagNotFound
+	}
+
+	return s.repo.AttachTagToExercise(ctx, exerciseID, tagID)
+}
+
+func (s *exerciseService) DetachTagFromExercise(ctx context.Context, exerciseID, tagID int64) error {
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+	if exerciseID <= 0 {
+		return ErrInvalidExerciseID
+	}
+	if tagID <= 0 {
+		return ErrInvalidTagID
+	}
+
+	detached, err := s.repo.DetachTagFromExercise(ctx, exerciseID, tagID)
+	if err != nil {
+		return err
+	}
+	if !detached {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+func (s *exerciseService) GetExerciseAliases(ctx context.Context, exerciseID int64) ([]string, error) {
+	if exerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	if _, err := s.repo.GetExerciseByID(ctx, exerciseID); err != nil {
+		return nil, ErrExerciseNotFound
+	}
+	return s.repo.GetExerciseAliases(ctx, exerciseID)
+}
+
+// SetExerciseAliases заменяет другие названия упражнения. Повторы без учета регистра отбрасываются
+func (s *exerciseService) SetExerciseAliases(ctx context.Context, exerciseID int64, aliases []string) ([]string, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if exerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+
+	normalized := make([]string, 0, len(aliases))
+	seen := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			return nil, ErrEmptyExerciseAlias
+		}
+		if key := strings.ToLower(alias); !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, alias)
+		}
+	}
+
+	if _, err := s.repo.GetExerciseByID(ctx, exerciseID); err != nil {
+		return nil, ErrExerciseNotFound
+	}
+
+	saved, err := s.repo.SetExerciseAliases(ctx, exerciseID, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrExerciseAliasTaken
+	}
+
+	return s.repo.GetExerciseAliases(ctx, exerciseID)
+}
+
+// validateExercise проверяет обязательные поля и ссылки на медиа упражнения
+func validateExercise(exercise *domain.Exercise) error {
+	if exercise.Title == "" {
+		return ErrEmptyExerciseTitle
+	}
+	if !isValidMediaURL(exercise.VideoUrl) {
+		return ErrInvalidVideoURL
+	}
+	if !isValidMediaURL(exercise.ImageUrl) {
+		return ErrInvalidImageURL
+	}
+	if !exercise.Type.IsValid() {
+		return ErrInvalidExerciseType
+	}
+	return nil
+}
+
+// isValidMediaURL допускает пустую строку (медиа нет) или абсолютный http(s) URL
+func isValidMediaURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
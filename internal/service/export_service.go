@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidExportFormat = errors.New("export format must be one of json, csv, ndjson")
+	ErrInvalidExportRange  = errors.New("export range start must not be after its end")
+)
+
+// exportBatchSize - сколько тренировок выгрузки читается из базы за раз
+const exportBatchSize = 100
+
+// ExportTrainings пишет тренировки пользователя в w пачками по exportBatchSize, не загружая
+// всю историю в память. Ошибка после начала записи оставляет выгрузку оборванной
+func (s *trainingService) ExportTrainings(ctx context.Context, cmd domain.ExportCmd, w io.Writer) error {
+	if cmd.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return err
+	}
+	if !cmd.Format.Valid() {
+		return ErrInvalidExportFormat
+	}
+	if cmd.From != nil && cmd.To != nil && cmd.From.After(*cmd.To) {
+		return ErrInvalidExportRange
+	}
+
+	titles, err := s.exerciseTitles(ctx)
+	if err != nil {
+		return err
+	}
+
+	writer, err := domain.NewExportWriter(cmd.Format, w)
+	if err != nil {
+		return ErrInvalidExportFormat
+	}
+
+	exported := 0
+	var after *domain.ExportCursor
+	for {
+		batch, err := s.repo.GetTrainingsForExport(ctx, cmd.UserID, cmd.From, cmd.To, after, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, training := range batch {
+			if err := writer.Write(domain.ExportTraining(training, titles)); err != nil {
+				return err
+			}
+		}
+		exported += len(batch)
+		if len(batch) < exportBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		after = &domain.ExportCursor{PlannedDate: last.PlannedDate, ID: last.ID}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":   cmd.UserID.String(),
+		"format":    cmd.Format,
+		"trainings": exported,
+	})
+	logging.Info("ExportTrainings", jsonData, "trainings exported")
+
+	return nil
+}
+
+// exerciseTitles возвращает названия упражнений каталога по ID
+func (s *trainingService) exerciseTitles(ctx context.Context) (map[int64]string, error) {
+	catalog, err := s.repo.GetExerciseCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	titles := make(map[int64]string, len(catalog))
+	for _, ex := range catalog {
+		titles[ex.ID] = ex.Title
+	}
+	return titles, nil
+}
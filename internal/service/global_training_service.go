@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+)
+
+var (
+	ErrEmptyGlobalTrainingTitle   = errors.New("global training title is required")
+	ErrInvalidGlobalTrainingLevel = errors.New("level must be one of: beginner, intermediate, advanced")
+	ErrInvalidPrescription        = errors.New("invalid exercise prescription")
+	ErrGlobalTrainingInUse        = errors.New("global training is used in programs")
+)
+
+// globalTrainingLevels — уровни сложности, допустимые ограничением в схеме
+var globalTrainingLevels = map[string]bool{
+	"beginner":     true,
+	"intermediate": true,
+	"advanced":     true,
+}
+
+func (s *trainingService) CreateGlobalTraining(ctx context.Context, cmd domain.GlobalTrainingCmd) (*domain.GlobalTraining, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if err := s.validateGlobalTraining(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateGlobalTraining(ctx, cmd)
+}
+
+func (s *trainingService) UpdateGlobalTraining(ctx context.Context, id int64, cmd domain.GlobalTrainingCmd) (*domain.GlobalTraining, error) {
+	if id <= 0 {
+		return nil, ErrInvalidGlobalTrainingID
+	}
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if err := s.validateGlobalTraining(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	globalTraining, err := s.repo.UpdateGlobalTraining(ctx, id, cmd)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrGlobalTrainingNotFound
+		}
+		return nil, err
+	}
+	return globalTraining, nil
+}
+
+func (s *trainingService) DeleteGlobalTraining(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ErrInvalidGlobalTrainingID
+	}
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+
+	// Сессии программ ссылаются на тренировку, и удаление молча
+	// выбросило бы их из программ, поэтому сначала ее нужно убрать из программ
+	used, err := s.repo.IsGlobalTrainingUsedByProgram(ctx, id)
+	if err != nil {
+		return err
+	}
+	if used {
+		return ErrGlobalTrainingInUse
+	}
+
+	deleted, err := s.repo.DeleteGlobalTraining(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGlobalTrainingNotFound
+	}
+	return nil
+}
+
+// validateGlobalTraining проверяет поля тренировки, предписанную нагрузку
+// и существование всех упражнений из слотов
+func (s *trainingService) validateGlobalTraining(ctx context.Context, cmd domain.GlobalTrainingCmd) error {
+	if cmd.Title == "" {
+		return ErrEmptyGlobalTrainingTitle
+	}
+	if !globalTrainingLevels[cmd.Level] {
+		return ErrInvalidGlobalTrainingLevel
+	}
+	return s.validateExerciseSlots(ctx, cmd.Exercises)
+}
+
+// validateExerciseSlots проверяет предписанную нагрузку упражнений
+// и существование самих упражнений
+func (s *trainingService) validateExerciseSlots(ctx context.Context, exercises []domain.GlobalTrainingExerciseCmd) error {
+	ids := make([]int64, 0, len(exercises))
+	seen := make(map[int64]bool, len(exercises))
+	for _, ex := range exercises {
+		if ex.ExerciseID <= 0 {
+			return ErrInvalidExerciseID
+		}
+		if ex.Approaches != nil && *ex.Approaches <= 0 ||
+			ex.Reps != nil && *ex.Reps <= 0 ||
+			ex.Weight != nil && ex.Weight.IsNegative() ||
+			ex.Duration != nil && *ex.Duration <= 0 ||
+			ex.Rest != nil && *ex.Rest < 0 {
+			return ErrInvalidPrescription
+		}
+		// Одно упражнение может стоять в нескольких слотах, проверяем его один раз
+		if !seen[ex.ExerciseID] {
+			seen[ex.ExerciseID] = true
+			ids = append(ids, ex.ExerciseID)
+		}
+	}
+	if err := validateSlotGroups(exercises); err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+	count, err := s.repo.CountExercises(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return ErrExerciseNotFound
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidImportFormat = errors.New("import format must be one of strong, hevy, json, gpx, tcx, fit")
+	ErrInvalidImportFile   = errors.New("invalid import file")
+	ErrEmptyImportFile     = errors.New("import file contains no workouts")
+)
+
+// ImportHistory импортирует историю тренировок из выгрузки Strong, Hevy, выгрузки этого сервиса
+// или файла кардио-активности GPX, TCX или FIT. Упражнения сопоставляются с каталогом, несопоставленные
+// упражнения и уже импортированные тренировки пропускаются и попадают в отчет. Все тренировки создаются одной транзакцией; при DryRun только строится отчет
+func (s *trainingService) ImportHistory(ctx context.Context, cmd domain.ImportHistoryCmd) (*domain.ImportReport, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+	if !cmd.Format.Valid() {
+		return nil, ErrInvalidImportFormat
+	}
+
+	loc, err := s.userLocation(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var workouts []domain.ImportedWorkout
+	switch cmd.Format {
+	case domain.ImportFormatJSON:
+		workouts, err = domain.ParseImportJSON(cmd.File, loc)
+	case domain.ImportFormatGPX, domain.ImportFormatTCX, domain.ImportFormatFIT:
+		workouts, err = domain.ParseImportCardio(cmd.Format, cmd.File, loc)
+	default:
+		workouts, err = domain.ParseImportCSV(cmd.Format, cmd.File, loc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (%v)", ErrInvalidImportFile, err)
+	}
+	if len(workouts) == 0 {
+		return nil, ErrEmptyImportFile
+	}
+
+	catalog, err := s.repo.GetExerciseCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Тренировки отсортированы по времени начала, поэтому дубликаты ищутся между первой и последней
+	existing, err := s.repo.GetTrainingTitles(ctx, cmd.UserID, workouts[0].Date(), workouts[len(workouts)-1].Date())
+	if err != nil {
+		return nil, err
+	}
+
+	planned, report := domain.PlanImport(cmd.Format, workouts, domain.NewExerciseMatcher(catalog), existing)
+	report.DryRun = cmd.DryRun
+
+	if !cmd.DryRun && len(planned) > 0 {
+		trainings, err := s.repo.ImportTrainings(ctx, cmd.UserID, planned)
+		if err != nil {
+			return nil, err
+		}
+		// Рекорды пересчитываются в хронологическом порядке, как если бы тренировки завершались по очереди
+		for _, training := range trainings {
+			report.TrainingIDs = append(report.TrainingIDs, training.ID)
+			for i := range training.Exercises {
+				s.detectPersonalRecords(ctx, cmd.UserID, &training.Exercises[i])
+			}
+		}
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"user_id":   cmd.UserID.String(),
+		"format":    cmd.Format,
+		"dry_run":   cmd.DryRun,
+		"workouts":  report.Workouts,
+		"imported":  report.Imported,
+		"skipped":   len(report.Skipped),
+		"unmatched": len(report.Unmatched),
+	})
+	logging.Info("ImportHistory", jsonData, "training history import finished")
+
+	return report, nil
+}
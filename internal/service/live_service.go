@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SubscribeTraining подписывает вызывающего пользователя на события его тренировки.
+// Функцию отмены нужно вызвать, когда клиент отключился
+func (s *trainingService) SubscribeTraining(ctx context.Context, trainingID int64) (<-chan domain.LiveEvent, func(), error) {
+	if trainingID <= 0 {
+		return nil, nil, ErrInvalidTrainingID
+	}
+	if _, err := s.authorizeTraining(ctx, trainingID); err != nil {
+		return nil, nil, err
+	}
+
+	events, unsubscribe := s.events.Subscribe(trainingID)
+	return events, unsubscribe, nil
+}
+
+func (s *trainingService) publishTraining(eventType domain.LiveEventType, training *domain.Training) {
+	s.events.Publish(domain.LiveEvent{
+		Type:       eventType,
+		TrainingID: training.ID,
+		UserID:     training.UserID,
+		Training:   training,
+	})
+}
+
+func (s *trainingService) publishExerciseUpdated(trainingID int64, userID uuid.UUID, exercise *domain.TrainedExercise) {
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventExerciseUpdated,
+		TrainingID:        trainingID,
+		UserID:            userID,
+		TrainedExerciseID: exercise.ID,
+		Exercise:          exercise,
+	})
+}
+
+// updateExerciseTime сохраняет таймеры упражнения, пересчитывает его рекорды
+// и оповещает подписчиков тренировки
+func (s *trainingService) updateExerciseTime(ctx context.Context, trainingID int64, exercise *domain.TrainedExercise, userID uuid.UUID) (*domain.TrainedExercise, error) {
+	updated, err := s.repo.UpdateExerciseTime(ctx, exercise, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.refreshPersonalRecords(ctx, userID, updated)
+
+	s.publishExerciseUpdated(trainingID, userID, updated)
+	return updated, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+	"github.com/google/uuid"
+)
+
+func (s *trainingService) GetPersonalRecords(ctx context.Context, userID uuid.UUID) ([]*domain.PersonalRecord, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetPersonalRecords(ctx, userID)
+}
+
+func (s *trainingService) GetExercisePersonalRecords(ctx context.Context, userID uuid.UUID, exerciseID int64) ([]*domain.PersonalRecord, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if exerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetExercisePersonalRecords(ctx, userID, exerciseID)
+}
+
+// detectPersonalRecords пересчитывает рекорды упражнения и возвращает новые.
+// Рекорды засчитываются только для завершенных тренировок, это проверяет репозиторий.
+// Ошибка пересчета не должна срывать сохранение тренировки, поэтому она только логируется
+func (s *trainingService) detectPersonalRecords(ctx context.Context, userID uuid.UUID, exercise *domain.TrainedExercise) []domain.PersonalRecord {
+	candidates := domain.PersonalRecordCandidates(exercise)
+	records, err := s.repo.SavePersonalRecords(ctx, exercise.ID, userID, candidates)
+	if err != nil {
+		jsonData := logging.MarshalLogData(map[string]interface{}{
+			"trained_exercise_id": exercise.ID,
+			"user_id":             userID.String(),
+		})
+		logging.Error(err, "detectPersonalRecords", jsonData, "failed to update personal records")
+		return nil
+	}
+	return records
+}
+
+// refreshPersonalRecords пересчитывает рекорды измененного упражнения с учетом его подходов
+func (s *trainingService) refreshPersonalRecords(ctx context.Context, userID uuid.UUID, exercise *domain.TrainedExercise) {
+	sets, err := s.repo.GetTrainedSets(ctx, exercise.ID, userID)
+	if err != nil {
+		return
+	}
+
+	withSets := *exercise
+	withSets.Sets = make([]domain.TrainedSet, len(sets))
+	for i, set := range sets {
+		withSets.Sets[i] = *set
+	}
+	s.detectPersonalRecords(ctx, userID, &withSets)
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidProgramID        = errors.New("invalid program id")
+	ErrProgramNotFound         = errors.New("program not found")
+	ErrEmptyProgramTitle       = errors.New("program title is required")
+	ErrInvalidProgramWeeks     = fmt.Errorf("weeks must be between 1 and %d", domain.MaxProgramWeeks)
+	ErrInvalidProgramSession   = errors.New("session week must be within program weeks, day between 1 and 7, (week, day) unique and increments non-negative")
+	ErrEmptyProgram            = errors.New("program has no sessions")
+	ErrInvalidEnrollmentID     = errors.New("invalid enrollment id")
+	ErrEnrollmentNotFound      = errors.New("enrollment not found")
+	ErrInvalidProgramWeekdays  = errors.New("weekdays must be distinct days from 0 (sunday) to 6 (saturday)")
+	ErrNotEnoughWeekdays       = errors.New("program week has more sessions than chosen weekdays")
+	ErrInvalidProgramStartDate = errors.New("start_date must not be in the past")
+)
+
+func (s *trainingService) GetPrograms(ctx context.Context) ([]*domain.Program, error) {
+	return s.repo.GetPrograms(ctx)
+}
+
+func (s *trainingService) GetProgram(ctx context.Context, programID int64) (*domain.Program, error) {
+	if programID <= 0 {
+		return nil, ErrInvalidProgramID
+	}
+
+	program, err := s.repo.GetProgram(ctx, programID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProgramNotFound
+		}
+		return nil, err
+	}
+	return program, nil
+}
+
+func (s *trainingService) CreateProgram(ctx context.Context, cmd domain.ProgramCmd) (*domain.Program, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if err := s.validateProgram(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateProgram(ctx, cmd)
+}
+
+func (s *trainingService) UpdateProgram(ctx context.Context, programID int64, cmd domain.ProgramCmd) (*domain.Program, error) {
+	if programID <= 0 {
+		return nil, ErrInvalidProgramID
+	}
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if err := s.validateProgram(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	program, err := s.repo.UpdateProgram(ctx, programID, cmd)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrProgramNotFound
+		}
+		return nil, err
+	}
+	return program, nil
+}
+
+func (s *trainingService) DeleteProgram(ctx context.Context, programID int64) error {
+	if programID <= 0 {
+		return ErrInvalidProgramID
+	}
+	if err := authorizeAdmin(ctx); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteProgram(ctx, programID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrProgramNotFound
+	}
+	return nil
+}
+
+// validateProgram проверяет поля программы, тренировочные дни
+// и существование всех глобальных тренировок, на которые они ссылаются
+func (s *trainingService) validateProgram(ctx context.Context, cmd domain.ProgramCmd) error {
+	if cmd.Title == "" {
+		return ErrEmptyProgramTitle
+	}
+	if !globalTrainingLevels[cmd.Level] {
+		return ErrInvalidGlobalTrainingLevel
+	}
+	if cmd.Weeks < 1 || cmd.Weeks > domain.MaxProgramWeeks {
+		return ErrInvalidProgramWeeks
+	}
+
+	type slot struct{ week, day int32 }
+	slots := make(map[slot]bool, len(cmd.Sessions))
+	ids := make([]int64, 0, len(cmd.Sessions))
+	seen := make(map[int64]bool)
+	for _, session := range cmd.Sessions {
+		if session.Week < 1 || session.Week > cmd.Weeks ||
+			session.Day < 1 || session.Day > 7 ||
+			slots[slot{session.Week, session.Day}] ||
+			session.WeightIncrement != nil && session.WeightIncrement.IsNegative() ||
+			session.RepsIncrement != nil && *session.RepsIncrement < 0 {
+			return ErrInvalidProgramSession
+		}
+		slots[slot{session.Week, session.Day}] = true
+
+		if session.GlobalTrainingID <= 0 {
+			return ErrInvalidGlobalTrainingID
+		}
+		if !seen[session.GlobalTrainingID] {
+			seen[session.GlobalTrainingID] = true
+			ids = append(ids, session.GlobalTrainingID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+	count, err := s.repo.CountGlobalTrainings(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return ErrGlobalTrainingNotFound
+	}
+	return nil
+}
+
+func (s *trainingService) EnrollInProgram(ctx context.Context, cmd domain.EnrollProgramCmd) (*domain.ProgramProgress, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if cmd.ProgramID <= 0 {
+		return nil, ErrInvalidProgramID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	program, err := s.GetProgram(ctx, cmd.ProgramID)
+	if err != nil {
+		return nil, err
+	}
+	if len(program.Sessions) == 0 {
+		return nil, ErrEmptyProgram
+	}
+
+	today, err := s.userToday(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	startDate := today
+	if cmd.StartDate != nil {
+		startDate = truncateToDay(*cmd.StartDate)
+		if startDate.Before(today) {
+			return nil, ErrInvalidProgramStartDate
+		}
+	}
+
+	plan, err := planProgramSessions(program, startDate, cmd.Weekdays)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.repo.EnrollInProgram(ctx, &domain.ProgramEnrollment{
+		ProgramID: program.ID,
+		UserID:    cmd.UserID,
+		StartDate: startDate,
+		Weekdays:  cmd.Weekdays,
+	}, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.enrollmentProgress(ctx, enrollment, today)
+}
+
+func (s *trainingService) GetEnrollments(ctx context.Context, userID uuid.UUID) ([]*domain.ProgramProgress, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	enrollments, err := s.repo.GetEnrollments(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make([]*domain.ProgramProgress, 0, len(enrollments))
+	for _, enrollment := range enrollments {
+		p, err := s.enrollmentProgress(ctx, enrollment, today)
+		if err != nil {
+			return nil, err
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+func (s *trainingService) GetEnrollmentProgress(ctx context.Context, userID uuid.UUID, enrollmentID int64) (*domain.ProgramProgress, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if enrollmentID <= 0 {
+		return nil, ErrInvalidEnrollmentID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.repo.GetEnrollment(ctx, enrollmentID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrEnrollmentNotFound
+		}
+		return nil, err
+	}
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.enrollmentProgress(ctx, enrollment, today)
+}
+
+func (s *trainingService) Unenroll(ctx context.Context, userID uuid.UUID, enrollmentID int64) error {
+	if userID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if enrollmentID <= 0 {
+		return ErrInvalidEnrollmentID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteEnrollment(ctx, enrollmentID, userID, today)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrEnrollmentNotFound
+	}
+	return nil
+}
+
+// enrollmentProgress дополняет счетчики тренировок записи процентом выполнения и текущей неделей
+func (s *trainingService) enrollmentProgress(ctx context.Context, enrollment *domain.ProgramEnrollment, today time.Time) (*domain.ProgramProgress, error) {
+	progress, err := s.repo.GetEnrollmentProgress(ctx, enrollment)
+	if err != nil {
+		return nil, err
+	}
+
+	if progress.TotalSessions > 0 {
+		percent := float64(progress.CompletedSessions) / float64(progress.TotalSessions) * 100
+		progress.CompletionPercent = math.Round(percent*10) / 10
+	}
+
+	start := truncateToDay(enrollment.StartDate)
+	if !today.Before(start) {
+		progress.CurrentWeek = int32(daysBetween(start, today)/7) + 1
+		if progress.CurrentWeek > enrollment.Weeks {
+			progress.CurrentWeek = enrollment.Weeks
+		}
+	}
+	return progress, nil
+}
+
+// planProgramSessions назначает тренировочные дни программы на даты. Неделя w занимает
+// семь дней начиная с start + 7*(w-1); тренировки недели по порядку Day ставятся
+// на выбранные дни недели в порядке их следования от начала недели программы
+func planProgramSessions(program *domain.Program, start time.Time, weekdays []time.Weekday) ([]domain.ProgramSessionPlan, error) {
+	if len(weekdays) == 0 || len(weekdays) > 7 {
+		return nil, ErrInvalidProgramWeekdays
+	}
+	chosen := make(map[time.Weekday]bool, len(weekdays))
+	for _, day := range weekdays {
+		if day < time.Sunday || day > time.Saturday || chosen[day] {
+			return nil, ErrInvalidProgramWeekdays
+		}
+		chosen[day] = true
+	}
+
+	sessions := make([]domain.ProgramSession, len(program.Sessions))
+	copy(sessions, program.Sessions)
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].Week != sessions[j].Week {
+			return sessions[i].Week < sessions[j].Week
+		}
+		return sessions[i].Day < sessions[j].Day
+	})
+
+	plan := make([]domain.ProgramSessionPlan, 0, len(sessions))
+	for i := 0; i < len(sessions); {
+		week := sessions[i].Week
+		weekStart := start.AddDate(0, 0, 7*int(week-1))
+
+		var dates []time.Time
+		for offset := 0; offset < 7; offset++ {
+			day := weekStart.AddDate(0, 0, offset)
+			if chosen[day.Weekday()] {
+				dates = append(dates, day)
+			}
+		}
+
+		n := 0
+		for ; i < len(sessions) && sessions[i].Week == week; i++ {
+			if n >= len(dates) {
+				return nil, fmt.Errorf("%w (week %d)", ErrNotEnoughWeekdays, week)
+			}
+			plan = append(plan, domain.ProgramSessionPlan{
+				Session:     sessions[i],
+				PlannedDate: dates[n],
+			})
+			n++
+		}
+	}
+	return plan, nil
+}
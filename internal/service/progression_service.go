@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+func (s *trainingService) SuggestExerciseLoad(ctx context.Context, userID uuid.UUID, exerciseID int64) (*domain.LoadSuggestion, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if exerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountExercises(ctx, []int64{exerciseID})
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrExerciseNotFound
+	}
+
+	return s.suggestExerciseLoad(ctx, userID, exerciseID)
+}
+
+// suggestExerciseLoad читает столько последних выполнений, сколько нужно для решения о снижении веса
+func (s *trainingService) suggestExerciseLoad(ctx context.Context, userID uuid.UUID, exerciseID int64) (*domain.LoadSuggestion, error) {
+	limit := s.progression.DeloadAfter
+	if limit < 1 {
+		limit = 1
+	}
+
+	history, err := s.repo.GetRecentExerciseSessions(ctx, userID, exerciseID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion := s.progression.Suggest(exerciseID, history)
+	return &suggestion, nil
+}
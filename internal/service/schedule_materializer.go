@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/EnduranNSU/trainings/internal/logging"
+)
+
+// ScheduleMaterializer в фоне создает тренировки по расписаниям всех пользователей,
+// поддерживая их на горизонт scheduleHorizonDays дней вперед
+type ScheduleMaterializer struct {
+	repo     domain.TrainingRepository
+	interval time.Duration
+}
+
+func NewScheduleMaterializer(repo domain.TrainingRepository, interval time.Duration) *ScheduleMaterializer {
+	return &ScheduleMaterializer{repo: repo, interval: interval}
+}
+
+// Run материализует расписания сразу и затем каждые interval, пока не отменен ctx
+func (m *ScheduleMaterializer) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.MaterializeAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// MaterializeAll проходит по расписаниям, развернутым не до конца горизонта.
+// Ошибка одного расписания не мешает остальным
+func (m *ScheduleMaterializer) MaterializeAll(ctx context.Context) {
+	// День считается по UTC: горизонт в несколько недель не чувствителен к часовому поясу,
+	// а новые расписания разворачиваются сразу при создании в поясе пользователя
+	today := truncateToDay(time.Now().UTC())
+	horizon := today.AddDate(0, 0, scheduleHorizonDays)
+
+	schedules, err := m.repo.GetSchedulesToMaterialize(ctx, horizon)
+	if err != nil {
+		logging.Error(err, "MaterializeAll", nil, "failed to get schedules to materialize")
+		return
+	}
+
+	total := 0
+	for _, schedule := range schedules {
+		if ctx.Err() != nil {
+			return
+		}
+		created, err := materializeSchedule(ctx, m.repo, schedule, today)
+		if err != nil {
+			jsonData := logging.MarshalLogData(map[string]interface{}{
+				"schedule_id": schedule.ID,
+			})
+			logging.Error(err, "MaterializeAll", jsonData, "failed to materialize schedule")
+			continue
+		}
+		total += created
+	}
+
+	jsonData := logging.MarshalLogData(map[string]interface{}{
+		"schedules_count":   len(schedules),
+		"created_trainings": total,
+	})
+	logging.Info("MaterializeAll", jsonData, "schedules materialized")
+}
@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidScheduleID      = errors.New("invalid schedule id")
+	ErrScheduleNotFound       = errors.New("schedule not found")
+	ErrEmptyScheduleTitle     = errors.New("schedule title is required")
+	ErrInvalidRecurrenceRule  = errors.New("rrule must be FREQ=WEEKLY with optional INTERVAL, BYDAY and UNTIL or COUNT")
+	ErrInvalidOccurrenceDate  = errors.New("date is not an occurrence of the schedule")
+	ErrOccurrenceSkipped      = errors.New("occurrence is skipped")
+	ErrOccurrenceStarted      = errors.New("occurrence training has already been started")
+	ErrScheduleSourceConflict = errors.New("schedule can take exercises from global_training_id or template_id, not both")
+)
+
+// scheduleHorizonDays - на сколько дней вперед от сегодняшнего создаются тренировки по расписаниям
+const scheduleHorizonDays = 28
+
+func (s *trainingService) CreateSchedule(ctx context.Context, cmd domain.CreateScheduleCmd) (*domain.TrainingSchedule, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.TrainingSchedule{
+		UserID:           cmd.UserID,
+		Title:            strings.TrimSpace(cmd.Title),
+		StartDate:        cmd.StartDate,
+		GlobalTrainingID: cmd.GlobalTrainingID,
+		TemplateID:       cmd.TemplateID,
+	}
+	if err := s.prepareSchedule(ctx, schedule, cmd.RRule); err != nil {
+		return nil, err
+	}
+
+	today, err := s.userToday(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.StartDate.IsZero() {
+		schedule.StartDate = today
+	}
+
+	created, err := s.repo.CreateSchedule(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	// Ближайшие тренировки создаются сразу, не дожидаясь фоновой материализации
+	if _, err := materializeSchedule(ctx, s.repo, created, today); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSchedule(ctx, created.ID, cmd.UserID)
+}
+
+func (s *trainingService) GetSchedules(ctx context.Context, userID uuid.UUID) ([]*domain.TrainingSchedule, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSchedules(ctx, userID)
+}
+
+func (s *trainingService) GetSchedule(ctx context.Context, userID uuid.UUID, scheduleID int64) (*domain.TrainingSchedule, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if scheduleID <= 0 {
+		return nil, ErrInvalidScheduleID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getSchedule(ctx, scheduleID, userID)
+}
+
+func (s *trainingService) UpdateSchedule(ctx context.Context, cmd domain.UpdateScheduleCmd) (*domain.TrainingSchedule, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if cmd.ID <= 0 {
+		return nil, ErrInvalidScheduleID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.getSchedule(ctx, cmd.ID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Title = strings.TrimSpace(cmd.Title)
+	schedule.GlobalTrainingID = cmd.GlobalTrainingID
+	schedule.TemplateID = cmd.TemplateID
+	if err := s.prepareSchedule(ctx, schedule, cmd.RRule); err != nil {
+		return nil, err
+	}
+
+	today, err := s.userToday(ctx, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// Не начатые тренировки с сегодняшнего дня удаляются и создаются заново по новому правилу
+	if schedule.MaterializedUntil != nil && !schedule.MaterializedUntil.Before(today) {
+		yesterday := today.AddDate(0, 0, -1)
+		schedule.MaterializedUntil = &yesterday
+	}
+
+	updated, err := s.repo.UpdateSchedule(ctx, schedule, today)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	if _, err := materializeSchedule(ctx, s.repo, updated, today); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSchedule(ctx, updated.ID, cmd.UserID)
+}
+
+func (s *trainingService) DeleteSchedule(ctx context.Context, userID uuid.UUID, scheduleID int64) error {
+	if userID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if scheduleID <= 0 {
+		return ErrInvalidScheduleID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteSchedule(ctx, scheduleID, userID, today)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (s *trainingService) GetScheduleOccurrences(ctx context.Context, userID uuid.UUID, scheduleID int64, from, to time.Time) ([]domain.ScheduleOccurrence, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if scheduleID <= 0 {
+		return nil, ErrInvalidScheduleID
+	}
+	if from.IsZero() || to.IsZero() || from.After(to) || daysBetween(from, to) >= maxCalendarDays {
+		return nil, ErrInvalidCalendarRange
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.getSchedule(ctx, scheduleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	skips, err := s.repo.GetScheduleSkips(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	trainings, err := s.repo.GetScheduledTrainings(ctx, scheduleID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make(map[time.Time]domain.ScheduleOccurrence, len(trainings))
+	for _, t := range trainings {
+		created[truncateToDay(t.Date)] = t
+	}
+	skipped := skipSet(skips)
+
+	dates := schedule.Rule.Occurrences(schedule.StartDate, from, to)
+	occurrences := make([]domain.ScheduleOccurrence, 0, len(dates))
+	for _, date := range dates {
+		occurrence, ok := created[date]
+		if !ok {
+			occurrence = domain.ScheduleOccurrence{Date: date}
+		}
+		occurrence.Skipped = skipped[date]
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences, nil
+}
+
+func (s *trainingService) SkipScheduleOccurrence(ctx context.Context, userID uuid.UUID, scheduleID int64, date time.Time) error {
+	if userID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if scheduleID <= 0 {
+		return ErrInvalidScheduleID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	schedule, err := s.getSchedule(ctx, scheduleID, userID)
+	if err != nil {
+		return err
+	}
+	date = truncateToDay(date)
+	if !schedule.Rule.IsOccurrence(schedule.StartDate, date) {
+		return ErrInvalidOccurrenceDate
+	}
+
+	occurrence, err := s.scheduledTraining(ctx, scheduleID, date)
+	if err != nil {
+		return err
+	}
+	if occurrence != nil && *occurrence.Status != domain.TrainingStatusPlanned {
+		return ErrOccurrenceStarted
+	}
+
+	return s.repo.SkipScheduleOccurrence(ctx, scheduleID, date)
+}
+
+func (s *trainingService) UpdateScheduleOccurrence(ctx context.Context, cmd domain.UpdateScheduleOccurrenceCmd) (*domain.Training, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if cmd.ScheduleID <= 0 {
+		return nil, ErrInvalidScheduleID
+	}
+	if cmd.Title != nil && strings.TrimSpace(*cmd.Title) == "" {
+		return nil, ErrEmptyScheduleTitle
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.getSchedule(ctx, cmd.ScheduleID, cmd.UserID)
+	if err != nil {
+		return nil, err
+	}
+	date := truncateToDay(cmd.Date)
+	if !schedule.Rule.IsOccurrence(schedule.StartDate, date) {
+		return nil, ErrInvalidOccurrenceDate
+	}
+	skips, err := s.repo.GetScheduleSkips(ctx, cmd.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if skipSet(skips)[date] {
+		return nil, ErrOccurrenceSkipped
+	}
+
+	// Повторение за горизонтом еще не развернуто: создаем его тренировку, чтобы изменить
+	occurrence, err := s.scheduledTraining(ctx, cmd.ScheduleID, date)
+	if err != nil {
+		return nil, err
+	}
+	if occurrence == nil {
+		if _, err := s.repo.MaterializeSchedule(ctx, schedule, []time.Time{date}, nil); err != nil {
+			return nil, err
+		}
+		if occurrence, err = s.scheduledTraining(ctx, cmd.ScheduleID, date); err != nil {
+			return nil, err
+		}
+		if occurrence == nil {
+			return nil, ErrTrainingNotFound
+		}
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, *occurrence.TrainingID, cmd.UserID)
+	if err != nil {
+		return nil, ErrTrainingNotFound
+	}
+	if cmd.Title != nil {
+		training.Title = strings.TrimSpace(*cmd.Title)
+	}
+	if cmd.PlannedDate != nil {
+		training.PlannedDate = *cmd.PlannedDate
+	}
+
+	return s.repo.UpdateTraining(ctx, training)
+}
+
+// getSchedule возвращает расписание пользователя, переводя отсутствие строки в ErrScheduleNotFound
+func (s *trainingService) getSchedule(ctx context.Context, scheduleID int64, userID uuid.UUID) (*domain.TrainingSchedule, error) {
+	schedule, err := s.repo.GetSchedule(ctx, scheduleID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// scheduledTraining возвращает созданную тренировку повторения или nil, если ее еще нет
+func (s *trainingService) scheduledTraining(ctx context.Context, scheduleID int64, date time.Time) (*domain.ScheduleOccurrence, error) {
+	trainings, err := s.repo.GetScheduledTrainings(ctx, scheduleID, date, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(trainings) == 0 {
+		return nil, nil
+	}
+	return &trainings[0], nil
+}
+
+// prepareSchedule проверяет название и источник упражнений и разбирает правило повторения
+func (s *trainingService) prepareSchedule(ctx context.Context, schedule *domain.TrainingSchedule, rrule string) error {
+	if schedule.Title == "" {
+		return ErrEmptyScheduleTitle
+	}
+	rule, err := domain.ParseRecurrenceRule(rrule)
+	if err != nil {
+		return fmt.Errorf("%w (%v)", ErrInvalidRecurrenceRule, err)
+	}
+	schedule.Rule = *rule
+
+	if schedule.GlobalTrainingID != nil && schedule.TemplateID != nil {
+		return ErrScheduleSourceConflict
+	}
+	if schedule.TemplateID != nil {
+		if *schedule.TemplateID <= 0 {
+			return ErrInvalidTemplateID
+		}
+		// Шаблон должен принадлежать владельцу расписания
+		if _, err := s.repo.GetTemplate(ctx, *schedule.TemplateID, schedule.UserID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTemplateNotFound
+			}
+			return err
+		}
+	}
+	if schedule.GlobalTrainingID != nil {
+		if *schedule.GlobalTrainingID <= 0 {
+			return ErrInvalidGlobalTrainingID
+		}
+		if _, err := s.repo.GetGlobalTrainingById(ctx, *schedule.GlobalTrainingID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrGlobalTrainingNotFound
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// materializeSchedule создает тренировки на повторения расписания до конца горизонта от today,
+// начиная со дня после уже развернутой части. Повторения в прошлом и пропущенные не создаются
+func materializeSchedule(ctx context.Context, repo domain.TrainingRepository, schedule *domain.TrainingSchedule, today time.Time) (int, error) {
+	horizon := today.AddDate(0, 0, scheduleHorizonDays)
+
+	from := truncateToDay(schedule.StartDate)
+	if from.Before(today) {
+		from = today
+	}
+	if schedule.MaterializedUntil != nil {
+		if next := truncateToDay(*schedule.MaterializedUntil).AddDate(0, 0, 1); next.After(from) {
+			from = next
+		}
+	}
+	if from.After(horizon) {
+		return 0, nil
+	}
+
+	skips, err := repo.GetScheduleSkips(ctx, schedule.ID)
+	if err != nil {
+		return 0, err
+	}
+	skipped := skipSet(skips)
+
+	var dates []time.Time
+	for _, date := range schedule.Rule.Occurrences(schedule.StartDate, from, horizon) {
+		if !skipped[date] {
+			dates = append(dates, date)
+		}
+	}
+
+	return repo.MaterializeSchedule(ctx, schedule, dates, &horizon)
+}
+
+func skipSet(skips []time.Time) map[time.Time]bool {
+	set := make(map[time.Time]bool, len(skips))
+	for _, skip := range skips {
+		set[truncateToDay(skip)] = true
+	}
+	return set
+}
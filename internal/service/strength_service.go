@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidProgressRange    = errors.New("from must not be after to, range must not exceed 5 years")
+	ErrInvalidGranularity      = errors.New("granularity must be one of: day, week, month")
+	ErrInvalidOneRepMaxFormula = errors.New("formula must be one of: epley, brzycki, lombardi")
+)
+
+const (
+	// defaultProgressMonths - глубина ряда в месяцах, если from не задан
+	defaultProgressMonths = 3
+	// maxProgressYears ограничивает размер выборки подходов
+	maxProgressYears = 5
+)
+
+func (s *trainingService) GetExerciseProgress(ctx context.Context, userID uuid.UUID, exerciseID int64, query domain.StrengthProgressQuery) (*domain.StrengthProgress, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if exerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if query.Granularity == "" {
+		query.Granularity = domain.ProgressWeek
+	}
+	if !query.Granularity.IsValid() {
+		return nil, ErrInvalidGranularity
+	}
+	if query.Formula == "" {
+		query.Formula = domain.OneRepMaxEpley
+	}
+	if !query.Formula.IsValid() {
+		return nil, ErrInvalidOneRepMaxFormula
+	}
+
+	if query.To.IsZero() {
+		today, err := s.userToday(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		query.To = today
+	}
+	if query.From.IsZero() {
+		query.From = query.To.AddDate(0, -defaultProgressMonths, 0)
+	}
+	if query.From.After(query.To) || query.From.AddDate(maxProgressYears, 0, 0).Before(query.To) {
+		return nil, ErrInvalidProgressRange
+	}
+
+	efforts, err := s.repo.GetExerciseStrengthEfforts(ctx, userID, exerciseID, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.StrengthProgress{
+		ExerciseID:  exerciseID,
+		From:        query.From,
+		To:          query.To,
+		Granularity: query.Granularity,
+		Formula:     query.Formula,
+		Points:      domain.BuildStrengthProgress(efforts, query.Granularity, query.Formula),
+	}, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTemplateID    = errors.New("invalid template id")
+	ErrTemplateNotFound     = errors.New("template not found")
+	ErrEmptyTemplateTitle   = errors.New("template title is required")
+	ErrTrainingNotCompleted = errors.New("only a completed training can be saved as a template")
+)
+
+func (s *trainingService) GetTemplates(ctx context.Context, userID uuid.UUID) ([]*domain.TrainingTemplate, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTemplates(ctx, userID)
+}
+
+func (s *trainingService) GetTemplate(ctx context.Context, userID uuid.UUID, templateID int64) (*domain.TrainingTemplate, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if templateID <= 0 {
+		return nil, ErrInvalidTemplateID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	template, err := s.repo.GetTemplate(ctx, templateID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *trainingService) CreateTemplate(ctx context.Context, cmd domain.TemplateCmd) (*domain.TrainingTemplate, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if err := s.validateTemplate(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateTemplate(ctx, cmd)
+}
+
+func (s *trainingService) UpdateTemplate(ctx context.Context, templateID int64, cmd domain.TemplateCmd) (*domain.TrainingTemplate, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if templateID <= 0 {
+		return nil, ErrInvalidTemplateID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if err := s.validateTemplate(ctx, cmd); err != nil {
+		return nil, err
+	}
+
+	template, err := s.repo.UpdateTemplate(ctx, templateID, cmd)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTemplateNotFound
+		}
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *trainingService) DeleteTemplate(ctx context.Context, userID uuid.UUID, templateID int64) error {
+	if userID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if templateID <= 0 {
+		return ErrInvalidTemplateID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteTemplate(ctx, templateID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// validateTemplate проверяет название шаблона, целевую нагрузку и существование упражнений
+func (s *trainingService) validateTemplate(ctx context.Context, cmd domain.TemplateCmd) error {
+	if cmd.Title == "" {
+		return ErrEmptyTemplateTitle
+	}
+	return s.validateExerciseSlots(ctx, cmd.Exercises)
+}
+
+func (s *trainingService) CreateTemplateFromTraining(ctx context.Context, cmd domain.TemplateFromTrainingCmd) (*domain.TrainingTemplate, error) {
+	if cmd.UserID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if cmd.TrainingID <= 0 {
+		return nil, ErrInvalidTrainingID
+	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, cmd.TrainingID, cmd.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrainingNotFound
+		}
+		return nil, err
+	}
+	if training.Status != domain.TrainingStatusCompleted {
+		return nil, ErrTrainingNotCompleted
+	}
+
+	cmd.Title = strings.TrimSpace(cmd.Title)
+	if cmd.Title == "" {
+		cmd.Title = training.Title
+	}
+
+	return s.repo.CreateTemplateFromTraining(ctx, cmd)
+}
+
+func (s *trainingService) InstantiateTemplate(ctx context.Context, cmd domain.InstantiateTemplateCmd) (*domain.Training, error) {
+	template, err := s.GetTemplate(ctx, cmd.UserID, cmd.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	plannedDate, err := s.plannedDateOrToday(ctx, cmd.UserID, cmd.PlannedDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CreateTrainingFromTemplate(ctx, template, plannedDate)
+}
+
+func (s *trainingService) CloneTraining(ctx context.Context, trainingID int64, plannedDate *time.Time) (*domain.Training, error) {
+	if trainingID <= 0 {
+		return nil, ErrInvalidTrainingID
+	}
+
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrainingNotFound
+		}
+		return nil, err
+	}
+
+	date, err := s.plannedDateOrToday(ctx, userID, plannedDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.CloneTraining(ctx, training, date)
+}
+
+// plannedDateOrToday возвращает дату новой тренировки: заданную или сегодняшнюю в часовом поясе пользователя
+func (s *trainingService) plannedDateOrToday(ctx context.Context, userID uuid.UUID, plannedDate *time.Time) (time.Time, error) {
+	if plannedDate != nil {
+		return truncateToDay(*plannedDate), nil
+	}
+	return s.userToday(ctx, userID)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidTimezone = errors.New("timezone must be an IANA time zone name, e.g. Asia/Novosibirsk")
+
+// parseTimezone проверяет имя часового пояса IANA
+func parseTimezone(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return loc, nil
+}
+
+// settingsLocation возвращает часовой пояс из заголовка запроса, а если его нет - из настроек пользователя
+func settingsLocation(ctx context.Context, settings *domain.UserSettings) *time.Location {
+	if loc, ok := domain.LocationFromContext(ctx); ok {
+		return loc
+	}
+	return settings.Location()
+}
+
+// userToday возвращает сегодняшнюю дату в часовом поясе пользователя
+func (s *trainingService) userToday(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	loc, err := s.userLocation(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return domain.LocalDate(time.Now(), loc), nil
+}
+
+// userLocation возвращает часовой пояс из заголовка запроса, а если его нет - из настроек пользователя
+func (s *trainingService) userLocation(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	if loc, ok := domain.LocationFromContext(ctx); ok {
+		return loc, nil
+	}
+
+	settings, err := s.repo.GetUserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return settings.Location(), nil
+}
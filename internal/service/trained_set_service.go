@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidTrainedSetID = errors.New("invalid set id")
+	ErrTrainedSetNotFound  = errors.New("set not found")
+	ErrInvalidTrainedSet   = errors.New("invalid set: weight, reps and duration must not be negative, rpe must be between 1 and 10")
+	ErrInvalidSetOrder     = errors.New("set order must list every set of the exercise exactly once")
+)
+
+var (
+	minRPE = decimal.NewFromInt(1)
+	maxRPE = decimal.NewFromInt(10)
+)
+
+func (s *trainingService) GetTrainedSets(ctx context.Context, trainedExerciseID int64) ([]*domain.TrainedSet, error) {
+	if trainedExerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	userID, _, err := s.authorizeTrainedExercise(ctx, trainedExerciseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTrainedSets(ctx, trainedExerciseID, userID)
+}
+
+func (s *trainingService) AddTrainedSet(ctx context.Context, cmd domain.AddTrainedSetCmd) (*domain.TrainedSet, error) {
+	if cmd.TrainedExerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	if err := validateTrainedSet(cmd.Weight, cmd.Reps, cmd.RPE, cmd.Duration); err != nil {
+		return nil, err
+	}
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, cmd.TrainedExerciseID)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := s.repo.AddTrainedSet(ctx, &domain.TrainedSet{
+		TrainedExerciseID: cmd.TrainedExerciseID,
+		Weight:            cmd.Weight,
+		Reps:              cmd.Reps,
+		Duration:          cmd.Duration,
+		RPE:               cmd.RPE,
+		IsWarmup:          cmd.IsWarmup,
+	}, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrainedExerciseNotFound
+		}
+		return nil, err
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventSetAdded,
+		TrainingID:        trainingID,
+		UserID:            userID,
+		TrainedExerciseID: set.TrainedExerciseID,
+		SetID:             set.ID,
+		Set:               set,
+	})
+	return set, nil
+}
+
+func (s *trainingService) UpdateTrainedSet(ctx context.Context, cmd domain.UpdateTrainedSetCmd) (*domain.TrainedSet, error) {
+	if cmd.TrainedExerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	if cmd.ID <= 0 {
+		return nil, ErrInvalidTrainedSetID
+	}
+	if err := validateTrainedSet(cmd.Weight, cmd.Reps, cmd.RPE, cmd.Duration); err != nil {
+		return nil, err
+	}
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, cmd.TrainedExerciseID)
+	if err != nil {
+		return nil, err
+	}
+
+	set, err := s.repo.UpdateTrainedSet(ctx, cmd, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrainedSetNotFound
+		}
+		return nil, err
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventSetUpdated,
+		TrainingID:        trainingID,
+		UserID:            userID,
+		TrainedExerciseID: set.TrainedExerciseID,
+		SetID:             set.ID,
+		Set:               set,
+	})
+	return set, nil
+}
+
+func (s *trainingService) DeleteTrainedSet(ctx context.Context, trainedExerciseID, setID int64) error {
+	if trainedExerciseID <= 0 {
+		return ErrInvalidExerciseID
+	}
+	if setID <= 0 {
+		return ErrInvalidTrainedSetID
+	}
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, trainedExerciseID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteTrainedSet(ctx, trainedExerciseID, setID, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTrainedSetNotFound
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventSetRemoved,
+		TrainingID:        trainingID,
+		UserID:            userID,
+		TrainedExerciseID: trainedExerciseID,
+		SetID:             setID,
+	})
+	return nil
+}
+
+func (s *trainingService) ReorderTrainedSets(ctx context.Context, trainedExerciseID int64, setIDs []int64) ([]*domain.TrainedSet, error) {
+	if trainedExerciseID <= 0 {
+		return nil, ErrInvalidExerciseID
+	}
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, trainedExerciseID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetTrainedSets(ctx, trainedExerciseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Новый порядок должен быть перестановкой текущих подходов
+	if len(setIDs) != len(current) {
+		return nil, ErrInvalidSetOrder
+	}
+	known := make(map[int64]bool, len(current))
+	for _, set := range current {
+		known[set.ID] = true
+	}
+	for _, id := range setIDs {
+		if !known[id] {
+			return nil, ErrInvalidSetOrder
+		}
+		delete(known, id)
+	}
+
+	if err := s.repo.ReorderTrainedSets(ctx, trainedExerciseID, setIDs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidSetOrder
+		}
+		return nil, err
+	}
+
+	sets, err := s.repo.GetTrainedSets(ctx, trainedExerciseID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventSetsReordered,
+		TrainingID:        trainingID,
+		UserID:            userID,
+		TrainedExerciseID: trainedExerciseID,
+		Sets:              sets,
+	})
+	return sets, nil
+}
+
+func validateTrainedSet(weight *decimal.Decimal, reps *int32, rpe *decimal.Decimal, duration *time.Duration) error {
+	if weight != nil && weight.IsNegative() ||
+		reps != nil && *reps < 0 ||
+		rpe != nil && (rpe.LessThan(minRPE) || rpe.GreaterThan(maxRPE)) ||
+		duration != nil && *duration < 0 {
+		return ErrInvalidTrainedSet
+	}
+	return nil
+}
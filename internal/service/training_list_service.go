@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/EnduranNSU/trainings/internal/domain"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTrainingSort   = errors.New("sort must be one of: planned_date, actual_date, rating")
+	ErrInvalidPageLimit      = errors.New("limit must be between 1 and 100")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrInvalidTrainingFilter = errors.New("planned_from must not be after planned_to, ratings must be between 1 and 5")
+)
+
+const (
+	defaultTrainingPageLimit = 20
+	maxTrainingPageLimit     = 100
+)
+
+func (s *trainingService) ListTrainings(ctx context.Context, userID uuid.UUID, filter domain.TrainingFilter) (*domain.TrainingPage, error) {
+	if userID == uuid.Nil {
+		return nil, ErrInvalidUserID
+	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if filter.SortBy == "" {
+		filter.SortBy = domain.TrainingSortPlannedDate
+	}
+	if !filter.SortBy.IsValid() {
+		return nil, ErrInvalidTrainingSort
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultTrainingPageLimit
+	}
+	if filter.Limit < 0 || filter.Limit > maxTrainingPageLimit {
+		return nil, ErrInvalidPageLimit
+	}
+	// Курсор от списка с другой сортировкой указывал бы на случайное место
+	if filter.Cursor != nil && (filter.Cursor.SortBy != filter.SortBy || filter.Cursor.SortDesc != filter.SortDesc) {
+		return nil, ErrInvalidCursor
+	}
+
+	if filter.PlannedFrom != nil && filter.PlannedTo != nil && filter.PlannedFrom.After(*filter.PlannedTo) {
+		return nil, ErrInvalidTrainingFilter
+	}
+	for _, rating := range []*int32{filter.MinRating, filter.MaxRating} {
+		if rating != nil && (*rating < 1 || *rating > 5) {
+			return nil, ErrInvalidTrainingFilter
+		}
+	}
+	if filter.Search != nil && strings.TrimSpace(*filter.Search) == "" {
+		filter.Search = nil
+	}
+
+	return s.repo.GetTrainingsFiltered(ctx, userID, filter)
+}
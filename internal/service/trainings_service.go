@@ -18,28 +18,55 @@ var (
 	ErrTrainingNotActive = errors.New("training is not active")
 	ErrInvalidGlobalTrainingID = errors.New("invalid global training id")
     ErrGlobalTrainingNotFound  = errors.New("global training not found")
+	ErrInvalidStatsRange       = errors.New("from must not be after to")
+	ErrInvalidStatsGroupBy     = errors.New("group_by must be one of: day, week, month")
 )
 
-func NewTrainingService(repo domain.TrainingRepository) domain.TrainingService {
-	return &trainingService{repo: repo}
+func NewTrainingService(repo domain.TrainingRepository, progression domain.ProgressionRules) domain.TrainingService {
+	return &trainingService{repo: repo, events: NewEventBus(), progression: progression}
 }
 
 type trainingService struct {
-	repo domain.TrainingRepository
+	repo        domain.TrainingRepository
+	events      *EventBus
+	progression domain.ProgressionRules
 }
 
-func (s *trainingService) GetUserTrainingStats(ctx context.Context, userID uuid.UUID) (*domain.TrainingStats, error) {
+func (s *trainingService) GetUserTrainingStats(ctx context.Context, userID uuid.UUID, query domain.UserStatsQuery) (*domain.UserTrainingStats, error) {
 	if userID == uuid.Nil {
 		return nil, errors.New("invalid user id")
 	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if query.GroupBy == "" {
+		query.GroupBy = domain.ProgressMonth
+	}
+	if !query.GroupBy.IsValid() {
+		return nil, ErrInvalidStatsGroupBy
+	}
+	if query.To.IsZero() {
+		today, err := s.userToday(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		query.To = today
+	}
+	if query.From.After(query.To) {
+		return nil, ErrInvalidStatsRange
+	}
 
-	return s.repo.GetUserTrainingStats(ctx, userID)
+	return s.repo.GetUserTrainingStats(ctx, userID, query)
 }
 
 func (s *trainingService) GetTrainingsByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Training, error) {
 	if userID == uuid.Nil {
 		return nil, errors.New("invalid user id")
 	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
 
 	return s.repo.GetTrainingsByUser(ctx, userID)
 }
@@ -49,13 +76,21 @@ func (s *trainingService) GetTrainingWithExercises(ctx context.Context, training
 		return nil, ErrInvalidTrainingID
 	}
 
-	return s.repo.GetTrainingWithExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 }
 
 func (s *trainingService) CreateTraining(ctx context.Context, cmd domain.CreateTrainingCmd) (*domain.Training, error) {
 	if cmd.UserID == uuid.Nil {
 		return nil, errors.New("invalid user id")
 	}
+	if err := authorizeUser(ctx, cmd.UserID); err != nil {
+		return nil, err
+	}
 
 	if cmd.PlannedDate.IsZero() {
 		return nil, errors.New("planned date is required")
@@ -72,6 +107,7 @@ func (s *trainingService) CreateTraining(ctx context.Context, cmd domain.CreateT
 		TotalRestTime:     cmd.TotalRestTime,
 		TotalExerciseTime: cmd.TotalExerciseTime,
 		Rating:            cmd.Rating,
+		Status:            initialTrainingStatus(cmd),
 	}
 
 	return s.repo.CreateTraining(ctx, training)
@@ -82,28 +118,25 @@ func (s *trainingService) UpdateTraining(ctx context.Context, cmd domain.UpdateT
 		return nil, ErrInvalidTrainingID
 	}
 
+	userID, err := s.authorizeTraining(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Проверяем существование тренировки
-	existing, err := s.repo.GetTrainingWithExercises(ctx, cmd.ID)
+	existing, err := s.repo.GetTrainingWithExercises(ctx, cmd.ID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
 
-	// Обновляем только переданные поля
-	if cmd.IsDone != nil {
-		existing.IsDone = *cmd.IsDone
+	// Обновляем только переданные поля. Статус и связанные с ним поля меняются
+	// только переходами жизненного цикла (start, pause, complete, skip, cancel)
+	if cmd.Title != "" {
+		existing.Title = cmd.Title
 	}
 	if !cmd.PlannedDate.IsZero() {
 		existing.PlannedDate = cmd.PlannedDate
 	}
-	if cmd.ActualDate != nil {
-		existing.ActualDate = cmd.ActualDate
-	}
-	if cmd.StartedAt != nil {
-		existing.StartedAt = cmd.StartedAt
-	}
-	if cmd.FinishedAt != nil {
-		existing.FinishedAt = cmd.FinishedAt
-	}
 	if cmd.TotalDuration != nil {
 		existing.TotalDuration = cmd.TotalDuration
 	}
@@ -125,7 +158,12 @@ func (s *trainingService) DeleteTraining(ctx context.Context, trainingID int64)
 		return ErrInvalidTrainingID
 	}
 
-	return s.repo.DeleteTrainingAndExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteTrainingAndExercises(ctx, trainingID, userID)
 }
 
 func (s *trainingService) AddExerciseToTraining(ctx context.Context, cmd domain.AddExerciseToTrainingCmd) (*domain.TrainedExercise, error) {
@@ -136,10 +174,43 @@ func (s *trainingService) AddExerciseToTraining(ctx context.Context, cmd domain.
 		return nil, ErrInvalidExerciseID
 	}
 
-	// Проверяем существование тренировки
-	_, err := s.repo.GetTrainingWithExercises(ctx, cmd.TrainingID)
+	userID, err := s.authorizeTraining(ctx, cmd.TrainingID)
 	if err != nil {
-		return nil, ErrTrainingNotFound
+		return nil, err
+	}
+
+	if cmd.Group != nil {
+		if err := s.checkTrainingGroup(ctx, cmd.TrainingID, userID, 0, *cmd.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.Suggest && (cmd.Weight == nil || cmd.Approaches == nil || cmd.Reps == nil) {
+		suggestion, err := s.suggestExerciseLoad(ctx, userID, cmd.ExerciseID)
+		if err != nil {
+			return nil, err
+		}
+		// Явно переданные значения важнее предложенных
+		if cmd.Weight == nil {
+			cmd.Weight = suggestion.Weight
+		}
+		if cmd.Approaches == nil {
+			cmd.Approaches = suggestion.Approaches
+		}
+		if cmd.Reps == nil {
+			cmd.Reps = suggestion.Reps
+		}
+	}
+
+	var cardio *domain.CardioMetrics
+	if cmd.Cardio != nil {
+		exerciseType, err := s.exerciseType(ctx, cmd.ExerciseID)
+		if err != nil {
+			return nil, err
+		}
+		if cardio, err = normalizeCardio(exerciseType, nil, cmd.Cardio, cmd.Doing, cmd.Time); err != nil {
+			return nil, err
+		}
 	}
 
 	exercise := &domain.TrainedExercise{
@@ -152,9 +223,23 @@ func (s *trainingService) AddExerciseToTraining(ctx context.Context, cmd domain.
 		Doing:      cmd.Doing,
 		Rest:       cmd.Rest,
 		Notes:      cmd.Notes,
+		Group:      cmd.Group,
+		Cardio:     cardio,
 	}
 
-	return s.repo.AddExerciseToTraining(ctx, exercise)
+	added, err := s.repo.AddExerciseToTraining(ctx, exercise, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventExerciseAdded,
+		TrainingID:        cmd.TrainingID,
+		UserID:            userID,
+		TrainedExerciseID: added.ID,
+		Exercise:          added,
+	})
+	return added, nil
 }
 
 func (s *trainingService) UpdateTrainedExercise(ctx context.Context, cmd domain.UpdateTrainedExerciseCmd) (*domain.TrainedExercise, error) {
@@ -162,6 +247,19 @@ func (s *trainingService) UpdateTrainedExercise(ctx context.Context, cmd domain.
 		return nil, ErrInvalidExerciseID
 	}
 
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exerciseType, current, err := s.repo.GetTrainedExerciseCardio(ctx, cmd.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTrainedExerciseNotFound
+		}
+		return nil, err
+	}
+
 	exercise := &domain.TrainedExercise{
 		ID:         cmd.ID,
 		Weight:     cmd.Weight,
@@ -172,8 +270,24 @@ func (s *trainingService) UpdateTrainedExercise(ctx context.Context, cmd domain.
 		Rest:       cmd.Rest,
 		Notes:      cmd.Notes,
 	}
+	if cmd.Cardio != nil {
+		if exercise.Cardio, err = normalizeCardio(exerciseType, current, cmd.Cardio, cmd.Doing, cmd.Time); err != nil {
+			return nil, err
+		}
+	}
 
-	return s.repo.UpdateTrainedExercise(ctx, exercise)
+	updated, err := s.repo.UpdateTrainedExercise(ctx, exercise, userID)
+	if err != nil {
+		return nil, err
+	}
+	if updated.Cardio == nil {
+		updated.Cardio = current
+	}
+
+	s.refreshPersonalRecords(ctx, userID, updated)
+
+	s.publishExerciseUpdated(trainingID, userID, updated)
+	return updated, nil
 }
 
 func (s *trainingService) RemoveExerciseFromTraining(ctx context.Context, trainingID, exerciseID int64) error {
@@ -184,7 +298,22 @@ func (s *trainingService) RemoveExerciseFromTraining(ctx context.Context, traini
 		return ErrInvalidExerciseID
 	}
 
-	return s.repo.DeleteExerciseFromTraining(ctx, exerciseID, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteExerciseFromTraining(ctx, exerciseID, trainingID, userID); err != nil {
+		return err
+	}
+
+	s.events.Publish(domain.LiveEvent{
+		Type:              domain.LiveEventExerciseRemoved,
+		TrainingID:        trainingID,
+		UserID:            userID,
+		TrainedExerciseID: exerciseID,
+	})
+	return nil
 }
 
 func (s *trainingService) CompleteTraining(ctx context.Context, trainingID int64, rating *int32) (*domain.Training, error) {
@@ -192,24 +321,95 @@ func (s *trainingService) CompleteTraining(ctx context.Context, trainingID int64
 		return nil, ErrInvalidTrainingID
 	}
 
-	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
 
-	training.IsDone = true
+	if _, err := training.Status.Transition(domain.TrainingEventComplete); err != nil {
+		return nil, err
+	}
+
+	// Незакрытая пауза заканчивается вместе с тренировкой
+	if training.Status == domain.TrainingStatusPaused {
+		if _, err := s.repo.ResumeTraining(ctx, trainingID, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now().UTC()
-	training.ActualDate = &now
-	training.FinishedAt = &now
-	training.Rating = rating
+	finished := *training
+	finished.FinishedAt = &now
+	totalDuration, err := s.activeDuration(ctx, &finished)
+	if err != nil {
+		return nil, err
+	}
+
+	// Дата выполнения - "сегодня" в часовом поясе пользователя, как в MarkTrainingAsDone
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Если не было начато, устанавливаем время начала как текущее минус предполагаемая длительность
-	if training.StartedAt == nil {
-		startedAt := now.Add(-*training.TotalDuration)
-		training.StartedAt = &startedAt
+	// Переход сохраняется условным запросом: параллельный пропуск, отмена или повторное
+	// завершение не перезаписываются, а рекорды не определяются дважды
+	completed, err := s.repo.CompleteTraining(ctx, trainingID, userID, today, now, rating, totalDuration)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, statusChanged(training.Status, domain.TrainingEventComplete)
+		}
+		return nil, err
+	}
+
+	for i := range training.Exercises {
+		records := s.detectPersonalRecords(ctx, userID, &training.Exercises[i])
+		completed.PersonalRecords = append(completed.PersonalRecords, records...)
+	}
+
+	s.publishTraining(domain.LiveEventTrainingCompleted, completed)
+	return completed, nil
+}
+
+// initialTrainingStatus выводит статус новой тренировки из переданных полей
+func initialTrainingStatus(cmd domain.CreateTrainingCmd) domain.TrainingStatus {
+	switch {
+	case cmd.IsDone:
+		return domain.TrainingStatusCompleted
+	case cmd.StartedAt != nil:
+		return domain.TrainingStatusInProgress
+	default:
+		return domain.TrainingStatusPlanned
 	}
+}
 
-	return s.repo.UpdateTraining(ctx, training)
+// statusChanged — статус тренировки изменил параллельный запрос
+// между проверкой перехода и записью
+func statusChanged(from domain.TrainingStatus, event domain.TrainingEvent) error {
+	return &domain.TransitionError{From: from, Event: event, Reason: domain.TransitionReasonStatusChanged}
+}
+
+// activeDuration считает длительность тренировки как finished_at - started_at
+// за вычетом всех пауз. Для не начатой или не завершенной тренировки возвращает nil
+func (s *trainingService) activeDuration(ctx context.Context, training *domain.Training) (*time.Duration, error) {
+	if training.StartedAt == nil || training.FinishedAt == nil {
+		return nil, nil
+	}
+
+	paused, err := s.repo.GetTrainingPausedTime(ctx, training.ID, training.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	total := training.FinishedAt.Sub(*training.StartedAt) - paused
+	if total < 0 {
+		total = 0
+	}
+	return &total, nil
 }
 
 
@@ -218,6 +418,11 @@ func (s *trainingService) UpdateExerciseTime(ctx context.Context, exerciseID int
 		return nil, ErrInvalidExerciseID
 	}
 
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Создаем объект упражнения с обновленными временными параметрами
 	exercise := &domain.TrainedExercise{
 		ID:         exerciseID,
@@ -229,7 +434,7 @@ func (s *trainingService) UpdateExerciseTime(ctx context.Context, exerciseID int
 		Rest:       rest,
 	}
 
-	return s.repo.UpdateExerciseTime(ctx, exercise)
+	return s.updateExerciseTime(ctx, trainingID, exercise, userID)
 }
 
 func (s *trainingService) UpdateTrainingTimers(ctx context.Context, trainingID int64, totalDuration *time.Duration, totalRestTime *time.Duration, totalExerciseTime *time.Duration) (*domain.Training, error) {
@@ -238,7 +443,12 @@ func (s *trainingService) UpdateTrainingTimers(ctx context.Context, trainingID i
 	}
 
 	// Получаем существующую тренировку
-	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
@@ -262,29 +472,44 @@ func (s *trainingService) CalculateTrainingTotalTime(ctx context.Context, traini
 		return nil, ErrInvalidTrainingID
 	}
 
-	// Проверяем существование тренировки
-	_, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
 	if err != nil {
-		return nil, ErrTrainingNotFound
+		return nil, err
 	}
 
-	return s.repo.CalculateTrainingTotalTime(ctx, trainingID)
+	return s.repo.CalculateTrainingTotalTime(ctx, trainingID, userID)
 }
 
 func (s *trainingService) GetCurrentTraining(ctx context.Context, userID uuid.UUID) (*domain.Training, error) {
 	if userID == uuid.Nil {
 		return nil, ErrInvalidUserID
 	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.repo.GetCurrentTraining(ctx, userID)
+	return s.repo.GetCurrentTraining(ctx, userID, today)
 }
 
 func (s *trainingService) GetTodaysTraining(ctx context.Context, userID uuid.UUID) ([]*domain.Training, error) {
 	if userID == uuid.Nil {
 		return nil, ErrInvalidUserID
 	}
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-	trainings, err := s.repo.GetTodaysTraining(ctx, userID)
+	trainings, err := s.repo.GetTodaysTraining(ctx, userID, today)
 	if err != nil {
 		return nil, err
 	}
@@ -325,23 +550,66 @@ func (s *trainingService) MarkTrainingAsDone(ctx context.Context, trainingID int
 		return nil, ErrInvalidUserID
 	}
 
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Проверяем, что тренировка принадлежит пользователю
-	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	if _, err := s.authorizeTraining(ctx, trainingID); err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
 
-	if training.UserID != userID {
-		return nil, errors.New("training does not belong to user")
+	if _, err := training.Status.Transition(domain.TrainingEventComplete); err != nil {
+		return nil, err
+	}
+
+	if training.Status == domain.TrainingStatusPaused {
+		if _, err := s.repo.ResumeTraining(ctx, trainingID, userID); err != nil {
+			return nil, err
+		}
 	}
 
-	// Проверяем, что тренировка не завершена
-	if training.IsDone {
-		return training, nil // Уже завершена
+	today, err := s.userToday(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Завершаем тренировку
-	return s.repo.MarkTrainingAsDone(ctx, trainingID, userID)
+	done, err := s.repo.MarkTrainingAsDone(ctx, trainingID, userID, today)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, statusChanged(training.Status, domain.TrainingEventComplete)
+		}
+		return nil, err
+	}
+
+	totalDuration, err := s.activeDuration(ctx, done)
+	if err != nil {
+		return nil, err
+	}
+	if totalDuration != nil {
+		done, err = s.repo.UpdateTrainingTimers(ctx, &domain.Training{
+			ID:            done.ID,
+			UserID:        done.UserID,
+			TotalDuration: totalDuration,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range training.Exercises {
+		records := s.detectPersonalRecords(ctx, userID, &training.Exercises[i])
+		done.PersonalRecords = append(done.PersonalRecords, records...)
+	}
+
+	s.publishTraining(domain.LiveEventTrainingCompleted, done)
+	return done, nil
 }
 
 func (s *trainingService) GetTrainingStats(ctx context.Context, trainingID int64) (*domain.TrainingStats, error) {
@@ -349,7 +617,12 @@ func (s *trainingService) GetTrainingStats(ctx context.Context, trainingID int64
 		return nil, ErrInvalidTrainingID
 	}
 
-	return s.repo.GetTrainingStats(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTrainingStats(ctx, trainingID, userID)
 }
 
 func (s *trainingService) StartTraining(ctx context.Context, trainingID int64, userID uuid.UUID) (*domain.Training, error) {
@@ -360,23 +633,35 @@ func (s *trainingService) StartTraining(ctx context.Context, trainingID int64, u
 		return nil, ErrInvalidUserID
 	}
 
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// Проверяем, что тренировка принадлежит пользователю
-	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	if _, err := s.authorizeTraining(ctx, trainingID); err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
 
-	if training.UserID != userID {
-		return nil, errors.New("training does not belong to user")
+	if _, err := training.Status.Transition(domain.TrainingEventStart); err != nil {
+		return nil, err
 	}
 
-	// Проверяем, что тренировка еще не начата
-	if training.StartedAt != nil {
-		return training, nil // Уже начата
+	// Начинаем тренировку
+	started, err := s.repo.StartTraining(ctx, trainingID, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, statusChanged(training.Status, domain.TrainingEventStart)
+		}
+		return nil, err
 	}
 
-	// Начинаем тренировку
-	return s.repo.StartTraining(ctx, trainingID, userID)
+	s.publishTraining(domain.LiveEventTrainingStarted, started)
+	return started, nil
 }
 
 // Дополнительные методы для управления временем тренировки
@@ -386,6 +671,11 @@ func (s *trainingService) UpdateExerciseRestTime(ctx context.Context, exerciseID
 		return nil, ErrInvalidExerciseID
 	}
 
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Получаем упражнение
 	// В реальной реализации нужно получить упражнение из репозитория
 	// Для примера создаем новый объект
@@ -394,7 +684,7 @@ func (s *trainingService) UpdateExerciseRestTime(ctx context.Context, exerciseID
 		Rest: &restTime,
 	}
 
-	return s.repo.UpdateExerciseTime(ctx, exercise)
+	return s.updateExerciseTime(ctx, trainingID, exercise, userID)
 }
 
 func (s *trainingService) UpdateExerciseDoingTime(ctx context.Context, exerciseID int64, doingTime time.Duration) (*domain.TrainedExercise, error) {
@@ -402,12 +692,17 @@ func (s *trainingService) UpdateExerciseDoingTime(ctx context.Context, exerciseI
 		return nil, ErrInvalidExerciseID
 	}
 
+	userID, trainingID, err := s.authorizeTrainedExercise(ctx, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+
 	exercise := &domain.TrainedExercise{
 		ID:    exerciseID,
 		Doing: &doingTime,
 	}
 
-	return s.repo.UpdateExerciseTime(ctx, exercise)
+	return s.updateExerciseTime(ctx, trainingID, exercise, userID)
 }
 
 func (s *trainingService) PauseTraining(ctx context.Context, trainingID int64) (*domain.Training, error) {
@@ -416,21 +711,36 @@ func (s *trainingService) PauseTraining(ctx context.Context, trainingID int64) (
 	}
 
 	// Получаем тренировку
-	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
 
-	// Проверяем, что тренировка начата
-	if training.StartedAt == nil {
-		return nil, ErrTrainingNotActive
+	// Поставить на паузу можно только идущую тренировку
+	if _, err := training.Status.Transition(domain.TrainingEventPause); err != nil {
+		return nil, err
+	}
+
+	paused, err := s.repo.PauseTraining(ctx, trainingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Статус мог быть изменен параллельным запросом
+	if !paused {
+		return nil, statusChanged(training.Status, domain.TrainingEventPause)
 	}
 
-	// В реальной реализации здесь можно:
-	// 1. Сохранить время паузы
-	// 2. Обновить общее время тренировки
-	// 3. Запустить таймер паузы
+	training, err = s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
+	if err != nil {
+		return nil, err
+	}
 
+	s.publishTraining(domain.LiveEventTrainingPaused, training)
 	return training, nil
 }
 
@@ -440,20 +750,34 @@ func (s *trainingService) ResumeTraining(ctx context.Context, trainingID int64)
 	}
 
 	// Получаем тренировку
-	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID)
+	userID, err := s.authorizeTraining(ctx, trainingID)
+	if err != nil {
+		return nil, err
+	}
+
+	training, err := s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
 	if err != nil {
 		return nil, ErrTrainingNotFound
 	}
 
-	// Проверяем, что тренировка начата
-	if training.StartedAt == nil {
-		return nil, ErrTrainingNotActive
+	if _, err := training.Status.Transition(domain.TrainingEventResume); err != nil {
+		return nil, err
 	}
 
-	// В реальной реализации здесь можно:
-	// 1. Обновить время тренировки с учетом паузы
-	// 2. Запустить таймер заново
+	resumed, err := s.repo.ResumeTraining(ctx, trainingID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, statusChanged(training.Status, domain.TrainingEventResume)
+	}
 
+	training, err = s.repo.GetTrainingWithExercises(ctx, trainingID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishTraining(domain.LiveEventTrainingResumed, training)
 	return training, nil
 }
 
@@ -463,6 +787,9 @@ func (s *trainingService) AssignGlobalTraining(ctx context.Context, cmd domain.A
     if cmd.UserID == uuid.Nil {
         return nil, ErrInvalidUserID
     }
+    if err := authorizeUser(ctx, cmd.UserID); err != nil {
+        return nil, err
+    }
     if cmd.GlobalTrainingID <= 0 {
         return nil, ErrInvalidGlobalTrainingID
     }